@@ -0,0 +1,269 @@
+package pulse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AgentBatch is the payload an agent pushes to a collector.
+type AgentBatch struct {
+	Instance     string             `json:"instance"`
+	AppName      string             `json:"app_name"`
+	SentAt       time.Time          `json:"sent_at"`
+	Requests     []RequestMetric    `json:"requests,omitempty"`
+	Queries      []QueryMetric      `json:"queries,omitempty"`
+	Dependencies []DependencyMetric `json:"dependencies,omitempty"`
+	Errors       []ErrorRecord      `json:"errors,omitempty"`
+	Runtime      []RuntimeMetric    `json:"runtime,omitempty"`
+	Spans        []Span             `json:"spans,omitempty"`
+	Connections  []ConnectionMetric `json:"connections,omitempty"`
+}
+
+// Len returns the number of metrics in the batch.
+func (b *AgentBatch) Len() int {
+	return len(b.Requests) + len(b.Queries) + len(b.Dependencies) + len(b.Errors) + len(b.Runtime) + len(b.Spans) + len(b.Connections)
+}
+
+func (b *AgentBatch) add(item interface{}) {
+	switch m := item.(type) {
+	case RequestMetric:
+		b.Requests = append(b.Requests, m)
+	case QueryMetric:
+		b.Queries = append(b.Queries, m)
+	case DependencyMetric:
+		b.Dependencies = append(b.Dependencies, m)
+	case ErrorRecord:
+		b.Errors = append(b.Errors, m)
+	case RuntimeMetric:
+		b.Runtime = append(b.Runtime, m)
+	case Span:
+		b.Spans = append(b.Spans, m)
+	case ConnectionMetric:
+		b.Connections = append(b.Connections, m)
+	}
+}
+
+// AgentStats reports the state of the agent's push buffer.
+type AgentStats struct {
+	CollectorURL string    `json:"collector_url"`
+	Pending      int       `json:"pending"`  // metrics waiting to be pushed
+	Sent         uint64    `json:"sent"`     // metrics accepted by the collector
+	Dropped      uint64    `json:"dropped"`  // metrics discarded because the buffer was full
+	Failures     uint64    `json:"failures"` // failed pushes
+	LastPush     time.Time `json:"last_push,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+}
+
+// Agent buffers the metrics this instance collects and pushes them in
+// batches to a central collector. While the collector is unreachable,
+// metrics are kept up to MaxPending and retried on the next flush.
+type Agent struct {
+	pulse      *Pulse
+	client     *http.Client
+	endpoint   string
+	token      string
+	batchSize  int
+	maxPending int
+
+	mu      sync.Mutex
+	pending []interface{}
+	stats   AgentStats
+
+	// pushMu serializes flushes so batches reach the collector in order
+	pushMu sync.Mutex
+	kick   chan struct{}
+}
+
+// newAgent creates the agent and starts its flush loop. It returns nil when
+// no collector URL is configured.
+func newAgent(p *Pulse) *Agent {
+	cfg := p.config.Agent
+	if cfg.CollectorURL == "" {
+		return nil
+	}
+
+	a := &Agent{
+		pulse:      p,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		endpoint:   cfg.CollectorURL + "/api/ingest",
+		token:      cfg.Token,
+		batchSize:  cfg.BatchSize,
+		maxPending: cfg.MaxPending,
+		kick:       make(chan struct{}, 1),
+	}
+	a.stats.CollectorURL = cfg.CollectorURL
+
+	p.startBackground("agent", func(ctx context.Context) {
+		ticker := time.NewTicker(cfg.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Shutdown pushes the rest once the ingestion queue is drained
+				return
+			case <-ticker.C:
+				a.flush()
+			case <-a.kick:
+				a.flush()
+			}
+		}
+	})
+
+	return a
+}
+
+// addBatch buffers a stored metric batch for the next push.
+func (a *Agent) addBatch(b MetricBatch) {
+	items := make([]interface{}, 0, b.Len())
+	for _, m := range b.Requests {
+		items = append(items, m)
+	}
+	for _, m := range b.Queries {
+		items = append(items, m)
+	}
+	for _, m := range b.Dependencies {
+		items = append(items, m)
+	}
+	for _, e := range b.Errors {
+		items = append(items, e)
+	}
+	for _, s := range b.Spans {
+		items = append(items, s)
+	}
+	for _, c := range b.Connections {
+		items = append(items, c)
+	}
+	a.add(items...)
+}
+
+// addRuntime buffers a runtime sample for the next push.
+func (a *Agent) addRuntime(m RuntimeMetric) {
+	a.add(m)
+}
+
+func (a *Agent) add(items ...interface{}) {
+	a.mu.Lock()
+	a.pending = append(a.pending, items...)
+	a.trimLocked()
+	full := len(a.pending) >= a.batchSize
+	a.mu.Unlock()
+
+	if full {
+		select {
+		case a.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest pending metrics beyond MaxPending.
+func (a *Agent) trimLocked() {
+	if over := len(a.pending) - a.maxPending; over > 0 {
+		a.pending = append(a.pending[:0:0], a.pending[over:]...)
+		a.stats.Dropped += uint64(over)
+	}
+}
+
+// flush pushes pending metrics in batches until the buffer is empty or a
+// push fails. Failed batches go back to the front of the buffer.
+func (a *Agent) flush() error {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+
+	for {
+		a.mu.Lock()
+		n := len(a.pending)
+		if n > a.batchSize {
+			n = a.batchSize
+		}
+		items := a.pending[:n:n]
+		a.pending = a.pending[n:]
+		a.mu.Unlock()
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		err := a.push(items)
+
+		a.mu.Lock()
+		if err != nil {
+			a.pending = append(items, a.pending...)
+			a.trimLocked()
+			a.stats.Failures++
+			a.stats.LastError = err.Error()
+		} else {
+			a.stats.Sent += uint64(len(items))
+			a.stats.LastPush = time.Now()
+			a.stats.LastError = ""
+		}
+		a.mu.Unlock()
+
+		if err != nil {
+			if a.pulse.config.DevMode {
+				a.pulse.logger.Printf("[pulse] agent push to %s failed: %v", a.endpoint, err)
+			}
+			return err
+		}
+	}
+}
+
+// push sends one gzip-compressed batch to the collector.
+func (a *Agent) push(items []interface{}) error {
+	batch := AgentBatch{
+		Instance: a.pulse.config.Instance,
+		AppName:  a.pulse.config.AppName,
+		SentAt:   time.Now(),
+	}
+	for _, item := range items {
+		batch.add(item)
+	}
+
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(zw).Encode(&batch); err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Stats returns the current push counters.
+func (a *Agent) Stats() AgentStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.stats
+	stats.Pending = len(a.pending)
+	return stats
+}
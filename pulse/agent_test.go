@@ -0,0 +1,151 @@
+package pulse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestAgentPulse(t *testing.T, agent AgentConfig) *Pulse {
+	t.Helper()
+	p := newPulse(applyDefaults(Config{Instance: "web-1", AppName: "shop", Agent: agent}))
+	p.storage = NewMemoryStorage("test")
+	p.agent = newAgent(p)
+	t.Cleanup(func() { p.Shutdown() })
+	return p
+}
+
+func TestApplyDefaults_Agent(t *testing.T) {
+	cfg := applyDefaults(Config{Agent: AgentConfig{CollectorURL: "http://collector:8080/pulse/"}})
+	if cfg.Agent.CollectorURL != "http://collector:8080/pulse" {
+		t.Errorf("expected trailing slash trimmed, got %q", cfg.Agent.CollectorURL)
+	}
+	if cfg.Agent.FlushInterval != 5*time.Second || cfg.Agent.BatchSize != 1000 || cfg.Agent.MaxPending != 50000 {
+		t.Errorf("unexpected agent defaults: %+v", cfg.Agent)
+	}
+	if cfg.Instance == "" {
+		t.Error("expected a default instance name")
+	}
+}
+
+func TestAgent_DisabledWithoutCollectorURL(t *testing.T) {
+	p := newTestAgentPulse(t, AgentConfig{})
+	if p.agent != nil {
+		t.Fatal("expected no agent without a collector URL")
+	}
+}
+
+func TestAgent_PushesToCollector(t *testing.T) {
+	collector, router := setupCollectorPulse(t, "secret")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	p := newTestAgentPulse(t, AgentConfig{CollectorURL: srv.URL + "/pulse", Token: "secret", FlushInterval: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		p.ingest(RequestMetric{Method: "GET", Path: "/a", StatusCode: 200, Timestamp: time.Now()})
+	}
+	p.ingest(ErrorRecord{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: time.Now(), LastSeen: time.Now()})
+	p.agent.addRuntime(RuntimeMetric{NumGoroutine: 7, Instance: "web-1", Timestamp: time.Now()})
+
+	if err := p.agent.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	reqs, _ := collector.storage.GetRequests(RequestFilter{Instance: "web-1"})
+	if len(reqs) != 3 {
+		t.Errorf("expected 3 requests from web-1 on the collector, got %d", len(reqs))
+	}
+	errs, _ := collector.storage.GetErrors(ErrorFilter{Instance: "web-1"})
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error from web-1 on the collector, got %d", len(errs))
+	}
+
+	// Metrics are still stored locally
+	local, _ := p.storage.GetRequests(RequestFilter{})
+	if len(local) != 3 || local[0].Instance != "web-1" {
+		t.Errorf("expected 3 labelled local requests, got %+v", local)
+	}
+
+	stats := p.agent.Stats()
+	if stats.Sent != 5 || stats.Pending != 0 || stats.Failures != 0 {
+		t.Errorf("unexpected agent stats: %+v", stats)
+	}
+}
+
+func TestAgent_RetriesAndCapsPending(t *testing.T) {
+	var up atomic.Bool
+	var received atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p := newTestAgentPulse(t, AgentConfig{CollectorURL: srv.URL, FlushInterval: time.Hour, BatchSize: 100, MaxPending: 5})
+
+	for i := 0; i < 8; i++ {
+		p.agent.addRuntime(RuntimeMetric{NumGoroutine: i, Timestamp: time.Now()})
+	}
+	if stats := p.agent.Stats(); stats.Pending != 5 || stats.Dropped != 3 {
+		t.Fatalf("expected 5 pending and 3 dropped, got %+v", stats)
+	}
+
+	if err := p.agent.flush(); err == nil {
+		t.Fatal("expected push to fail while the collector is down")
+	}
+	if stats := p.agent.Stats(); stats.Pending != 5 || stats.Failures != 1 || stats.LastError == "" {
+		t.Fatalf("expected failed batch to be kept, got %+v", stats)
+	}
+
+	// The oldest samples were dropped; the rest are retried in order
+	p.agent.mu.Lock()
+	first := p.agent.pending[0].(RuntimeMetric)
+	p.agent.mu.Unlock()
+	if first.NumGoroutine != 3 {
+		t.Errorf("expected oldest kept sample to be #3, got #%d", first.NumGoroutine)
+	}
+
+	up.Store(true)
+	if err := p.agent.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if stats := p.agent.Stats(); stats.Pending != 0 || stats.Sent != 5 || stats.LastError != "" {
+		t.Errorf("expected pending metrics sent, got %+v", stats)
+	}
+	if received.Load() != 1 {
+		t.Errorf("expected 1 push, got %d", received.Load())
+	}
+}
+
+func TestAgent_FlushesOnShutdown(t *testing.T) {
+	collector, router := setupCollectorPulse(t, "")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	p := newPulse(applyDefaults(Config{
+		Instance: "web-1",
+		Agent:    AgentConfig{CollectorURL: srv.URL + "/pulse", FlushInterval: time.Hour},
+	}))
+	p.storage = NewMemoryStorage("test")
+	p.ingester = newIngester(p)
+	p.agent = newAgent(p)
+
+	for i := 0; i < 20; i++ {
+		p.ingest(RequestMetric{Method: "GET", Path: "/a", StatusCode: 200, Timestamp: time.Now()})
+	}
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	reqs, _ := collector.storage.GetRequests(RequestFilter{Instance: "web-1"})
+	if len(reqs) != 20 {
+		t.Errorf("expected queued requests pushed on shutdown, got %d", len(reqs))
+	}
+}
@@ -2,6 +2,7 @@ package pulse
 
 import (
 	"context"
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -233,16 +234,26 @@ func ResolutionForRange(tr TimeRange) time.Duration {
 }
 
 // TimeSeriesBucket holds raw data for a single time bucket during rollup.
+// Latencies is only populated when the bucket was built from raw requests;
+// TotalLatency is always set.
 type TimeSeriesBucket struct {
-	Timestamp  time.Time
-	Count      int64
-	ErrorCount int64
-	Latencies  []time.Duration
+	Timestamp    time.Time
+	Count        int64 // extrapolated from sampled requests
+	ErrorCount   int64
+	Samples      int64 // recorded requests, which TotalLatency covers
+	TotalLatency time.Duration
+	Latencies    []time.Duration
 }
 
 func (agg *Aggregator) computeTimeSeries(tr TimeRange) (throughput, errors, latency []TimeSeriesPoint) {
+	return requestTimeSeries(agg.pulse.storage, tr)
+}
+
+// requestTimeSeries computes throughput, error, and average latency series
+// for the time range at the resolution ResolutionForRange picks.
+func requestTimeSeries(storage Storage, tr TimeRange) (throughput, errors, latency []TimeSeriesPoint) {
 	resolution := ResolutionForRange(tr)
-	buckets := rollupRequests(agg.pulse.storage, tr, resolution)
+	buckets := rollupRequests(storage, tr, resolution)
 
 	throughput = make([]TimeSeriesPoint, 0, len(buckets))
 	errors = make([]TimeSeriesPoint, 0, len(buckets))
@@ -259,8 +270,8 @@ func (agg *Aggregator) computeTimeSeries(tr TimeRange) (throughput, errors, late
 		})
 
 		var avgLatency float64
-		if len(b.Latencies) > 0 {
-			avgLatency = float64(ComputeAvg(b.Latencies)) / float64(time.Millisecond)
+		if b.Samples > 0 {
+			avgLatency = float64(b.TotalLatency/time.Duration(b.Samples)) / float64(time.Millisecond)
 		}
 		latency = append(latency, TimeSeriesPoint{
 			Timestamp: b.Timestamp,
@@ -271,18 +282,18 @@ func (agg *Aggregator) computeTimeSeries(tr TimeRange) (throughput, errors, late
 	return
 }
 
-// rollupRequests buckets request metrics into time intervals of the given resolution.
+// rollupRequests buckets request metrics into time intervals of the given
+// resolution. Ranges the raw data no longer covers are served from the
+// storage's rollup tiers, at no finer than the tier's resolution.
 func rollupRequests(storage Storage, tr TimeRange, resolution time.Duration) []TimeSeriesBucket {
-	// Calculate bucket boundaries
-	start := tr.Start.Truncate(resolution)
-	end := tr.End
-
-	numBuckets := int(end.Sub(start)/resolution) + 1
-	if numBuckets > 10000 {
-		numBuckets = 10000
+	if rr, ok := storage.(rollupReader); ok {
+		if tier := rr.requestRollupTier(tr); tier != nil {
+			return tier.requestSeries(tr, resolution)
+		}
 	}
 
-	bucketMap := make(map[int64]*TimeSeriesBucket, numBuckets)
+	bucketMap := make(map[int64]*TimeSeriesBucket)
+	counts := make(map[int64]*weightedCount)
 
 	// Iterate over raw requests and assign to buckets
 	requests, _ := storage.GetRequests(RequestFilter{TimeRange: tr, Limit: 0})
@@ -294,15 +305,49 @@ func rollupRequests(storage Storage, tr TimeRange, resolution time.Duration) []T
 		if !ok {
 			b = &TimeSeriesBucket{Timestamp: bucketTS}
 			bucketMap[key] = b
+			counts[key] = &weightedCount{}
 		}
-		b.Count++
+		w := r.weight()
 		if r.StatusCode >= 400 {
-			b.ErrorCount++
+			counts[key].add(w, w)
+		} else {
+			counts[key].add(w, 0)
 		}
+		b.Samples++
+		b.TotalLatency += r.Latency
 		b.Latencies = append(b.Latencies, r.Latency)
 	}
+	setBucketCounts(bucketMap, counts)
+
+	return fillTimeSeriesBuckets(bucketMap, tr.Start.Truncate(resolution), tr.End, resolution)
+}
+
+// weightedCount sums extrapolated request and error counts of a bucket.
+type weightedCount struct {
+	count, errors float64
+}
+
+func (w *weightedCount) add(count, errors float64) {
+	w.count += count
+	w.errors += errors
+}
+
+// setBucketCounts sets the bucket counts to their rounded weighted sums.
+func setBucketCounts(bucketMap map[int64]*TimeSeriesBucket, counts map[int64]*weightedCount) {
+	for key, b := range bucketMap {
+		b.Count = int64(math.Round(counts[key].count))
+		b.ErrorCount = int64(math.Round(counts[key].errors))
+	}
+}
+
+// fillTimeSeriesBuckets converts a bucket map keyed by Unix seconds into a
+// sorted slice from start to end, filling gaps with zero-value buckets.
+func fillTimeSeriesBuckets(bucketMap map[int64]*TimeSeriesBucket, start, end time.Time, resolution time.Duration) []TimeSeriesBucket {
+	numBuckets := int(end.Sub(start)/resolution) + 1
+	if numBuckets > 10000 {
+		numBuckets = 10000
+	}
 
-	// Convert map to sorted slice, filling gaps with zero-value buckets
 	result := make([]TimeSeriesBucket, 0, numBuckets)
 	for ts := start; !ts.After(end); ts = ts.Add(resolution) {
 		key := ts.Unix()
@@ -318,6 +363,12 @@ func rollupRequests(storage Storage, tr TimeRange, resolution time.Duration) []T
 
 // RollupRuntime buckets runtime metrics into time intervals. Exported for API use.
 func RollupRuntime(storage Storage, tr TimeRange, resolution time.Duration) []RuntimeMetric {
+	if rr, ok := storage.(rollupReader); ok {
+		if tier := rr.runtimeRollupTier(tr); tier != nil {
+			return tier.runtimeSeries(tr, resolution)
+		}
+	}
+
 	history, _ := storage.GetRuntimeHistory(tr)
 	if len(history) == 0 {
 		return nil
@@ -350,6 +401,35 @@ func RollupRuntime(storage Storage, tr TimeRange, resolution time.Duration) []Ru
 	return result
 }
 
+// RollupPoolStats buckets connection pool samples into time intervals,
+// keeping the busiest sample (highest InUse) per bucket so saturation peaks
+// survive downsampling. Exported for API use.
+func RollupPoolStats(storage Storage, tr TimeRange, resolution time.Duration) []PoolStats {
+	history, _ := storage.GetPoolStatsHistory(tr)
+	if len(history) == 0 {
+		return nil
+	}
+
+	bucketMap := make(map[int64]PoolStats)
+	for _, s := range history {
+		key := s.Timestamp.Truncate(resolution).Unix()
+		existing, ok := bucketMap[key]
+		if !ok || s.InUse > existing.InUse || (s.InUse == existing.InUse && s.Timestamp.After(existing.Timestamp)) {
+			bucketMap[key] = s
+		}
+	}
+
+	result := make([]PoolStats, 0, len(bucketMap))
+	for _, s := range bucketMap {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+
+	return result
+}
+
 // --- Overview Snapshot ---
 
 func (agg *Aggregator) computeOverview(tr TimeRange, routeStats []RouteStats, throughputTS, errorTS []TimeSeriesPoint) *Overview {
@@ -357,6 +437,7 @@ func (agg *Aggregator) computeOverview(tr TimeRange, routeStats []RouteStats, th
 	if overview == nil {
 		return nil
 	}
+	agg.pulse.setFleetRuntime(overview, tr)
 
 	// Enrich with trend-aware route stats
 	topRoutes := routeStats
@@ -370,16 +451,14 @@ func (agg *Aggregator) computeOverview(tr TimeRange, routeStats []RouteStats, th
 	overview.ErrorSeries = errorTS
 
 	// Compute health status from stored health results
-	if ms, ok := agg.pulse.storage.(*MemoryStorage); ok {
-		overview.HealthStatus = computeCompositeHealth(agg.pulse, ms)
-	}
+	overview.HealthStatus = computeCompositeHealth(agg.pulse, agg.pulse.storage)
 
 	return overview
 }
 
 // computeCompositeHealth determines the overall health status.
-func computeCompositeHealth(p *Pulse, ms *MemoryStorage) string {
-	latestResults := ms.getLatestHealthResults()
+func computeCompositeHealth(p *Pulse, s Storage) string {
+	latestResults, _ := s.GetLatestHealthResults()
 	if len(latestResults) == 0 {
 		return "healthy" // no checks registered → healthy by default
 	}
@@ -359,6 +359,33 @@ func TestRollupRuntime(t *testing.T) {
 	}
 }
 
+func TestRollupPoolStats_KeepsPeak(t *testing.T) {
+	p := setupAggregatorPulse(t)
+
+	base := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+	for i, inUse := range []int{2, 9, 4, 3, 1} {
+		p.storage.StorePoolStats(PoolStats{
+			MaxOpenConnections: 10,
+			InUse:              inUse,
+			Timestamp:          base.Add(time.Duration(i) * 10 * time.Second),
+		})
+	}
+	p.storage.StorePoolStats(PoolStats{MaxOpenConnections: 10, InUse: 6, Timestamp: base.Add(5 * time.Minute)})
+
+	tr := TimeRange{Start: base.Add(-time.Minute), End: base.Add(10 * time.Minute)}
+	result := RollupPoolStats(p.storage, tr, time.Minute)
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(result))
+	}
+	if result[0].InUse != 9 {
+		t.Errorf("expected first bucket to keep peak InUse 9, got %d", result[0].InUse)
+	}
+	if result[1].InUse != 6 {
+		t.Errorf("expected second bucket InUse 6, got %d", result[1].InUse)
+	}
+}
+
 func TestAggregator_Run(t *testing.T) {
 	p := setupAggregatorPulse(t)
 	agg := &Aggregator{pulse: p}
@@ -0,0 +1,140 @@
+package pulse
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+// IPAnonymization selects how client IPs are anonymized before storage.
+type IPAnonymization string
+
+const (
+	// AnonymizeIPTruncate zeroes the last octet of IPv4 addresses and all but
+	// the first 48 bits of IPv6 addresses, e.g. "203.0.113.0".
+	AnonymizeIPTruncate IPAnonymization = "truncate"
+	// AnonymizeIPHash replaces addresses with a keyed hash, so requests from
+	// one client can still be told apart without storing the address.
+	AnonymizeIPHash IPAnonymization = "hash"
+)
+
+// clientIPHeaders are request headers proxies use to pass on the client IP.
+var clientIPHeaders = map[string]bool{
+	"x-forwarded-for":  true,
+	"x-real-ip":        true,
+	"true-client-ip":   true,
+	"cf-connecting-ip": true,
+	"forwarded":        true,
+}
+
+// ipAnonymizer anonymizes the client IPs of metrics. A nil anonymizer
+// leaves them unchanged.
+type ipAnonymizer struct {
+	mode IPAnonymization
+	key  []byte
+}
+
+// newIPAnonymizer returns nil when client IPs are kept.
+func newIPAnonymizer(cfg PrivacyConfig) *ipAnonymizer {
+	if cfg.AnonymizeIP == "" {
+		return nil
+	}
+	return &ipAnonymizer{mode: cfg.AnonymizeIP, key: []byte(cfg.IPHashKey)}
+}
+
+// anonymize returns a copy of a metric with its client IP anonymized.
+// Metrics without a client IP are returned unchanged.
+func (a *ipAnonymizer) anonymize(item interface{}) interface{} {
+	if a == nil {
+		return item
+	}
+	switch m := item.(type) {
+	case RequestMetric:
+		m.ClientIP = a.ip(m.ClientIP)
+		if m.Capture != nil {
+			c := *m.Capture
+			c.RequestHeaders = a.headers(c.RequestHeaders)
+			m.Capture = &c
+		}
+		return m
+	case ConnectionMetric:
+		m.ClientIP = a.ip(m.ClientIP)
+		return m
+	case ErrorRecord:
+		return a.errorRecord(m)
+	}
+	return item
+}
+
+// errorRecord anonymizes the client IP of an error's request context.
+func (a *ipAnonymizer) errorRecord(e ErrorRecord) ErrorRecord {
+	if a == nil || e.RequestContext == nil {
+		return e
+	}
+	rc := *e.RequestContext
+	rc.ClientIP = a.ip(rc.ClientIP)
+	rc.Headers = a.headers(rc.Headers)
+	e.RequestContext = &rc
+	return e
+}
+
+// headers returns a copy of captured request headers with the addresses in
+// forwarding headers anonymized. Values that don't parse as a list of
+// addresses, such as the Forwarded header, are redacted.
+func (a *ipAnonymizer) headers(headers map[string]string) map[string]string {
+	var result map[string]string
+	for key, value := range headers {
+		if !clientIPHeaders[strings.ToLower(key)] || value == redacted {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string, len(headers))
+			for k, v := range headers {
+				result[k] = v
+			}
+		}
+		addrs := strings.Split(value, ",")
+		for i, addr := range addrs {
+			addr = strings.TrimSpace(addr)
+			if net.ParseIP(addr) == nil {
+				addrs = nil
+				break
+			}
+			addrs[i] = a.ip(addr)
+		}
+		if addrs == nil {
+			result[key] = redacted
+		} else {
+			result[key] = strings.Join(addrs, ", ")
+		}
+	}
+	if result == nil {
+		return headers
+	}
+	return result
+}
+
+// ip anonymizes one address. Values that are not IP addresses are returned
+// unchanged, so metrics already anonymized by an agent are not hashed twice.
+func (a *ipAnonymizer) ip(addr string) string {
+	if a == nil {
+		return addr
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return addr
+	}
+	if a.mode == AnonymizeIPTruncate {
+		if v4 := ip.To4(); v4 != nil {
+			return v4.Mask(net.CIDRMask(24, 32)).String()
+		}
+		return ip.Mask(net.CIDRMask(48, 128)).String()
+	}
+
+	// Any other mode hashes, the safer choice for a mistyped mode
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(ip.String()))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
@@ -0,0 +1,105 @@
+package pulse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPAnonymizer(t *testing.T) {
+	truncate := newIPAnonymizer(PrivacyConfig{AnonymizeIP: AnonymizeIPTruncate})
+	for in, want := range map[string]string{
+		"203.0.113.77":        "203.0.113.0",
+		"::ffff:10.1.2.3":     "10.1.2.0",
+		"2001:db8:abcd:12::1": "2001:db8:abcd::",
+		"not-an-ip":           "not-an-ip",
+		"":                    "",
+	} {
+		if got := truncate.ip(in); got != want {
+			t.Errorf("truncate(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	hash := newIPAnonymizer(PrivacyConfig{AnonymizeIP: AnonymizeIPHash, IPHashKey: "k1"})
+	hashed := hash.ip("203.0.113.77")
+	if len(hashed) != 16 || hashed == "203.0.113.77" {
+		t.Fatalf("expected a 16 character hash, got %q", hashed)
+	}
+	if hash.ip("203.0.113.77") != hashed || hash.ip(hashed) != hashed {
+		t.Error("expected hashes to be stable and not hashed twice")
+	}
+	other := newIPAnonymizer(PrivacyConfig{AnonymizeIP: AnonymizeIPHash, IPHashKey: "k2"})
+	if other.ip("203.0.113.77") == hashed {
+		t.Error("expected a different key to give a different hash")
+	}
+
+	var keep *ipAnonymizer = newIPAnonymizer(PrivacyConfig{})
+	if keep.ip("203.0.113.77") != "203.0.113.77" {
+		t.Error("expected IPs kept without a mode")
+	}
+
+	headers := map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1", "Forwarded": "for=203.0.113.7", "Accept": "*/*"}
+	got := truncate.headers(headers)
+	if got["X-Forwarded-For"] != "203.0.113.0, 10.0.0.0" || got["Forwarded"] != redacted || got["Accept"] != "*/*" {
+		t.Errorf("unexpected anonymized headers: %v", got)
+	}
+	if headers["X-Forwarded-For"] != "203.0.113.7, 10.0.0.1" {
+		t.Error("expected the caller's headers to be left alone")
+	}
+}
+
+func TestAPI_DataDelete(t *testing.T) {
+	router, p := setupTestRouter(Config{
+		Dashboard: DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"},
+		Privacy:   PrivacyConfig{AnonymizeIP: AnonymizeIPHash},
+	})
+	defer p.Shutdown()
+	token := loginAndGetToken(t, router)
+
+	router.GET("/me", func(c *gin.Context) {
+		SetTag(c.Request.Context(), "user", c.Query("user"))
+		c.String(http.StatusOK, "ok")
+	})
+	for _, client := range []struct{ addr, user string }{
+		{"198.51.100.7:4000", "ada"},
+		{"198.51.100.8:4000", "grace"},
+		{"198.51.100.9:4000", "grace"},
+	} {
+		req := httptest.NewRequest("GET", "/me?user="+client.user, nil)
+		req.RemoteAddr = client.addr
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	for _, r := range waitForRequests(t, p, 3) {
+		if r.ClientIP == "" || r.ClientIP[:7] == "198.51." {
+			t.Errorf("expected the client IP hashed, got %q", r.ClientIP)
+		}
+	}
+
+	deleteSubject := func(body string) (int, PruneResult) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/pulse/api/data/delete", token, body))
+		var resp struct {
+			Deleted PruneResult `json:"deleted"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Deleted
+	}
+
+	// The raw address matches its stored hash
+	if code, deleted := deleteSubject(`{"client_ip": "198.51.100.7"}`); code != http.StatusOK || deleted.Requests != 1 || deleted.Spans != 1 {
+		t.Fatalf("expected the request and its span deleted, got %d: %+v", code, deleted)
+	}
+	if code, deleted := deleteSubject(`{"tags": {"user": "grace"}}`); code != http.StatusOK || deleted.Requests != 2 {
+		t.Fatalf("expected 2 requests deleted, got %d: %+v", code, deleted)
+	}
+	if reqs, _ := p.storage.GetRequests(RequestFilter{Path: "/me"}); len(reqs) != 0 {
+		t.Errorf("expected every request deleted, got %+v", reqs)
+	}
+
+	if code, _ := deleteSubject(`{}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an empty filter, got %d", code)
+	}
+}
@@ -111,23 +111,35 @@ func registerAPIRoutes(router *gin.Engine, p *Pulse) {
 	api.POST("/auth/login", loginHandler(p))
 	api.GET("/auth/verify", authMiddleware(p), verifyHandler())
 
+	// Agent pushes (authenticated with the collector token)
+	if p.collector != nil {
+		api.POST("/ingest", collectorIngestHandler(p))
+	}
+
 	// Protected: all other endpoints
 	protected := api.Group("")
 	protected.Use(authMiddleware(p))
 
 	// Overview
 	protected.GET("/overview", overviewHandler(p))
+	protected.GET("/instances", instancesHandler(p))
 
 	// Routes
 	protected.GET("/routes", routesListHandler(p))
 	protected.GET("/routes/:method/*path", routeDetailHandler(p))
 
+	// Requests, in-flight requests and long-lived connections
+	protected.GET("/requests/inflight", inflightRequestsHandler(p))
+	protected.GET("/requests/:traceID", requestDetailHandler(p))
+	protected.GET("/connections", connectionsHandler(p))
+
 	// Database
 	protected.GET("/database/overview", dbOverviewHandler(p))
 	protected.GET("/database/slow-queries", dbSlowQueriesHandler(p))
 	protected.GET("/database/patterns", dbPatternsHandler(p))
 	protected.GET("/database/n1", dbN1Handler(p))
 	protected.GET("/database/pool", dbPoolHandler(p))
+	protected.GET("/database/pool/history", dbPoolHistoryHandler(p))
 
 	// Errors
 	protected.GET("/errors", errorsListHandler(p))
@@ -136,6 +148,10 @@ func registerAPIRoutes(router *gin.Engine, p *Pulse) {
 	protected.POST("/errors/:id/resolve", errorResolveHandler(p))
 	protected.DELETE("/errors/:id", errorDeleteHandler(p))
 
+	// Traces
+	protected.GET("/traces/:traceID", traceHandler(p))
+	protected.GET("/spans", spanStatsHandler(p))
+
 	// Runtime
 	protected.GET("/runtime/current", runtimeCurrentHandler(p))
 	protected.GET("/runtime/history", runtimeHistoryHandler(p))
@@ -151,7 +167,9 @@ func registerAPIRoutes(router *gin.Engine, p *Pulse) {
 
 	// Settings & data
 	protected.GET("/settings", settingsHandler(p))
+	protected.GET("/settings/retention", retentionStatusHandler(p))
 	protected.POST("/data/reset", dataResetHandler(p))
+	protected.POST("/data/delete", dataDeleteHandler(p))
 
 	// Data export
 	registerExportRoute(protected, p)
@@ -198,19 +216,46 @@ func verifyHandler() gin.HandlerFunc {
 func overviewHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tr := parseTimeRangeParam(c)
+		tags, err := parseTagParams(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		instance := c.Query("instance")
+		view := newFilteredView(p, instance, tags)
 
-		if p.aggregator != nil {
-			overview := p.aggregator.GetCachedOverview()
-			if overview != nil {
-				c.JSON(http.StatusOK, overview)
+		var overview *Overview
+		if instance != "" || tags != nil {
+			overview, err = view.GetOverview(tr)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
+			overview.ThroughputSeries, overview.ErrorSeries, _ = requestTimeSeries(view, tr)
+			overview.HealthStatus = computeCompositeHealth(p, p.storage)
+		} else {
+			if p.aggregator != nil {
+				overview = p.aggregator.GetCachedOverview()
+			}
+			if overview == nil {
+				overview, err = p.storage.GetOverview(tr)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				p.setFleetRuntime(overview, tr)
+			}
 		}
 
-		overview, err := p.storage.GetOverview(tr)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		if groupBy := c.Query("group_by"); groupBy != "" {
+			reqs, err := view.GetRequests(RequestFilter{TimeRange: tr})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			grouped := *overview // the cached overview is shared
+			grouped.TagGroups = buildTagStats(reqs, groupBy, tr.End.Sub(tr.Start), false)
+			overview = &grouped
 		}
 		c.JSON(http.StatusOK, overview)
 	}
@@ -221,31 +266,61 @@ func overviewHandler(p *Pulse) gin.HandlerFunc {
 func routesListHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tr := parseTimeRangeParam(c)
-
-		var stats []RouteStats
-		if p.aggregator != nil {
-			stats = p.aggregator.GetCachedRouteStats()
+		tags, err := parseTagParams(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
-		if len(stats) == 0 {
-			stats, _ = p.storage.GetRouteStats(tr)
+		instance := c.Query("instance")
+		view := newFilteredView(p, instance, tags)
+		search := strings.ToLower(c.Query("search"))
+
+		// Group by a tag, with each group's routes
+		if groupBy := c.Query("group_by"); groupBy != "" {
+			reqs, err := view.GetRequests(RequestFilter{TimeRange: tr})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			groups := buildTagStats(reqs, groupBy, tr.End.Sub(tr.Start), true)
+			for i := range groups {
+				groups[i].Routes = searchRoutes(groups[i].Routes, search)
+			}
+			c.JSON(http.StatusOK, groups)
+			return
 		}
 
-		// Filter by search term
-		if search := c.Query("search"); search != "" {
-			search = strings.ToLower(search)
-			var filtered []RouteStats
-			for _, s := range stats {
-				if strings.Contains(strings.ToLower(s.Path), search) {
-					filtered = append(filtered, s)
-				}
+		var stats []RouteStats
+		if instance != "" || tags != nil {
+			stats, _ = view.GetRouteStats(tr)
+		} else {
+			if p.aggregator != nil {
+				stats = p.aggregator.GetCachedRouteStats()
+			}
+			if len(stats) == 0 {
+				stats, _ = p.storage.GetRouteStats(tr)
 			}
-			stats = filtered
 		}
 
-		c.JSON(http.StatusOK, stats)
+		c.JSON(http.StatusOK, searchRoutes(stats, search))
 	}
 }
 
+// searchRoutes returns the routes whose path contains the lower-case search
+// term, or all routes for an empty term.
+func searchRoutes(stats []RouteStats, search string) []RouteStats {
+	if search == "" {
+		return stats
+	}
+	var filtered []RouteStats
+	for _, s := range stats {
+		if strings.Contains(strings.ToLower(s.Path), search) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
 func routeDetailHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := strings.ToUpper(c.Param("method"))
@@ -265,6 +340,75 @@ func routeDetailHandler(p *Pulse) gin.HandlerFunc {
 	}
 }
 
+// --- Requests ---
+
+// requestDetailHandler returns the recorded requests of a trace, oldest
+// first, with the payloads capture rules kept. A trace spanning services
+// that report to one collector has a request per service.
+func requestDetailHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqs, err := p.storage.GetRequests(RequestFilter{TraceID: c.Param("traceID")})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(reqs) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "request not found"})
+			return
+		}
+		c.JSON(http.StatusOK, reqs)
+	}
+}
+
+// --- In-flight requests ---
+
+func inflightRequestsHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := p.inflight.status()
+		if c.Query("stuck") == "true" {
+			stuck := make([]InFlightRequest, 0, status.StuckCount)
+			for _, r := range status.Requests {
+				if r.Stuck {
+					stuck = append(stuck, r)
+				}
+			}
+			status.Requests = stuck
+		}
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// --- Connections ---
+
+func connectionsHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store, ok := p.storage.(connectionStore)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
+			return
+		}
+		conns, err := store.GetConnections(parseTimeRangeParam(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if kind := ConnectionKind(c.Query("kind")); kind != "" {
+			filtered := make([]ConnectionMetric, 0, len(conns))
+			for _, conn := range conns {
+				if conn.Kind == kind {
+					filtered = append(filtered, conn)
+				}
+			}
+			conns = filtered
+		}
+		c.JSON(http.StatusOK, ConnectionsOverview{
+			Active: p.connections.snapshot(),
+			Routes: buildConnectionStats(conns),
+		})
+	}
+}
+
 // --- Database ---
 
 func dbOverviewHandler(p *Pulse) gin.HandlerFunc {
@@ -331,15 +475,31 @@ func dbPoolHandler(p *Pulse) gin.HandlerFunc {
 	}
 }
 
+func dbPoolHistoryHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tr := parseTimeRangeParam(c)
+		resolution := ResolutionForRange(tr)
+		history := RollupPoolStats(p.storage, tr, resolution)
+		c.JSON(http.StatusOK, history)
+	}
+}
+
 // --- Errors ---
 
 func errorsListHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tr := parseTimeRangeParam(c)
+		tags, err := parseTagParams(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		filter := ErrorFilter{
 			TimeRange: tr,
 			ErrorType: c.Query("type"),
 			Route:     c.Query("route"),
+			Instance:  c.Query("instance"),
+			Tags:      tags,
 			Limit:     queryInt(c, "limit", 50),
 			Offset:    queryInt(c, "offset", 0),
 		}
@@ -360,12 +520,12 @@ func errorsListHandler(p *Pulse) gin.HandlerFunc {
 func errorDetailHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
-		ms, ok := p.storage.(*MemoryStorage)
+		store, ok := p.storage.(errorRecordStore)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
 			return
 		}
-		record, err := ms.getErrorByID(id)
+		record, err := store.getErrorByID(id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "error not found"})
 			return
@@ -399,12 +559,12 @@ func errorResolveHandler(p *Pulse) gin.HandlerFunc {
 func errorDeleteHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
-		ms, ok := p.storage.(*MemoryStorage)
+		store, ok := p.storage.(errorRecordStore)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
 			return
 		}
-		if err := ms.deleteError(id); err != nil {
+		if err := store.deleteError(id); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
@@ -412,6 +572,56 @@ func errorDeleteHandler(p *Pulse) gin.HandlerFunc {
 	}
 }
 
+// --- Traces ---
+
+func traceHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store, ok := p.storage.(traceStore)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
+			return
+		}
+		traceID := c.Param("traceID")
+		spans, err := store.GetTrace(traceID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		trace := buildTrace(traceID, spans)
+		if trace == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "trace not found"})
+			return
+		}
+		c.JSON(http.StatusOK, trace)
+	}
+}
+
+func spanStatsHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reader, ok := p.storage.(spanStatsReader)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
+			return
+		}
+		stats, err := reader.GetSpanStats(parseTimeRangeParam(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if kind := SpanKind(c.Query("kind")); kind != "" {
+			filtered := make([]SpanStats, 0, len(stats))
+			for _, s := range stats {
+				if s.Kind == kind {
+					filtered = append(filtered, s)
+				}
+			}
+			stats = filtered
+		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
 // --- Runtime ---
 
 func runtimeCurrentHandler(p *Pulse) gin.HandlerFunc {
@@ -432,7 +642,12 @@ func runtimeHistoryHandler(p *Pulse) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tr := parseTimeRangeParam(c)
 		resolution := ResolutionForRange(tr)
-		history := RollupRuntime(p.storage, tr, resolution)
+
+		var storage Storage = p.storage
+		if instance := c.Query("instance"); instance != "" {
+			storage = newFilteredView(p, instance, nil)
+		}
+		history := RollupRuntime(storage, tr, resolution)
 		c.JSON(http.StatusOK, history)
 	}
 }
@@ -507,7 +722,68 @@ func settingsHandler(p *Pulse) gin.HandlerFunc {
 		// Sanitize secrets
 		cfg.Dashboard.SecretKey = "[REDACTED]"
 		cfg.Dashboard.Password = "[REDACTED]"
-		c.JSON(http.StatusOK, cfg)
+		if cfg.Agent.Token != "" {
+			cfg.Agent.Token = "[REDACTED]"
+		}
+		if cfg.Collector.Token != "" {
+			cfg.Collector.Token = "[REDACTED]"
+		}
+		if cfg.Privacy.IPHashKey != "" {
+			cfg.Privacy.IPHashKey = "[REDACTED]"
+		}
+		if len(cfg.OTLP.Headers) > 0 {
+			// Headers usually carry API keys
+			headers := make(map[string]string, len(cfg.OTLP.Headers))
+			for k := range cfg.OTLP.Headers {
+				headers[k] = "[REDACTED]"
+			}
+			cfg.OTLP.Headers = headers
+		}
+
+		var ingest *IngestStats
+		if p.ingester != nil {
+			stats := p.ingester.Stats()
+			ingest = &stats
+		}
+		var agent *AgentStats
+		if p.agent != nil {
+			stats := p.agent.Stats()
+			agent = &stats
+		}
+		var otlp *OTLPStats
+		if p.otlp != nil {
+			stats := p.otlp.Stats()
+			otlp = &stats
+		}
+		var storage []TeeBackendStats
+		if tee, ok := p.storage.(*TeeStorage); ok {
+			storage = tee.Stats()
+		}
+		c.JSON(http.StatusOK, struct {
+			Config
+			IngestionStats *IngestStats      `json:"ingestion_stats,omitempty"`
+			AgentStats     *AgentStats       `json:"agent_stats,omitempty"`
+			OTLPStats      *OTLPStats        `json:"otlp_stats,omitempty"`
+			StorageStats   []TeeBackendStats `json:"storage_stats,omitempty"`
+		}{cfg, ingest, agent, otlp, storage})
+	}
+}
+
+func retentionStatusHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var status RetentionStatus
+		if p.retention != nil {
+			status = p.retention.Status()
+		}
+		resp := gin.H{
+			"policy":           p.config.Storage.Retention,
+			"cleanup_interval": p.config.Storage.CleanupInterval,
+			"last_cleanup":     status,
+		}
+		if p.snapshotter != nil {
+			resp["snapshot"] = p.snapshotter.Status()
+		}
+		c.JSON(http.StatusOK, resp)
 	}
 }
 
@@ -528,6 +804,39 @@ func dataResetHandler(p *Pulse) gin.HandlerFunc {
 	}
 }
 
+// dataDeleteHandler deletes the stored records of one data subject, e.g.
+// for a GDPR erasure request.
+func dataDeleteHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter SubjectFilter
+		if err := c.ShouldBindJSON(&filter); err != nil || filter.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "send a client_ip, tags or trace_id to delete"})
+			return
+		}
+		deleter, ok := p.storage.(subjectDeleter)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unsupported storage backend"})
+			return
+		}
+
+		// Stored IPs are anonymized, so match the address the same way
+		filter.ClientIP = p.anonymizer.ip(filter.ClientIP)
+		result, err := deleter.DeleteSubject(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// Rewrite the snapshot so the records don't come back on restart
+		if p.snapshotter != nil {
+			if err := p.snapshotter.save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "deleted, but the snapshot was not updated: " + err.Error()})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"deleted": result, "total": result.Total()})
+	}
+}
+
 // --- Query helpers ---
 
 func parseTimeRangeParam(c *gin.Context) TimeRange {
@@ -537,6 +846,23 @@ func parseTimeRangeParam(c *gin.Context) TimeRange {
 	return Last1h()
 }
 
+// parseTagParams parses the repeatable tag=key:value query parameter into a
+// tag filter, nil when there is none.
+func parseTagParams(c *gin.Context) (map[string]string, error) {
+	var tags map[string]string
+	for _, param := range c.QueryArray("tag") {
+		key, value, ok := strings.Cut(param, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag filter %q, expected key:value", param)
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
 func queryInt(c *gin.Context, key string, defaultVal int) int {
 	if v := c.Query(key); v != "" {
 		var n int
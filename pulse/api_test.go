@@ -352,6 +352,36 @@ func TestAPI_DatabasePool(t *testing.T) {
 	}
 }
 
+func TestAPI_DatabasePoolHistory(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	token := loginAndGetToken(t, router)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		p.storage.StorePoolStats(PoolStats{
+			MaxOpenConnections: 10,
+			InUse:              i,
+			Timestamp:          now.Add(-time.Duration(i) * 10 * time.Minute),
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req := authedRequest("GET", "/pulse/api/database/pool/history?range=1h", token, "")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var history []PoolStats
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(history) == 0 {
+		t.Error("expected pool history samples")
+	}
+}
+
 // --- Errors ---
 
 func TestAPI_ErrorsList(t *testing.T) {
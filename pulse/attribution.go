@@ -0,0 +1,104 @@
+package pulse
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type requestTimeKey struct{}
+
+// requestTime adds up the database queries and dependency calls a request
+// makes while its handler runs.
+type requestTime struct {
+	mu             sync.Mutex
+	queries        int
+	dbTime         time.Duration
+	dependencies   int
+	dependencyTime time.Duration
+}
+
+// contextWithRequestTime attaches an empty tally for the request's queries
+// and dependency calls.
+func contextWithRequestTime(ctx context.Context) (context.Context, *requestTime) {
+	rt := &requestTime{}
+	return context.WithValue(ctx, requestTimeKey{}, rt), rt
+}
+
+// addQueryTime charges a query to the request running in ctx, if any.
+func addQueryTime(ctx context.Context, d time.Duration) {
+	if rt, ok := ctx.Value(requestTimeKey{}).(*requestTime); ok {
+		rt.mu.Lock()
+		rt.queries++
+		rt.dbTime += d
+		rt.mu.Unlock()
+	}
+}
+
+// addDependencyTime charges an outbound call to the request running in ctx,
+// if any.
+func addDependencyTime(ctx context.Context, d time.Duration) {
+	if rt, ok := ctx.Value(requestTimeKey{}).(*requestTime); ok {
+		rt.mu.Lock()
+		rt.dependencies++
+		rt.dependencyTime += d
+		rt.mu.Unlock()
+	}
+}
+
+// apply copies the tally onto the request's metric.
+func (rt *requestTime) apply(m *RequestMetric) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	m.QueryCount = rt.queries
+	m.DBTime = rt.dbTime
+	m.DependencyCount = rt.dependencies
+	m.DependencyTime = rt.dependencyTime
+}
+
+// appTime returns the latency not spent in queries or dependency calls.
+// Calls made in parallel can add up to more than the latency, leaving none.
+func (m RequestMetric) appTime() time.Duration {
+	return max(m.Latency-m.DBTime-m.DependencyTime, 0)
+}
+
+// timeShares totals where the latency of a route's recorded requests went.
+type timeShares struct {
+	latency, db, dependencies, app time.Duration
+}
+
+func (ts *timeShares) add(m RequestMetric) {
+	ts.latency += m.Latency
+	ts.db += m.DBTime
+	ts.dependencies += m.DependencyTime
+	ts.app += m.appTime()
+}
+
+// apply sets the route's shares of latency, in percent.
+func (ts timeShares) apply(rs *RouteStats) {
+	if ts.latency <= 0 {
+		return
+	}
+	total := float64(ts.latency)
+	rs.DBShare = float64(ts.db) / total * 100
+	rs.DependencyShare = float64(ts.dependencies) / total * 100
+	rs.AppShare = float64(ts.app) / total * 100
+}
+
+// addTimeStats fills in a route detail's database, dependency and
+// application time percentiles from its requests.
+func addTimeStats(detail *RouteDetail, reqs []RequestMetric) {
+	if len(reqs) == 0 {
+		return
+	}
+	db := make([]time.Duration, len(reqs))
+	deps := make([]time.Duration, len(reqs))
+	app := make([]time.Duration, len(reqs))
+	for i, r := range reqs {
+		db[i], deps[i], app[i] = r.DBTime, r.DependencyTime, r.appTime()
+	}
+	dbStats := computePhaseStats("db", db)
+	depStats := computePhaseStats("dependencies", deps)
+	appStats := computePhaseStats("app", app)
+	detail.DBTime, detail.DependencyTime, detail.AppTime = &dbStats, &depStats, &appStats
+}
@@ -0,0 +1,94 @@
+package pulse
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMiddleware_AttributesQueryAndDependencyTime(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "app.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&TestUser{})
+
+	payments := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer payments.Close()
+
+	router := gin.New()
+	p := Mount(router, db, Config{})
+	defer p.Shutdown()
+	client := WrapHTTPClient(p, nil, "payments")
+
+	router.GET("/checkout", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var users []TestUser
+		db.WithContext(ctx).Find(&users)
+		db.WithContext(ctx).Where("age > ?", 30).Find(&users)
+		req, _ := http.NewRequestWithContext(ctx, "POST", payments.URL, nil)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+		time.Sleep(10 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/checkout", nil))
+
+	reqs := waitForRequests(t, p, 1)
+	r := reqs[0]
+	if r.QueryCount != 2 || r.DBTime <= 0 {
+		t.Errorf("expected 2 queries with their time, got %d in %v", r.QueryCount, r.DBTime)
+	}
+	if r.DependencyCount != 1 || r.DependencyTime < 20*time.Millisecond {
+		t.Errorf("expected 1 call of at least 20ms, got %d in %v", r.DependencyCount, r.DependencyTime)
+	}
+	if app := r.appTime(); app < 10*time.Millisecond || app != r.Latency-r.DBTime-r.DependencyTime {
+		t.Errorf("expected the remaining %v as app time, got %v", r.Latency-r.DBTime-r.DependencyTime, app)
+	}
+
+	// Queries outside a request are not charged to one
+	var users []TestUser
+	db.Find(&users)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+	for _, r := range waitForRequests(t, p, 2) {
+		if r.Path != "/checkout" && (r.QueryCount != 0 || r.DependencyCount != 0) {
+			t.Errorf("expected nothing charged to %s, got %+v", r.Path, r)
+		}
+	}
+}
+
+func TestRouteStats_TimeShares(t *testing.T) {
+	reqs := []RequestMetric{
+		{Method: "GET", Path: "/a", Latency: 100 * time.Millisecond, DBTime: 30 * time.Millisecond, DependencyTime: 50 * time.Millisecond},
+		{Method: "GET", Path: "/a", Latency: 100 * time.Millisecond},
+		// Parallel calls can outlast the request
+		{Method: "GET", Path: "/a", Latency: 200 * time.Millisecond, DBTime: 150 * time.Millisecond, DependencyTime: 150 * time.Millisecond},
+	}
+	rs := computeRouteStats("GET", "/a", reqs, time.Minute)
+	if math.Abs(rs.DBShare-45) > 1e-9 || math.Abs(rs.DependencyShare-50) > 1e-9 || math.Abs(rs.AppShare-30) > 1e-9 {
+		t.Errorf("expected 45%% / 50%% / 30%%, got %.1f / %.1f / %.1f", rs.DBShare, rs.DependencyShare, rs.AppShare)
+	}
+
+	var detail RouteDetail
+	addTimeStats(&detail, reqs)
+	if detail.AppTime == nil || detail.AppTime.Count != 3 || detail.AppTime.Max != 100*time.Millisecond || detail.AppTime.P50 != 20*time.Millisecond {
+		t.Errorf("unexpected app time stats: %+v", detail.AppTime)
+	}
+	if detail.DBTime == nil || detail.DBTime.Max != 150*time.Millisecond {
+		t.Errorf("unexpected db time stats: %+v", detail.DBTime)
+	}
+}
@@ -0,0 +1,203 @@
+package pulse
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CaptureRule decides which payloads are kept for the finished requests it
+// matches. Empty match fields match anything; a rule matches when every set
+// field does. Every matching rule adds what it captures.
+type CaptureRule struct {
+	// Method matches the request method, e.g. "POST".
+	Method string
+	// Route matches the route pattern (e.g. "/users/:id") with a glob, or a
+	// prefix when it ends in "/*". Unrouted requests match by path.
+	Route string
+	// StatusClass matches the response status class: 2 for 2xx up to 5 for 5xx.
+	StatusClass int
+	// MinLatency matches requests that took at least this long.
+	MinLatency time.Duration
+
+	// RequestHeaders keeps the request headers, with credentials redacted.
+	RequestHeaders bool
+	// RequestBody keeps the request body as read by the handler.
+	RequestBody bool
+	// ResponseHeaders keeps the response headers, with cookies redacted.
+	ResponseHeaders bool
+	// ResponseBody keeps the response body.
+	ResponseBody bool
+}
+
+// RequestCapture holds the payloads kept for a request by CaptureRules.
+// Bodies are truncated to ErrorConfig.MaxBodySize.
+type RequestCapture struct {
+	RequestHeaders        map[string]string `json:"request_headers,omitempty"`
+	RequestBody           string            `json:"request_body,omitempty"`
+	RequestBodyTruncated  bool              `json:"request_body_truncated,omitempty"`
+	ResponseHeaders       map[string]string `json:"response_headers,omitempty"`
+	ResponseBody          string            `json:"response_body,omitempty"`
+	ResponseBodyTruncated bool              `json:"response_body_truncated,omitempty"`
+}
+
+// matches reports whether the finished request satisfies every set field.
+func (r CaptureRule) matches(c sampleCandidate) bool {
+	return SamplingRule{
+		Method:      r.Method,
+		Route:       r.Route,
+		StatusClass: r.StatusClass,
+		MinLatency:  r.MinLatency,
+	}.matches(c)
+}
+
+// capturePolicy applies the capture rules. Status and latency are only
+// known once the request finishes, so bodies are buffered for every
+// request a body rule may match and dropped when none does.
+type capturePolicy struct {
+	rules   []CaptureRule
+	maxBody int
+}
+
+// newCapturePolicy returns nil when no capture rules are configured.
+func newCapturePolicy(cfg Config) *capturePolicy {
+	if len(cfg.Tracing.CaptureRules) == 0 {
+		return nil
+	}
+	return &capturePolicy{rules: cfg.Tracing.CaptureRules, maxBody: cfg.Errors.MaxBodySize}
+}
+
+// bodies reports whether a rule may keep the request or response body of a
+// request, before its status and latency are known. An empty route is not
+// known yet and matches any rule.
+func (c *capturePolicy) bodies(method, route string) (request, response bool) {
+	for _, rule := range c.rules {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+			continue
+		}
+		if route != "" && rule.Route != "" && !matchPathPattern(route, rule.Route) {
+			continue
+		}
+		request = request || rule.RequestBody
+		response = response || rule.ResponseBody
+	}
+	return request, response
+}
+
+// capture returns the payloads the matching rules keep of a finished
+// request, or nil when no rule matches.
+func (c *capturePolicy) capture(cand sampleCandidate, respHeader http.Header, reqBody, respBody *cappedBuffer) *RequestCapture {
+	var keep CaptureRule
+	matched := false
+	for _, rule := range c.rules {
+		if rule.matches(cand) {
+			matched = true
+			keep.RequestHeaders = keep.RequestHeaders || rule.RequestHeaders
+			keep.RequestBody = keep.RequestBody || rule.RequestBody
+			keep.ResponseHeaders = keep.ResponseHeaders || rule.ResponseHeaders
+			keep.ResponseBody = keep.ResponseBody || rule.ResponseBody
+		}
+	}
+	if !matched {
+		return nil
+	}
+
+	capture := &RequestCapture{}
+	if keep.RequestHeaders {
+		capture.RequestHeaders = redactHeaders(cand.header)
+	}
+	if keep.RequestBody && reqBody != nil {
+		capture.RequestBody = string(reqBody.bytes())
+		capture.RequestBodyTruncated = reqBody.truncated
+	}
+	if keep.ResponseHeaders {
+		capture.ResponseHeaders = redactHeaders(respHeader)
+	}
+	if keep.ResponseBody && respBody != nil {
+		capture.ResponseBody = string(respBody.bytes())
+		capture.ResponseBodyTruncated = respBody.truncated
+	}
+	return capture
+}
+
+// captureResponse keeps written response bytes for the capture rules.
+func (tr *tracedRequest) captureResponse(p []byte) {
+	if tr != nil && tr.respBody != nil {
+		tr.respBody.Write(p)
+	}
+}
+
+func (tr *tracedRequest) captureResponseString(s string) {
+	if tr != nil && tr.respBody != nil {
+		tr.respBody.WriteString(s)
+	}
+}
+
+// cappedBuffer keeps the first max bytes written to it. Writes never fail,
+// so it can sit behind an io.TeeReader.
+type cappedBuffer struct {
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - len(b.buf); len(p) > room {
+		b.truncated = true
+		b.buf = append(b.buf, p[:max(room, 0)]...)
+	} else {
+		b.buf = append(b.buf, p...)
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) WriteString(s string) (int, error) {
+	if room := b.max - len(b.buf); len(s) > room {
+		b.truncated = true
+		b.buf = append(b.buf, s[:max(room, 0)]...)
+	} else {
+		b.buf = append(b.buf, s...)
+	}
+	return len(s), nil
+}
+
+// bytes returns the captured bytes, or nil for a nil buffer.
+func (b *cappedBuffer) bytes() []byte {
+	if b == nil {
+		return nil
+	}
+	return b.buf
+}
+
+// teeRequestBody replaces the request body with one that copies the first
+// maxSize bytes into the returned buffer as the handler reads it. Unlike
+// reading the body up front, this works for chunked and streamed bodies of
+// unknown length without blocking or holding them in memory.
+func teeRequestBody(r *http.Request, maxSize int) *cappedBuffer {
+	if r.Body == nil || r.Body == http.NoBody || maxSize <= 0 {
+		return nil
+	}
+	buf := &cappedBuffer{max: maxSize}
+	r.Body = teeReadCloser{Reader: io.TeeReader(r.Body, buf), Closer: r.Body}
+	return buf
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactHeaders flattens headers to their first value, redacting
+// credentials and cookies.
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if sensitiveHeaders[strings.ToLower(key)] {
+			headers[key] = redacted
+		} else if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+	return headers
+}
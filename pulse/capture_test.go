@@ -0,0 +1,147 @@
+package pulse
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCapturePolicy(t *testing.T) {
+	c := newCapturePolicy(Config{
+		Tracing: TracingConfig{CaptureRules: []CaptureRule{
+			{StatusClass: 5, RequestHeaders: true, ResponseBody: true},
+			{Method: "POST", Route: "/orders/*", MinLatency: time.Second, RequestBody: true},
+		}},
+		Errors: ErrorConfig{MaxBodySize: 8},
+	})
+
+	if req, resp := c.bodies("GET", "/users"); req || !resp {
+		t.Errorf("expected only the response body kept for GET /users, got %v/%v", req, resp)
+	}
+	if req, _ := c.bodies("POST", "/orders/:id"); !req {
+		t.Error("expected the request body kept for POST /orders/:id")
+	}
+	if req, _ := c.bodies("POST", ""); !req {
+		t.Error("expected an unknown route to keep the request body")
+	}
+
+	header := http.Header{"Authorization": {"Bearer secret"}, "Accept": {"application/json"}}
+	reqBody := &cappedBuffer{max: 8}
+	reqBody.Write([]byte(`{"item":42}`))
+	respBody := &cappedBuffer{max: 8}
+	respBody.WriteString("boom")
+
+	if got := c.capture(sampleCandidate{method: "GET", route: "/users", status: 200, header: header}, nil, reqBody, respBody); got != nil {
+		t.Errorf("expected nothing captured for a fast 200, got %+v", got)
+	}
+
+	got := c.capture(sampleCandidate{method: "POST", route: "/orders/:id", status: 502, latency: 2 * time.Second, header: header}, http.Header{}, reqBody, respBody)
+	if got == nil {
+		t.Fatal("expected a capture for a slow failing POST")
+	}
+	if got.RequestHeaders["Authorization"] != "[REDACTED]" || got.RequestHeaders["Accept"] != "application/json" {
+		t.Errorf("expected redacted request headers, got %v", got.RequestHeaders)
+	}
+	if got.RequestBody != `{"item":` || !got.RequestBodyTruncated {
+		t.Errorf("expected the request body truncated to 8 bytes, got %q", got.RequestBody)
+	}
+	if got.ResponseBody != "boom" || got.ResponseBodyTruncated || got.ResponseHeaders != nil {
+		t.Errorf("expected the response body only, got %+v", got)
+	}
+
+	if newCapturePolicy(Config{}) != nil {
+		t.Error("expected no policy without rules")
+	}
+}
+
+func TestMiddleware_CapturesFailingRequests(t *testing.T) {
+	router, p := setupTestRouter(Config{
+		Dashboard: DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"},
+		Tracing: TracingConfig{CaptureRules: []CaptureRule{
+			{StatusClass: 5, RequestHeaders: true, RequestBody: true, ResponseHeaders: true, ResponseBody: true},
+		}},
+	})
+	defer p.Shutdown()
+	token := loginAndGetToken(t, router)
+
+	router.POST("/charges", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Header("Set-Cookie", "session=abc")
+		if strings.Contains(string(body), "decline") {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "card declined"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	// Chunked bodies have no ContentLength
+	send := func(body string) string {
+		req := httptest.NewRequest("POST", "/charges", io.NopCloser(strings.NewReader(body)))
+		req.ContentLength = -1
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header().Get(TraceIDHeader)
+	}
+	okTrace := send(`{"card":"4242"}`)
+	failTrace := send(`{"card":"decline"}`)
+
+	reqs := waitForRequests(t, p, 2)
+	for _, r := range reqs {
+		if r.TraceID == okTrace && r.Capture != nil {
+			t.Errorf("expected nothing captured for the 200, got %+v", r.Capture)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/requests/"+failTrace, token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var detail []RequestMetric
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if len(detail) != 1 || detail[0].Capture == nil {
+		t.Fatalf("expected the failing request with its capture, got %+v", detail)
+	}
+	c := detail[0].Capture
+	if c.RequestBody != `{"card":"decline"}` || c.ResponseBody != `{"error":"card declined"}` {
+		t.Errorf("unexpected captured bodies: %q / %q", c.RequestBody, c.ResponseBody)
+	}
+	if c.RequestHeaders["Authorization"] != "[REDACTED]" || c.ResponseHeaders["Set-Cookie"] != "[REDACTED]" {
+		t.Errorf("expected credentials redacted, got %v / %v", c.RequestHeaders, c.ResponseHeaders)
+	}
+	if c.ResponseHeaders["Content-Type"] == "" {
+		t.Errorf("expected response headers, got %v", c.ResponseHeaders)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/requests/unknown", token, ""))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown trace, got %d", w.Code)
+	}
+}
+
+func TestHandler_CapturesChunkedErrorBody(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{Tracing: TracingConfig{Enabled: boolPtr(false)}})
+	handler := Handler(p, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	req := httptest.NewRequest("POST", "/import", io.NopCloser(strings.NewReader("id,name\n1,ada\n")))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	errs := waitForErrors(t, p, 1)
+	if ctx := errs[0].RequestContext; ctx == nil || ctx.Body != "id,name\n1,ada\n" {
+		t.Errorf("expected the chunked body on the error, got %+v", ctx)
+	}
+}
@@ -0,0 +1,319 @@
+package pulse
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAgentBatchBytes limits the decompressed size of a pushed batch.
+const maxAgentBatchBytes = 32 << 20
+
+// InstanceInfo describes an instance whose metrics this Pulse holds.
+type InstanceInfo struct {
+	Name     string    `json:"name"`
+	AppName  string    `json:"app_name,omitempty"`
+	Local    bool      `json:"local,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+	Batches  uint64    `json:"batches"`
+	Metrics  uint64    `json:"metrics"`
+}
+
+// Collector ingests metric batches pushed by agents into this Pulse's
+// storage. Metrics keep their instance label, so the dashboard can aggregate
+// across instances or filter by one.
+type Collector struct {
+	pulse *Pulse
+
+	mu        sync.RWMutex
+	instances map[string]*InstanceInfo
+}
+
+// newCollector creates the collector. It returns nil when collector mode is
+// disabled.
+func newCollector(p *Pulse) *Collector {
+	if !p.config.Collector.Enabled {
+		return nil
+	}
+	if p.config.Collector.Token == "" {
+		p.logger.Printf("[pulse] warning: collector token is empty — any client can push metrics to %s/api/ingest", p.config.Prefix)
+	}
+	return &Collector{
+		pulse:     p,
+		instances: make(map[string]*InstanceInfo),
+	}
+}
+
+// Ingest queues a pushed batch for storage and returns the number of
+// metrics accepted.
+func (c *Collector) Ingest(b AgentBatch) (int, error) {
+	if b.Instance == "" {
+		return 0, errors.New("batch has no instance")
+	}
+
+	p := c.pulse
+	for _, m := range b.Requests {
+		p.ingest(labelInstance(m, b.Instance))
+	}
+	for _, m := range b.Queries {
+		p.ingest(labelInstance(m, b.Instance))
+	}
+	for _, m := range b.Dependencies {
+		p.ingest(labelInstance(m, b.Instance))
+	}
+	for _, e := range b.Errors {
+		p.ingest(labelInstance(e, b.Instance))
+	}
+	for _, s := range b.Spans {
+		p.ingest(labelInstance(s, b.Instance))
+	}
+	for _, c := range b.Connections {
+		p.ingest(labelInstance(c, b.Instance))
+	}
+	for _, m := range b.Runtime {
+		if m.Instance == "" {
+			m.Instance = b.Instance
+		}
+		if err := p.storage.StoreRuntime(m); err != nil && p.config.DevMode {
+			p.logger.Printf("[pulse] failed to store runtime metric from %s: %v", b.Instance, err)
+		}
+		if p.otlp != nil {
+			p.otlp.addRuntime(m)
+		}
+	}
+
+	n := b.Len()
+	c.mu.Lock()
+	info, ok := c.instances[b.Instance]
+	if !ok {
+		info = &InstanceInfo{Name: b.Instance}
+		c.instances[b.Instance] = info
+	}
+	info.AppName = b.AppName
+	info.LastSeen = time.Now()
+	info.Batches++
+	info.Metrics += uint64(n)
+	c.mu.Unlock()
+
+	return n, nil
+}
+
+// Instances returns the instances that have pushed metrics, sorted by name.
+func (c *Collector) Instances() []InstanceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]InstanceInfo, 0, len(c.instances))
+	for _, info := range c.instances {
+		result = append(result, *info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// --- HTTP ---
+
+// collectorIngestHandler accepts an AgentBatch, optionally gzip-compressed.
+// Agents authenticate with the collector token rather than a dashboard JWT.
+func collectorIngestHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := p.config.Collector.Token; token != "" {
+			got := c.GetHeader("Authorization")
+			if subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+token)) != 1 {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid collector token"})
+				return
+			}
+		}
+
+		var body io.Reader = c.Request.Body
+		if c.GetHeader("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+				return
+			}
+			defer zr.Close()
+			body = zr
+		}
+
+		var batch AgentBatch
+		if err := json.NewDecoder(io.LimitReader(body, maxAgentBatchBytes)).Decode(&batch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch: " + err.Error()})
+			return
+		}
+
+		n, err := p.collector.Ingest(batch)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"accepted": n})
+	}
+}
+
+// instancesHandler lists this instance and, in collector mode, every
+// instance that has pushed metrics.
+func instancesHandler(p *Pulse) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		instances := []InstanceInfo{{
+			Name:     p.config.Instance,
+			AppName:  p.config.AppName,
+			Local:    true,
+			LastSeen: time.Now(),
+		}}
+		if p.collector != nil {
+			for _, info := range p.collector.Instances() {
+				if info.Name == p.config.Instance {
+					info.Local = true
+					instances[0] = info
+					continue
+				}
+				instances = append(instances, info)
+			}
+		}
+		c.JSON(http.StatusOK, instances)
+	}
+}
+
+// --- Per-instance reads ---
+
+// filteredView narrows dashboard reads to one instance's metrics and to
+// requests and errors carrying the given tags. Stats are computed from the
+// matching raw requests rather than from rollups, which are not labelled.
+type filteredView struct {
+	Storage
+	pulse    *Pulse
+	instance string            // "" for all instances
+	tags     map[string]string // nil for any tags
+}
+
+func newFilteredView(p *Pulse, instance string, tags map[string]string) filteredView {
+	return filteredView{Storage: p.storage, pulse: p, instance: instance, tags: tags}
+}
+
+// GetRequests returns the requests matching both the view and the filter.
+func (v filteredView) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
+	if v.instance != "" {
+		filter.Instance = v.instance
+	}
+	filter.Tags = mergeTags(filter.Tags, v.tags)
+	return v.Storage.GetRequests(filter)
+}
+
+// GetRouteStats returns the matching requests' stats per route.
+func (v filteredView) GetRouteStats(timeRange TimeRange) ([]RouteStats, error) {
+	reqs, err := v.GetRequests(RequestFilter{TimeRange: timeRange})
+	if err != nil {
+		return nil, err
+	}
+	return buildRouteStats(reqs, timeRange.End.Sub(timeRange.Start)), nil
+}
+
+// GetRuntimeHistory returns the instance's runtime samples. Runtime samples
+// are not tagged, so tags do not narrow them.
+func (v filteredView) GetRuntimeHistory(timeRange TimeRange) ([]RuntimeMetric, error) {
+	all, err := v.Storage.GetRuntimeHistory(timeRange)
+	if err != nil || v.instance == "" {
+		return all, err
+	}
+	var result []RuntimeMetric
+	for _, m := range all {
+		if m.Instance == v.instance {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// GetErrors returns the errors matching both the view and the filter.
+func (v filteredView) GetErrors(filter ErrorFilter) ([]ErrorRecord, error) {
+	if v.instance != "" {
+		filter.Instance = v.instance
+	}
+	filter.Tags = mergeTags(filter.Tags, v.tags)
+	return v.Storage.GetErrors(filter)
+}
+
+// GetOverview computes the overview from the matching metrics. Alerts are
+// not labelled and are counted across all instances.
+func (v filteredView) GetOverview(timeRange TimeRange) (*Overview, error) {
+	reqs, err := v.GetRequests(RequestFilter{TimeRange: timeRange})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *RuntimeMetric
+	if history, _ := v.GetRuntimeHistory(v.pulse.recentRuntime(timeRange)); len(history) > 0 {
+		latest = fleetRuntime(history)
+	}
+
+	activeAlerts := 0
+	if alerts, _ := v.Storage.GetAlerts(AlertFilter{State: AlertStateFiring}); alerts != nil {
+		activeAlerts = len(alerts)
+	}
+
+	recentErrors, _ := v.GetErrors(ErrorFilter{TimeRange: timeRange, Limit: 5})
+
+	return buildOverview(v.pulse.config.AppName, v.pulse.Uptime(), timeRange, reqs, latest, activeAlerts, recentErrors), nil
+}
+
+// fleetRuntime sums the latest runtime sample of each instance in history,
+// so an overview across instances doesn't report whichever one sampled last.
+// Callers pass the samples of recentRuntime. It returns nil without samples.
+func fleetRuntime(history []RuntimeMetric) *RuntimeMetric {
+	latest := make(map[string]RuntimeMetric)
+	for _, m := range history {
+		if prev, ok := latest[m.Instance]; !ok || !m.Timestamp.Before(prev.Timestamp) {
+			latest[m.Instance] = m
+		}
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+	var sum RuntimeMetric
+	for _, m := range latest {
+		sum.NumGoroutine += m.NumGoroutine
+		sum.HeapAlloc += m.HeapAlloc
+		if m.Timestamp.After(sum.Timestamp) {
+			sum.Timestamp = m.Timestamp
+		}
+	}
+	return &sum
+}
+
+// fleetRuntimeIntervals is how many runtime sample intervals an instance's
+// latest sample may lag the end of the range and still count as reporting.
+const fleetRuntimeIntervals = 3
+
+// recentRuntime narrows timeRange to its last few runtime sample intervals,
+// so fleet totals skip instances that stopped reporting and only the recent
+// samples are loaded.
+func (p *Pulse) recentRuntime(timeRange TimeRange) TimeRange {
+	start := timeRange.End.Add(-fleetRuntimeIntervals * p.config.Runtime.SampleInterval)
+	if start.After(timeRange.Start) {
+		timeRange.Start = start
+	}
+	return timeRange
+}
+
+// setFleetRuntime replaces an unfiltered overview's runtime figures, which
+// storage takes from the newest sample of any instance, with the sum over
+// the instances a collector gathers.
+func (p *Pulse) setFleetRuntime(o *Overview, timeRange TimeRange) {
+	if p.collector == nil {
+		return
+	}
+	history, err := p.storage.GetRuntimeHistory(p.recentRuntime(timeRange))
+	if err != nil {
+		return
+	}
+	o.ActiveGoroutines, o.HeapAllocMB = runtimeFigures(fleetRuntime(history))
+}
@@ -0,0 +1,218 @@
+package pulse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupCollectorPulse(t *testing.T, token string) (*Pulse, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg := applyDefaults(Config{
+		Instance: "collector",
+		Dashboard: DashboardConfig{
+			Username:  "admin",
+			Password:  "testpass",
+			SecretKey: "test-secret-key-for-jwt",
+		},
+		Collector: CollectorConfig{Enabled: true, Token: token},
+	})
+	p := newPulse(cfg)
+	p.storage = NewMemoryStorage("test")
+	p.aggregator = &Aggregator{pulse: p}
+	p.collector = newCollector(p)
+	t.Cleanup(func() { p.Shutdown() })
+
+	router := gin.New()
+	registerAPIRoutes(router, p)
+	return p, router
+}
+
+func ingestRequest(t *testing.T, batch AgentBatch, token string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(zw).Encode(batch); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	req := httptest.NewRequest("POST", "/pulse/api/ingest", &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestCollector_IngestLabelsInstance(t *testing.T) {
+	p, router := setupCollectorPulse(t, "secret")
+	now := time.Now()
+
+	batch := AgentBatch{
+		Instance: "web-1",
+		AppName:  "shop",
+		Requests: []RequestMetric{{Method: "GET", Path: "/a", StatusCode: 200, Timestamp: now}},
+		Queries:  []QueryMetric{{SQL: "SELECT 1", Timestamp: now}},
+		Errors:   []ErrorRecord{{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now}},
+		Runtime:  []RuntimeMetric{{NumGoroutine: 12, Timestamp: now}},
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, ingestRequest(t, batch, "secret"))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	reqs, _ := p.storage.GetRequests(RequestFilter{Instance: "web-1"})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request labelled web-1, got %d", len(reqs))
+	}
+	queries, _ := p.storage.GetSlowQueries(0, 0)
+	if len(queries) != 1 || queries[0].Instance != "web-1" {
+		t.Errorf("expected query labelled web-1, got %+v", queries)
+	}
+	errs, _ := p.storage.GetErrors(ErrorFilter{Instance: "web-1"})
+	if len(errs) != 1 {
+		t.Errorf("expected error reported by web-1, got %d", len(errs))
+	}
+	runtime, _ := p.storage.GetRuntimeHistory(Last5m())
+	if len(runtime) != 1 || runtime[0].Instance != "web-1" {
+		t.Errorf("expected runtime sample labelled web-1, got %+v", runtime)
+	}
+
+	instances := p.collector.Instances()
+	if len(instances) != 1 || instances[0].Name != "web-1" || instances[0].Metrics != 4 || instances[0].AppName != "shop" {
+		t.Errorf("unexpected instances: %+v", instances)
+	}
+}
+
+func TestCollector_RejectsInvalidPushes(t *testing.T) {
+	_, router := setupCollectorPulse(t, "secret")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, ingestRequest(t, AgentBatch{Instance: "web-1"}, "wrong"))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad token, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, ingestRequest(t, AgentBatch{}, "secret"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a batch without instance, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/pulse/api/ingest", bytes.NewBufferString("{not json"))
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed JSON, got %d", w.Code)
+	}
+}
+
+func TestCollector_IngestRouteDisabledByDefault(t *testing.T) {
+	_, router := setupAPIPulse(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, ingestRequest(t, AgentBatch{Instance: "web-1"}, ""))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without collector mode, got %d", w.Code)
+	}
+}
+
+func TestAPI_FilterByInstance(t *testing.T) {
+	p, router := setupCollectorPulse(t, "")
+	token := loginAndGetToken(t, router)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		p.ingest(RequestMetric{Method: "GET", Path: "/a", StatusCode: 200, Latency: 10 * time.Millisecond, Instance: "web-1", Timestamp: now})
+	}
+	p.ingest(RequestMetric{Method: "GET", Path: "/b", StatusCode: 500, Latency: 10 * time.Millisecond, Instance: "web-2", Timestamp: now})
+	p.ingest(ErrorRecord{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now, Instances: []string{"web-2"}})
+	p.collector.Ingest(AgentBatch{Instance: "web-1"})
+	p.collector.Ingest(AgentBatch{Instance: "web-2"})
+	p.storage.StoreRuntime(RuntimeMetric{NumGoroutine: 100, Instance: "web-2", Timestamp: now.Add(-2 * time.Second)})
+	// web-3 stopped reporting long ago and no longer counts
+	p.storage.StoreRuntime(RuntimeMetric{NumGoroutine: 1000, Instance: "web-3", Timestamp: now.Add(-10 * time.Minute)})
+	p.storage.StoreRuntime(RuntimeMetric{NumGoroutine: 10, Instance: "web-1", Timestamp: now.Add(-time.Second)})
+	p.storage.StoreRuntime(RuntimeMetric{NumGoroutine: 15, Instance: "web-1", Timestamp: now})
+
+	get := func(path string, v interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", path, token, ""))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, w.Code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	var overview Overview
+	get("/pulse/api/overview?range=1h&instance=web-1", &overview)
+	if overview.TotalRequests != 3 || overview.TotalErrors != 0 {
+		t.Errorf("expected web-1 overview with 3 requests and no errors, got %d / %d", overview.TotalRequests, overview.TotalErrors)
+	}
+	if overview.ActiveGoroutines != 15 {
+		t.Errorf("expected web-1's latest goroutine count, got %d", overview.ActiveGoroutines)
+	}
+	// Across instances, the latest sample of each reporting instance counts
+	get("/pulse/api/overview?range=1h", &overview)
+	if overview.ActiveGoroutines != 115 {
+		t.Errorf("expected goroutines summed across instances, got %d", overview.ActiveGoroutines)
+	}
+
+	var routes []RouteStats
+	get("/pulse/api/routes?range=1h&instance=web-2", &routes)
+	if len(routes) != 1 || routes[0].Path != "/b" {
+		t.Errorf("expected only /b for web-2, got %+v", routes)
+	}
+	get("/pulse/api/routes?range=1h", &routes)
+	if len(routes) != 2 {
+		t.Errorf("expected routes aggregated across instances, got %d", len(routes))
+	}
+
+	var errs []ErrorRecord
+	get("/pulse/api/errors?range=1h&instance=web-1", &errs)
+	if len(errs) != 0 {
+		t.Errorf("expected no errors for web-1, got %d", len(errs))
+	}
+	get("/pulse/api/errors?range=1h&instance=web-2", &errs)
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error for web-2, got %d", len(errs))
+	}
+
+	var instances []InstanceInfo
+	get("/pulse/api/instances", &instances)
+	if len(instances) != 3 || !instances[0].Local || instances[1].Name != "web-1" || instances[2].Name != "web-2" {
+		t.Errorf("unexpected instances: %+v", instances)
+	}
+}
+
+func TestMemoryStorage_ErrorInstancesMerge(t *testing.T) {
+	s := NewMemoryStorage("test")
+	now := time.Now()
+
+	s.StoreError(ErrorRecord{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now, Instances: []string{"web-1"}})
+	s.StoreError(ErrorRecord{ID: "e2", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now, Instances: []string{"web-2"}})
+	s.StoreError(ErrorRecord{ID: "e3", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now, Instances: []string{"web-1"}})
+
+	errs, _ := s.GetErrors(ErrorFilter{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error group, got %d", len(errs))
+	}
+	if got := errs[0].Instances; len(got) != 2 || got[0] != "web-1" || got[1] != "web-2" {
+		t.Errorf("expected instances [web-1 web-2], got %v", got)
+	}
+}
@@ -3,7 +3,12 @@ package pulse
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // StorageDriver represents the storage backend type.
@@ -12,8 +17,23 @@ type StorageDriver int
 const (
 	// Memory is the default in-memory storage backend using ring buffers.
 	Memory StorageDriver = iota
-	// SQLite uses modernc.org/sqlite for persistent storage.
+	// SQLite persists metrics to a local SQLite file (pure Go, no CGo).
 	SQLite
+	// GORM persists metrics to pulse_* tables through GORM, using the
+	// application's database or StorageConfig.Dialector.
+	GORM
+)
+
+// OverflowPolicy decides what happens when the ingestion queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest discards the oldest queued metric to make room (default).
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowDropNew discards the incoming metric.
+	OverflowDropNew OverflowPolicy = "drop-new"
+	// OverflowBlock makes the caller wait for room in the queue.
+	OverflowBlock OverflowPolicy = "block"
 )
 
 // Config holds all configuration for Pulse.
@@ -24,12 +44,19 @@ type Config struct {
 	// AppName is the application name displayed in the dashboard.
 	AppName string
 
+	// Instance labels every metric collected by this process, so a collector
+	// can tell replicas apart (default: hostname).
+	Instance string
+
 	// Dashboard holds authentication settings.
 	Dashboard DashboardConfig
 
 	// Storage configures the storage backend.
 	Storage StorageConfig
 
+	// Ingestion configures the queue between collectors and storage.
+	Ingestion IngestionConfig
+
 	// Tracing configures request tracing middleware.
 	Tracing TracingConfig
 
@@ -51,6 +78,19 @@ type Config struct {
 	// Prometheus configures the optional Prometheus endpoint.
 	Prometheus PrometheusConfig
 
+	// Agent pushes this instance's metrics to a central collector.
+	Agent AgentConfig
+
+	// Collector accepts metrics pushed by agents.
+	Collector CollectorConfig
+
+	// OTLP exports traces and metrics to an OpenTelemetry endpoint.
+	OTLP OTLPConfig
+
+	// Privacy scrubs personal data and secrets and anonymizes client IPs
+	// before metrics are stored.
+	Privacy PrivacyConfig
+
 	// DevMode enables verbose logging and more frequent aggregation.
 	DevMode bool
 }
@@ -71,8 +111,56 @@ type StorageConfig struct {
 	Driver StorageDriver
 	// DSN is the SQLite database path (default: "pulse.db").
 	DSN string
+	// Dialector opens a separate database for the GORM driver
+	// (e.g. postgres.Open(dsn)). When nil, the *gorm.DB passed to Mount is used.
+	Dialector gorm.Dialector `json:"-"`
 	// RetentionHours sets data retention period (default: 24).
 	RetentionHours int
+	// Retention overrides RetentionHours per data type.
+	Retention RetentionConfig
+	// CleanupInterval is how often expired data is pruned (default: 5m).
+	CleanupInterval time.Duration
+	// SnapshotPath enables memory storage snapshots: the file is restored on
+	// Mount and rewritten on Shutdown. Ignored by other drivers.
+	SnapshotPath string
+	// SnapshotInterval additionally writes the snapshot periodically
+	// (default: 0, only on Shutdown).
+	SnapshotInterval time.Duration
+	// HotWindow keeps the most recent data in memory in front of the SQLite
+	// or GORM driver: writes go to both, and recent ranges are read from
+	// memory (default: 0, disabled).
+	HotWindow time.Duration
+}
+
+// IngestionConfig configures the bounded queue that batches metric writes.
+type IngestionConfig struct {
+	// QueueSize is the maximum number of queued metrics (default: 10000).
+	QueueSize int
+	// Workers is the number of goroutines writing to storage (default: 2).
+	Workers int
+	// BatchSize is the maximum number of metrics per storage write (default: 256).
+	BatchSize int
+	// Overflow is the policy applied when the queue is full (default: OverflowDropOldest).
+	Overflow OverflowPolicy
+}
+
+// RetentionConfig sets how long each data type is kept. Zero values default
+// to StorageConfig.RetentionHours.
+type RetentionConfig struct {
+	// Requests is the retention for request metrics.
+	Requests time.Duration
+	// Queries is the retention for query metrics and N+1 detections.
+	Queries time.Duration
+	// Runtime is the retention for runtime samples and pool stats.
+	Runtime time.Duration
+	// Errors is the retention for error groups, measured from last seen.
+	Errors time.Duration
+	// Alerts is the retention for alert history.
+	Alerts time.Duration
+	// Health is the retention for health check results.
+	Health time.Duration
+	// Dependencies is the retention for outbound dependency calls.
+	Dependencies time.Duration
 }
 
 // TracingConfig configures request tracing.
@@ -82,11 +170,36 @@ type TracingConfig struct {
 	// SlowRequestThreshold flags requests slower than this (default: 1s).
 	SlowRequestThreshold time.Duration
 	// SampleRate controls sampling between 0.0-1.0 (default: 1.0).
-	// Errors and slow requests are always recorded regardless of sample rate.
+	// Errors and slow requests are always recorded regardless of sample rate,
+	// unless a sampling rule matches them.
 	// Use a pointer so that an explicit 0.0 is distinguishable from unset.
 	SampleRate *float64
+	// SamplingRules set the sample rate per kind of request, decided after
+	// the request finishes. The first matching rule wins; requests matching
+	// none fall back to SampleRate.
+	SamplingRules []SamplingRule
+	// MaxRecordedPerSecond adaptively lowers sample rates to record about
+	// this many requests per second (default: 0, disabled). Errors and slow
+	// requests are not thinned out.
+	MaxRecordedPerSecond float64
 	// ExcludePaths lists glob patterns for paths to skip tracing.
 	ExcludePaths []string
+	// CaptureRules keep request and response headers and bodies of the
+	// recorded requests they match, such as failing or slow requests
+	// (default: none).
+	CaptureRules []CaptureRule
+	// StuckRequestThreshold flags requests still running after this long as
+	// stuck and captures their goroutine stacks (default: 30s). Negative
+	// disables stuck request detection.
+	StuckRequestThreshold time.Duration
+	// MaxUnmatchedRoutes caps the distinct templated paths recorded for
+	// requests no route matched, such as 404s (default: 100). Further paths
+	// are grouped under the "other" route. Negative disables the cap.
+	MaxUnmatchedRoutes int
+	// RouteFunc returns the route pattern of a request traced by Handler,
+	// called after the handler returns (default: ServeMuxPattern). Return ""
+	// for unmatched requests. The Gin middleware uses the Gin route instead.
+	RouteFunc func(r *http.Request) string `json:"-"`
 }
 
 // DatabaseConfig configures GORM query monitoring.
@@ -121,10 +234,41 @@ type ErrorConfig struct {
 	CaptureStackTrace *bool
 	// CaptureRequestBody captures request body on errors (default: true).
 	CaptureRequestBody *bool
-	// MaxBodySize limits captured request body size in bytes (default: 4096).
+	// MaxBodySize limits captured request and response body sizes in bytes,
+	// for errors and TracingConfig.CaptureRules (default: 4096).
 	MaxBodySize int
 }
 
+// PrivacyConfig configures the scrubbing of personal data and secrets from
+// error messages, SQL, URLs, query strings, headers and bodies, and the
+// anonymization of client IPs. Both run before metrics reach storage,
+// exports and live dashboard clients.
+type PrivacyConfig struct {
+	// Scrub toggles scrubbing (default: true).
+	Scrub *bool
+	// Detectors lists the built-in detectors to run (default: email,
+	// credit_card, jwt and bearer_token). An empty, non-nil list runs none.
+	Detectors []ScrubDetector
+	// MaskFields lists fields whose values are replaced in JSON bodies, form
+	// bodies, query strings and headers, matched case-insensitively. A name
+	// matches the field at any depth; a dotted path such as "card.number" or
+	// "items.*.ssn" matches from the root of a JSON body (default: password,
+	// passwd, secret, token, access_token, refresh_token, api_key, apikey,
+	// client_secret). An empty, non-nil list masks none.
+	MaskFields []string
+	// Scrubbers run on every scrubbed string after the built-in detectors.
+	Scrubbers []Scrubber `json:"-"`
+
+	// AnonymizeIP truncates or hashes the client IPs of requests, errors and
+	// connections before they are stored (default: "", IPs are kept as is).
+	// Unknown modes hash.
+	AnonymizeIP IPAnonymization
+	// IPHashKey keys the hashes of AnonymizeIPHash. Hashes only stay stable
+	// across restarts and instances with the same key (default:
+	// Dashboard.SecretKey).
+	IPHashKey string
+}
+
 // HealthConfig configures the health check system.
 type HealthConfig struct {
 	// Enabled toggles health checks (default: true).
@@ -211,6 +355,55 @@ type AlertRule struct {
 	Route string
 }
 
+// AgentConfig configures pushing metrics to a central Pulse collector.
+// Metrics are still stored locally.
+type AgentConfig struct {
+	// CollectorURL is the collector's Pulse prefix, e.g.
+	// "http://collector:8080/pulse". Agent mode is enabled when set.
+	CollectorURL string
+	// Token is sent as a bearer token and must match the collector's.
+	Token string
+	// FlushInterval is how often buffered metrics are pushed (default: 5s).
+	FlushInterval time.Duration
+	// BatchSize triggers an early push when this many metrics are buffered (default: 1000).
+	BatchSize int
+	// MaxPending caps the metrics kept while the collector is unreachable;
+	// the oldest are dropped first (default: 50000).
+	MaxPending int
+	// Timeout bounds each push request (default: 10s).
+	Timeout time.Duration
+}
+
+// CollectorConfig configures accepting metrics from agents at
+// POST {Prefix}/api/ingest.
+type CollectorConfig struct {
+	// Enabled toggles the ingest endpoint (default: false).
+	Enabled bool
+	// Token is the bearer token agents must send. Leaving it empty accepts
+	// unauthenticated pushes.
+	Token string
+}
+
+// OTLPConfig configures exporting spans and metrics over OTLP/HTTP with JSON
+// encoding. Data is still stored locally.
+type OTLPConfig struct {
+	// Endpoint is the OTLP/HTTP base URL, e.g. "http://otel-collector:4318".
+	// Traces are sent to {Endpoint}/v1/traces and metrics to
+	// {Endpoint}/v1/metrics. Export is enabled when set.
+	Endpoint string
+	// Headers are added to every export request, e.g. for authentication.
+	Headers map[string]string
+	// FlushInterval is how often buffered data is exported (default: 10s).
+	FlushInterval time.Duration
+	// BatchSize triggers an early export when this many items are buffered (default: 512).
+	BatchSize int
+	// MaxPending caps the items kept while the endpoint is unreachable;
+	// the oldest are dropped first (default: 20000).
+	MaxPending int
+	// Timeout bounds each export request (default: 10s).
+	Timeout time.Duration
+}
+
 // PrometheusConfig configures the optional Prometheus endpoint.
 type PrometheusConfig struct {
 	// Enabled toggles the Prometheus endpoint (default: false).
@@ -230,15 +423,24 @@ func DefaultConfig() Config {
 			SecretKey: generateSecretKey(),
 		},
 		Storage: StorageConfig{
-			Driver:         Memory,
-			DSN:            "pulse.db",
-			RetentionHours: 24,
+			Driver:          Memory,
+			DSN:             "pulse.db",
+			RetentionHours:  24,
+			CleanupInterval: 5 * time.Minute,
+		},
+		Ingestion: IngestionConfig{
+			QueueSize: 10000,
+			Workers:   2,
+			BatchSize: 256,
+			Overflow:  OverflowDropOldest,
 		},
 		Tracing: TracingConfig{
-			Enabled:              boolPtr(true),
-			SlowRequestThreshold: 1 * time.Second,
-			SampleRate:           float64Ptr(1.0),
-			ExcludePaths:         []string{},
+			Enabled:               boolPtr(true),
+			SlowRequestThreshold:  1 * time.Second,
+			SampleRate:            float64Ptr(1.0),
+			ExcludePaths:          []string{},
+			StuckRequestThreshold: 30 * time.Second,
+			MaxUnmatchedRoutes:    100,
 		},
 		Database: DatabaseConfig{
 			Enabled:            boolPtr(true),
@@ -271,6 +473,23 @@ func DefaultConfig() Config {
 			Enabled: false,
 			Path:    "/pulse/metrics",
 		},
+		Agent: AgentConfig{
+			FlushInterval: 5 * time.Second,
+			BatchSize:     1000,
+			MaxPending:    50000,
+			Timeout:       10 * time.Second,
+		},
+		OTLP: OTLPConfig{
+			FlushInterval: 10 * time.Second,
+			BatchSize:     512,
+			MaxPending:    20000,
+			Timeout:       10 * time.Second,
+		},
+		Privacy: PrivacyConfig{
+			Scrub:      boolPtr(true),
+			Detectors:  []ScrubDetector{DetectEmail, DetectCreditCard, DetectJWT, DetectBearerToken},
+			MaskFields: []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "api_key", "apikey", "client_secret"},
+		},
 		DevMode: false,
 	}
 }
@@ -285,6 +504,9 @@ func applyDefaults(cfg Config) Config {
 	if cfg.AppName == "" {
 		cfg.AppName = defaults.AppName
 	}
+	if cfg.Instance == "" {
+		cfg.Instance = defaultInstanceName()
+	}
 
 	// Dashboard
 	if cfg.Dashboard.Username == "" {
@@ -304,6 +526,39 @@ func applyDefaults(cfg Config) Config {
 	if cfg.Storage.RetentionHours == 0 {
 		cfg.Storage.RetentionHours = defaults.Storage.RetentionHours
 	}
+	if cfg.Storage.CleanupInterval == 0 {
+		cfg.Storage.CleanupInterval = defaults.Storage.CleanupInterval
+	}
+	retention := time.Duration(cfg.Storage.RetentionHours) * time.Hour
+	for _, d := range []*time.Duration{
+		&cfg.Storage.Retention.Requests,
+		&cfg.Storage.Retention.Queries,
+		&cfg.Storage.Retention.Runtime,
+		&cfg.Storage.Retention.Errors,
+		&cfg.Storage.Retention.Alerts,
+		&cfg.Storage.Retention.Health,
+		&cfg.Storage.Retention.Dependencies,
+	} {
+		if *d == 0 {
+			*d = retention
+		}
+	}
+
+	// Ingestion
+	if cfg.Ingestion.QueueSize <= 0 {
+		cfg.Ingestion.QueueSize = defaults.Ingestion.QueueSize
+	}
+	if cfg.Ingestion.Workers <= 0 {
+		cfg.Ingestion.Workers = defaults.Ingestion.Workers
+	}
+	if cfg.Ingestion.BatchSize <= 0 {
+		cfg.Ingestion.BatchSize = defaults.Ingestion.BatchSize
+	}
+	switch cfg.Ingestion.Overflow {
+	case OverflowDropOldest, OverflowDropNew, OverflowBlock:
+	default:
+		cfg.Ingestion.Overflow = defaults.Ingestion.Overflow
+	}
 
 	// Tracing
 	if cfg.Tracing.Enabled == nil {
@@ -315,6 +570,12 @@ func applyDefaults(cfg Config) Config {
 	if cfg.Tracing.SampleRate == nil {
 		cfg.Tracing.SampleRate = defaults.Tracing.SampleRate
 	}
+	if cfg.Tracing.StuckRequestThreshold == 0 {
+		cfg.Tracing.StuckRequestThreshold = defaults.Tracing.StuckRequestThreshold
+	}
+	if cfg.Tracing.MaxUnmatchedRoutes == 0 {
+		cfg.Tracing.MaxUnmatchedRoutes = defaults.Tracing.MaxUnmatchedRoutes
+	}
 
 	// Database
 	if cfg.Database.Enabled == nil {
@@ -382,6 +643,50 @@ func applyDefaults(cfg Config) Config {
 		cfg.Prometheus.Path = defaults.Prometheus.Path
 	}
 
+	// Agent
+	cfg.Agent.CollectorURL = strings.TrimRight(cfg.Agent.CollectorURL, "/")
+	if cfg.Agent.FlushInterval <= 0 {
+		cfg.Agent.FlushInterval = defaults.Agent.FlushInterval
+	}
+	if cfg.Agent.BatchSize <= 0 {
+		cfg.Agent.BatchSize = defaults.Agent.BatchSize
+	}
+	if cfg.Agent.MaxPending <= 0 {
+		cfg.Agent.MaxPending = defaults.Agent.MaxPending
+	}
+	if cfg.Agent.Timeout <= 0 {
+		cfg.Agent.Timeout = defaults.Agent.Timeout
+	}
+
+	// OTLP
+	cfg.OTLP.Endpoint = strings.TrimRight(cfg.OTLP.Endpoint, "/")
+	if cfg.OTLP.FlushInterval <= 0 {
+		cfg.OTLP.FlushInterval = defaults.OTLP.FlushInterval
+	}
+	if cfg.OTLP.BatchSize <= 0 {
+		cfg.OTLP.BatchSize = defaults.OTLP.BatchSize
+	}
+	if cfg.OTLP.MaxPending <= 0 {
+		cfg.OTLP.MaxPending = defaults.OTLP.MaxPending
+	}
+	if cfg.OTLP.Timeout <= 0 {
+		cfg.OTLP.Timeout = defaults.OTLP.Timeout
+	}
+
+	// Privacy
+	if cfg.Privacy.Scrub == nil {
+		cfg.Privacy.Scrub = defaults.Privacy.Scrub
+	}
+	if cfg.Privacy.Detectors == nil {
+		cfg.Privacy.Detectors = defaults.Privacy.Detectors
+	}
+	if cfg.Privacy.MaskFields == nil {
+		cfg.Privacy.MaskFields = defaults.Privacy.MaskFields
+	}
+	if cfg.Privacy.IPHashKey == "" {
+		cfg.Privacy.IPHashKey = cfg.Dashboard.SecretKey
+	}
+
 	return cfg
 }
 
@@ -407,6 +712,15 @@ func float64Value(f *float64) float64 {
 	return *f
 }
 
+// defaultInstanceName returns the hostname, which is unique per replica in
+// most deployments.
+func defaultInstanceName() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "local"
+}
+
 func generateSecretKey() string {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
@@ -0,0 +1,311 @@
+package pulse
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnectionKind categorizes a long-lived connection.
+type ConnectionKind string
+
+const (
+	// ConnectionWebSocket is a hijacked WebSocket upgrade.
+	ConnectionWebSocket ConnectionKind = "websocket"
+	// ConnectionUpgrade is any other hijacked connection.
+	ConnectionUpgrade ConnectionKind = "upgrade"
+	// ConnectionSSE is a text/event-stream response.
+	ConnectionSSE ConnectionKind = "sse"
+	// ConnectionStream is any other flushed, streaming response.
+	ConnectionStream ConnectionKind = "stream"
+)
+
+// ConnectionMetric records a long-lived connection: a response that was
+// hijacked (WebSocket) or streamed with Flush (SSE). Connections are kept
+// apart from request metrics so they don't skew request latency.
+type ConnectionMetric struct {
+	Kind          ConnectionKind `json:"kind"`
+	Method        string         `json:"method"`
+	Path          string         `json:"path"` // route pattern
+	StatusCode    int            `json:"status_code"`
+	Duration      time.Duration  `json:"duration"`
+	TTFB          time.Duration  `json:"ttfb"` // time to first byte
+	BytesSent     int64          `json:"bytes_sent"`
+	BytesReceived int64          `json:"bytes_received"`
+	Messages      int64          `json:"messages"` // writes on hijacked connections, flushes on streams
+	ClientIP      string         `json:"client_ip"`
+	TraceID       string         `json:"trace_id,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"` // when the connection was opened
+}
+
+// ConnectionStats aggregates the finished connections of one route.
+type ConnectionStats struct {
+	Kind          ConnectionKind `json:"kind"`
+	Method        string         `json:"method"`
+	Path          string         `json:"path"`
+	Count         int64          `json:"count"`
+	AvgDuration   time.Duration  `json:"avg_duration"`
+	P95Duration   time.Duration  `json:"p95_duration"`
+	MaxDuration   time.Duration  `json:"max_duration"`
+	AvgTTFB       time.Duration  `json:"avg_ttfb"`
+	P95TTFB       time.Duration  `json:"p95_ttfb"`
+	BytesSent     int64          `json:"bytes_sent"`
+	BytesReceived int64          `json:"bytes_received"`
+	Messages      int64          `json:"messages"`
+}
+
+// ConnectionsOverview is the response of the connections API.
+type ConnectionsOverview struct {
+	Active map[ConnectionKind]int64 `json:"active"` // open connections by kind
+	Routes []ConnectionStats        `json:"routes"` // finished connections in the time range
+}
+
+// buildConnectionStats groups connections by kind and route, sorted by
+// count descending.
+func buildConnectionStats(conns []ConnectionMetric) []ConnectionStats {
+	type connKey struct {
+		kind         ConnectionKind
+		method, path string
+	}
+
+	groups := make(map[connKey][]ConnectionMetric)
+	for _, c := range conns {
+		key := connKey{c.Kind, c.Method, c.Path}
+		groups[key] = append(groups[key], c)
+	}
+
+	result := make([]ConnectionStats, 0, len(groups))
+	for key, group := range groups {
+		stats := ConnectionStats{Kind: key.kind, Method: key.method, Path: key.path, Count: int64(len(group))}
+		durations := make([]time.Duration, len(group))
+		ttfbs := make([]time.Duration, len(group))
+		for i, c := range group {
+			durations[i] = c.Duration
+			ttfbs[i] = c.TTFB
+			stats.BytesSent += c.BytesSent
+			stats.BytesReceived += c.BytesReceived
+			stats.Messages += c.Messages
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		sort.Slice(ttfbs, func(i, j int) bool { return ttfbs[i] < ttfbs[j] })
+
+		stats.AvgDuration = ComputeAvg(durations)
+		stats.P95Duration = Percentile(durations, 95)
+		stats.MaxDuration = durations[len(durations)-1]
+		stats.AvgTTFB = ComputeAvg(ttfbs)
+		stats.P95TTFB = Percentile(ttfbs, 95)
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Method+" "+result[i].Path < result[j].Method+" "+result[j].Path
+	})
+	return result
+}
+
+// activeConnections counts the open connections by kind.
+type activeConnections struct {
+	mu     sync.Mutex
+	counts map[ConnectionKind]int64
+}
+
+func newActiveConnections() *activeConnections {
+	return &activeConnections{counts: make(map[ConnectionKind]int64)}
+}
+
+func (a *activeConnections) add(kind ConnectionKind, delta int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.counts[kind] += delta
+}
+
+// snapshot returns the open connections by kind.
+func (a *activeConnections) snapshot() map[ConnectionKind]int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	counts := make(map[ConnectionKind]int64, len(a.counts))
+	for kind, n := range a.counts {
+		counts[kind] = n
+	}
+	return counts
+}
+
+// liveConnection is a traced request whose response turned out to be a
+// long-lived connection. It is recorded once the handler has returned and,
+// for hijacked connections, the connection has been closed, whichever is
+// later.
+type liveConnection struct {
+	p     *Pulse
+	start time.Time
+
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+	messages      atomic.Int64
+	firstByte     atomic.Int64 // unix nanoseconds, 0 until the first byte is written
+
+	mu      sync.Mutex
+	metric  ConnectionMetric
+	pending int // handler return, plus Close for hijacked connections
+}
+
+// connectionKind returns the kind of a hijacked or streamed response.
+// upgrade is the request's Upgrade header.
+func connectionKind(upgrade string, header http.Header, hijacked bool) ConnectionKind {
+	if hijacked {
+		if strings.EqualFold(upgrade, "websocket") {
+			return ConnectionWebSocket
+		}
+		return ConnectionUpgrade
+	}
+	if isEventStream(header) {
+		return ConnectionSSE
+	}
+	return ConnectionStream
+}
+
+// isEventStream reports whether the response is a server-sent event stream.
+func isEventStream(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+// openConnection turns the traced request into a long-lived connection,
+// removing it from the in-flight requests. It returns the existing
+// connection when called again.
+func (tr *tracedRequest) openConnection(kind ConnectionKind, hijacked bool) *liveConnection {
+	if tr.live != nil {
+		return tr.live
+	}
+	tr.untrack()
+
+	p := tr.tracer.p
+	pending := 1
+	if hijacked {
+		pending = 2
+	}
+	tr.live = &liveConnection{
+		p:     p,
+		start: tr.start,
+		metric: ConnectionMetric{
+			Kind:      kind,
+			Method:    tr.method,
+			Path:      tr.path, // until the route is known
+			TraceID:   tr.tc.TraceID,
+			Timestamp: tr.start,
+		},
+		pending: pending,
+	}
+	p.connections.add(kind, 1)
+	return tr.live
+}
+
+// wrote notes that the response writer sent the first bytes.
+func (tr *tracedRequest) wrote() {
+	if tr != nil && tr.firstByte.IsZero() {
+		tr.firstByte = time.Now()
+	}
+}
+
+// flushed marks a flushed response as a streaming connection.
+func (tr *tracedRequest) flushed(header http.Header) {
+	if tr == nil {
+		return
+	}
+	tr.wrote()
+	tr.openConnection(connectionKind(tr.upgrade, header, false), false).messages.Add(1)
+}
+
+// hijacked marks the request as a hijacked connection and returns conn
+// wrapped to count its traffic.
+func (tr *tracedRequest) hijacked(conn net.Conn) net.Conn {
+	if tr == nil || tr.live != nil {
+		return conn
+	}
+	live := tr.openConnection(connectionKind(tr.upgrade, nil, true), true)
+	return &trackedConn{Conn: conn, live: live}
+}
+
+// handlerDone records what the handler knows about the connection: its
+// route, status and the bytes written through the response writer.
+func (c *liveConnection) handlerDone(r *http.Request, out requestOutcome, firstByte time.Time) {
+	if !firstByte.IsZero() {
+		c.firstByte.CompareAndSwap(0, firstByte.UnixNano())
+	}
+	c.bytesSent.Add(out.responseSize)
+	if r.ContentLength > 0 {
+		c.bytesReceived.Add(r.ContentLength)
+	}
+
+	c.mu.Lock()
+	c.metric.Method = r.Method
+	c.metric.Path = out.route
+	c.metric.StatusCode = out.status
+	c.metric.ClientIP = out.clientIP
+	if c.metric.Kind == ConnectionWebSocket || c.metric.Kind == ConnectionUpgrade {
+		c.metric.StatusCode = http.StatusSwitchingProtocols
+	}
+	c.mu.Unlock()
+	c.done()
+}
+
+// done records the connection once every pending event has happened.
+func (c *liveConnection) done() {
+	c.mu.Lock()
+	c.pending--
+	if c.pending > 0 {
+		c.mu.Unlock()
+		return
+	}
+	m := c.metric
+	c.mu.Unlock()
+
+	m.Duration = time.Since(c.start)
+	if first := c.firstByte.Load(); first != 0 {
+		m.TTFB = time.Unix(0, first).Sub(c.start)
+	}
+	m.BytesSent = c.bytesSent.Load()
+	m.BytesReceived = c.bytesReceived.Load()
+	m.Messages = c.messages.Load()
+
+	c.p.connections.add(m.Kind, -1)
+	c.p.ingest(m)
+}
+
+// trackedConn counts the traffic of a hijacked connection and records the
+// connection when it is closed.
+type trackedConn struct {
+	net.Conn
+	live   *liveConnection
+	closed atomic.Bool
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.live.bytesReceived.Add(int64(n))
+	return n, err
+}
+
+func (c *trackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.live.firstByte.CompareAndSwap(0, time.Now().UnixNano())
+		c.live.bytesSent.Add(int64(n))
+		c.live.messages.Add(1)
+	}
+	return n, err
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	if c.closed.CompareAndSwap(false, true) {
+		c.live.done()
+	}
+	return err
+}
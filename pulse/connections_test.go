@@ -0,0 +1,205 @@
+package pulse
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func waitForConnections(t *testing.T, p *Pulse, n int) []ConnectionMetric {
+	t.Helper()
+	store := p.storage.(connectionStore)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conns, _ := store.GetConnections(Last1h())
+		if len(conns) >= n {
+			return conns
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d stored connections, got %d", n, len(conns))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMiddleware_RecordsEventStreamAsConnection(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	router.GET("/events/:topic", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			c.SSEvent("tick", i)
+			c.Writer.Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/events/news", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+	if strings.Count(w.Body.String(), "event:tick") != 3 {
+		t.Fatalf("expected 3 events in the response, got %q", w.Body.String())
+	}
+
+	conns := waitForConnections(t, p, 1)
+	c := conns[0]
+	if c.Kind != ConnectionSSE || c.Path != "/events/:topic" || c.StatusCode != http.StatusOK {
+		t.Errorf("unexpected connection: %+v", c)
+	}
+	if c.Messages != 3 || c.BytesSent != int64(w.Body.Len()) || c.TraceID == "" {
+		t.Errorf("expected 3 messages and %d bytes, got %+v", w.Body.Len(), c)
+	}
+	if c.TTFB <= 0 || c.TTFB >= c.Duration || c.Duration < 15*time.Millisecond {
+		t.Errorf("expected TTFB before the full duration, got ttfb=%s duration=%s", c.TTFB, c.Duration)
+	}
+
+	// The stream stays out of the request metrics
+	for _, r := range waitForRequests(t, p, 1) {
+		if r.Path != "/ping" {
+			t.Errorf("expected only /ping among requests, got %s", r.Path)
+		}
+	}
+	if active := p.connections.snapshot()[ConnectionSSE]; active != 0 {
+		t.Errorf("expected no open streams, got %d", active)
+	}
+	if inflight, _ := p.inflight.counts(); inflight != 0 {
+		t.Errorf("expected no in-flight requests, got %d", inflight)
+	}
+}
+
+func TestHandler_RecordsHijackedConnection(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{})
+
+	opened := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/{room}", func(w http.ResponseWriter, r *http.Request) {
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		go func() {
+			defer conn.Close()
+			fmt.Fprint(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+			close(opened)
+			for {
+				line, err := brw.ReadString('\n')
+				if err != nil {
+					return
+				}
+				conn.Write([]byte("echo " + line))
+			}
+		}()
+	})
+	srv := httptest.NewServer(Handler(p, mux))
+	defer srv.Close()
+
+	client, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(client, "GET /ws/lobby HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+	<-opened
+
+	// The handler has returned, but the connection is still open
+	time.Sleep(20 * time.Millisecond)
+	if active := p.connections.snapshot()[ConnectionWebSocket]; active != 1 {
+		t.Errorf("expected 1 open websocket, got %d", active)
+	}
+	if conns, _ := p.storage.(connectionStore).GetConnections(Last1h()); len(conns) != 0 {
+		t.Errorf("expected nothing recorded while open, got %+v", conns)
+	}
+
+	reader := bufio.NewReader(client)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %v (%v)", resp, err)
+	}
+	for _, msg := range []string{"hello\n", "world\n"} {
+		client.Write([]byte(msg))
+		if echo, _ := reader.ReadString('\n'); echo != "echo "+msg {
+			t.Fatalf("expected echo of %q, got %q", msg, echo)
+		}
+	}
+	client.Close()
+
+	c := waitForConnections(t, p, 1)[0]
+	if c.Kind != ConnectionWebSocket || c.Path != "/ws/{room}" || c.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("unexpected connection: %+v", c)
+	}
+	if c.Messages != 3 || c.BytesSent == 0 || c.TTFB <= 0 || c.Duration < 20*time.Millisecond {
+		t.Errorf("expected the handshake and 2 echoes, got %+v", c)
+	}
+	if active := p.connections.snapshot()[ConnectionWebSocket]; active != 0 {
+		t.Errorf("expected the websocket closed, got %d open", active)
+	}
+	if reqs, _ := p.storage.GetRequests(RequestFilter{}); len(reqs) != 0 {
+		t.Errorf("expected the upgrade kept out of requests, got %+v", reqs)
+	}
+}
+
+func TestBuildConnectionStats(t *testing.T) {
+	stats := buildConnectionStats([]ConnectionMetric{
+		{Kind: ConnectionWebSocket, Method: "GET", Path: "/ws", Duration: time.Minute, TTFB: time.Millisecond, Messages: 10, BytesSent: 100},
+		{Kind: ConnectionWebSocket, Method: "GET", Path: "/ws", Duration: 3 * time.Minute, TTFB: 3 * time.Millisecond, Messages: 20, BytesSent: 300},
+		{Kind: ConnectionSSE, Method: "GET", Path: "/events", Duration: time.Second},
+	})
+	if len(stats) != 2 || stats[0].Path != "/ws" || stats[1].Path != "/events" {
+		t.Fatalf("expected /ws then /events, got %+v", stats)
+	}
+	ws := stats[0]
+	if ws.Count != 2 || ws.AvgDuration != 2*time.Minute || ws.MaxDuration != 3*time.Minute || ws.AvgTTFB != 2*time.Millisecond {
+		t.Errorf("unexpected durations: %+v", ws)
+	}
+	if ws.Messages != 30 || ws.BytesSent != 400 {
+		t.Errorf("expected traffic summed, got %+v", ws)
+	}
+}
+
+func TestAPI_Connections(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	token := loginAndGetToken(t, router)
+	now := time.Now()
+
+	store := p.storage.(connectionStore)
+	store.StoreConnections([]ConnectionMetric{
+		{Kind: ConnectionWebSocket, Method: "GET", Path: "/ws", Duration: time.Minute, Messages: 4, Timestamp: now},
+		{Kind: ConnectionSSE, Method: "GET", Path: "/events", Duration: time.Second, BytesSent: 64, Timestamp: now},
+	})
+	p.connections.add(ConnectionSSE, 2)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/connections?range=1h&kind=sse", token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var overview ConnectionsOverview
+	if err := json.NewDecoder(w.Body).Decode(&overview); err != nil {
+		t.Fatal(err)
+	}
+	if len(overview.Routes) != 1 || overview.Routes[0].Path != "/events" || overview.Active[ConnectionSSE] != 2 {
+		t.Errorf("expected the SSE route and 2 open streams, got %+v", overview)
+	}
+
+	metrics := buildPrometheusMetrics(p)
+	for _, want := range []string{
+		`pulse_connections_active{kind="sse"} 2`,
+		`pulse_connections_total{kind="websocket",method="GET",path="/ws"} 1`,
+		`pulse_connection_messages_total{kind="websocket",method="GET",path="/ws"} 4`,
+		`pulse_connection_sent_bytes_total{kind="sse",method="GET",path="/events"} 64`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("expected %q in Prometheus output", want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package pulse
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -49,13 +50,26 @@ func newInstrumentedTransport(p *Pulse, rt http.RoundTripper, name string) *inst
 	}
 }
 
-// RoundTrip implements http.RoundTripper.
+// RoundTrip implements http.RoundTripper. Calls made within a trace become
+// client spans, and the trace is propagated downstream with the client span
+// as the parent.
 func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	_, span, traced := childSpanContext(ctx)
+
+	// RoundTrippers must not modify the caller's request, so headers go on a
+	// clone. A traceparent set by the caller is left alone.
+	if traced && isValidTraceID(span.TraceID) && req.Header.Get(TraceParentHeader) == "" {
+		req = req.Clone(ctx)
+		setTraceHeaders(req.Header, span)
+	}
+
 	start := time.Now()
 
 	resp, err := t.wrapped.RoundTrip(req)
 
 	latency := time.Since(start)
+	addDependencyTime(ctx, latency)
 
 	metric := DependencyMetric{
 		Name:        t.name,
@@ -63,6 +77,7 @@ func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 		URL:         req.URL.String(),
 		Latency:     latency,
 		RequestSize: req.ContentLength,
+		TraceID:     span.TraceID,
 		Timestamp:   start,
 	}
 
@@ -73,16 +88,50 @@ func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 		metric.ResponseSize = resp.ContentLength
 	}
 
-	// Store asynchronously
-	go func() {
-		if storeErr := t.pulse.storage.StoreDependencyMetric(metric); storeErr != nil && t.pulse.config.DevMode {
-			t.pulse.logger.Printf("[pulse] failed to store dependency metric: %v", storeErr)
-		}
-	}()
+	// Queue for storage
+	t.pulse.ingest(metric)
+
+	if traced {
+		t.pulse.recordSpan(ctx, dependencySpan(span, req, metric))
+	}
 
 	return resp, err
 }
 
+// dependencySpan builds the client span of an outbound call.
+func dependencySpan(tc TraceContext, req *http.Request, m DependencyMetric) Span {
+	// The query string may carry credentials; keep it out of the span
+	target := *req.URL
+	target.RawQuery, target.User = "", nil
+
+	span := Span{
+		TraceID:   tc.TraceID,
+		SpanID:    tc.SpanID,
+		ParentID:  tc.ParentSpanID,
+		Name:      m.Method + " " + m.Name,
+		Kind:      SpanKindClient,
+		StartTime: m.Timestamp,
+		Duration:  m.Latency,
+		Attributes: map[string]string{
+			"http.request.method": m.Method,
+			"url.full":            target.String(),
+			"server.address":      req.URL.Host,
+			"peer.service":        m.Name,
+		},
+		Status: SpanStatusOK,
+	}
+	switch {
+	case m.Error != "":
+		span.Status, span.StatusMessage = SpanStatusError, m.Error
+	case m.StatusCode >= 400:
+		span.Status, span.StatusMessage = SpanStatusError, http.StatusText(m.StatusCode)
+	}
+	if m.StatusCode != 0 {
+		span.Attributes["http.response.status_code"] = strconv.Itoa(m.StatusCode)
+	}
+	return span
+}
+
 // CircuitBreaker is an optional interface that wrapped transports can implement
 // to expose circuit breaker state to Pulse.
 type CircuitBreaker interface {
@@ -3,6 +3,7 @@ package pulse
 import (
 	"context"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 )
@@ -49,6 +50,43 @@ type Pulse struct {
 	// Alert engine
 	alertEngine *AlertEngine
 
+	// Ingestion queue (nil until mounted)
+	ingester *Ingester
+
+	// Retention worker
+	retention *RetentionWorker
+
+	// Memory storage snapshots (nil when disabled)
+	snapshotter *Snapshotter
+
+	// Agent mode: pushes metrics to a collector (nil when disabled)
+	agent *Agent
+
+	// Collector mode: tracks instances pushing metrics (nil when disabled)
+	collector *Collector
+
+	// OTLP exporter (nil when disabled)
+	otlp *OTLPExporter
+
+	// Route templates for requests no route matched
+	unmatched *unmatchedRoutes
+
+	// Requests whose handlers are running
+	inflight *inflightRegistry
+
+	// Open long-lived connections by kind
+	connections *activeConnections
+
+	// Scrubs personal data and secrets before storage (nil when disabled)
+	scrubber *scrubber
+
+	// Anonymizes client IPs before storage (nil when disabled)
+	anonymizer *ipAnonymizer
+
+	// Dashboard and API routes for HTTPHandler (built on first use)
+	handler     http.Handler
+	handlerOnce sync.Once
+
 	// Lifecycle management
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -69,6 +107,11 @@ func newPulse(cfg Config) *Pulse {
 		ctx:          ctx,
 		cancel:       cancel,
 		logger:       log.Default(),
+		unmatched:    newUnmatchedRoutes(cfg.Tracing.MaxUnmatchedRoutes),
+		inflight:     newInflightRegistry(cfg.Tracing.StuckRequestThreshold),
+		connections:  newActiveConnections(),
+		scrubber:     newScrubber(cfg.Privacy),
+		anonymizer:   newIPAnonymizer(cfg.Privacy),
 	}
 
 	return p
@@ -104,6 +147,18 @@ func (p *Pulse) Shutdown() error {
 	p.cancel()
 	p.wg.Wait()
 
+	if p.snapshotter != nil {
+		p.snapshotter.save()
+	}
+
+	// Push what the ingestion workers flushed on their way out
+	if p.agent != nil {
+		p.agent.flush()
+	}
+	if p.otlp != nil {
+		p.otlp.flush()
+	}
+
 	if p.storage != nil {
 		return p.storage.Close()
 	}
@@ -1,6 +1,7 @@
 package pulse
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -23,6 +24,9 @@ const (
 	ErrorTypeInternal   = "internal"
 )
 
+// maxErrorTraceIDs caps the trace IDs an error group keeps.
+const maxErrorTraceIDs = 20
+
 // Sensitive header names that should be redacted in request context.
 var sensitiveHeaders = map[string]bool{
 	"authorization":   true,
@@ -40,16 +44,10 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		// Capture request body early if configured (before it's consumed by handlers)
-		var bodyBytes []byte
-		if boolValue(cfg.CaptureRequestBody) && c.Request.Body != nil && c.Request.ContentLength > 0 {
-			maxSize := int64(cfg.MaxBodySize)
-			if c.Request.ContentLength < maxSize {
-				maxSize = c.Request.ContentLength
-			}
-			bodyBytes, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxSize))
-			// Restore the body so handlers can still read it
-			c.Request.Body = io.NopCloser(strings.NewReader(string(bodyBytes)))
-		}
+		body := captureRequestBody(c.Request, cfg)
+
+		// Share a tag set with the handlers so errors carry their tags
+		c.Request = c.Request.WithContext(contextWithRequestTags(c.Request.Context()))
 
 		// Panic recovery
 		defer func() {
@@ -62,10 +60,7 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 
 				// Get trace ID and route
 				traceID := TraceIDFromContext(c.Request.Context())
-				routePattern := c.FullPath()
-				if routePattern == "" {
-					routePattern = c.Request.URL.Path
-				}
+				routePattern := p.routePattern(c)
 
 				// Build and store error record
 				record := buildErrorRecord(
@@ -74,14 +69,11 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 					errMsg,
 					ErrorTypePanic,
 					stack,
-					captureRequestContext(c, bodyBytes),
+					captureRequestContext(c, body.bytes()),
 					traceID,
 				)
-
-				if err := p.storage.StoreError(record); err != nil && p.config.DevMode {
-					p.logger.Printf("[pulse] failed to store panic error: %v", err)
-				}
-				p.BroadcastError(record)
+				record.Tags = tagValues(TagsFromContext(c.Request.Context()))
+				p.storePanic(record)
 
 				// Abort with 500
 				c.AbortWithStatus(http.StatusInternalServerError)
@@ -93,11 +85,9 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 
 		// After handler: capture errors from c.Errors and status codes
 		statusCode := c.Writer.Status()
-		routePattern := c.FullPath()
-		if routePattern == "" {
-			routePattern = c.Request.URL.Path
-		}
+		routePattern := p.routePattern(c)
 		traceID := TraceIDFromContext(c.Request.Context())
+		tags := tagValues(TagsFromContext(c.Request.Context()))
 
 		// Capture Gin errors (set via c.Error())
 		if len(c.Errors) > 0 {
@@ -116,20 +106,16 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 					errMsg,
 					errType,
 					stack,
-					captureRequestContext(c, bodyBytes),
+					captureRequestContext(c, body.bytes()),
 					traceID,
 				)
+				record.Tags = tags
 
-				go func(r ErrorRecord) {
-					if err := p.storage.StoreError(r); err != nil && p.config.DevMode {
-						p.logger.Printf("[pulse] failed to store error: %v", err)
-					}
-					p.BroadcastError(r)
-				}(record)
+				p.ingest(record)
 			}
 		} else if statusCode >= 500 {
 			// No explicit Gin errors, but 5xx status code — record as internal error
-			errMsg := fmt.Sprintf("HTTP %d: %s", statusCode, http.StatusText(statusCode))
+			errMsg := statusErrorMessage(statusCode)
 			errType := classifyError(errMsg, statusCode)
 
 			var stack string
@@ -143,25 +129,65 @@ func newErrorMiddleware(p *Pulse) gin.HandlerFunc {
 				errMsg,
 				errType,
 				stack,
-				captureRequestContext(c, bodyBytes),
+				captureRequestContext(c, body.bytes()),
 				traceID,
 			)
+			record.Tags = tags
 
-			go func(r ErrorRecord) {
-				if err := p.storage.StoreError(r); err != nil && p.config.DevMode {
-					p.logger.Printf("[pulse] failed to store error: %v", err)
-				}
-				p.BroadcastError(r)
-			}(record)
+			p.ingest(record)
 		}
 	}
 }
 
+// captureRequestBody captures the request body for error context when
+// configured. A body of known length is read up front and restored so
+// handlers can still read it; a chunked body is captured as the handler
+// reads it.
+func captureRequestBody(r *http.Request, cfg ErrorConfig) *cappedBuffer {
+	if !boolValue(cfg.CaptureRequestBody) || r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	if r.ContentLength < 0 {
+		return teeRequestBody(r, cfg.MaxBodySize)
+	}
+	maxSize := int64(cfg.MaxBodySize)
+	if r.ContentLength < maxSize {
+		maxSize = r.ContentLength
+	}
+	bodyBytes, _ := io.ReadAll(io.LimitReader(r.Body, maxSize))
+	// Restore the body so handlers can still read it
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyBytes), r.Body))
+	return &cappedBuffer{buf: bodyBytes, max: cfg.MaxBodySize, truncated: r.ContentLength > maxSize}
+}
+
+// statusErrorMessage is the error message of a 5xx response without an
+// explicit error.
+func statusErrorMessage(statusCode int) string {
+	return fmt.Sprintf("HTTP %d: %s", statusCode, http.StatusText(statusCode))
+}
+
+// storePanic stores a panic's error record right away rather than queueing
+// it, since the process may be about to crash, and broadcasts it.
+func (p *Pulse) storePanic(record ErrorRecord) {
+	record = p.anonymizer.errorRecord(p.scrubber.errorRecord(record))
+	if err := p.storage.StoreError(record); err != nil && p.config.DevMode {
+		p.logger.Printf("[pulse] failed to store panic error: %v", err)
+	}
+	p.BroadcastError(record)
+}
+
 // buildErrorRecord constructs a complete ErrorRecord with fingerprint and timestamps.
 func buildErrorRecord(method, route, errMsg, errType, stack string, reqCtx *RequestContext, traceID string) ErrorRecord {
 	now := time.Now()
 	fingerprint := generateFingerprint(method, route, errMsg)
 
+	var traceIDs []string
+	if traceID != "" {
+		traceIDs = []string{traceID}
+		if reqCtx != nil {
+			reqCtx.TraceID = traceID
+		}
+	}
 	return ErrorRecord{
 		ID:             GenerateTraceID(), // reuse trace ID generator for unique IDs
 		Fingerprint:    fingerprint,
@@ -174,7 +200,23 @@ func buildErrorRecord(method, route, errMsg, errType, stack string, reqCtx *Requ
 		Count:          1,
 		FirstSeen:      now,
 		LastSeen:       now,
+		TraceIDs:       traceIDs,
+	}
+}
+
+// mergeTraceIDs appends the trace IDs of src to dst, keeping the latest
+// maxErrorTraceIDs. dst is not modified.
+func mergeTraceIDs(dst, src []string) []string {
+	merged := dst
+	for _, id := range src {
+		if id != "" && !containsString(merged, id) {
+			merged = append(merged[:len(merged):len(merged)], id)
+		}
+	}
+	if len(merged) > maxErrorTraceIDs {
+		merged = merged[len(merged)-maxErrorTraceIDs:]
 	}
+	return merged
 }
 
 // generateFingerprint creates a stable hash from method+route+error message for dedup.
@@ -286,24 +328,19 @@ func shouldSkipFrame(fn string) bool {
 
 // captureRequestContext builds a RequestContext from the Gin context with sensitive data redacted.
 func captureRequestContext(c *gin.Context, bodyBytes []byte) *RequestContext {
-	headers := make(map[string]string)
-	for key, values := range c.Request.Header {
-		lowerKey := strings.ToLower(key)
-		if sensitiveHeaders[lowerKey] {
-			headers[key] = "[REDACTED]"
-		} else if len(values) > 0 {
-			headers[key] = values[0]
-		}
-	}
+	return newRequestContext(c.Request, c.ClientIP(), bodyBytes)
+}
 
+// newRequestContext builds a RequestContext from the request with sensitive data redacted.
+func newRequestContext(r *http.Request, clientIP string, bodyBytes []byte) *RequestContext {
 	reqCtx := &RequestContext{
-		Method:      c.Request.Method,
-		Path:        c.Request.URL.Path,
-		Query:       c.Request.URL.RawQuery,
-		Headers:     headers,
-		ClientIP:    c.ClientIP(),
-		UserAgent:   c.Request.UserAgent(),
-		ContentType: c.ContentType(),
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       r.URL.RawQuery,
+		Headers:     redactHeaders(r.Header),
+		ClientIP:    clientIP,
+		UserAgent:   r.UserAgent(),
+		ContentType: contentType(r),
 	}
 
 	if len(bodyBytes) > 0 {
@@ -312,3 +349,12 @@ func captureRequestContext(c *gin.Context, bodyBytes []byte) *RequestContext {
 
 	return reqCtx
 }
+
+// contentType returns the request's media type without parameters.
+func contentType(r *http.Request) string {
+	ct := r.Header.Get("Content-Type")
+	if i := strings.IndexAny(ct, "; "); i >= 0 {
+		ct = ct[:i]
+	}
+	return ct
+}
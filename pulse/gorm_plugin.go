@@ -3,6 +3,7 @@ package pulse
 import (
 	"context"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -93,7 +94,7 @@ func (p *PulsePlugin) Initialize(db *gorm.DB) error {
 
 // beforeCallback records the start time in the GORM statement context.
 func (p *PulsePlugin) beforeCallback(db *gorm.DB) {
-	if db == nil || db.Statement == nil {
+	if db == nil || db.Statement == nil || isInternalQuery(db.Statement.Context) {
 		return
 	}
 	db.Set(startTimeKey, time.Now())
@@ -101,7 +102,7 @@ func (p *PulsePlugin) beforeCallback(db *gorm.DB) {
 
 // afterCallback captures query metrics after execution.
 func (p *PulsePlugin) afterCallback(db *gorm.DB) {
-	if db == nil || db.Statement == nil {
+	if db == nil || db.Statement == nil || isInternalQuery(db.Statement.Context) {
 		return
 	}
 
@@ -139,10 +140,13 @@ func (p *PulsePlugin) afterCallback(db *gorm.DB) {
 		callerFile, callerLine = findCaller()
 	}
 
-	// Get trace ID from context
+	// Get trace ID and request tags from context
 	var traceID string
+	var tags map[string]string
 	if db.Statement.Context != nil {
 		traceID = TraceIDFromContext(db.Statement.Context)
+		tags = TagsFromContext(db.Statement.Context)
+		addQueryTime(db.Statement.Context, duration)
 	}
 
 	metric := QueryMetric{
@@ -156,15 +160,23 @@ func (p *PulsePlugin) afterCallback(db *gorm.DB) {
 		CallerFile:     callerFile,
 		CallerLine:     callerLine,
 		RequestTraceID: traceID,
+		Tags:           tags,
 		Timestamp:      startTime,
 	}
 
-	// Store asynchronously
-	go func() {
-		if err := p.pulse.storage.StoreQuery(metric); err != nil && p.pulse.config.DevMode {
-			p.pulse.logger.Printf("[pulse] failed to store query metric: %v", err)
+	// Queue for storage
+	p.pulse.ingest(metric)
+
+	// Queries within a trace become client spans of the request
+	if ctx := db.Statement.Context; ctx != nil {
+		if _, span, ok := childSpanContext(ctx); ok {
+			var system string
+			if db.Dialector != nil {
+				system = db.Dialector.Name()
+			}
+			p.pulse.recordSpan(ctx, querySpan(span, metric, system))
 		}
-	}()
+	}
 
 	// N+1 detection
 	if boolValue(cfg.DetectN1) && traceID != "" && normalized.Normalized != "" {
@@ -172,6 +184,44 @@ func (p *PulsePlugin) afterCallback(db *gorm.DB) {
 	}
 }
 
+// querySpan builds the client span of a query against the given database
+// system. The statement is recorded in normalized form so bound values do
+// not end up in traces.
+func querySpan(tc TraceContext, m QueryMetric, system string) Span {
+	name := m.Operation
+	if name == "" {
+		name = "query"
+	}
+	if m.Table != "" {
+		name += " " + m.Table
+	}
+
+	attrs := map[string]string{
+		"db.statement":     m.NormalizedSQL,
+		"db.operation":     m.Operation,
+		"db.rows_affected": strconv.FormatInt(m.RowsAffected, 10),
+	}
+	if m.Table != "" {
+		attrs["db.sql.table"] = m.Table
+	}
+	if system != "" {
+		attrs["db.system"] = system
+	}
+
+	return Span{
+		TraceID:       tc.TraceID,
+		SpanID:        tc.SpanID,
+		ParentID:      tc.ParentSpanID,
+		Name:          name,
+		Kind:          SpanKindClient,
+		StartTime:     m.Timestamp,
+		Duration:      m.Duration,
+		Attributes:    attrs,
+		Status:        spanStatus(m.Error),
+		StatusMessage: m.Error,
+	}
+}
+
 // trackN1 detects N+1 query patterns within a single request.
 func (p *PulsePlugin) trackN1(traceID, normalizedSQL string, duration time.Duration, db *gorm.DB) {
 	p.n1TrackerMu.Lock()
@@ -214,9 +264,8 @@ func (p *PulsePlugin) trackN1(traceID, normalizedSQL string, duration time.Durat
 			DetectedAt:     time.Now(),
 		}
 
-		// Store via MemoryStorage's N+1 specific method
-		if ms, ok := p.pulse.storage.(*MemoryStorage); ok {
-			ms.StoreN1Detection(detection)
+		if err := p.pulse.storage.StoreN1Detection(detection); err != nil && p.pulse.config.DevMode {
+			p.pulse.logger.Printf("[pulse] failed to store N+1 detection: %v", err)
 		}
 
 		if p.pulse.config.DevMode {
@@ -263,16 +312,32 @@ func (p *PulsePlugin) startPoolMonitoring(db *gorm.DB) {
 					MaxIdleClosed:      stats.MaxIdleClosed,
 					MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
 					MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+					Timestamp:          time.Now(),
 				}
 
-				if ms, ok := p.pulse.storage.(*MemoryStorage); ok {
-					ms.UpdatePoolStats(poolStats)
+				if err := p.pulse.storage.StorePoolStats(poolStats); err != nil && p.pulse.config.DevMode {
+					p.pulse.logger.Printf("[pulse] failed to store pool stats: %v", err)
 				}
 			}
 		}
 	})
 }
 
+// withInternalQuery marks a context so PulsePlugin ignores queries issued by
+// Pulse itself (e.g. GormStorage writes on the application's database).
+func withInternalQuery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalQueryKey, true)
+}
+
+// isInternalQuery reports whether ctx was marked by withInternalQuery.
+func isInternalQuery(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	internal, _ := ctx.Value(internalQueryKey).(bool)
+	return internal
+}
+
 // findCaller walks the call stack to find the first frame outside of
 // GORM internals and the pulse package itself.
 func findCaller() (string, int) {
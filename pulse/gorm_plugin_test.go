@@ -246,6 +246,26 @@ func TestGormPlugin_TraceIDLinksToRequest(t *testing.T) {
 	}
 }
 
+func TestGormPlugin_RecordsRequestTags(t *testing.T) {
+	db, p := setupTestPulseWithDB(t)
+
+	ctx := contextWithRequestTags(context.Background())
+	SetTag(ctx, "tenant", "acme")
+	db.WithContext(ctx).Create(&TestUser{Name: "Frank", Age: 41})
+	time.Sleep(50 * time.Millisecond)
+
+	queries, _ := p.storage.GetSlowQueries(0, 100)
+	found := false
+	for _, q := range queries {
+		if q.Tags["tenant"] == "acme" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected query to carry the request's tags")
+	}
+}
+
 func TestGormPlugin_NormalizesSQL(t *testing.T) {
 	db, p := setupTestPulseWithDB(t)
 
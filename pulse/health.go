@@ -165,12 +165,10 @@ func (hr *HealthRunner) updateComposite() {
 	copy(checks, hr.pulse.healthChecks)
 	hr.pulse.healthMu.RUnlock()
 
-	if ms, ok := hr.pulse.storage.(*MemoryStorage); ok {
-		state := computeCompositeHealth(hr.pulse, ms)
-		hr.mu.Lock()
-		hr.compositeState = state
-		hr.mu.Unlock()
-	}
+	state := computeCompositeHealth(hr.pulse, hr.pulse.storage)
+	hr.mu.Lock()
+	hr.compositeState = state
+	hr.mu.Unlock()
 }
 
 // detectFlapping checks if a health check is alternating between healthy/unhealthy.
@@ -290,14 +288,12 @@ func buildHealthResponse(p *Pulse) HealthResponse {
 		Checks:    make(map[string]HealthCheckResponse),
 	}
 
-	ms, ok := p.storage.(*MemoryStorage)
-	if !ok {
+	latestResults, err := p.storage.GetLatestHealthResults()
+	if err != nil {
 		resp.Status = "healthy"
 		return resp
 	}
 
-	latestResults := ms.getLatestHealthResults()
-
 	p.healthMu.RLock()
 	checks := make([]HealthCheck, len(p.healthChecks))
 	copy(checks, p.healthChecks)
@@ -0,0 +1,204 @@
+package pulse
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Handler returns net/http middleware that traces requests and tracks errors
+// like the Gin middleware Mount installs, for services built on net/http,
+// chi or other routers:
+//
+//	p := pulse.New(db, pulse.Config{})
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("GET /users/{id}", getUser)
+//	http.ListenAndServe(":8080", pulse.Handler(p, mux))
+//
+// Requests are grouped by the route pattern TracingConfig.RouteFunc returns,
+// which defaults to ServeMuxPattern.
+func Handler(p *Pulse, next http.Handler) http.Handler {
+	tracer := newRequestTracer(p)
+	errCfg := p.config.Errors
+	trackErrors := boolValue(errCfg.Enabled)
+	routeFunc := p.config.Tracing.RouteFunc
+	if routeFunc == nil {
+		routeFunc = ServeMuxPattern
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body *cappedBuffer
+		if trackErrors {
+			body = captureRequestBody(r, errCfg)
+		}
+
+		// Share a tag set with the handler so errors carry its tags
+		r = r.WithContext(contextWithRequestTags(r.Context()))
+		tr, r := tracer.begin(r, w.Header(), "", remoteIP(r))
+		if tr != nil {
+			defer tr.release() // also when the handler panics
+		}
+		sw := &statusWriter{ResponseWriter: w, statusCode: http.StatusOK, tr: tr}
+
+		route := func() string {
+			if route := routeFunc(r); route != "" {
+				return route
+			}
+			return p.unmatched.route(r.URL.Path)
+		}
+
+		panicked := false
+		func() {
+			if !trackErrors {
+				next.ServeHTTP(sw, r)
+				return
+			}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered) // the server's signal to abort silently
+				}
+				panicked = true
+				stack := captureStackTrace(3) // skip recover, defer, runtime.gopanic
+				record := buildErrorRecord(
+					r.Method,
+					route(),
+					fmt.Sprintf("%v", recovered),
+					ErrorTypePanic,
+					stack,
+					newRequestContext(r, remoteIP(r), body.bytes()),
+					TraceIDFromContext(r.Context()),
+				)
+				record.Tags = tagValues(TagsFromContext(r.Context()))
+				p.storePanic(record)
+				if !sw.written {
+					sw.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(sw, r)
+		}()
+
+		routePattern := route()
+		if trackErrors && !panicked && sw.statusCode >= 500 {
+			// No explicit error in net/http — record 5xx as internal error
+			errMsg := statusErrorMessage(sw.statusCode)
+			var stack string
+			if boolValue(errCfg.CaptureStackTrace) {
+				stack = captureStackTrace(2)
+			}
+			record := buildErrorRecord(
+				r.Method,
+				routePattern,
+				errMsg,
+				classifyError(errMsg, sw.statusCode),
+				stack,
+				newRequestContext(r, remoteIP(r), body.bytes()),
+				TraceIDFromContext(r.Context()),
+			)
+			record.Tags = tagValues(TagsFromContext(r.Context()))
+			p.ingest(record)
+		}
+
+		if tr != nil {
+			tr.end(r, requestOutcome{
+				route:        routePattern,
+				status:       sw.statusCode,
+				responseSize: sw.bytesWritten,
+				clientIP:     remoteIP(r),
+			})
+		}
+	})
+}
+
+// Middleware returns Handler as a func(http.Handler) http.Handler, the
+// middleware signature chi and most net/http routers accept.
+func Middleware(p *Pulse) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Handler(p, next)
+	}
+}
+
+// ServeMuxPattern returns the path of the http.ServeMux pattern that matched
+// r (e.g., "/users/{id}" for "GET example.com/users/{id}"), or "" when no
+// pattern matched. It is the default TracingConfig.RouteFunc.
+func ServeMuxPattern(r *http.Request) string {
+	pattern := r.Pattern
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " \t") // drop the method
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:] // drop the host
+	}
+	return pattern
+}
+
+// remoteIP returns the IP address of the request's peer.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusWriter wraps an http.ResponseWriter to capture the status code and
+// bytes written, and to detect hijacked and streamed responses.
+type statusWriter struct {
+	http.ResponseWriter
+	statusCode   int
+	bytesWritten int64
+	written      bool
+	tr           *tracedRequest // nil when the request is not traced
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.written {
+		w.statusCode = code
+		w.written = true
+	}
+	w.tr.wrote()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	w.written = true
+	w.tr.wrote()
+	n, err := w.ResponseWriter.Write(data)
+	w.bytesWritten += int64(n)
+	w.tr.captureResponse(data[:n])
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush implements http.Flusher for streaming responses, which are recorded
+// as connections instead of requests.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		w.tr.flushed(w.Header())
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for WebSocket support. The request is
+// recorded as a connection instead.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("pulse: %T does not support hijacking", w.ResponseWriter)
+	}
+	conn, brw, err := h.Hijack()
+	if err != nil {
+		return conn, brw, err
+	}
+	return w.tr.hijacked(conn), brw, nil
+}
@@ -0,0 +1,175 @@
+package pulse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestHTTPPulse(t *testing.T, cfg Config) *Pulse {
+	t.Helper()
+	cfg.Dashboard = DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"}
+	p := New(nil, cfg)
+	t.Cleanup(func() { p.Shutdown() })
+	return p
+}
+
+func TestHandler_TracesServeMux(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := TraceContextFromContext(r.Context()); !ok {
+			t.Error("expected trace context in handler")
+		}
+		w.Write([]byte(`{"id":"` + r.PathValue("id") + `"}`))
+	})
+	mux.HandleFunc("POST /orders", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	handler := Handler(p, mux)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+	if w.Code != http.StatusOK || w.Header().Get(TraceIDHeader) == "" {
+		t.Fatalf("expected 200 with a trace ID, got %d %v", w.Code, w.Header())
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/orders", strings.NewReader(`{"sku":"a1"}`)))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing/7", nil))
+
+	reqs := waitForRequests(t, p, 3)
+	routes := make(map[string]RequestMetric)
+	for _, r := range reqs {
+		routes[r.Method+" "+r.Path] = r
+	}
+	if r, ok := routes["GET /users/{id}"]; !ok || r.StatusCode != 200 || r.ResponseSize == 0 || r.ClientIP != "192.0.2.1" {
+		t.Errorf("unexpected metric for the ServeMux route: %+v", routes)
+	}
+	if r, ok := routes["POST /orders"]; !ok || r.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the 503 to be recorded, got %+v", routes)
+	}
+	if _, ok := routes["GET /missing/:id"]; !ok {
+		t.Errorf("expected the unmatched path to be templated, got %+v", routes)
+	}
+
+	// 5xx responses are tracked as errors with the request context
+	errs := waitForErrors(t, p, 1)
+	if errs[0].Route != "/orders" || errs[0].RequestContext == nil || errs[0].RequestContext.Body != `{"sku":"a1"}` {
+		t.Errorf("unexpected error record: %+v", errs[0])
+	}
+}
+
+func TestHandler_RecoversPanics(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	})
+
+	w := httptest.NewRecorder()
+	Handler(p, mux).ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+
+	errs := waitForErrors(t, p, 1)
+	if errs[0].ErrorType != ErrorTypePanic || errs[0].Route != "/panic" || errs[0].StackTrace == "" {
+		t.Errorf("unexpected panic record: %+v", errs[0])
+	}
+	reqs := waitForRequests(t, p, 1)
+	if reqs[0].StatusCode != http.StatusInternalServerError || reqs[0].TraceID != w.Header().Get(TraceIDHeader) {
+		t.Errorf("expected the panicking request to be recorded as a 500, got %+v", reqs[0])
+	}
+}
+
+// routeKey stands in for a router, like chi, that records the matched
+// pattern in a context value it shares with middleware.
+type routeKey struct{}
+
+func TestHandler_RouteFunc(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{Tracing: TracingConfig{
+		RouteFunc: func(r *http.Request) string {
+			return *r.Context().Value(routeKey{}).(*string)
+		},
+	}})
+
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*r.Context().Value(routeKey{}).(*string) = "/articles/{slug}"
+	})
+	handler := Middleware(p)(router)
+
+	var pattern string
+	req := httptest.NewRequest("GET", "/articles/hello-world", nil)
+	req = req.WithContext(context.WithValue(req.Context(), routeKey{}, &pattern))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	reqs := waitForRequests(t, p, 1)
+	if reqs[0].Path != "/articles/{slug}" {
+		t.Errorf("expected route from RouteFunc, got %q", reqs[0].Path)
+	}
+}
+
+func TestServeMuxPattern(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"/users/{id}":               "/users/{id}",
+		"GET /users/{id}":           "/users/{id}",
+		"GET example.com/users/{$}": "/users/{$}",
+		"api.example.com/":          "/",
+	}
+	for pattern, want := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Pattern = pattern
+		if got := ServeMuxPattern(r); got != want {
+			t.Errorf("ServeMuxPattern(%q): expected %q, got %q", pattern, want, got)
+		}
+	}
+}
+
+func TestHTTPHandler_ServesAPI(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{})
+
+	mux := http.NewServeMux()
+	mux.Handle("/pulse/", p.HTTPHandler())
+	handler := Handler(p, mux)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/pulse/api/auth/login", strings.NewReader(`{"username":"admin","password":"testpass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "token") {
+		t.Fatalf("expected login through the mux, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pulse/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected health endpoint, got %d", w.Code)
+	}
+
+	// Pulse's own endpoints are not traced
+	time.Sleep(50 * time.Millisecond)
+	if reqs, _ := p.storage.GetRequests(RequestFilter{}); len(reqs) != 0 {
+		t.Errorf("expected no traced requests, got %d", len(reqs))
+	}
+}
+
+// waitForErrors polls until at least n error groups are stored.
+func waitForErrors(t *testing.T, p *Pulse, n int) []ErrorRecord {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		errs, _ := p.storage.GetErrors(ErrorFilter{})
+		if len(errs) >= n {
+			return errs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d stored errors, got %d", n, len(errs))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,250 @@
+package pulse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxRecentStuck caps the finished stuck requests kept for diagnosis.
+	maxRecentStuck = 20
+	// maxStuckStackLen truncates longer goroutine stacks.
+	maxStuckStackLen = 64 << 10
+)
+
+// InFlightRequest is a traced request whose handler is still running.
+type InFlightRequest struct {
+	TraceID   string        `json:"trace_id"`
+	Method    string        `json:"method"`
+	Route     string        `json:"route"` // route pattern, "" when matched after the middleware runs
+	Path      string        `json:"path"`
+	ClientIP  string        `json:"client_ip"`
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"` // running time, or total time once finished
+	Stuck     bool          `json:"stuck"`
+	Stack     string        `json:"stack,omitempty"` // goroutine stack captured when flagged stuck
+}
+
+// InFlightStatus is a snapshot of the in-flight request registry.
+type InFlightStatus struct {
+	Count          int               `json:"count"`
+	StuckCount     int               `json:"stuck_count"`
+	StuckThreshold time.Duration     `json:"stuck_threshold"`
+	Requests       []InFlightRequest `json:"requests"`     // oldest first
+	RecentStuck    []InFlightRequest `json:"recent_stuck"` // finished stuck requests, newest first
+}
+
+// inflightRegistry tracks the requests whose handlers are running. Requests
+// running longer than the threshold are flagged as stuck and their goroutine
+// stacks captured, so hung handlers show up before they finish.
+type inflightRegistry struct {
+	threshold time.Duration // <= 0 disables stuck detection
+
+	mu          sync.Mutex
+	nextID      uint64
+	requests    map[uint64]*inflightEntry
+	recentStuck []InFlightRequest
+
+	stuckTotal atomic.Int64
+}
+
+type inflightEntry struct {
+	req  InFlightRequest
+	goid int64 // handler goroutine, 0 when stuck detection is off
+}
+
+func newInflightRegistry(threshold time.Duration) *inflightRegistry {
+	return &inflightRegistry{threshold: threshold, requests: make(map[uint64]*inflightEntry)}
+}
+
+// add registers a request running on the calling goroutine and returns its
+// registry ID.
+func (r *inflightRegistry) add(req InFlightRequest) uint64 {
+	var goid int64
+	if r.threshold > 0 {
+		goid = goroutineID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.requests[r.nextID] = &inflightEntry{req: req, goid: goid}
+	return r.nextID
+}
+
+// remove unregisters a finished request. Stuck requests are kept among the
+// recent stuck requests with their total duration.
+func (r *inflightRegistry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.requests[id]
+	if !ok {
+		return
+	}
+	delete(r.requests, id)
+
+	if e.req.Stuck {
+		e.req.Duration = time.Since(e.req.StartTime)
+		r.recentStuck = append([]InFlightRequest{e.req}, r.recentStuck...)
+		if len(r.recentStuck) > maxRecentStuck {
+			r.recentStuck = r.recentStuck[:maxRecentStuck]
+		}
+	}
+}
+
+// status returns the in-flight requests, oldest first.
+func (r *inflightRegistry) status() InFlightStatus {
+	now := time.Now()
+
+	r.mu.Lock()
+	s := InFlightStatus{
+		StuckThreshold: r.threshold,
+		Requests:       make([]InFlightRequest, 0, len(r.requests)),
+		RecentStuck:    append([]InFlightRequest{}, r.recentStuck...),
+	}
+	for _, e := range r.requests {
+		req := e.req
+		req.Duration = now.Sub(req.StartTime)
+		s.Requests = append(s.Requests, req)
+		if req.Stuck {
+			s.StuckCount++
+		}
+	}
+	r.mu.Unlock()
+
+	s.Count = len(s.Requests)
+	sort.Slice(s.Requests, func(i, j int) bool {
+		return s.Requests[i].StartTime.Before(s.Requests[j].StartTime)
+	})
+	return s
+}
+
+// counts returns the number of in-flight and stuck requests.
+func (r *inflightRegistry) counts() (inflight, stuck int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.requests {
+		if e.req.Stuck {
+			stuck++
+		}
+	}
+	return len(r.requests), stuck
+}
+
+// detectStuck flags requests that have been running for at least the
+// threshold and captures their goroutine stacks. It returns the newly
+// flagged requests.
+func (r *inflightRegistry) detectStuck(now time.Time) []InFlightRequest {
+	r.mu.Lock()
+	var stuck []uint64
+	for id, e := range r.requests {
+		if !e.req.Stuck && now.Sub(e.req.StartTime) >= r.threshold {
+			stuck = append(stuck, id)
+		}
+	}
+	r.mu.Unlock()
+	if len(stuck) == 0 {
+		return nil
+	}
+
+	// One dump serves every newly stuck request. It stops the world, so it
+	// runs outside the lock every request takes on its way in and out.
+	stacks := goroutineStacks()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	flagged := make([]InFlightRequest, 0, len(stuck))
+	for _, id := range stuck {
+		e, ok := r.requests[id]
+		if !ok || e.req.Stuck {
+			continue // finished during the dump
+		}
+		e.req.Stuck = true
+		e.req.Stack = stacks[e.goid]
+		req := e.req
+		req.Duration = now.Sub(req.StartTime)
+		flagged = append(flagged, req)
+	}
+	r.stuckTotal.Add(int64(len(flagged)))
+	return flagged
+}
+
+// startStuckRequestMonitor periodically flags stuck requests and logs them.
+func startStuckRequestMonitor(p *Pulse) {
+	threshold := p.inflight.threshold
+	if threshold <= 0 || !boolValue(p.config.Tracing.Enabled) {
+		return
+	}
+	interval := threshold / 2
+	if interval > time.Second {
+		interval = time.Second
+	}
+
+	p.startBackground("stuck-requests", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, req := range p.inflight.detectStuck(now) {
+					route := req.Route
+					if route == "" {
+						route = req.Path
+					}
+					p.logger.Printf("[pulse] stuck request: %s %s running for %s (trace %s)",
+						req.Method, route, req.Duration.Round(time.Millisecond), req.TraceID)
+				}
+			}
+		}
+	})
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine 42 [running]:" header of its stack.
+func goroutineID() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(string(fields[1]), 10, 64)
+	return id
+}
+
+// goroutineStacks returns the stacks of all goroutines by goroutine ID.
+func goroutineStacks() map[int64]string {
+	buf := make([]byte, 256<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[int64]string)
+	for _, g := range strings.Split(string(buf), "\n\n") {
+		var id int64
+		if _, err := fmt.Sscanf(g, "goroutine %d ", &id); err != nil {
+			continue
+		}
+		if len(g) > maxStuckStackLen {
+			g = g[:maxStuckStackLen]
+		}
+		stacks[id] = g
+	}
+	return stacks
+}
@@ -0,0 +1,131 @@
+package pulse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInflightRegistry_DetectsStuck(t *testing.T) {
+	r := newInflightRegistry(time.Minute)
+	start := time.Now()
+
+	registered := make(chan uint64)
+	release := make(chan struct{})
+	go func() {
+		registered <- r.add(InFlightRequest{TraceID: "t1", Method: "GET", Path: "/hang", StartTime: start})
+		<-release
+	}()
+	id := <-registered
+	defer close(release)
+	r.add(InFlightRequest{TraceID: "t2", Method: "GET", Path: "/fast", StartTime: start.Add(30 * time.Second)})
+
+	if flagged := r.detectStuck(start.Add(30 * time.Second)); len(flagged) != 0 {
+		t.Fatalf("expected nothing stuck before the threshold, got %+v", flagged)
+	}
+	flagged := r.detectStuck(start.Add(time.Minute))
+	if len(flagged) != 1 || flagged[0].TraceID != "t1" || flagged[0].Duration != time.Minute {
+		t.Fatalf("expected t1 flagged after a minute, got %+v", flagged)
+	}
+	if !strings.Contains(flagged[0].Stack, "TestInflightRegistry_DetectsStuck") {
+		t.Errorf("expected the handler goroutine's stack, got %q", flagged[0].Stack)
+	}
+	if again := r.detectStuck(start.Add(2 * time.Minute)); len(again) != 1 || again[0].TraceID != "t2" {
+		t.Errorf("expected only t2 to be newly flagged, got %+v", again)
+	}
+	if inflight, stuck := r.counts(); inflight != 2 || stuck != 2 || r.stuckTotal.Load() != 2 {
+		t.Errorf("expected 2 in flight and 2 stuck, got %d / %d", inflight, stuck)
+	}
+
+	r.remove(id)
+	s := r.status()
+	if s.Count != 1 || s.Requests[0].TraceID != "t2" {
+		t.Errorf("expected t2 still in flight, got %+v", s.Requests)
+	}
+	if len(s.RecentStuck) != 1 || s.RecentStuck[0].TraceID != "t1" || s.RecentStuck[0].Stack == "" {
+		t.Errorf("expected t1 kept among recent stuck requests, got %+v", s.RecentStuck)
+	}
+}
+
+func TestMiddleware_TracksInflightRequests(t *testing.T) {
+	router, p := setupTestRouter(Config{
+		Dashboard: DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"},
+		Tracing:   TracingConfig{StuckRequestThreshold: 20 * time.Millisecond},
+	})
+	defer p.Shutdown()
+	token := loginAndGetToken(t, router)
+
+	release := make(chan struct{})
+	router.GET("/hang/:id", func(c *gin.Context) {
+		<-release
+		c.Status(http.StatusOK)
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/hang/1", nil))
+	}()
+
+	getStatus := func() InFlightStatus {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/pulse/api/requests/inflight?stuck=true", token, ""))
+		var s InFlightStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+			t.Fatalf("decode: %v (%s)", err, w.Body.String())
+		}
+		return s
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var s InFlightStatus
+	for s = getStatus(); s.StuckCount == 0; s = getStatus() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the hanging request to be flagged as stuck, got %+v", s)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(s.Requests) != 1 || s.Requests[0].Route != "/hang/:id" || s.Requests[0].Path != "/hang/1" || s.Requests[0].TraceID == "" {
+		t.Errorf("unexpected stuck request: %+v", s.Requests)
+	}
+	if !strings.Contains(s.Requests[0].Stack, "TestMiddleware_TracksInflightRequests") {
+		t.Errorf("expected the handler's stack, got %q", s.Requests[0].Stack)
+	}
+
+	metrics := buildPrometheusMetrics(p)
+	for _, want := range []string{"pulse_http_requests_in_flight 1", "pulse_http_requests_stuck 1", "pulse_http_stuck_requests_total 1"} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("expected %q in Prometheus output", want)
+		}
+	}
+
+	close(release)
+	<-done
+	s = getStatus()
+	if s.Count != 0 || len(s.RecentStuck) != 1 || s.RecentStuck[0].Duration < 20*time.Millisecond {
+		t.Errorf("expected the finished request among recent stuck requests, got %+v", s)
+	}
+}
+
+func TestHandler_UntracksPanickingRequests(t *testing.T) {
+	p := newTestHTTPPulse(t, Config{Errors: ErrorConfig{Enabled: boolPtr(false)}})
+	handler := Handler(p, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inflight, _ := p.inflight.counts(); inflight != 1 {
+			t.Errorf("expected the request in flight, got %d", inflight)
+		}
+		panic(http.ErrAbortHandler)
+	}))
+
+	func() {
+		defer func() { recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	}()
+	if inflight, _ := p.inflight.counts(); inflight != 0 {
+		t.Errorf("expected the aborted request to be removed, got %d in flight", inflight)
+	}
+}
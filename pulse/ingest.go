@@ -0,0 +1,349 @@
+package pulse
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricBatch is a group of metrics written to storage together.
+type MetricBatch struct {
+	Requests     []RequestMetric
+	Queries      []QueryMetric
+	Dependencies []DependencyMetric
+	Errors       []ErrorRecord
+	Spans        []Span
+	Connections  []ConnectionMetric
+}
+
+// Len returns the number of metrics in the batch.
+func (b *MetricBatch) Len() int {
+	return len(b.Requests) + len(b.Queries) + len(b.Dependencies) + len(b.Errors) + len(b.Spans) + len(b.Connections)
+}
+
+func (b *MetricBatch) add(item interface{}) {
+	switch m := item.(type) {
+	case RequestMetric:
+		b.Requests = append(b.Requests, m)
+	case QueryMetric:
+		b.Queries = append(b.Queries, m)
+	case DependencyMetric:
+		b.Dependencies = append(b.Dependencies, m)
+	case ErrorRecord:
+		b.Errors = append(b.Errors, m)
+	case Span:
+		b.Spans = append(b.Spans, m)
+	case ConnectionMetric:
+		b.Connections = append(b.Connections, m)
+	}
+}
+
+// clone returns a copy of the batch that does not share its slices.
+func (b *MetricBatch) clone() MetricBatch {
+	return MetricBatch{
+		Requests:     append([]RequestMetric(nil), b.Requests...),
+		Queries:      append([]QueryMetric(nil), b.Queries...),
+		Dependencies: append([]DependencyMetric(nil), b.Dependencies...),
+		Errors:       append([]ErrorRecord(nil), b.Errors...),
+		Spans:        append([]Span(nil), b.Spans...),
+		Connections:  append([]ConnectionMetric(nil), b.Connections...),
+	}
+}
+
+func (b *MetricBatch) reset() {
+	b.Requests = b.Requests[:0]
+	b.Queries = b.Queries[:0]
+	b.Dependencies = b.Dependencies[:0]
+	b.Errors = b.Errors[:0]
+	b.Spans = b.Spans[:0]
+	b.Connections = b.Connections[:0]
+}
+
+// storeBatch writes a batch with the backend's batch writer, or one metric at
+// a time for backends without one.
+func storeBatch(s Storage, b MetricBatch) error {
+	if bs, ok := s.(batchStorer); ok {
+		return bs.StoreBatch(b)
+	}
+
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, m := range b.Requests {
+		keep(s.StoreRequest(m))
+	}
+	for _, m := range b.Queries {
+		keep(s.StoreQuery(m))
+	}
+	for _, m := range b.Dependencies {
+		keep(s.StoreDependencyMetric(m))
+	}
+	for _, e := range b.Errors {
+		keep(s.StoreError(e))
+	}
+	if ts, ok := s.(traceStore); ok && len(b.Spans) > 0 {
+		keep(ts.StoreSpans(b.Spans))
+	}
+	if cs, ok := s.(connectionStore); ok && len(b.Connections) > 0 {
+		keep(cs.StoreConnections(b.Connections))
+	}
+	return firstErr
+}
+
+// IngestStats reports the state of the ingestion queue.
+type IngestStats struct {
+	Policy      OverflowPolicy `json:"policy"`
+	Capacity    int            `json:"capacity"`
+	Queued      int            `json:"queued"`       // items waiting to be written
+	Enqueued    uint64         `json:"enqueued"`     // items accepted since startup
+	Dropped     uint64         `json:"dropped"`      // items discarded by the overflow policy or after shutdown
+	Written     uint64         `json:"written"`      // items storage accepted
+	Failed      uint64         `json:"failed"`       // batches storage rejected
+	FailedItems uint64         `json:"failed_items"` // items in the batches storage rejected
+}
+
+// Ingester is a bounded queue that decouples metric collection from storage
+// writes. Collectors enqueue without blocking the request path (unless the
+// Block policy is configured) and a fixed pool of workers drains the queue,
+// writing to storage in batches.
+type Ingester struct {
+	pulse     *Pulse
+	queue     chan interface{}
+	policy    OverflowPolicy
+	batchSize int
+
+	// closed is set under mu before the final drain, so no item can land in
+	// the queue after the workers have emptied it for the last time
+	mu     sync.RWMutex
+	closed bool
+
+	enqueued    atomic.Uint64
+	dropped     atomic.Uint64
+	written     atomic.Uint64
+	failed      atomic.Uint64
+	failedItems atomic.Uint64
+}
+
+// newIngester creates the ingestion queue and starts its workers.
+func newIngester(p *Pulse) *Ingester {
+	cfg := p.config.Ingestion
+	in := &Ingester{
+		pulse:     p,
+		queue:     make(chan interface{}, cfg.QueueSize),
+		policy:    cfg.Overflow,
+		batchSize: cfg.BatchSize,
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		p.startBackground("ingest-worker", in.work)
+	}
+
+	return in
+}
+
+// Enqueue adds a metric to the queue, applying the overflow policy when the
+// queue is full. It returns false if the metric was dropped.
+func (in *Ingester) Enqueue(item interface{}) bool {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+
+	if in.closed {
+		// Workers have drained the queue for the last time
+		in.dropped.Add(1)
+		return false
+	}
+
+	select {
+	case in.queue <- item:
+		in.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	switch in.policy {
+	case OverflowBlock:
+		select {
+		case in.queue <- item:
+			in.enqueued.Add(1)
+			return true
+		case <-in.pulse.ctx.Done():
+			in.dropped.Add(1)
+			return false
+		}
+
+	case OverflowDropNew:
+		in.dropped.Add(1)
+		return false
+
+	default: // OverflowDropOldest
+		for {
+			select {
+			case in.queue <- item:
+				in.enqueued.Add(1)
+				return true
+			default:
+			}
+			select {
+			case <-in.queue:
+				in.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+// work drains the queue until shutdown, then flushes what is left.
+func (in *Ingester) work(ctx context.Context) {
+	var batch MetricBatch
+
+	for {
+		select {
+		case item := <-in.queue:
+			batch.add(in.pulse.sanitize(item))
+			in.fill(&batch)
+			in.write(&batch)
+
+		case <-ctx.Done():
+			in.close()
+			for {
+				in.fill(&batch)
+				if batch.Len() == 0 {
+					return
+				}
+				in.write(&batch)
+			}
+		}
+	}
+}
+
+// close stops Enqueue from accepting items. It waits for senders already
+// past the check, so their items are in the queue before the final drain.
+func (in *Ingester) close() {
+	in.mu.Lock()
+	in.closed = true
+	in.mu.Unlock()
+}
+
+// fill adds immediately available items to batch up to the batch size.
+func (in *Ingester) fill(batch *MetricBatch) {
+	for batch.Len() < in.batchSize {
+		select {
+		case item := <-in.queue:
+			batch.add(in.pulse.sanitize(item))
+		default:
+			return
+		}
+	}
+}
+
+// write stores the batch, notifies live dashboard clients and resets it.
+func (in *Ingester) write(batch *MetricBatch) {
+	n := uint64(batch.Len())
+	if err := in.pulse.writeBatch(*batch); err != nil {
+		in.failed.Add(1)
+		in.failedItems.Add(n)
+	} else {
+		in.written.Add(n)
+	}
+	batch.reset()
+}
+
+// Stats returns the current queue counters.
+func (in *Ingester) Stats() IngestStats {
+	return IngestStats{
+		Policy:      in.policy,
+		Capacity:    cap(in.queue),
+		Queued:      len(in.queue),
+		Enqueued:    in.enqueued.Load(),
+		Dropped:     in.dropped.Load(),
+		Written:     in.written.Load(),
+		Failed:      in.failed.Load(),
+		FailedItems: in.failedItems.Load(),
+	}
+}
+
+// ingest hands a metric to the ingestion queue, whose workers scrub it and
+// anonymize its client IP off the caller's goroutine. Without a queue (Pulse
+// not mounted) the metric is sanitized and stored synchronously.
+func (p *Pulse) ingest(item interface{}) {
+	item = labelInstance(item, p.config.Instance)
+	if p.ingester != nil {
+		p.ingester.Enqueue(item)
+		return
+	}
+
+	var batch MetricBatch
+	batch.add(p.sanitize(item))
+	p.writeBatch(batch)
+}
+
+// sanitize scrubs a metric and anonymizes its client IP before it is stored,
+// exported or broadcast.
+func (p *Pulse) sanitize(item interface{}) interface{} {
+	return p.anonymizer.anonymize(p.scrubber.scrub(item))
+}
+
+// writeBatch stores a batch, forwards it to the collector in agent mode and
+// to the OTLP exporter, and broadcasts its requests and errors to live
+// dashboard clients.
+func (p *Pulse) writeBatch(batch MetricBatch) error {
+	err := storeBatch(p.storage, batch)
+	if err != nil && p.config.DevMode {
+		p.logger.Printf("[pulse] failed to store metric batch: %v", err)
+	}
+
+	if p.agent != nil {
+		p.agent.addBatch(batch)
+	}
+	if p.otlp != nil {
+		p.otlp.addBatch(batch)
+	}
+
+	for _, m := range batch.Requests {
+		p.BroadcastRequest(m)
+	}
+	for _, e := range batch.Errors {
+		p.BroadcastError(e)
+	}
+	return err
+}
+
+// labelInstance sets the instance label on a metric that does not have one.
+func labelInstance(item interface{}, instance string) interface{} {
+	switch m := item.(type) {
+	case RequestMetric:
+		if m.Instance == "" {
+			m.Instance = instance
+		}
+		return m
+	case QueryMetric:
+		if m.Instance == "" {
+			m.Instance = instance
+		}
+		return m
+	case DependencyMetric:
+		if m.Instance == "" {
+			m.Instance = instance
+		}
+		return m
+	case ErrorRecord:
+		if len(m.Instances) == 0 {
+			m.Instances = []string{instance}
+		}
+		return m
+	case Span:
+		if m.Instance == "" {
+			m.Instance = instance
+		}
+		return m
+	case ConnectionMetric:
+		if m.Instance == "" {
+			m.Instance = instance
+		}
+		return m
+	}
+	return item
+}
@@ -0,0 +1,309 @@
+package pulse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestIngester creates an ingester without workers so the queue can be
+// filled deterministically.
+func newTestIngester(t *testing.T, size int, policy OverflowPolicy) (*Pulse, *Ingester) {
+	t.Helper()
+	p := newPulse(applyDefaults(Config{}))
+	p.storage = NewMemoryStorage("test")
+	t.Cleanup(func() { p.Shutdown() })
+	in := &Ingester{
+		pulse:     p,
+		queue:     make(chan interface{}, size),
+		policy:    policy,
+		batchSize: 256,
+	}
+	return p, in
+}
+
+func TestApplyDefaults_Ingestion(t *testing.T) {
+	cfg := applyDefaults(Config{Ingestion: IngestionConfig{Overflow: "bogus"}})
+	if cfg.Ingestion.QueueSize != 10000 || cfg.Ingestion.Workers != 2 || cfg.Ingestion.BatchSize != 256 {
+		t.Errorf("unexpected ingestion defaults: %+v", cfg.Ingestion)
+	}
+	if cfg.Ingestion.Overflow != OverflowDropOldest {
+		t.Errorf("expected unknown policy to fall back to drop-oldest, got %q", cfg.Ingestion.Overflow)
+	}
+
+	cfg = applyDefaults(Config{Ingestion: IngestionConfig{Overflow: OverflowBlock}})
+	if cfg.Ingestion.Overflow != OverflowBlock {
+		t.Errorf("expected block policy to be kept, got %q", cfg.Ingestion.Overflow)
+	}
+}
+
+func TestIngester_DropNew(t *testing.T) {
+	_, in := newTestIngester(t, 2, OverflowDropNew)
+
+	for i := 0; i < 5; i++ {
+		in.Enqueue(RequestMetric{StatusCode: 200 + i})
+	}
+
+	stats := in.Stats()
+	if stats.Queued != 2 || stats.Enqueued != 2 || stats.Dropped != 3 {
+		t.Errorf("expected 2 queued / 3 dropped, got %+v", stats)
+	}
+	if first := (<-in.queue).(RequestMetric); first.StatusCode != 200 {
+		t.Errorf("expected the oldest item to be kept, got status %d", first.StatusCode)
+	}
+}
+
+func TestIngester_DropOldest(t *testing.T) {
+	_, in := newTestIngester(t, 2, OverflowDropOldest)
+
+	for i := 0; i < 5; i++ {
+		in.Enqueue(RequestMetric{StatusCode: 200 + i})
+	}
+
+	stats := in.Stats()
+	if stats.Queued != 2 || stats.Enqueued != 5 || stats.Dropped != 3 {
+		t.Errorf("expected 2 queued / 3 dropped, got %+v", stats)
+	}
+	a := (<-in.queue).(RequestMetric)
+	b := (<-in.queue).(RequestMetric)
+	if a.StatusCode != 203 || b.StatusCode != 204 {
+		t.Errorf("expected the newest items to be kept, got %d and %d", a.StatusCode, b.StatusCode)
+	}
+}
+
+func TestIngester_Block(t *testing.T) {
+	_, in := newTestIngester(t, 1, OverflowBlock)
+	in.Enqueue(RequestMetric{StatusCode: 200})
+
+	done := make(chan bool)
+	go func() { done <- in.Enqueue(RequestMetric{StatusCode: 201}) }()
+
+	select {
+	case <-done:
+		t.Fatal("expected Enqueue to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-in.queue
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Error("expected blocked item to be accepted")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Enqueue to unblock once there was room")
+	}
+	if in.Stats().Dropped != 0 {
+		t.Errorf("expected nothing dropped, got %d", in.Stats().Dropped)
+	}
+}
+
+func TestIngester_BlockReleasedOnShutdown(t *testing.T) {
+	p, in := newTestIngester(t, 1, OverflowBlock)
+	in.Enqueue(RequestMetric{})
+
+	done := make(chan bool)
+	go func() { done <- in.Enqueue(RequestMetric{}) }()
+	time.Sleep(20 * time.Millisecond)
+	p.cancel()
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("expected item to be dropped on shutdown")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected blocked Enqueue to return on shutdown")
+	}
+}
+
+func TestIngester_WritesBatchesAndDrainsOnShutdown(t *testing.T) {
+	p := newPulse(applyDefaults(Config{Ingestion: IngestionConfig{BatchSize: 10}}))
+	p.storage = NewMemoryStorage("test")
+	p.ingester = newIngester(p)
+
+	for i := 0; i < 100; i++ {
+		p.ingest(RequestMetric{Method: "GET", Path: "/batch", StatusCode: 200, Timestamp: time.Now()})
+	}
+	p.ingest(QueryMetric{SQL: "SELECT 1", Timestamp: time.Now()})
+	p.ingest(DependencyMetric{Name: "stripe", Timestamp: time.Now()})
+	p.ingest(ErrorRecord{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: time.Now(), LastSeen: time.Now()})
+
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	reqs, _ := p.storage.GetRequests(RequestFilter{})
+	if len(reqs) != 100 {
+		t.Errorf("expected 100 requests written, got %d", len(reqs))
+	}
+	queries, _ := p.storage.GetSlowQueries(0, 0)
+	errs, _ := p.storage.GetErrors(ErrorFilter{})
+	deps, _ := p.storage.GetDependencyStats(Last1h())
+	if len(queries) != 1 || len(errs) != 1 || len(deps) != 1 {
+		t.Errorf("expected 1 query, error and dependency, got %d / %d / %d", len(queries), len(errs), len(deps))
+	}
+
+	stats := p.ingester.Stats()
+	if stats.Written != 103 || stats.Queued != 0 {
+		t.Errorf("expected 103 written and an empty queue, got %+v", stats)
+	}
+
+	// Metrics arriving after shutdown are counted as dropped
+	p.ingest(RequestMetric{})
+	if p.ingester.Stats().Dropped != 1 {
+		t.Errorf("expected post-shutdown metric to be dropped, got %+v", p.ingester.Stats())
+	}
+}
+
+func TestIngester_CountsFailedItems(t *testing.T) {
+	p := newPulse(applyDefaults(Config{Ingestion: IngestionConfig{BatchSize: 10}}))
+	p.storage = &failingStorage{MemoryStorage: NewMemoryStorage("test"), failWrites: true}
+	p.ingester = newIngester(p)
+
+	for i := 0; i < 25; i++ {
+		p.ingest(RequestMetric{Method: "GET", Path: "/fail", Timestamp: time.Now()})
+	}
+	p.ingest(QueryMetric{SQL: "SELECT 1", Timestamp: time.Now()})
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	stats := p.ingester.Stats()
+	if stats.Written != 0 || stats.FailedItems != 26 || stats.Failed == 0 {
+		t.Errorf("expected all 26 items counted as failed and none written, got %+v", stats)
+	}
+}
+
+func TestIngester_RejectsItemsAfterFinalDrain(t *testing.T) {
+	_, in := newTestIngester(t, 10, OverflowDropOldest)
+	in.Enqueue(RequestMetric{StatusCode: 200})
+
+	// Once the workers start their final drain, nothing may land in the
+	// queue behind them, even before the context is observed as done
+	in.close()
+	if in.Enqueue(RequestMetric{StatusCode: 201}) {
+		t.Error("expected Enqueue to reject items after close")
+	}
+	if stats := in.Stats(); stats.Queued != 1 || stats.Enqueued != 1 || stats.Dropped != 1 {
+		t.Errorf("expected the late item counted as dropped, got %+v", stats)
+	}
+}
+
+func TestIngest_WithoutIngesterStoresSynchronously(t *testing.T) {
+	p := newPulse(applyDefaults(Config{}))
+	p.storage = NewMemoryStorage("test")
+	defer p.Shutdown()
+
+	p.ingest(RequestMetric{Method: "GET", Path: "/sync", Timestamp: time.Now()})
+	if p.storage.(*MemoryStorage).requests.Len() != 1 {
+		t.Error("expected request to be stored immediately")
+	}
+}
+
+func TestMiddleware_UsesIngestionQueue(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	for i := 0; i < 10; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for p.ingester.Stats().Written < 10 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := p.ingester.Stats().Written; got < 10 {
+		t.Errorf("expected 10 requests written through the queue, got %d", got)
+	}
+}
+
+func TestAPI_SettingsIncludesIngestionStats(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	p.ingester = newIngester(p)
+	p.ingester.dropped.Add(7)
+	token := loginAndGetToken(t, router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/settings", token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Storage        map[string]interface{} `json:"Storage"`
+		IngestionStats *IngestStats           `json:"ingestion_stats"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Storage == nil {
+		t.Error("expected config fields to remain at the top level")
+	}
+	if body.IngestionStats == nil || body.IngestionStats.Dropped != 7 {
+		t.Errorf("expected ingestion stats with 7 dropped, got %+v", body.IngestionStats)
+	}
+}
+
+func TestPrometheus_IngestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	p := setupPromPulse(t)
+	p.ingester = newIngester(p)
+	p.ingester.dropped.Add(3)
+
+	router := gin.New()
+	registerPrometheusRoute(router, p)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/pulse/metrics", nil))
+
+	body := w.Body.String()
+	if !strings.Contains(body, `pulse_ingest_dropped_total{policy="drop-oldest"} 3`) {
+		t.Errorf("expected dropped counter in output, got:\n%s", body)
+	}
+	if !strings.Contains(body, "pulse_ingest_queue_length 0") {
+		t.Error("expected queue length gauge in output")
+	}
+}
+
+// testMetricBatch builds a batch with n requests and one of each other type.
+func testMetricBatch(n int) MetricBatch {
+	now := time.Now()
+	var b MetricBatch
+	for i := 0; i < n; i++ {
+		b.Requests = append(b.Requests, RequestMetric{Method: "GET", Path: "/batch", StatusCode: 200, Latency: time.Millisecond, Timestamp: now})
+	}
+	b.Queries = []QueryMetric{{SQL: "SELECT 1", NormalizedSQL: "SELECT ?", Duration: time.Millisecond, Timestamp: now}}
+	b.Dependencies = []DependencyMetric{{Name: "stripe", Method: "GET", StatusCode: 200, Timestamp: now}}
+	b.Errors = []ErrorRecord{
+		{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now},
+		{ID: "e2", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now},
+	}
+	return b
+}
+
+// assertBatchStored checks that testMetricBatch(n) was fully written to s.
+func assertBatchStored(t *testing.T, s Storage, n int) {
+	t.Helper()
+	reqs, _ := s.GetRequests(RequestFilter{})
+	if len(reqs) != n {
+		t.Errorf("expected %d requests, got %d", n, len(reqs))
+	}
+	patterns, _ := s.GetQueryPatterns(Last1h())
+	if len(patterns) != 1 {
+		t.Errorf("expected 1 query pattern, got %d", len(patterns))
+	}
+	deps, _ := s.GetDependencyStats(Last1h())
+	if len(deps) != 1 {
+		t.Errorf("expected 1 dependency, got %d", len(deps))
+	}
+	errs, _ := s.GetErrors(ErrorFilter{})
+	if len(errs) != 1 || errs[0].Count != 2 {
+		t.Errorf("expected 1 deduplicated error with count 2, got %+v", errs)
+	}
+}
@@ -6,32 +6,46 @@ import (
 
 // RequestMetric captures data about a single HTTP request.
 type RequestMetric struct {
-	Method       string        `json:"method"`
-	Path         string        `json:"path"`
-	StatusCode   int           `json:"status_code"`
-	Latency      time.Duration `json:"latency"`
-	RequestSize  int64         `json:"request_size"`
-	ResponseSize int64         `json:"response_size"`
-	ClientIP     string        `json:"client_ip"`
-	UserAgent    string        `json:"user_agent"`
-	Error        string        `json:"error,omitempty"`
-	TraceID      string        `json:"trace_id"`
-	Timestamp    time.Time     `json:"timestamp"`
+	Method          string             `json:"method"`
+	Path            string             `json:"path"`
+	StatusCode      int                `json:"status_code"`
+	Latency         time.Duration      `json:"latency"`
+	TTFB            time.Duration      `json:"ttfb,omitempty"`             // time to the first WriteHeader or Write
+	QueryCount      int                `json:"query_count,omitempty"`      // GORM queries run by the handler
+	DBTime          time.Duration      `json:"db_time,omitempty"`          // total time of those queries
+	DependencyCount int                `json:"dependency_count,omitempty"` // calls through WrapHTTPClient
+	DependencyTime  time.Duration      `json:"dependency_time,omitempty"`  // total time of those calls
+	RequestSize     int64              `json:"request_size"`
+	ResponseSize    int64              `json:"response_size"`
+	ClientIP        string             `json:"client_ip"`
+	UserAgent       string             `json:"user_agent"`
+	Error           string             `json:"error,omitempty"`
+	TraceID         string             `json:"trace_id"`
+	SpanID          string             `json:"span_id,omitempty"`
+	ParentSpanID    string             `json:"parent_span_id,omitempty"`
+	SampleRate      float64            `json:"sample_rate,omitempty"` // rate the request was recorded at; 0 means 1
+	Tags            map[string]string  `json:"tags,omitempty"`
+	Capture         *RequestCapture    `json:"capture,omitempty"`    // payloads kept by TracingConfig.CaptureRules
+	Middleware      []MiddlewareTiming `json:"middleware,omitempty"` // time taken by each Timed middleware
+	Instance        string             `json:"instance,omitempty"`
+	Timestamp       time.Time          `json:"timestamp"`
 }
 
 // QueryMetric captures data about a single database query.
 type QueryMetric struct {
-	SQL            string        `json:"sql"`
-	NormalizedSQL  string        `json:"normalized_sql"`
-	Duration       time.Duration `json:"duration"`
-	RowsAffected   int64         `json:"rows_affected"`
-	Error          string        `json:"error,omitempty"`
-	Operation      string        `json:"operation"`
-	Table          string        `json:"table"`
-	CallerFile     string        `json:"caller_file,omitempty"`
-	CallerLine     int           `json:"caller_line,omitempty"`
-	RequestTraceID string        `json:"request_trace_id,omitempty"`
-	Timestamp      time.Time     `json:"timestamp"`
+	SQL            string            `json:"sql"`
+	NormalizedSQL  string            `json:"normalized_sql"`
+	Duration       time.Duration     `json:"duration"`
+	RowsAffected   int64             `json:"rows_affected"`
+	Error          string            `json:"error,omitempty"`
+	Operation      string            `json:"operation"`
+	Table          string            `json:"table"`
+	CallerFile     string            `json:"caller_file,omitempty"`
+	CallerLine     int               `json:"caller_line,omitempty"`
+	RequestTraceID string            `json:"request_trace_id,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"` // tags of the request that ran the query
+	Instance       string            `json:"instance,omitempty"`
+	Timestamp      time.Time         `json:"timestamp"`
 }
 
 // RuntimeMetric captures a snapshot of Go runtime statistics.
@@ -46,6 +60,7 @@ type RuntimeMetric struct {
 	GCPauseNs     uint64    `json:"gc_pause_ns"`
 	NumGC         uint32    `json:"num_gc"`
 	GCCPUFraction float64   `json:"gc_cpu_fraction"`
+	Instance      string    `json:"instance,omitempty"`
 	Timestamp     time.Time `json:"timestamp"`
 }
 
@@ -59,23 +74,27 @@ type RequestContext struct {
 	ClientIP    string            `json:"client_ip"`
 	UserAgent   string            `json:"user_agent"`
 	ContentType string            `json:"content_type,omitempty"`
+	TraceID     string            `json:"trace_id,omitempty"`
 }
 
 // ErrorRecord represents an aggregated error occurrence.
 type ErrorRecord struct {
-	ID             string          `json:"id"`
-	Fingerprint    string          `json:"fingerprint"`
-	Method         string          `json:"method"`
-	Route          string          `json:"route"`
-	ErrorMessage   string          `json:"error_message"`
-	ErrorType      string          `json:"error_type"`
-	StackTrace     string          `json:"stack_trace,omitempty"`
-	RequestContext *RequestContext `json:"request_context,omitempty"`
-	Count          int64           `json:"count"`
-	FirstSeen      time.Time       `json:"first_seen"`
-	LastSeen       time.Time       `json:"last_seen"`
-	Muted          bool            `json:"muted"`
-	Resolved       bool            `json:"resolved"`
+	ID             string              `json:"id"`
+	Fingerprint    string              `json:"fingerprint"`
+	Method         string              `json:"method"`
+	Route          string              `json:"route"`
+	ErrorMessage   string              `json:"error_message"`
+	ErrorType      string              `json:"error_type"`
+	StackTrace     string              `json:"stack_trace,omitempty"`
+	RequestContext *RequestContext     `json:"request_context,omitempty"`
+	Count          int64               `json:"count"`
+	FirstSeen      time.Time           `json:"first_seen"`
+	LastSeen       time.Time           `json:"last_seen"`
+	Muted          bool                `json:"muted"`
+	Resolved       bool                `json:"resolved"`
+	Instances      []string            `json:"instances,omitempty"` // instances that reported the error
+	Tags           map[string][]string `json:"tags,omitempty"`      // tag values seen across occurrences
+	TraceIDs       []string            `json:"trace_ids,omitempty"` // traces of the latest occurrences
 }
 
 // HealthCheckResult records the outcome of a single health check execution.
@@ -125,6 +144,8 @@ type DependencyMetric struct {
 	RequestSize  int64         `json:"request_size"`
 	ResponseSize int64         `json:"response_size"`
 	Error        string        `json:"error,omitempty"`
+	TraceID      string        `json:"trace_id,omitempty"`
+	Instance     string        `json:"instance,omitempty"`
 	Timestamp    time.Time     `json:"timestamp"`
 }
 
@@ -192,22 +213,25 @@ func ParseTimeRange(r string) TimeRange {
 
 // RouteStats holds aggregated statistics for a single route.
 type RouteStats struct {
-	Method       string        `json:"method"`
-	Path         string        `json:"path"`
-	RequestCount int64         `json:"request_count"`
-	ErrorCount   int64         `json:"error_count"`
-	ErrorRate    float64       `json:"error_rate"`
-	AvgLatency   time.Duration `json:"avg_latency"`
-	MinLatency   time.Duration `json:"min_latency"`
-	MaxLatency   time.Duration `json:"max_latency"`
-	P50Latency   time.Duration `json:"p50_latency"`
-	P75Latency   time.Duration `json:"p75_latency"`
-	P90Latency   time.Duration `json:"p90_latency"`
-	P95Latency   time.Duration `json:"p95_latency"`
-	P99Latency   time.Duration `json:"p99_latency"`
-	RPM          float64       `json:"rpm"`
-	StatusCodes  map[int]int64 `json:"status_codes"`
-	Trend        string        `json:"trend"` // "improving", "stable", "degrading"
+	Method          string        `json:"method"`
+	Path            string        `json:"path"`
+	RequestCount    int64         `json:"request_count"`
+	ErrorCount      int64         `json:"error_count"`
+	ErrorRate       float64       `json:"error_rate"`
+	AvgLatency      time.Duration `json:"avg_latency"`
+	MinLatency      time.Duration `json:"min_latency"`
+	MaxLatency      time.Duration `json:"max_latency"`
+	P50Latency      time.Duration `json:"p50_latency"`
+	P75Latency      time.Duration `json:"p75_latency"`
+	P90Latency      time.Duration `json:"p90_latency"`
+	P95Latency      time.Duration `json:"p95_latency"`
+	P99Latency      time.Duration `json:"p99_latency"`
+	RPM             float64       `json:"rpm"`
+	DBShare         float64       `json:"db_share"`         // percent of recorded latency spent in database queries
+	DependencyShare float64       `json:"dependency_share"` // percent spent in dependency calls
+	AppShare        float64       `json:"app_share"`        // percent spent in application code
+	StatusCodes     map[int]int64 `json:"status_codes"`
+	Trend           string        `json:"trend"` // "improving", "stable", "degrading"
 }
 
 // RouteDetail holds detailed information for a specific route.
@@ -217,6 +241,12 @@ type RouteDetail struct {
 	RecentRequests    []RequestMetric   `json:"recent_requests"`
 	RecentErrors      []ErrorRecord     `json:"recent_errors"`
 	TopQueries        []QueryPattern    `json:"top_queries"`
+	TTFB              *PhaseStats       `json:"ttfb,omitempty"`            // server think time, up to the first byte
+	Transfer          *PhaseStats       `json:"transfer,omitempty"`        // first byte to the end of the handler
+	Middleware        []PhaseStats      `json:"middleware,omitempty"`      // per Timed middleware, most total time first
+	DBTime            *PhaseStats       `json:"db_time,omitempty"`         // per request, in database queries
+	DependencyTime    *PhaseStats       `json:"dependency_time,omitempty"` // per request, in dependency calls
+	AppTime           *PhaseStats       `json:"app_time,omitempty"`        // per request, in application code
 }
 
 // TimeSeriesPoint is a single data point in a time series.
@@ -249,15 +279,16 @@ type N1Detection struct {
 
 // PoolStats holds database connection pool statistics.
 type PoolStats struct {
-	MaxOpenConnections int   `json:"max_open_connections"`
-	OpenConnections    int   `json:"open_connections"`
-	InUse              int   `json:"in_use"`
-	Idle               int   `json:"idle"`
-	WaitCount          int64 `json:"wait_count"`
-	WaitDuration       int64 `json:"wait_duration_ms"`
-	MaxIdleClosed      int64 `json:"max_idle_closed"`
-	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
-	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+	MaxOpenConnections int       `json:"max_open_connections"`
+	OpenConnections    int       `json:"open_connections"`
+	InUse              int       `json:"in_use"`
+	Idle               int       `json:"idle"`
+	WaitCount          int64     `json:"wait_count"`
+	WaitDuration       int64     `json:"wait_duration_ms"`
+	MaxIdleClosed      int64     `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64     `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64     `json:"max_lifetime_closed"`
+	Timestamp          time.Time `json:"timestamp"`
 }
 
 // ErrorGroup groups errors by fingerprint with aggregate counts.
@@ -308,6 +339,7 @@ type Overview struct {
 	RecentErrors     []ErrorRecord     `json:"recent_errors"`
 	ThroughputSeries []TimeSeriesPoint `json:"throughput_series"`
 	ErrorSeries      []TimeSeriesPoint `json:"error_series"`
+	TagGroups        []TagStats        `json:"tag_groups,omitempty"` // with ?group_by=<tag>
 	Timestamp        time.Time         `json:"timestamp"`
 }
 
@@ -318,6 +350,9 @@ type RequestFilter struct {
 	Path       string
 	StatusCode int
 	MinLatency time.Duration
+	Instance   string
+	Tags       map[string]string // requests carrying every tag
+	TraceID    string
 	Limit      int
 	Offset     int
 }
@@ -329,6 +364,8 @@ type ErrorFilter struct {
 	Route     string
 	Muted     *bool
 	Resolved  *bool
+	Instance  string
+	Tags      map[string]string // errors seen with every tag value
 	Limit     int
 	Offset    int
 }
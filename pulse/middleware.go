@@ -6,28 +6,36 @@ import (
 	"net"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// responseWriter wraps gin.ResponseWriter to capture the status code and bytes written.
+// responseWriter wraps gin.ResponseWriter to capture the status code and bytes
+// written, and to detect hijacked and streamed responses.
 type responseWriter struct {
 	gin.ResponseWriter
 	statusCode  int
 	bytesWriten int64
 	written     bool
+	tr          *tracedRequest
 }
 
-func newResponseWriter(w gin.ResponseWriter) *responseWriter {
-	return &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+func newResponseWriter(w gin.ResponseWriter, tr *tracedRequest) *responseWriter {
+	return &responseWriter{ResponseWriter: w, statusCode: http.StatusOK, tr: tr}
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	if !rw.written {
-		rw.statusCode = code
-		rw.written = true
+	// Gin renders streamed events with code -1 to keep the current status
+	if code > 0 {
+		if !rw.written {
+			rw.statusCode = code
+			rw.written = true
+		}
+		rw.tr.wrote()
 	}
 	rw.ResponseWriter.WriteHeader(code)
 }
@@ -36,8 +44,10 @@ func (rw *responseWriter) Write(data []byte) (int, error) {
 	if !rw.written {
 		rw.written = true
 	}
+	rw.tr.wrote()
 	n, err := rw.ResponseWriter.Write(data)
 	rw.bytesWriten += int64(n)
+	rw.tr.captureResponse(data[:n])
 	return n, err
 }
 
@@ -45,127 +55,399 @@ func (rw *responseWriter) WriteString(s string) (int, error) {
 	if !rw.written {
 		rw.written = true
 	}
+	rw.tr.wrote()
 	n, err := rw.ResponseWriter.WriteString(s)
 	rw.bytesWriten += int64(n)
+	rw.tr.captureResponseString(s[:n])
 	return n, err
 }
 
-// Hijack implements http.Hijacker for WebSocket support.
+// Hijack implements http.Hijacker for WebSocket support. The request is
+// recorded as a connection instead.
 func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	return rw.ResponseWriter.Hijack()
+	conn, brw, err := rw.ResponseWriter.Hijack()
+	if err != nil {
+		return conn, brw, err
+	}
+	return rw.tr.hijacked(conn), brw, nil
 }
 
-// Flush implements http.Flusher.
+// Flush implements http.Flusher. A flushed response is recorded as a
+// streaming connection instead of a request.
 func (rw *responseWriter) Flush() {
+	rw.tr.flushed(rw.Header())
 	rw.ResponseWriter.Flush()
 }
 
 // newTracingMiddleware creates the Gin middleware for request tracing.
 func newTracingMiddleware(p *Pulse) gin.HandlerFunc {
+	tracer := newRequestTracer(p)
+
+	return func(c *gin.Context) {
+		tr, req := tracer.begin(c.Request, c.Writer.Header(), c.FullPath(), c.ClientIP())
+		if tr == nil {
+			c.Next()
+			return
+		}
+		defer tr.release() // also when the handler panics
+		c.Request = req
+
+		// Wrap response writer
+		rw := newResponseWriter(c.Writer, tr)
+		c.Writer = rw
+
+		// Process request
+		c.Next()
+
+		// Collect error message from gin errors
+		var errMsg string
+		if len(c.Errors) > 0 {
+			errMsg = c.Errors.Last().Error()
+		}
+
+		tr.end(c.Request, requestOutcome{
+			route:        p.routePattern(c),
+			status:       rw.statusCode,
+			responseSize: rw.bytesWriten,
+			clientIP:     c.ClientIP(),
+			err:          errMsg,
+		})
+	}
+}
+
+// requestTracer holds the request tracing logic shared by the Gin
+// middleware and Handler.
+type requestTracer struct {
+	p       *Pulse
+	cfg     TracingConfig
+	sampler *requestSampler
+	capture *capturePolicy // nil without capture rules
+	exclude []string
+}
+
+func newRequestTracer(p *Pulse) *requestTracer {
 	cfg := p.config.Tracing
-	prefix := p.config.Prefix
 
 	// Pre-compile exclude patterns
 	excludePatterns := make([]string, 0, len(cfg.ExcludePaths)+2)
-	excludePatterns = append(excludePatterns, prefix+"/*")
+	excludePatterns = append(excludePatterns, p.config.Prefix+"/*")
 	excludePatterns = append(excludePatterns, "/favicon.ico")
 	excludePatterns = append(excludePatterns, cfg.ExcludePaths...)
 
-	return func(c *gin.Context) {
-		// Skip if tracing disabled
-		if !boolValue(cfg.Enabled) {
-			c.Next()
-			return
+	return &requestTracer{
+		p:       p,
+		cfg:     cfg,
+		sampler: newRequestSampler(cfg),
+		capture: newCapturePolicy(p.config),
+		exclude: excludePatterns,
+	}
+}
+
+// tracedRequest is a traced request whose handler is running.
+type tracedRequest struct {
+	tracer     *requestTracer
+	tc         TraceContext
+	decided    bool // the caller's traceparent made the sampling decision
+	sampled    bool
+	recorder   *spanRecorder
+	start      time.Time
+	inflightID uint64
+	method     string
+	path       string
+	upgrade    string          // the request's Upgrade header
+	firstByte  time.Time       // zero until the response writes its header or a byte
+	live       *liveConnection // set once the response is hijacked or streamed
+	ended      bool
+	header     http.Header   // response headers
+	reqBody    *cappedBuffer // request body kept for capture rules, or nil
+	respBody   *cappedBuffer // response body kept for capture rules, or nil
+	timer      *middlewareTimer
+	attributed *requestTime // queries and dependency calls made by the handler
+}
+
+// requestOutcome describes how a traced request was handled.
+type requestOutcome struct {
+	route        string // route pattern, e.g. "/users/:id"
+	status       int
+	responseSize int64
+	clientIP     string
+	err          string
+}
+
+// begin starts tracing r, registers it as in flight and sets the trace ID
+// response header. route is the route pattern if already matched. It returns
+// the request carrying the trace context, or nil when r is not traced.
+func (t *requestTracer) begin(r *http.Request, header http.Header, route, clientIP string) (*tracedRequest, *http.Request) {
+	// Skip if tracing disabled or the path is excluded
+	if !boolValue(t.cfg.Enabled) || shouldExclude(r.URL.Path, t.exclude) {
+		return nil, r
+	}
+
+	// Continue the caller's trace, or start a new one. Under head sampling
+	// a traceparent's sampled flag is honored so traces stay complete
+	// across services.
+	tc, fromCaller := extractTraceContext(r.Header)
+	decided := fromCaller && tc.ParentSpanID != ""
+	sampled := tc.Sampled()
+	if !fromCaller {
+		tc.TraceID = GenerateTraceID()
+	}
+	if !decided {
+		sampled = t.sampler.headSampled()
+		if sampled {
+			tc.Flags |= traceFlagSampled
 		}
+	}
+	tc.SpanID = GenerateSpanID()
+	header.Set(TraceIDHeader, tc.TraceID)
 
-		// Check path exclusion
-		requestPath := c.Request.URL.Path
-		if shouldExclude(requestPath, excludePatterns) {
-			c.Next()
-			return
+	// Attach trace context, span recorder and pulse instance to context
+	recorder := &spanRecorder{}
+	ctx := ContextWithTraceContext(r.Context(), tc)
+	ctx = contextWithSpanRecorder(ctx, recorder)
+	ctx = ContextWithPulse(ctx, t.p)
+	ctx = contextWithRequestTags(ctx)
+	ctx, timer := contextWithMiddlewareTimer(ctx)
+	ctx, attributed := contextWithRequestTime(ctx)
+
+	// Keep the bodies a capture rule may ask for once the request finishes
+	var reqBody, respBody *cappedBuffer
+	if t.capture != nil {
+		captureRequest, captureResponse := t.capture.bodies(r.Method, route)
+		if captureRequest {
+			reqBody = teeRequestBody(r, t.capture.maxBody)
+		}
+		if captureResponse {
+			respBody = &cappedBuffer{max: t.capture.maxBody}
 		}
+	}
 
-		// Generate trace ID
-		traceID := GenerateTraceID()
-		c.Header(TraceIDHeader, traceID)
+	start := time.Now()
+	inflightID := t.p.inflight.add(InFlightRequest{
+		TraceID:   tc.TraceID,
+		Method:    r.Method,
+		Route:     route,
+		Path:      r.URL.Path,
+		ClientIP:  t.p.anonymizer.ip(clientIP),
+		StartTime: start,
+	})
 
-		// Attach trace ID and pulse instance to context
-		ctx := ContextWithTraceID(c.Request.Context(), traceID)
-		ctx = ContextWithPulse(ctx, p)
-		c.Request = c.Request.WithContext(ctx)
+	return &tracedRequest{
+		tracer:     t,
+		tc:         tc,
+		decided:    decided,
+		sampled:    sampled,
+		recorder:   recorder,
+		start:      start,
+		inflightID: inflightID,
+		method:     r.Method,
+		path:       r.URL.Path,
+		upgrade:    r.Header.Get("Upgrade"),
+		header:     header,
+		timer:      timer,
+		attributed: attributed,
+		reqBody:    reqBody,
+		respBody:   respBody,
+	}, r.WithContext(ctx)
+}
 
-		// Wrap response writer
-		rw := newResponseWriter(c.Writer)
-		c.Writer = rw
+// untrack removes the request from the in-flight registry.
+func (tr *tracedRequest) untrack() {
+	tr.tracer.p.inflight.remove(tr.inflightID)
+}
 
-		// Record start time
-		start := time.Now()
+// release runs when the handler returns or panics. It untracks the request
+// and, if end was not reached, closes out its connection by path.
+func (tr *tracedRequest) release() {
+	tr.untrack()
+	if tr.live != nil && !tr.ended {
+		tr.ended = true
+		tr.live.done()
+	}
+}
 
-		// Process request
-		c.Next()
+// end records the finished request and its spans unless sampling drops it.
+func (tr *tracedRequest) end(r *http.Request, out requestOutcome) {
+	t := tr.tracer
+	tr.ended = true
 
-		// Calculate latency
-		latency := time.Since(start)
-		statusCode := rw.statusCode
+	// Long-lived connections are recorded apart from requests, and their
+	// spans are dropped
+	if tr.live != nil {
+		tr.recorder.finish(false)
+		tr.live.handlerDone(r, out, tr.firstByte)
+		return
+	}
 
-		// Determine if we should record this request (sampling)
-		isError := statusCode >= 400
-		isSlow := latency >= cfg.SlowRequestThreshold
-		shouldRecord := isError || isSlow || shouldSample(float64Value(cfg.SampleRate))
+	latency := time.Since(tr.start)
+	// Headers not sent by the handler go out once it returns
+	ttfb := latency
+	if !tr.firstByte.IsZero() {
+		ttfb = tr.firstByte.Sub(tr.start)
+	}
+	finished := sampleCandidate{
+		method:  r.Method,
+		route:   out.route,
+		status:  out.status,
+		latency: latency,
+		header:  r.Header,
+	}
 
-		if !shouldRecord {
-			return
-		}
+	// Determine if we should record this request (sampling). The rate
+	// it was kept at lets stats extrapolate to the true request count.
+	isError := out.status >= 400
+	isSlow := latency >= t.cfg.SlowRequestThreshold
+	shouldRecord := isError || isSlow || tr.sampled
+	sampleRate := 1.0
+	switch {
+	case t.sampler.tail():
+		// Rules and the adaptive budget apply to continued traces too
+		sampleRate = t.sampler.sampleRate(finished, time.Now())
+		shouldRecord = shouldSample(sampleRate)
+	case tr.decided:
+		// The caller's decision stands; its rate is unknown
+	case !isError && !isSlow:
+		sampleRate = t.sampler.rate
+	}
 
-		// Get the route pattern (e.g., "/users/:id") instead of actual path
-		routePattern := c.FullPath()
-		if routePattern == "" {
-			routePattern = requestPath
-		}
+	// Child spans are kept only with their request
+	spans, droppedSpans := tr.recorder.finish(shouldRecord)
+	if !shouldRecord {
+		return
+	}
 
-		// Collect error message from gin errors
-		var errMsg string
-		if len(c.Errors) > 0 {
-			errMsg = c.Errors.Last().Error()
-		}
+	// Build metric
+	metric := RequestMetric{
+		Method:       r.Method,
+		Path:         out.route,
+		StatusCode:   out.status,
+		Latency:      latency,
+		TTFB:         ttfb,
+		RequestSize:  r.ContentLength,
+		ResponseSize: out.responseSize,
+		ClientIP:     out.clientIP,
+		UserAgent:    r.UserAgent(),
+		Error:        out.err,
+		TraceID:      tr.tc.TraceID,
+		SpanID:       tr.tc.SpanID,
+		ParentSpanID: tr.tc.ParentSpanID,
+		SampleRate:   sampleRate,
+		Tags:         TagsFromContext(r.Context()),
+		Middleware:   tr.timer.result(),
+		Timestamp:    tr.start,
+	}
+	tr.attributed.apply(&metric)
+	if t.capture != nil {
+		metric.Capture = t.capture.capture(finished, tr.header, tr.reqBody, tr.respBody)
+	}
+
+	// Queue for storage to avoid blocking the response
+	t.p.ingest(metric)
+
+	server := serverSpan(tr.tc, metric)
+	if droppedSpans > 0 {
+		server.Attributes["pulse.dropped_spans"] = strconv.Itoa(droppedSpans)
+	}
+	t.p.ingest(server)
+	for _, span := range spans {
+		t.p.ingest(span)
+	}
+}
 
-		// Build metric
-		metric := RequestMetric{
-			Method:       c.Request.Method,
-			Path:         routePattern,
-			StatusCode:   statusCode,
-			Latency:      latency,
-			RequestSize:  c.Request.ContentLength,
-			ResponseSize: rw.bytesWriten,
-			ClientIP:     c.ClientIP(),
-			UserAgent:    c.Request.UserAgent(),
-			Error:        errMsg,
-			TraceID:      traceID,
-			Timestamp:    start,
+// serverSpan builds the server span of a recorded request.
+func serverSpan(tc TraceContext, m RequestMetric) Span {
+	span := Span{
+		TraceID:   tc.TraceID,
+		SpanID:    tc.SpanID,
+		ParentID:  tc.ParentSpanID,
+		Name:      m.Method + " " + m.Path,
+		Kind:      SpanKindServer,
+		StartTime: m.Timestamp,
+		Duration:  m.Latency,
+		Attributes: map[string]string{
+			"http.request.method":       m.Method,
+			"http.route":                m.Path,
+			"http.response.status_code": strconv.Itoa(m.StatusCode),
+		},
+		Status: SpanStatusOK,
+	}
+	// Client errors are the caller's fault, not a failed span
+	if m.StatusCode >= 500 {
+		span.Status = SpanStatusError
+		span.StatusMessage = m.Error
+		if span.StatusMessage == "" {
+			span.StatusMessage = http.StatusText(m.StatusCode)
 		}
+	}
+	return span
+}
+
+// otherRoute groups unmatched requests once MaxUnmatchedRoutes is reached.
+const otherRoute = "other"
+
+// routePattern returns the matched route pattern (e.g., "/users/:id"), or
+// the templated path for requests no route matched.
+func (p *Pulse) routePattern(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return p.unmatched.route(c.Request.URL.Path)
+}
+
+// unmatchedRoutes templates the paths of unmatched requests and caps how
+// many distinct templates are kept, so scanners probing random paths
+// cannot grow route stats and error groups without bound.
+type unmatchedRoutes struct {
+	max int // negative for no cap
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
 
-		// Store asynchronously to avoid blocking the response
-		go func() {
-			if err := p.storage.StoreRequest(metric); err != nil && p.config.DevMode {
-				p.logger.Printf("[pulse] failed to store request metric: %v", err)
-			}
-			p.BroadcastRequest(metric)
-		}()
+func newUnmatchedRoutes(max int) *unmatchedRoutes {
+	return &unmatchedRoutes{max: max, seen: make(map[string]struct{})}
+}
+
+// route returns the templated path, or otherRoute once the cap is reached.
+func (u *unmatchedRoutes) route(path string) string {
+	tmpl := NormalizePath(path)
+	if u == nil {
+		return tmpl
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.seen[tmpl]; ok || u.max < 0 {
+		return tmpl
+	}
+	if len(u.seen) >= u.max {
+		return otherRoute
 	}
+	u.seen[tmpl] = struct{}{}
+	return tmpl
 }
 
 // shouldExclude checks if a path matches any exclusion pattern.
 func shouldExclude(path string, patterns []string) bool {
 	for _, pattern := range patterns {
-		if matched, _ := filepath.Match(pattern, path); matched {
+		if matchPathPattern(path, pattern) {
 			return true
 		}
-		// Also check with a simpler prefix match for patterns like "/pulse/*"
-		if strings.HasSuffix(pattern, "/*") {
-			prefix := strings.TrimSuffix(pattern, "/*")
-			if strings.HasPrefix(path, prefix+"/") || path == prefix {
-				return true
-			}
+	}
+	return false
+}
+
+// matchPathPattern matches a path against a glob pattern. Patterns ending in
+// "/*" also match everything below the prefix.
+func matchPathPattern(path, pattern string) bool {
+	if matched, _ := filepath.Match(pattern, path); matched {
+		return true
+	}
+	// Also check with a simpler prefix match for patterns like "/pulse/*"
+	if strings.HasSuffix(pattern, "/*") {
+		prefix := strings.TrimSuffix(pattern, "/*")
+		if strings.HasPrefix(path, prefix+"/") || path == prefix {
+			return true
 		}
 	}
 	return false
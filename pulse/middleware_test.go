@@ -329,6 +329,34 @@ func TestGenerateTraceID(t *testing.T) {
 	}
 }
 
+func TestMiddleware_TemplatesUnmatchedPaths(t *testing.T) {
+	router, p := setupTestRouter(Config{Tracing: TracingConfig{MaxUnmatchedRoutes: 2}})
+	defer p.Shutdown()
+
+	for _, path := range []string{"/wp-admin/xyz123", "/wp-admin/abc456", "/items/1", "/items/2", "/.env", "/phpinfo.php"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	waitForRequests(t, p, 6)
+	stats, err := p.storage.GetRouteStats(TimeRange{Start: time.Now().Add(-time.Minute), End: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int64)
+	for _, rs := range stats {
+		counts[rs.Path] = rs.RequestCount
+	}
+	want := map[string]int64{"/wp-admin/:token": 2, "/items/:id": 2, otherRoute: 2}
+	if len(counts) != len(want) {
+		t.Fatalf("expected routes %v, got %v", want, counts)
+	}
+	for path, n := range want {
+		if counts[path] != n {
+			t.Errorf("expected %d requests for %s, got %d", n, path, counts[path])
+		}
+	}
+}
+
 func BenchmarkMiddleware(b *testing.B) {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -22,6 +22,62 @@ import (
 //	p := pulse.Mount(router, db, pulse.Config{})
 //	// Dashboard available at http://localhost:8080/pulse
 func Mount(router *gin.Engine, db *gorm.DB, configs ...Config) *Pulse {
+	p := start(db, configs...)
+	cfg := p.config
+
+	// Register error tracking middleware (outermost — catches panics from all handlers)
+	if boolValue(cfg.Errors.Enabled) {
+		router.Use(newErrorMiddleware(p))
+	}
+
+	// Register request tracing middleware (before routes so it captures all requests)
+	if boolValue(cfg.Tracing.Enabled) {
+		router.Use(newTracingMiddleware(p))
+	}
+
+	registerRoutes(router, p)
+
+	prefix := cfg.Prefix
+	log.Printf("[pulse] mounted at %s — dashboard: http://localhost:8080%s/ui/", prefix, prefix)
+	if cfg.DevMode {
+		log.Printf("[pulse] dev mode enabled — verbose logging active")
+	}
+
+	return p
+}
+
+// New creates a Pulse instance without a Gin router, for services built on
+// net/http, chi or another framework. Wrap handlers with Handler to trace
+// requests, and serve the dashboard and API from HTTPHandler.
+//
+// Usage:
+//
+//	p := pulse.New(db, pulse.Config{})
+//	mux.Handle("/pulse/", p.HTTPHandler())
+//	http.ListenAndServe(":8080", pulse.Handler(p, mux))
+func New(db *gorm.DB, configs ...Config) *Pulse {
+	p := start(db, configs...)
+	if p.config.DevMode {
+		log.Printf("[pulse] started — serve HTTPHandler at %s/", p.config.Prefix)
+	}
+	return p
+}
+
+// HTTPHandler returns an http.Handler serving the dashboard, REST API,
+// WebSocket, health and Prometheus endpoints at their configured paths, so
+// they can be mounted on any mux under the prefix.
+func (p *Pulse) HTTPHandler() http.Handler {
+	p.handlerOnce.Do(func() {
+		engine := gin.New()
+		registerRoutes(engine, p)
+		p.handler = engine
+	})
+	return p.handler
+}
+
+// start creates the Pulse engine and its background components, everything
+// Mount and New share.
+func start(db *gorm.DB, configs ...Config) *Pulse {
 	// Merge user config with defaults
 	var cfg Config
 	if len(configs) > 0 {
@@ -33,7 +89,23 @@ func Mount(router *gin.Engine, db *gorm.DB, configs ...Config) *Pulse {
 	p := newPulse(cfg)
 
 	// Initialize storage
-	p.storage = NewMemoryStorage(cfg.AppName)
+	p.storage = newStorage(cfg, db)
+
+	// Restore the last memory snapshot before retention trims it
+	p.snapshotter = newSnapshotter(p)
+
+	// Start ingestion queue workers
+	p.ingester = newIngester(p)
+
+	// Agent mode pushes to a collector; collector mode accepts agent pushes
+	p.agent = newAgent(p)
+	p.collector = newCollector(p)
+
+	// Export spans and metrics to an OpenTelemetry endpoint
+	p.otlp = newOTLPExporter(p)
+
+	// Start retention worker (prunes data older than the configured retention)
+	p.retention = newRetentionWorker(p)
 
 	// Register GORM query tracking plugin
 	if db != nil && boolValue(cfg.Database.Enabled) {
@@ -47,6 +119,9 @@ func Mount(router *gin.Engine, db *gorm.DB, configs ...Config) *Pulse {
 		}
 	}
 
+	// Flag requests whose handlers have been running too long
+	startStuckRequestMonitor(p)
+
 	// Start runtime metrics sampler
 	if boolValue(cfg.Runtime.Enabled) {
 		p.runtimeSampler = newRuntimeSampler(p)
@@ -70,22 +145,20 @@ func Mount(router *gin.Engine, db *gorm.DB, configs ...Config) *Pulse {
 		p.alertEngine = newAlertEngine(p)
 	}
 
-	// Register error tracking middleware (outermost — catches panics from all handlers)
-	if boolValue(cfg.Errors.Enabled) {
-		router.Use(newErrorMiddleware(p))
-	}
-
-	// Register request tracing middleware (before routes so it captures all requests)
-	if boolValue(cfg.Tracing.Enabled) {
-		router.Use(newTracingMiddleware(p))
-	}
-
 	// Start WebSocket hub
 	p.wsHub = newWebSocketHub(p)
 	p.startBackground("websocket-hub", func(ctx context.Context) {
 		p.wsHub.run()
 	})
 
+	return p
+}
+
+// registerRoutes registers the health, WebSocket, Prometheus, REST API and
+// dashboard routes.
+func registerRoutes(router *gin.Engine, p *Pulse) {
+	cfg := p.config
+
 	// Register public health endpoints (no auth required)
 	if boolValue(cfg.Health.Enabled) {
 		registerHealthRoutes(router, p)
@@ -103,15 +176,43 @@ func Mount(router *gin.Engine, db *gorm.DB, configs ...Config) *Pulse {
 	registerAPIRoutes(router, p)
 
 	// Serve embedded React dashboard
-	prefix := cfg.Prefix
-	registerDashboardRoutes(router, prefix, cfg)
+	registerDashboardRoutes(router, cfg.Prefix, cfg)
+}
 
-	log.Printf("[pulse] mounted at %s — dashboard: http://localhost:8080%s/ui/", prefix, prefix)
-	if cfg.DevMode {
-		log.Printf("[pulse] dev mode enabled — verbose logging active")
+// newStorage creates the storage backend selected by cfg.Storage.Driver,
+// falling back to in-memory storage if the backend cannot be opened. With a
+// HotWindow, persistent backends are fronted by memory storage.
+func newStorage(cfg Config, db *gorm.DB) Storage {
+	var durable Storage
+	switch cfg.Storage.Driver {
+	case GORM:
+		var s *GormStorage
+		var err error
+		if cfg.Storage.Dialector != nil {
+			s, err = OpenGormStorage(cfg.Storage.Dialector, cfg.AppName)
+		} else {
+			s, err = NewGormStorage(db, cfg.AppName)
+		}
+		if err != nil {
+			log.Printf("[pulse] warning: failed to open GORM storage: %v (falling back to memory)", err)
+			return NewMemoryStorage(cfg.AppName)
+		}
+		durable = s
+	case SQLite:
+		s, err := NewSQLiteStorage(cfg.Storage.DSN, cfg.AppName)
+		if err != nil {
+			log.Printf("[pulse] warning: failed to open SQLite storage %q: %v (falling back to memory)", cfg.Storage.DSN, err)
+			return NewMemoryStorage(cfg.AppName)
+		}
+		durable = s
+	default:
+		return NewMemoryStorage(cfg.AppName)
 	}
 
-	return p
+	if cfg.Storage.HotWindow > 0 {
+		return NewTeeStorage(NewMemoryStorage(cfg.AppName), cfg.Storage.HotWindow, durable)
+	}
+	return durable
 }
 
 // registerDashboardRoutes serves the embedded React dashboard or falls back to a placeholder.
@@ -297,6 +398,8 @@ func storageDriverName(d StorageDriver) string {
 	switch d {
 	case SQLite:
 		return "SQLite"
+	case GORM:
+		return "GORM"
 	default:
 		return "Memory"
 	}
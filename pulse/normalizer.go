@@ -284,3 +284,82 @@ func isDigit(ch byte) bool {
 func isIdentChar(ch byte) bool {
 	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_' || (ch >= '0' && ch <= '9')
 }
+
+// NormalizePath templates a URL path for grouping requests no route matched:
+//   - Replaces numeric IDs with :id
+//   - Replaces UUIDs with :uuid
+//   - Replaces hex hashes of 16+ digits with :hash
+//   - Replaces long or digit-heavy tokens with :token
+//   - Replaces segments with characters outside letters, digits and
+//     "-_.~=" (emails, percent-escapes, non-ASCII names) with :token
+func NormalizePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = normalizePathSegment(seg)
+	}
+	normalized := strings.Join(segments, "/")
+	if !strings.HasPrefix(normalized, "/") {
+		normalized = "/" + normalized
+	}
+	return normalized
+}
+
+// normalizePathSegment returns the placeholder for a variable path segment,
+// or the segment itself.
+func normalizePathSegment(seg string) string {
+	if seg == "" {
+		return seg
+	}
+
+	var digits, letters, hex int
+	for i := 0; i < len(seg); i++ {
+		ch := seg[i]
+		switch {
+		case isDigit(ch):
+			digits++
+			hex++
+		case (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F'):
+			letters++
+			hex++
+		case (ch >= 'g' && ch <= 'z') || (ch >= 'G' && ch <= 'Z'):
+			letters++
+		case ch == '-' || ch == '_' || ch == '.' || ch == '~' || ch == '=':
+		default:
+			// "@", "+", ":", escapes and non-ASCII mostly carry user data
+			return ":token"
+		}
+	}
+
+	switch {
+	case digits == len(seg):
+		return ":id"
+	case isUUID(seg):
+		return ":uuid"
+	case hex == len(seg) && len(seg) >= 16:
+		return ":hash"
+	case len(seg) >= 24 || (len(seg) >= 6 && letters > 0 && digits >= 3):
+		return ":token"
+	}
+	return seg
+}
+
+// isUUID reports whether s is a UUID in its 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if ch != '-' {
+				return false
+			}
+		default:
+			if !isDigit(ch) && !((ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -118,3 +118,34 @@ func TestNormalizeSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/users/42", "/users/:id"},
+		{"/users/42/posts/7", "/users/:id/posts/:id"},
+		{"/orders/123e4567-e89b-12d3-a456-426614174000", "/orders/:uuid"},
+		{"/blobs/9f86d081884c7d659a2feaa0c55ad015", "/blobs/:hash"},
+		{"/reset/eyJhbGciOiJIUzI1NiJ9abcdefgh", "/reset/:token"},
+		{"/wp-admin/xyz123", "/wp-admin/:token"},
+		{"/wp-login.php", "/wp-login.php"},
+		{"/api/v2/oauth2/callback", "/api/v2/oauth2/callback"},
+		{"/static/app.js", "/static/app.js"},
+		{"/cafe", "/cafe"},
+		{"/users/jane@example.com", "/users/:token"},
+		{"/users/J%C3%B6rg/profile", "/users/:token/profile"},
+		{"/users/Jörg", "/users/:token"},
+		{"/tags/c++", "/tags/:token"},
+		{"/files/a:b", "/files/:token"},
+		{"/", "/"},
+		{"", "/"},
+		{"/users/42/", "/users/:id/"},
+	}
+	for _, tt := range tests {
+		if got := NormalizePath(tt.input); got != tt.want {
+			t.Errorf("NormalizePath(%q): expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,814 @@
+package pulse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// otlpScopeName identifies Pulse as the instrumentation scope of exported data.
+const otlpScopeName = "github.com/MUKE-coder/pulse"
+
+// OTLP enum values (opentelemetry/proto/trace/v1 and metrics/v1).
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+
+	otlpTemporalityDelta      = 1
+	otlpTemporalityCumulative = 2
+)
+
+// OTLPStats reports the state of the OTLP exporter's buffer.
+type OTLPStats struct {
+	Endpoint  string    `json:"endpoint"`
+	Pending   int       `json:"pending"`  // items waiting to be exported
+	Sent      uint64    `json:"sent"`     // spans and runtime samples accepted by the endpoint
+	Dropped   uint64    `json:"dropped"`  // items discarded because the buffer was full or the endpoint rejected them
+	Failures  uint64    `json:"failures"` // failed export requests
+	LastPush  time.Time `json:"last_push,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// OTLPExporter converts Pulse data into OTLP spans and metrics and sends
+// them in batches to an OpenTelemetry endpoint over OTLP/HTTP with JSON
+// encoding. Requests, and the queries and calls made within them, arrive as
+// spans; queries, calls and errors outside a trace become spans of their
+// own. Route stats and runtime samples are exported as metrics.
+//
+// Like the agent, the exporter keeps failed batches up to MaxPending and
+// retries them on the next flush. Batches the endpoint rejects as invalid
+// are dropped.
+type OTLPExporter struct {
+	pulse      *Pulse
+	client     *http.Client
+	endpoint   string
+	headers    map[string]string
+	batchSize  int
+	maxPending int
+
+	mu         sync.Mutex
+	pending    []interface{}
+	stats      OTLPStats
+	routes     []RequestMetric // requests stored since the last route stats export
+	routesFrom time.Time       // start of the route stats window not yet exported
+
+	// pushMu serializes flushes so batches reach the endpoint in order
+	pushMu sync.Mutex
+	kick   chan struct{}
+}
+
+// newOTLPExporter creates the exporter and starts its flush loop. It returns
+// nil when no endpoint is configured.
+func newOTLPExporter(p *Pulse) *OTLPExporter {
+	cfg := p.config.OTLP
+	if cfg.Endpoint == "" {
+		return nil
+	}
+
+	e := &OTLPExporter{
+		pulse:      p,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		endpoint:   cfg.Endpoint,
+		headers:    cfg.Headers,
+		batchSize:  cfg.BatchSize,
+		maxPending: cfg.MaxPending,
+		routesFrom: time.Now(),
+		kick:       make(chan struct{}, 1),
+	}
+	e.stats.Endpoint = cfg.Endpoint
+
+	p.startBackground("otlp-exporter", func(ctx context.Context) {
+		ticker := time.NewTicker(cfg.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Shutdown exports the rest once the ingestion queue is drained
+				return
+			case <-ticker.C:
+				e.flush()
+			case <-e.kick:
+				e.flush()
+			}
+		}
+	})
+
+	return e
+}
+
+// addBatch buffers the exportable items of a stored metric batch.
+func (e *OTLPExporter) addBatch(b MetricBatch) {
+	items := make([]interface{}, 0, len(b.Spans)+len(b.Errors))
+	for _, s := range b.Spans {
+		items = append(items, s)
+	}
+	// Recorded requests have a server span; older agents don't send one
+	for _, m := range b.Requests {
+		if m.SpanID == "" {
+			items = append(items, m)
+		}
+	}
+	// Queries and calls within a trace already have a span
+	for _, m := range b.Queries {
+		if m.RequestTraceID == "" {
+			items = append(items, m)
+		}
+	}
+	for _, m := range b.Dependencies {
+		if m.TraceID == "" {
+			items = append(items, m)
+		}
+	}
+	for _, err := range b.Errors {
+		items = append(items, err)
+	}
+	if len(items) > 0 {
+		e.add(items...)
+	}
+	if len(b.Requests) > 0 {
+		e.addRoutes(b.Requests)
+	}
+}
+
+// addRoutes keeps what the route stats need of stored requests. Requests
+// are counted when stored rather than by start time, so a slow request
+// lands in the next export instead of a window already sent.
+func (e *OTLPExporter) addRoutes(reqs []RequestMetric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, m := range reqs {
+		e.routes = append(e.routes, RequestMetric{
+			Method:         m.Method,
+			Path:           m.Path,
+			StatusCode:     m.StatusCode,
+			Latency:        m.Latency,
+			DBTime:         m.DBTime,
+			DependencyTime: m.DependencyTime,
+			SampleRate:     m.SampleRate,
+		})
+	}
+	e.trimRoutesLocked()
+}
+
+// trimRoutesLocked drops the oldest requests beyond MaxPending.
+func (e *OTLPExporter) trimRoutesLocked() {
+	if over := len(e.routes) - e.maxPending; over > 0 {
+		e.routes = append(e.routes[:0:0], e.routes[over:]...)
+		e.stats.Dropped += uint64(over)
+	}
+}
+
+// addRuntime buffers a runtime sample for the next export.
+func (e *OTLPExporter) addRuntime(m RuntimeMetric) {
+	e.add(m)
+}
+
+func (e *OTLPExporter) add(items ...interface{}) {
+	e.mu.Lock()
+	e.pending = append(e.pending, items...)
+	e.trimLocked()
+	full := len(e.pending) >= e.batchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest pending items beyond MaxPending.
+func (e *OTLPExporter) trimLocked() {
+	if over := len(e.pending) - e.maxPending; over > 0 {
+		e.pending = append(e.pending[:0:0], e.pending[over:]...)
+		e.stats.Dropped += uint64(over)
+	}
+}
+
+// flush exports the route stats since the last successful export, then
+// pending items in batches until the buffer is empty or an export fails.
+func (e *OTLPExporter) flush() error {
+	e.pushMu.Lock()
+	defer e.pushMu.Unlock()
+
+	routeErr := e.exportRouteStats()
+
+	for {
+		e.mu.Lock()
+		n := len(e.pending)
+		if n > e.batchSize {
+			n = e.batchSize
+		}
+		items := e.pending[:n:n]
+		e.pending = e.pending[n:]
+		e.mu.Unlock()
+
+		if len(items) == 0 {
+			return routeErr
+		}
+
+		if err := e.exportItems(items); err != nil {
+			return errors.Join(routeErr, err)
+		}
+	}
+}
+
+// exportItems sends the spans and runtime samples of a batch. Each signal
+// is sent separately so a failure only requeues its own items.
+func (e *OTLPExporter) exportItems(items []interface{}) error {
+	var spanItems, runtimeItems []interface{}
+	for _, item := range items {
+		if _, ok := item.(RuntimeMetric); ok {
+			runtimeItems = append(runtimeItems, item)
+		} else {
+			spanItems = append(spanItems, item)
+		}
+	}
+
+	signals := []struct {
+		path  string
+		items []interface{}
+		build func([]interface{}) interface{}
+	}{
+		{"/v1/traces", spanItems, e.traceRequest},
+		{"/v1/metrics", runtimeItems, e.runtimeMetricsRequest},
+	}
+
+	var errs []error
+	var requeue []interface{}
+	sent, dropped := 0, 0
+	for _, sig := range signals {
+		if len(sig.items) == 0 {
+			continue
+		}
+		err := e.send(sig.path, sig.build(sig.items))
+		switch {
+		case err == nil:
+			sent += len(sig.items)
+		case isRetryableOTLPError(err):
+			errs = append(errs, err)
+			requeue = append(requeue, sig.items...)
+		default:
+			errs = append(errs, err)
+			dropped += len(sig.items)
+		}
+	}
+
+	err := errors.Join(errs...)
+	e.mu.Lock()
+	e.stats.Sent += uint64(sent)
+	e.stats.Dropped += uint64(dropped)
+	if sent > 0 {
+		e.stats.LastPush = time.Now()
+	}
+	if err != nil {
+		e.pending = append(requeue, e.pending...)
+		e.trimLocked()
+		e.stats.Failures += uint64(len(errs))
+		e.stats.LastError = err.Error()
+	} else {
+		e.stats.LastError = ""
+	}
+	e.mu.Unlock()
+
+	if err != nil && e.pulse.config.DevMode {
+		e.pulse.logger.Printf("[pulse] OTLP export to %s failed: %v", e.endpoint, err)
+	}
+	return err
+}
+
+// exportRouteStats sends per-route request counts and latencies for the
+// requests stored since the last successful export. A failed window is
+// covered by the next one.
+func (e *OTLPExporter) exportRouteStats() error {
+	now := time.Now()
+	e.mu.Lock()
+	from, reqs := e.routesFrom, e.routes
+	e.routes = nil
+	e.mu.Unlock()
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	stats := buildRouteStats(reqs, now.Sub(from))
+	err := e.send("/v1/metrics", e.routeMetricsRequest(stats, from, now))
+	e.mu.Lock()
+	if err == nil {
+		e.routesFrom = now
+		e.stats.LastPush = now
+	} else {
+		e.routes = append(reqs, e.routes...)
+		e.trimRoutesLocked()
+		e.stats.Failures++
+		e.stats.LastError = err.Error()
+	}
+	e.mu.Unlock()
+	return err
+}
+
+// otlpHTTPError is an export request the endpoint answered with a non-2xx
+// status.
+type otlpHTTPError struct {
+	code   int
+	status string
+}
+
+func (e *otlpHTTPError) Error() string {
+	return "OTLP endpoint returned " + e.status
+}
+
+// isRetryableOTLPError reports whether a failed export should be retried.
+// Per the OTLP/HTTP spec only throttling and unavailability are retried;
+// network errors are treated as unavailability.
+func isRetryableOTLPError(err error) bool {
+	var se *otlpHTTPError
+	if !errors.As(err, &se) {
+		return true
+	}
+	switch se.code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// send posts one gzip-compressed OTLP/JSON request to the endpoint path.
+func (e *OTLPExporter) send(path string, payload interface{}) error {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(zw).Encode(payload); err != nil {
+		return fmt.Errorf("encode OTLP request: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint+path, &body)
+	if err != nil {
+		return err
+	}
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &otlpHTTPError{code: resp.StatusCode, status: resp.Status}
+	}
+	return nil
+}
+
+// Stats returns the current export counters.
+func (e *OTLPExporter) Stats() OTLPStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := e.stats
+	stats.Pending = len(e.pending)
+	return stats
+}
+
+// --- OTLP/JSON payloads ---
+//
+// These mirror the OTLP protobuf messages in their JSON mapping: IDs are hex
+// strings, 64-bit integers are decimal strings and enums are numbers.
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    string   `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano int64          `json:"startTimeUnixNano,string"`
+	EndTimeUnixNano   int64          `json:"endTimeUnixNano,string"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano int64          `json:"timeUnixNano,string"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name    string       `json:"name"`
+	Unit    string       `json:"unit,omitempty"`
+	Gauge   *otlpGauge   `json:"gauge,omitempty"`
+	Sum     *otlpSum     `json:"sum,omitempty"`
+	Summary *otlpSummary `json:"summary,omitempty"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano int64          `json:"startTimeUnixNano,omitempty,string"`
+	TimeUnixNano      int64          `json:"timeUnixNano,string"`
+	AsInt             string         `json:"asInt,omitempty"`
+	AsDouble          *float64       `json:"asDouble,omitempty"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+type otlpSummaryDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano int64          `json:"startTimeUnixNano,string"`
+	TimeUnixNano      int64          `json:"timeUnixNano,string"`
+	Count             int64          `json:"count,string"`
+	Sum               float64        `json:"sum"`
+	QuantileValues    []otlpQuantile `json:"quantileValues"`
+}
+
+type otlpQuantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int64) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: strconv.FormatInt(value, 10)}}
+}
+
+func otlpIntPoint(t time.Time, value int64) otlpNumberDataPoint {
+	return otlpNumberDataPoint{TimeUnixNano: t.UnixNano(), AsInt: strconv.FormatInt(value, 10)}
+}
+
+func otlpDoublePoint(t time.Time, value float64) otlpNumberDataPoint {
+	return otlpNumberDataPoint{TimeUnixNano: t.UnixNano(), AsDouble: &value}
+}
+
+// otlpResourceFor describes the service instance that produced the data.
+func (e *OTLPExporter) otlpResourceFor(instance string) otlpResource {
+	attrs := []otlpKeyValue{otlpString("service.name", e.pulse.config.AppName)}
+	if instance != "" {
+		attrs = append(attrs, otlpString("service.instance.id", instance))
+	}
+	return otlpResource{Attributes: attrs}
+}
+
+// --- Traces ---
+
+// traceRequest converts buffered items into OTLP spans grouped by instance.
+func (e *OTLPExporter) traceRequest(items []interface{}) interface{} {
+	// Pulse accepts non-W3C trace IDs through ContextWithTraceID; those get
+	// a generated ID, shared by all spans of the same trace in the batch
+	remapped := make(map[string]string)
+	traceID := func(id string) string {
+		if isValidTraceID(id) {
+			return id
+		}
+		if _, ok := remapped[id]; !ok || id == "" {
+			remapped[id] = GenerateTraceID()
+		}
+		return remapped[id]
+	}
+
+	byInstance := make(map[string][]otlpSpan)
+	var order []string
+	for _, item := range items {
+		instance, span, ok := otlpSpanFor(item)
+		if !ok {
+			continue
+		}
+		span.TraceID = traceID(span.TraceID)
+		if _, seen := byInstance[instance]; !seen {
+			order = append(order, instance)
+		}
+		byInstance[instance] = append(byInstance[instance], span)
+	}
+
+	req := otlpTraceRequest{ResourceSpans: make([]otlpResourceSpans, 0, len(order))}
+	for _, instance := range order {
+		req.ResourceSpans = append(req.ResourceSpans, otlpResourceSpans{
+			Resource:   e.otlpResourceFor(instance),
+			ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otlpScopeName}, Spans: byInstance[instance]}},
+		})
+	}
+	return req
+}
+
+// otlpSpanFor converts one buffered item and returns the instance it came from.
+func otlpSpanFor(item interface{}) (string, otlpSpan, bool) {
+	switch m := item.(type) {
+	case Span:
+		return m.Instance, otlpSpanFromSpan(m), true
+	case RequestMetric:
+		return m.Instance, otlpSpanFromSpan(serverSpan(TraceContext{TraceID: m.TraceID, SpanID: GenerateSpanID()}, m)), true
+	case QueryMetric:
+		tc := TraceContext{TraceID: GenerateTraceID(), SpanID: GenerateSpanID()}
+		return m.Instance, otlpSpanFromSpan(querySpan(tc, m, "")), true
+	case DependencyMetric:
+		span := Span{
+			TraceID:   GenerateTraceID(),
+			SpanID:    GenerateSpanID(),
+			Name:      m.Method + " " + m.Name,
+			Kind:      SpanKindClient,
+			StartTime: m.Timestamp,
+			Duration:  m.Latency,
+			Attributes: map[string]string{
+				"http.request.method": m.Method,
+				"peer.service":        m.Name,
+			},
+			Status:        spanStatus(m.Error),
+			StatusMessage: m.Error,
+		}
+		if m.StatusCode > 0 {
+			span.Attributes["http.response.status_code"] = strconv.Itoa(m.StatusCode)
+			if m.StatusCode >= 400 {
+				span.Status = SpanStatusError
+			}
+		}
+		return m.Instance, otlpSpanFromSpan(span), true
+	case ErrorRecord:
+		var instance string
+		if len(m.Instances) > 0 {
+			instance = m.Instances[0]
+		}
+		return instance, otlpSpanFromError(m), true
+	}
+	return "", otlpSpan{}, false
+}
+
+func otlpSpanFromSpan(s Span) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.TraceID,
+		SpanID:            s.SpanID,
+		ParentSpanID:      s.ParentID,
+		Name:              s.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: s.StartTime.UnixNano(),
+		EndTimeUnixNano:   s.EndTime().UnixNano(),
+	}
+	switch s.Kind {
+	case SpanKindServer:
+		span.Kind = otlpSpanKindServer
+	case SpanKindClient:
+		span.Kind = otlpSpanKindClient
+	}
+	switch s.Status {
+	case SpanStatusOK:
+		span.Status.Code = otlpStatusOK
+	case SpanStatusError:
+		span.Status = otlpStatus{Code: otlpStatusError, Message: s.StatusMessage}
+	}
+
+	// Sorted for stable output
+	keys := make([]string, 0, len(s.Attributes))
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		span.Attributes = append(span.Attributes, otlpString(k, s.Attributes[k]))
+	}
+	return span
+}
+
+// otlpSpanFromError represents an error occurrence as a failed span with an
+// "exception" event, following the OpenTelemetry exception conventions. The
+// span joins the trace of the failed request when it has a valid one.
+func otlpSpanFromError(e ErrorRecord) otlpSpan {
+	name := "error"
+	if e.Route != "" {
+		name = e.Method + " " + e.Route
+	}
+	traceID := GenerateTraceID()
+	if n := len(e.TraceIDs); n > 0 && isValidTraceID(e.TraceIDs[n-1]) {
+		traceID = e.TraceIDs[n-1]
+	}
+	span := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            GenerateSpanID(),
+		Name:              name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: e.LastSeen.UnixNano(),
+		EndTimeUnixNano:   e.LastSeen.UnixNano(),
+		Attributes: []otlpKeyValue{
+			otlpString("error.type", e.ErrorType),
+			otlpString("pulse.error.fingerprint", e.Fingerprint),
+			otlpInt("pulse.error.count", e.Count),
+		},
+		Status: otlpStatus{Code: otlpStatusError, Message: e.ErrorMessage},
+	}
+	if e.Route != "" {
+		span.Attributes = append(span.Attributes,
+			otlpString("http.request.method", e.Method),
+			otlpString("http.route", e.Route))
+	}
+
+	event := otlpEvent{
+		TimeUnixNano: e.LastSeen.UnixNano(),
+		Name:         "exception",
+		Attributes: []otlpKeyValue{
+			otlpString("exception.type", e.ErrorType),
+			otlpString("exception.message", e.ErrorMessage),
+		},
+	}
+	if e.StackTrace != "" {
+		event.Attributes = append(event.Attributes, otlpString("exception.stacktrace", e.StackTrace))
+	}
+	span.Events = []otlpEvent{event}
+	return span
+}
+
+// --- Metrics ---
+
+// runtimeMetricsRequest converts runtime samples into gauges, plus a
+// cumulative GC count, grouped by instance.
+func (e *OTLPExporter) runtimeMetricsRequest(items []interface{}) interface{} {
+	byInstance := make(map[string][]RuntimeMetric)
+	var order []string
+	for _, item := range items {
+		m := item.(RuntimeMetric)
+		if _, seen := byInstance[m.Instance]; !seen {
+			order = append(order, m.Instance)
+		}
+		byInstance[m.Instance] = append(byInstance[m.Instance], m)
+	}
+
+	req := otlpMetricsRequest{ResourceMetrics: make([]otlpResourceMetrics, 0, len(order))}
+	for _, instance := range order {
+		samples := byInstance[instance]
+		gauge := func(name, unit string, value func(RuntimeMetric) otlpNumberDataPoint) otlpMetric {
+			points := make([]otlpNumberDataPoint, len(samples))
+			for i, m := range samples {
+				points[i] = value(m)
+			}
+			return otlpMetric{Name: name, Unit: unit, Gauge: &otlpGauge{DataPoints: points}}
+		}
+		bytesGauge := func(name string, value func(RuntimeMetric) uint64) otlpMetric {
+			return gauge(name, "By", func(m RuntimeMetric) otlpNumberDataPoint {
+				return otlpIntPoint(m.Timestamp, int64(value(m)))
+			})
+		}
+
+		gcCount := gauge("go.gc.count", "{gc}", func(m RuntimeMetric) otlpNumberDataPoint {
+			return otlpIntPoint(m.Timestamp, int64(m.NumGC))
+		})
+		gcCount.Sum = &otlpSum{DataPoints: gcCount.Gauge.DataPoints, AggregationTemporality: otlpTemporalityCumulative, IsMonotonic: true}
+		gcCount.Gauge = nil
+
+		metrics := []otlpMetric{
+			gauge("go.goroutine.count", "{goroutine}", func(m RuntimeMetric) otlpNumberDataPoint {
+				return otlpIntPoint(m.Timestamp, int64(m.NumGoroutine))
+			}),
+			bytesGauge("go.memory.heap.alloc", func(m RuntimeMetric) uint64 { return m.HeapAlloc }),
+			bytesGauge("go.memory.heap.in_use", func(m RuntimeMetric) uint64 { return m.HeapInUse }),
+			bytesGauge("go.memory.stack.in_use", func(m RuntimeMetric) uint64 { return m.StackInUse }),
+			bytesGauge("go.memory.sys", func(m RuntimeMetric) uint64 { return m.Sys }),
+			gauge("go.memory.heap.objects", "{object}", func(m RuntimeMetric) otlpNumberDataPoint {
+				return otlpIntPoint(m.Timestamp, int64(m.HeapObjects))
+			}),
+			gcCount,
+			gauge("go.gc.pause", "ns", func(m RuntimeMetric) otlpNumberDataPoint {
+				return otlpIntPoint(m.Timestamp, int64(m.GCPauseNs))
+			}),
+			gauge("go.gc.cpu_fraction", "1", func(m RuntimeMetric) otlpNumberDataPoint {
+				return otlpDoublePoint(m.Timestamp, m.GCCPUFraction)
+			}),
+		}
+
+		req.ResourceMetrics = append(req.ResourceMetrics, otlpResourceMetrics{
+			Resource:     e.otlpResourceFor(instance),
+			ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: otlpScopeName}, Metrics: metrics}},
+		})
+	}
+	return req
+}
+
+// routeMetricsRequest converts route stats over [from, to) into delta
+// request and error counts and a request duration summary per route.
+func (e *OTLPExporter) routeMetricsRequest(stats []RouteStats, from, to time.Time) interface{} {
+	requests := &otlpSum{AggregationTemporality: otlpTemporalityDelta, IsMonotonic: true}
+	failures := &otlpSum{AggregationTemporality: otlpTemporalityDelta, IsMonotonic: true}
+	durations := &otlpSummary{}
+
+	for _, rs := range stats {
+		attrs := []otlpKeyValue{
+			otlpString("http.request.method", rs.Method),
+			otlpString("http.route", rs.Path),
+		}
+
+		point := otlpIntPoint(to, rs.RequestCount)
+		point.Attributes, point.StartTimeUnixNano = attrs, from.UnixNano()
+		requests.DataPoints = append(requests.DataPoints, point)
+
+		point = otlpIntPoint(to, rs.ErrorCount)
+		point.Attributes, point.StartTimeUnixNano = attrs, from.UnixNano()
+		failures.DataPoints = append(failures.DataPoints, point)
+
+		durations.DataPoints = append(durations.DataPoints, otlpSummaryDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: from.UnixNano(),
+			TimeUnixNano:      to.UnixNano(),
+			Count:             rs.RequestCount,
+			Sum:               rs.AvgLatency.Seconds() * float64(rs.RequestCount),
+			QuantileValues: []otlpQuantile{
+				{Quantile: 0, Value: rs.MinLatency.Seconds()},
+				{Quantile: 0.5, Value: rs.P50Latency.Seconds()},
+				{Quantile: 0.95, Value: rs.P95Latency.Seconds()},
+				{Quantile: 0.99, Value: rs.P99Latency.Seconds()},
+				{Quantile: 1, Value: rs.MaxLatency.Seconds()},
+			},
+		})
+	}
+
+	metrics := []otlpMetric{
+		{Name: "http.server.request.count", Unit: "{request}", Sum: requests},
+		{Name: "http.server.error.count", Unit: "{request}", Sum: failures},
+		{Name: "http.server.request.duration", Unit: "s", Summary: durations},
+	}
+	return otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     e.otlpResourceFor(e.pulse.config.Instance),
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: otlpScopeName}, Metrics: metrics}},
+	}}}
+}
@@ -0,0 +1,255 @@
+package pulse
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// otlpStandIn is a local stand-in for an OTLP/HTTP receiver. It records the
+// decoded requests per path and answers with the next queued status.
+type otlpStandIn struct {
+	mu       sync.Mutex
+	statuses []int
+	traces   []otlpTraceRequest
+	metrics  []otlpMetricsRequest
+	headers  http.Header
+}
+
+func (s *otlpStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = r.Header.Clone()
+
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch r.URL.Path {
+	case "/v1/traces":
+		var req otlpTraceRequest
+		err = json.NewDecoder(zr).Decode(&req)
+		s.traces = append(s.traces, req)
+	case "/v1/metrics":
+		var req otlpMetricsRequest
+		err = json.NewDecoder(zr).Decode(&req)
+		s.metrics = append(s.metrics, req)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newTestOTLPPulse(t *testing.T, endpoint string) *Pulse {
+	t.Helper()
+	p := newPulse(applyDefaults(Config{
+		Instance: "web-1",
+		AppName:  "shop",
+		OTLP: OTLPConfig{
+			Endpoint:      endpoint,
+			Headers:       map[string]string{"X-Api-Key": "secret"},
+			FlushInterval: time.Hour,
+		},
+	}))
+	p.storage = NewMemoryStorage("test")
+	p.otlp = newOTLPExporter(p)
+	t.Cleanup(func() { p.Shutdown() })
+	return p
+}
+
+func TestApplyDefaults_OTLP(t *testing.T) {
+	cfg := applyDefaults(Config{OTLP: OTLPConfig{Endpoint: "http://otel:4318/"}})
+	if cfg.OTLP.Endpoint != "http://otel:4318" {
+		t.Errorf("expected trailing slash trimmed, got %q", cfg.OTLP.Endpoint)
+	}
+	if cfg.OTLP.FlushInterval != 10*time.Second || cfg.OTLP.BatchSize != 512 || cfg.OTLP.MaxPending != 20000 {
+		t.Errorf("unexpected OTLP defaults: %+v", cfg.OTLP)
+	}
+
+	p := newPulse(applyDefaults(Config{}))
+	if newOTLPExporter(p) != nil {
+		t.Error("expected no exporter without an endpoint")
+	}
+}
+
+func TestOTLPExporter_ExportsSpansAndMetrics(t *testing.T) {
+	standIn := &otlpStandIn{}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+	p := newTestOTLPPulse(t, srv.URL)
+
+	now := time.Now()
+	p.ingest(RequestMetric{Method: "GET", Path: "/users/:id", StatusCode: 200, Latency: 20 * time.Millisecond, TraceID: testTraceID, SpanID: testParentID, Timestamp: now})
+	p.ingest(Span{TraceID: testTraceID, SpanID: testParentID, Name: "GET /users/:id", Kind: SpanKindServer,
+		StartTime: now, Duration: 20 * time.Millisecond, Status: SpanStatusOK,
+		Attributes: map[string]string{"http.route": "/users/:id"}})
+	p.ingest(QueryMetric{Operation: "SELECT", Table: "users", NormalizedSQL: "SELECT * FROM users", Timestamp: now})
+	p.ingest(ErrorRecord{Method: "GET", Route: "/users/:id", ErrorType: "database", ErrorMessage: "connection refused", Count: 1, LastSeen: now, TraceIDs: []string{testTraceID}})
+	p.otlp.addRuntime(RuntimeMetric{NumGoroutine: 42, NumGC: 7, GCCPUFraction: 0.01, Instance: "web-1", Timestamp: now})
+
+	if err := p.otlp.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	if standIn.headers.Get("X-Api-Key") != "secret" || standIn.headers.Get("Content-Type") != "application/json" {
+		t.Errorf("expected configured headers on export requests, got %v", standIn.headers)
+	}
+
+	// Traces: the server span, the query outside a trace and the error
+	if len(standIn.traces) != 1 || len(standIn.traces[0].ResourceSpans) != 1 {
+		t.Fatalf("expected one trace export, got %+v", standIn.traces)
+	}
+	rs := standIn.traces[0].ResourceSpans[0]
+	if !hasOTLPAttr(rs.Resource.Attributes, "service.name", "shop") || !hasOTLPAttr(rs.Resource.Attributes, "service.instance.id", "web-1") {
+		t.Errorf("unexpected resource: %+v", rs.Resource)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	server := spans[0]
+	if server.TraceID != testTraceID || server.Kind != otlpSpanKindServer || server.Status.Code != otlpStatusOK {
+		t.Errorf("unexpected server span: %+v", server)
+	}
+	if server.EndTimeUnixNano-server.StartTimeUnixNano != int64(20*time.Millisecond) {
+		t.Errorf("expected 20ms span, got %dns", server.EndTimeUnixNano-server.StartTimeUnixNano)
+	}
+	if query := spans[1]; query.Name != "SELECT users" || query.Kind != otlpSpanKindClient || !isValidTraceID(query.TraceID) {
+		t.Errorf("unexpected query span: %+v", query)
+	}
+	errSpan := spans[2]
+	if errSpan.TraceID != testTraceID || errSpan.Status.Code != otlpStatusError || len(errSpan.Events) != 1 || errSpan.Events[0].Name != "exception" ||
+		!hasOTLPAttr(errSpan.Events[0].Attributes, "exception.message", "connection refused") {
+		t.Errorf("unexpected error span: %+v", errSpan)
+	}
+
+	// Metrics: route stats and the runtime sample
+	metrics := make(map[string]otlpMetric)
+	for _, req := range standIn.metrics {
+		for _, m := range req.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	count, ok := metrics["http.server.request.count"]
+	if !ok || count.Sum == nil || count.Sum.AggregationTemporality != otlpTemporalityDelta || count.Sum.DataPoints[0].AsInt != "1" {
+		t.Errorf("unexpected request count metric: %+v", count)
+	}
+	if d := metrics["http.server.request.duration"]; d.Summary == nil || d.Summary.DataPoints[0].Count != 1 ||
+		!hasOTLPAttr(d.Summary.DataPoints[0].Attributes, "http.route", "/users/:id") {
+		t.Errorf("unexpected duration metric: %+v", d)
+	}
+	if g := metrics["go.goroutine.count"]; g.Gauge == nil || g.Gauge.DataPoints[0].AsInt != "42" {
+		t.Errorf("unexpected goroutine metric: %+v", g)
+	}
+	if gc := metrics["go.gc.count"]; gc.Sum == nil || gc.Sum.AggregationTemporality != otlpTemporalityCumulative {
+		t.Errorf("expected cumulative GC count, got %+v", gc)
+	}
+
+	if stats := p.otlp.Stats(); stats.Sent != 4 || stats.Pending != 0 || stats.Failures != 0 {
+		t.Errorf("unexpected exporter stats: %+v", stats)
+	}
+}
+
+func TestOTLPExporter_RetriesAndDrops(t *testing.T) {
+	standIn := &otlpStandIn{statuses: []int{http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+	p := newTestOTLPPulse(t, srv.URL)
+
+	span := Span{TraceID: testTraceID, SpanID: testParentID, Name: "job", StartTime: time.Now()}
+	p.ingest(span)
+
+	// Unavailable: the batch is kept for the next flush
+	if err := p.otlp.flush(); err == nil {
+		t.Fatal("expected export to fail while the endpoint is unavailable")
+	}
+	if stats := p.otlp.Stats(); stats.Pending != 1 || stats.Failures != 1 || stats.LastError == "" {
+		t.Fatalf("expected failed batch to be kept, got %+v", stats)
+	}
+	if err := p.otlp.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if stats := p.otlp.Stats(); stats.Pending != 0 || stats.Sent != 1 || stats.LastError != "" {
+		t.Errorf("expected batch sent on retry, got %+v", stats)
+	}
+
+	// Rejected: the batch is dropped
+	standIn.mu.Lock()
+	standIn.statuses = []int{http.StatusBadRequest}
+	standIn.mu.Unlock()
+	p.ingest(span)
+	if err := p.otlp.flush(); err == nil {
+		t.Fatal("expected rejected export to fail")
+	}
+	if stats := p.otlp.Stats(); stats.Pending != 0 || stats.Dropped != 1 {
+		t.Errorf("expected rejected batch to be dropped, got %+v", stats)
+	}
+}
+
+func TestOTLPExporter_CountsSlowRequestsWhenStored(t *testing.T) {
+	standIn := &otlpStandIn{}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+	p := newTestOTLPPulse(t, srv.URL)
+
+	p.ingest(RequestMetric{Method: "GET", Path: "/fast", StatusCode: 200, Latency: time.Millisecond, Timestamp: time.Now()})
+	if err := p.otlp.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	// Started before the first export, stored after it
+	p.ingest(RequestMetric{Method: "GET", Path: "/slow", StatusCode: 200, Latency: time.Minute, Timestamp: time.Now().Add(-time.Minute)})
+	if err := p.otlp.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	var routes []string
+	for _, req := range standIn.metrics {
+		for _, m := range req.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+			if m.Name != "http.server.request.count" {
+				continue
+			}
+			for _, point := range m.Sum.DataPoints {
+				for _, attr := range point.Attributes {
+					if attr.Key == "http.route" {
+						routes = append(routes, *attr.Value.StringValue)
+					}
+				}
+			}
+		}
+	}
+	if len(routes) != 2 || routes[0] != "/fast" || routes[1] != "/slow" {
+		t.Errorf("expected each request counted once in its own export, got %v", routes)
+	}
+}
+
+func hasOTLPAttr(attrs []otlpKeyValue, key, value string) bool {
+	for _, kv := range attrs {
+		if kv.Key == key && kv.Value.StringValue != nil && *kv.Value.StringValue == value {
+			return true
+		}
+	}
+	return false
+}
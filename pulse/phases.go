@@ -0,0 +1,169 @@
+package pulse
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTimedMiddleware caps the timed middleware recorded per request.
+const maxTimedMiddleware = 32
+
+// MiddlewareTiming is the time one timed middleware took of a request.
+type MiddlewareTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// PhaseStats summarizes how long one phase of a route's requests took.
+type PhaseStats struct {
+	Name  string        `json:"name"`
+	Count int64         `json:"count"` // recorded requests that went through the phase
+	Avg   time.Duration `json:"avg"`
+	P50   time.Duration `json:"p50"`
+	P75   time.Duration `json:"p75"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+type middlewareTimerKey struct{}
+
+// middlewareTimer records the timed middleware of one request. Frames nest
+// as timed middleware call c.Next(); each holds the time spent in timed
+// middleware nested inside it.
+type middlewareTimer struct {
+	mu      sync.Mutex
+	frames  []time.Duration
+	timings []MiddlewareTiming
+}
+
+// Timed wraps a Gin middleware so the time it takes is recorded on the
+// request and shown per middleware on route detail:
+//
+//	router.Use(pulse.Timed("auth", authMiddleware()))
+//
+// A middleware's time excludes timed middleware and handlers it runs with
+// c.Next(), but includes untimed ones. Wrap the route handler as well to
+// keep its time out of the middleware around it. Outside a request traced
+// by Pulse, h runs untimed.
+func Timed(name string, h gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mt, ok := c.Request.Context().Value(middlewareTimerKey{}).(*middlewareTimer)
+		if !ok {
+			h(c)
+			return
+		}
+		mt.enter()
+		start := time.Now()
+		defer func() { mt.exit(name, time.Since(start)) }()
+		h(c)
+	}
+}
+
+// contextWithMiddlewareTimer attaches a timer for Timed middleware.
+func contextWithMiddlewareTimer(ctx context.Context) (context.Context, *middlewareTimer) {
+	mt := &middlewareTimer{}
+	return context.WithValue(ctx, middlewareTimerKey{}, mt), mt
+}
+
+func (mt *middlewareTimer) enter() {
+	mt.mu.Lock()
+	mt.frames = append(mt.frames, 0)
+	mt.mu.Unlock()
+}
+
+// exit closes the innermost frame, charging its elapsed time minus nested
+// timed middleware to name, and the elapsed time to the enclosing frame.
+func (mt *middlewareTimer) exit(name string, elapsed time.Duration) {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	last := len(mt.frames) - 1
+	nested := mt.frames[last]
+	mt.frames = mt.frames[:last]
+	if last > 0 {
+		mt.frames[last-1] += elapsed
+	}
+
+	self := max(elapsed-nested, 0)
+	for i := range mt.timings {
+		if mt.timings[i].Name == name {
+			mt.timings[i].Duration += self
+			return
+		}
+	}
+	if len(mt.timings) < maxTimedMiddleware {
+		mt.timings = append(mt.timings, MiddlewareTiming{Name: name, Duration: self})
+	}
+}
+
+// result returns the recorded timings in the order the middleware finished,
+// or nil when none ran.
+func (mt *middlewareTimer) result() []MiddlewareTiming {
+	if mt == nil {
+		return nil
+	}
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	if len(mt.timings) == 0 {
+		return nil
+	}
+	return append([]MiddlewareTiming(nil), mt.timings...)
+}
+
+// addPhaseStats fills in a route detail's time to first byte, transfer,
+// per-middleware and time attribution stats from its requests. Requests
+// recorded before TTFB was tracked are left out of the TTFB and transfer
+// stats.
+func addPhaseStats(detail *RouteDetail, reqs []RequestMetric) {
+	var ttfb, transfer []time.Duration
+	middleware := make(map[string][]time.Duration)
+	for _, r := range reqs {
+		if r.TTFB > 0 {
+			ttfb = append(ttfb, r.TTFB)
+			transfer = append(transfer, max(r.Latency-r.TTFB, 0))
+		}
+		for _, m := range r.Middleware {
+			middleware[m.Name] = append(middleware[m.Name], m.Duration)
+		}
+	}
+
+	if len(ttfb) > 0 {
+		ttfbStats := computePhaseStats("ttfb", ttfb)
+		transferStats := computePhaseStats("transfer", transfer)
+		detail.TTFB, detail.Transfer = &ttfbStats, &transferStats
+	}
+	detail.Middleware = nil
+	for name, durations := range middleware {
+		detail.Middleware = append(detail.Middleware, computePhaseStats(name, durations))
+	}
+	// Slowest first, by total time spent
+	sort.Slice(detail.Middleware, func(i, j int) bool {
+		a, b := detail.Middleware[i], detail.Middleware[j]
+		if ta, tb := a.Avg*time.Duration(a.Count), b.Avg*time.Duration(b.Count); ta != tb {
+			return ta > tb
+		}
+		return a.Name < b.Name
+	})
+	addTimeStats(detail, reqs)
+}
+
+func computePhaseStats(name string, durations []time.Duration) PhaseStats {
+	p50, p75, p90, p95, p99 := ComputePercentiles(durations)
+	return PhaseStats{
+		Name:  name,
+		Count: int64(len(durations)),
+		Avg:   ComputeAvg(durations),
+		P50:   p50,
+		P75:   p75,
+		P90:   p90,
+		P95:   p95,
+		P99:   p99,
+		Max:   ComputeMax(durations),
+	}
+}
@@ -0,0 +1,84 @@
+package pulse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimed_RecordsSelfTime(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	router.GET("/timed",
+		Timed("auth", func(c *gin.Context) {
+			time.Sleep(10 * time.Millisecond)
+			c.Next()
+		}),
+		Timed("handler", func(c *gin.Context) {
+			time.Sleep(30 * time.Millisecond)
+			c.String(http.StatusOK, "ok")
+		}),
+	)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/timed", nil))
+
+	reqs := waitForRequests(t, p, 1)
+	timings := make(map[string]time.Duration)
+	for _, m := range reqs[0].Middleware {
+		timings[m.Name] = m.Duration
+	}
+	if len(timings) != 2 {
+		t.Fatalf("expected auth and handler timings, got %+v", reqs[0].Middleware)
+	}
+	// auth excludes the handler it runs with c.Next()
+	if auth := timings["auth"]; auth < 10*time.Millisecond || auth >= 30*time.Millisecond {
+		t.Errorf("expected auth to take about 10ms, got %v", auth)
+	}
+	if handler := timings["handler"]; handler < 30*time.Millisecond {
+		t.Errorf("expected handler to take at least 30ms, got %v", handler)
+	}
+}
+
+func TestTimed_UntracedRunsHandler(t *testing.T) {
+	router := gin.New()
+	ran := false
+	router.GET("/plain", Timed("auth", func(c *gin.Context) { ran = true }))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/plain", nil))
+	if !ran {
+		t.Error("expected the middleware to run outside Pulse")
+	}
+}
+
+func TestMiddleware_RecordsTTFB(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	router.GET("/stream", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		time.Sleep(30 * time.Millisecond)
+		c.Writer.WriteString("done")
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/stream", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+
+	for _, r := range waitForRequests(t, p, 2) {
+		if r.TTFB <= 0 || r.TTFB > r.Latency {
+			t.Errorf("%s: expected TTFB within latency, got %v of %v", r.Path, r.TTFB, r.Latency)
+		}
+		if r.Path == "/stream" && r.Latency-r.TTFB < 30*time.Millisecond {
+			t.Errorf("expected the body to take 30ms after the first byte, got %v of %v", r.TTFB, r.Latency)
+		}
+	}
+
+	detail, err := p.storage.GetRouteDetail("GET", "/stream", TimeRange{Start: time.Now().Add(-time.Minute), End: time.Now()})
+	if err != nil || detail == nil {
+		t.Fatalf("expected route detail, got %v", err)
+	}
+	if detail.TTFB == nil || detail.Transfer == nil || detail.Transfer.P50 < 30*time.Millisecond {
+		t.Errorf("expected TTFB and transfer stats, got %+v / %+v", detail.TTFB, detail.Transfer)
+	}
+}
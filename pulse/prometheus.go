@@ -28,6 +28,8 @@ func buildPrometheusMetrics(p *Pulse) string {
 
 	// --- HTTP Request Metrics ---
 	writeRequestMetrics(&b, p, tr)
+	writeInflightMetrics(&b, p)
+	writeConnectionMetrics(&b, p, tr)
 
 	// --- Runtime Metrics ---
 	writeRuntimeMetrics(&b, p)
@@ -41,6 +43,10 @@ func buildPrometheusMetrics(p *Pulse) string {
 	// --- Database Metrics ---
 	writeDatabaseMetrics(&b, p, tr)
 
+	// --- Ingestion Queue ---
+	writeIngestMetrics(&b, p)
+	writeTeeMetrics(&b, p)
+
 	// --- Uptime ---
 	fmt.Fprintf(&b, "# HELP pulse_uptime_seconds Pulse uptime in seconds\n")
 	fmt.Fprintf(&b, "# TYPE pulse_uptime_seconds gauge\n")
@@ -89,6 +95,80 @@ func writeRequestMetrics(b *strings.Builder, p *Pulse, tr TimeRange) {
 	b.WriteString("\n")
 }
 
+func writeInflightMetrics(b *strings.Builder, p *Pulse) {
+	if p.inflight == nil {
+		return
+	}
+	inflight, stuck := p.inflight.counts()
+
+	fmt.Fprintf(b, "# HELP pulse_http_requests_in_flight HTTP requests currently being handled\n")
+	fmt.Fprintf(b, "# TYPE pulse_http_requests_in_flight gauge\n")
+	fmt.Fprintf(b, "pulse_http_requests_in_flight %d\n\n", inflight)
+
+	fmt.Fprintf(b, "# HELP pulse_http_requests_stuck In-flight HTTP requests running past the stuck threshold\n")
+	fmt.Fprintf(b, "# TYPE pulse_http_requests_stuck gauge\n")
+	fmt.Fprintf(b, "pulse_http_requests_stuck %d\n\n", stuck)
+
+	fmt.Fprintf(b, "# HELP pulse_http_stuck_requests_total HTTP requests flagged as stuck\n")
+	fmt.Fprintf(b, "# TYPE pulse_http_stuck_requests_total counter\n")
+	fmt.Fprintf(b, "pulse_http_stuck_requests_total %d\n\n", p.inflight.stuckTotal.Load())
+}
+
+func writeConnectionMetrics(b *strings.Builder, p *Pulse, tr TimeRange) {
+	if p.connections == nil {
+		return
+	}
+
+	active := p.connections.snapshot()
+	fmt.Fprintf(b, "# HELP pulse_connections_active Open long-lived connections (WebSocket, SSE, streams)\n")
+	fmt.Fprintf(b, "# TYPE pulse_connections_active gauge\n")
+	for _, kind := range []ConnectionKind{ConnectionWebSocket, ConnectionUpgrade, ConnectionSSE, ConnectionStream} {
+		fmt.Fprintf(b, "pulse_connections_active{kind=%q} %d\n", kind, active[kind])
+	}
+	b.WriteString("\n")
+
+	store, ok := p.storage.(connectionStore)
+	if !ok {
+		return
+	}
+	conns, _ := store.GetConnections(tr)
+	stats := buildConnectionStats(conns)
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "# HELP pulse_connections_total Closed long-lived connections\n")
+	fmt.Fprintf(b, "# TYPE pulse_connections_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_connections_total{kind=%q,method=%q,path=%q} %d\n", s.Kind, s.Method, s.Path, s.Count)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "# HELP pulse_connection_duration_seconds Long-lived connection duration\n")
+	fmt.Fprintf(b, "# TYPE pulse_connection_duration_seconds summary\n")
+	for _, s := range stats {
+		labels := fmt.Sprintf("kind=%q,method=%q,path=%q", s.Kind, s.Method, s.Path)
+		fmt.Fprintf(b, "pulse_connection_duration_seconds{%s,quantile=\"0.95\"} %f\n", labels, s.P95Duration.Seconds())
+		fmt.Fprintf(b, "pulse_connection_duration_seconds_sum{%s} %f\n", labels, s.AvgDuration.Seconds()*float64(s.Count))
+		fmt.Fprintf(b, "pulse_connection_duration_seconds_count{%s} %d\n", labels, s.Count)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "# HELP pulse_connection_sent_bytes_total Bytes sent over long-lived connections\n")
+	fmt.Fprintf(b, "# TYPE pulse_connection_sent_bytes_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_connection_sent_bytes_total{kind=%q,method=%q,path=%q} %d\n", s.Kind, s.Method, s.Path, s.BytesSent)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "# HELP pulse_connection_messages_total Messages sent over long-lived connections\n")
+	fmt.Fprintf(b, "# TYPE pulse_connection_messages_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_connection_messages_total{kind=%q,method=%q,path=%q} %d\n", s.Kind, s.Method, s.Path, s.Messages)
+	}
+	b.WriteString("\n")
+}
+
 func writeRuntimeMetrics(b *strings.Builder, p *Pulse) {
 	history, _ := p.storage.GetRuntimeHistory(Last5m())
 	if len(history) == 0 {
@@ -208,3 +288,67 @@ func writeDatabaseMetrics(b *strings.Builder, p *Pulse, tr TimeRange) {
 		fmt.Fprintf(b, "pulse_db_pool_idle %d\n\n", pool.Idle)
 	}
 }
+
+func writeIngestMetrics(b *strings.Builder, p *Pulse) {
+	if p.ingester == nil {
+		return
+	}
+	stats := p.ingester.Stats()
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_queue_length Metrics waiting in the ingestion queue\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_queue_length gauge\n")
+	fmt.Fprintf(b, "pulse_ingest_queue_length %d\n\n", stats.Queued)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_queue_capacity Capacity of the ingestion queue\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_queue_capacity gauge\n")
+	fmt.Fprintf(b, "pulse_ingest_queue_capacity %d\n\n", stats.Capacity)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_enqueued_total Metrics accepted by the ingestion queue\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_enqueued_total counter\n")
+	fmt.Fprintf(b, "pulse_ingest_enqueued_total %d\n\n", stats.Enqueued)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_dropped_total Metrics dropped by the overflow policy\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_dropped_total counter\n")
+	fmt.Fprintf(b, "pulse_ingest_dropped_total{policy=%q} %d\n\n", stats.Policy, stats.Dropped)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_written_total Metrics written to storage\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_written_total counter\n")
+	fmt.Fprintf(b, "pulse_ingest_written_total %d\n\n", stats.Written)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_failed_batches_total Batches rejected by storage\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_failed_batches_total counter\n")
+	fmt.Fprintf(b, "pulse_ingest_failed_batches_total %d\n\n", stats.Failed)
+
+	fmt.Fprintf(b, "# HELP pulse_ingest_failed_total Metrics in batches rejected by storage\n")
+	fmt.Fprintf(b, "# TYPE pulse_ingest_failed_total counter\n")
+	fmt.Fprintf(b, "pulse_ingest_failed_total %d\n\n", stats.FailedItems)
+}
+
+func writeTeeMetrics(b *strings.Builder, p *Pulse) {
+	tee, ok := p.storage.(*TeeStorage)
+	if !ok || len(tee.writers) == 0 {
+		return
+	}
+	stats := tee.Stats()
+
+	fmt.Fprintf(b, "# HELP pulse_storage_queue_length Writes waiting for a durable storage backend\n")
+	fmt.Fprintf(b, "# TYPE pulse_storage_queue_length gauge\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_storage_queue_length{backend=\"%d\"} %d\n", s.Backend, s.Queued)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "# HELP pulse_storage_dropped_writes_total Writes dropped because a durable backend's queue was full\n")
+	fmt.Fprintf(b, "# TYPE pulse_storage_dropped_writes_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_storage_dropped_writes_total{backend=\"%d\"} %d\n", s.Backend, s.Dropped)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "# HELP pulse_storage_failed_writes_total Writes a durable backend rejected\n")
+	fmt.Fprintf(b, "# TYPE pulse_storage_failed_writes_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(b, "pulse_storage_failed_writes_total{backend=\"%d\"} %d\n", s.Backend, s.Failed)
+	}
+	b.WriteString("\n")
+}
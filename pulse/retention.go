@@ -0,0 +1,133 @@
+package pulse
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PruneResult counts the records removed by a retention pass, per data type.
+type PruneResult struct {
+	Requests     int64 `json:"requests"`
+	Queries      int64 `json:"queries"`
+	N1Detections int64 `json:"n1_detections"`
+	Runtime      int64 `json:"runtime"`
+	PoolStats    int64 `json:"pool_stats"`
+	Errors       int64 `json:"errors"`
+	Alerts       int64 `json:"alerts"`
+	Health       int64 `json:"health"`
+	Dependencies int64 `json:"dependencies"`
+	Spans        int64 `json:"spans"`
+	Connections  int64 `json:"connections"`
+}
+
+// Total returns the total number of records removed.
+func (r PruneResult) Total() int64 {
+	return r.Requests + r.Queries + r.N1Detections + r.Runtime + r.PoolStats +
+		r.Errors + r.Alerts + r.Health + r.Dependencies + r.Spans + r.Connections
+}
+
+// RetentionStatus describes the most recent retention pass.
+type RetentionStatus struct {
+	LastRun  time.Time     `json:"last_run"`
+	Duration time.Duration `json:"duration"`
+	Pruned   PruneResult   `json:"pruned"`
+	Error    string        `json:"error,omitempty"`
+	NextRun  time.Time     `json:"next_run"`
+	Runs     int64         `json:"runs"`
+}
+
+// uniformRetention applies the same retention period to every data type.
+func uniformRetention(retention time.Duration) RetentionConfig {
+	return RetentionConfig{
+		Requests:     retention,
+		Queries:      retention,
+		Runtime:      retention,
+		Errors:       retention,
+		Alerts:       retention,
+		Health:       retention,
+		Dependencies: retention,
+	}
+}
+
+// RetentionWorker periodically prunes expired data from storage according to
+// StorageConfig.Retention.
+type RetentionWorker struct {
+	pulse *Pulse
+
+	mu     sync.RWMutex
+	status RetentionStatus
+}
+
+// newRetentionWorker creates and starts the retention worker. The first pass
+// runs immediately so persistent backends are trimmed on startup.
+func newRetentionWorker(p *Pulse) *RetentionWorker {
+	w := &RetentionWorker{pulse: p}
+
+	interval := p.config.Storage.CleanupInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	p.startBackground("retention", func(ctx context.Context) {
+		w.run(interval)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.run(interval)
+			}
+		}
+	})
+
+	return w
+}
+
+// run performs a single retention pass and records its outcome.
+func (w *RetentionWorker) run(interval time.Duration) RetentionStatus {
+	p := w.pulse
+	start := time.Now()
+
+	var result PruneResult
+	var err error
+	if pruner, ok := p.storage.(retentionPruner); ok {
+		result, err = pruner.Prune(p.config.Storage.Retention)
+	} else {
+		// Custom backends without per-type pruning get a single cutoff
+		err = p.storage.Cleanup(time.Duration(p.config.Storage.RetentionHours) * time.Hour)
+	}
+
+	w.mu.Lock()
+	w.status.LastRun = start
+	w.status.Duration = time.Since(start)
+	w.status.Pruned = result
+	w.status.Error = ""
+	if err != nil {
+		w.status.Error = err.Error()
+	}
+	w.status.NextRun = start.Add(interval)
+	w.status.Runs++
+	status := w.status
+	w.mu.Unlock()
+
+	if err != nil {
+		p.logger.Printf("[pulse] retention cleanup failed: %v", err)
+	} else if p.config.DevMode && result.Total() > 0 {
+		p.logger.Printf("[pulse] retention cleanup pruned %d records (requests=%d queries=%d errors=%d alerts=%d)",
+			result.Total(), result.Requests, result.Queries, result.Errors, result.Alerts)
+	}
+
+	return status
+}
+
+// Status returns the outcome of the most recent retention pass.
+func (w *RetentionWorker) Status() RetentionStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
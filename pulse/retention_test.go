@@ -0,0 +1,144 @@
+package pulse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplyDefaults_Retention(t *testing.T) {
+	cfg := applyDefaults(Config{
+		Storage: StorageConfig{
+			RetentionHours: 48,
+			Retention:      RetentionConfig{Requests: time.Hour},
+		},
+	})
+
+	r := cfg.Storage.Retention
+	if r.Requests != time.Hour {
+		t.Fatalf("expected explicit request retention to be kept, got %v", r.Requests)
+	}
+	if r.Errors != 48*time.Hour || r.Dependencies != 48*time.Hour {
+		t.Fatalf("expected unset retention to default to RetentionHours, got %v / %v", r.Errors, r.Dependencies)
+	}
+	if cfg.Storage.CleanupInterval != 5*time.Minute {
+		t.Fatalf("expected default cleanup interval 5m, got %v", cfg.Storage.CleanupInterval)
+	}
+}
+
+func TestMemoryStorage_PrunePerType(t *testing.T) {
+	s := newTestStorage()
+	now := time.Now()
+	old := now.Add(-3 * time.Hour)
+
+	for _, ts := range []time.Time{old, old, now} {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/users", Timestamp: ts})
+		s.StoreQuery(QueryMetric{SQL: "SELECT 1", Timestamp: ts})
+		s.StoreHealthResult(HealthCheckResult{Name: "db", Timestamp: ts})
+	}
+	s.StoreError(ErrorRecord{ID: "e-old", Fingerprint: "fp-old", Count: 1, FirstSeen: old, LastSeen: old})
+	s.StoreAlert(AlertRecord{ID: "a-old", FiredAt: old})
+	s.StoreN1Detection(N1Detection{Pattern: "select ?", DetectedAt: old})
+
+	// Requests expire after 1h, everything else is kept for a day
+	retention := uniformRetention(24 * time.Hour)
+	retention.Requests = time.Hour
+
+	result, err := s.Prune(retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Requests != 2 {
+		t.Fatalf("expected 2 requests pruned, got %d", result.Requests)
+	}
+	if result.Queries != 0 || result.Errors != 0 || result.Alerts != 0 || result.Health != 0 {
+		t.Fatalf("expected other data to be kept, got %+v", result)
+	}
+
+	reqs, _ := s.GetRequests(RequestFilter{})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request left, got %d", len(reqs))
+	}
+
+	result, _ = s.Prune(uniformRetention(time.Hour))
+	if result.Queries != 2 || result.Health != 2 || result.Errors != 1 || result.Alerts != 1 || result.N1Detections != 1 {
+		t.Fatalf("unexpected prune result: %+v", result)
+	}
+	if result.Total() != 7 {
+		t.Fatalf("expected 7 records pruned in total, got %d", result.Total())
+	}
+}
+
+func TestRetentionWorker_RunRecordsStatus(t *testing.T) {
+	p := newPulse(applyDefaults(Config{}))
+	p.storage = NewMemoryStorage("test")
+	defer p.Shutdown()
+
+	old := time.Now().Add(-48 * time.Hour)
+	p.storage.StoreError(ErrorRecord{ID: "e-old", Fingerprint: "fp-old", Count: 1, FirstSeen: old, LastSeen: old})
+	p.storage.StoreRequest(RequestMetric{Method: "GET", Path: "/old", Timestamp: old})
+
+	w := &RetentionWorker{pulse: p}
+	status := w.run(time.Minute)
+
+	if status.Runs != 1 || status.LastRun.IsZero() {
+		t.Fatalf("expected a recorded run, got %+v", status)
+	}
+	if status.Pruned.Errors != 1 || status.Pruned.Requests != 1 {
+		t.Fatalf("expected pruned error and request, got %+v", status.Pruned)
+	}
+	if !status.NextRun.Equal(status.LastRun.Add(time.Minute)) {
+		t.Fatalf("expected next run one interval later, got %v", status.NextRun)
+	}
+	if w.Status().Runs != 1 {
+		t.Fatal("expected Status to return the last run")
+	}
+}
+
+func TestMount_StartsRetentionWorker(t *testing.T) {
+	_, p := setupTestRouter()
+	defer p.Shutdown()
+
+	if p.retention == nil {
+		t.Fatal("expected retention worker to be started")
+	}
+
+	// The first pass runs immediately on startup
+	deadline := time.Now().Add(2 * time.Second)
+	for p.retention.Status().Runs == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.retention.Status().Runs == 0 {
+		t.Fatal("expected an initial retention pass")
+	}
+}
+
+func TestAPI_RetentionStatus(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	token := loginAndGetToken(t, router)
+
+	p.retention = &RetentionWorker{pulse: p}
+	p.retention.run(p.config.Storage.CleanupInterval)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/settings/retention", token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Policy      RetentionConfig `json:"policy"`
+		LastCleanup RetentionStatus `json:"last_cleanup"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Policy.Requests != 24*time.Hour {
+		t.Fatalf("expected 24h request retention, got %v", resp.Policy.Requests)
+	}
+	if resp.LastCleanup.Runs != 1 || resp.LastCleanup.LastRun.IsZero() {
+		t.Fatalf("expected last cleanup to be reported, got %+v", resp.LastCleanup)
+	}
+}
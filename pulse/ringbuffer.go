@@ -5,15 +5,16 @@ import (
 	"sync/atomic"
 )
 
-// RingBuffer is a lock-free, fixed-capacity circular buffer for high-throughput
-// metric ingestion. When full, the oldest items are silently overwritten.
-// Push is lock-free (atomic CAS on head). Read operations take a read lock.
+// RingBuffer is a fixed-capacity circular buffer for high-throughput metric
+// ingestion. When full, the oldest items are silently overwritten.
+// Writers (Push, Prune, Remove, Reset) hold the write lock and read operations
+// take a read lock; Len and Dropped read the atomic counters without locking.
 type RingBuffer[T any] struct {
 	data     []T
-	head     atomic.Int64
-	size     atomic.Int64
+	head     atomic.Int64 // written under mu
+	size     atomic.Int64 // written under mu
 	capacity int64
-	mu       sync.RWMutex // protects reads for consistent snapshots
+	mu       sync.RWMutex
 }
 
 // NewRingBuffer creates a new RingBuffer with the given capacity.
@@ -30,23 +31,15 @@ func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
 // Push adds an item to the ring buffer. If full, the oldest item is overwritten.
 // This method is safe for concurrent use.
 func (rb *RingBuffer[T]) Push(item T) {
-	idx := rb.head.Add(1) - 1
-	pos := idx % rb.capacity
-
+	// Slot, head and size change together so Prune and Remove, which
+	// compact the slots, never see a half-finished push
 	rb.mu.Lock()
-	rb.data[pos] = item
-	rb.mu.Unlock()
-
-	// Update size (cap at capacity)
-	for {
-		current := rb.size.Load()
-		newSize := current + 1
-		if newSize > rb.capacity {
-			newSize = rb.capacity
-		}
-		if rb.size.CompareAndSwap(current, newSize) {
-			break
-		}
+	defer rb.mu.Unlock()
+
+	idx := rb.head.Add(1) - 1
+	rb.data[idx%rb.capacity] = item
+	if size := rb.size.Load(); size < rb.capacity {
+		rb.size.Store(size + 1)
 	}
 }
 
@@ -55,6 +48,24 @@ func (rb *RingBuffer[T]) Len() int {
 	return int(rb.size.Load())
 }
 
+// Oldest returns the oldest item in the buffer, if any.
+func (rb *RingBuffer[T]) Oldest() (T, bool) {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	var zero T
+	if rb.size.Load() == 0 {
+		return zero, false
+	}
+	return rb.data[rb.oldest()], true
+}
+
+// Dropped reports whether any item has been overwritten or pruned since the
+// buffer was created or last reset.
+func (rb *RingBuffer[T]) Dropped() bool {
+	return rb.head.Load() > rb.size.Load()
+}
+
 // GetAll returns all items in the buffer ordered from oldest to newest.
 func (rb *RingBuffer[T]) GetAll() []T {
 	rb.mu.RLock()
@@ -65,20 +76,12 @@ func (rb *RingBuffer[T]) GetAll() []T {
 		return nil
 	}
 
-	head := rb.head.Load()
 	result := make([]T, size)
 
-	if size < rb.capacity {
-		// Buffer not yet full — items are at indices 0..size-1
-		copy(result, rb.data[:size])
-	} else {
-		// Buffer is full — oldest item is at head % capacity
-		start := head % rb.capacity
-		// Copy from start to end of backing array
-		n := copy(result, rb.data[start:])
-		// Copy from beginning of backing array to start
-		copy(result[n:], rb.data[:start])
-	}
+	// Oldest item is at start; the items may wrap around the backing array
+	start := rb.oldest()
+	n := copy(result, rb.data[start:min(start+size, rb.capacity)])
+	copy(result[n:], rb.data[:size-int64(n)])
 
 	return result
 }
@@ -136,23 +139,79 @@ func (rb *RingBuffer[T]) ForEach(fn func(item T) bool) {
 		return
 	}
 
-	head := rb.head.Load()
+	start := rb.oldest()
+	for i := int64(0); i < size; i++ {
+		pos := (start + i) % rb.capacity
+		if !fn(rb.data[pos]) {
+			return
+		}
+	}
+}
 
-	if size < rb.capacity {
-		for i := int64(0); i < size; i++ {
-			if !fn(rb.data[i]) {
-				return
-			}
+// Prune removes items from the oldest end while expired returns true and
+// returns the number of items removed. Items are assumed to be roughly in
+// insertion order, so pruning stops at the first item that is not expired.
+func (rb *RingBuffer[T]) Prune(expired func(item T) bool) int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	var zero T
+	removed := 0
+	for rb.size.Load() > 0 {
+		pos := rb.oldest()
+		if !expired(rb.data[pos]) {
+			break
+		}
+		rb.data[pos] = zero
+		rb.size.Add(-1)
+		removed++
+	}
+	return removed
+}
+
+// Remove removes every item matching the predicate, keeping the rest in
+// order, and returns the number of items removed.
+func (rb *RingBuffer[T]) Remove(match func(item T) bool) int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	size := rb.size.Load()
+	start := rb.oldest()
+	kept := make([]T, 0, size)
+	for i := int64(0); i < size; i++ {
+		if item := rb.data[(start+i)%rb.capacity]; !match(item) {
+			kept = append(kept, item)
 		}
-	} else {
-		start := head % rb.capacity
-		for i := int64(0); i < rb.capacity; i++ {
-			pos := (start + i) % rb.capacity
-			if !fn(rb.data[pos]) {
-				return
-			}
+	}
+	removed := int(size) - len(kept)
+	if removed == 0 {
+		return 0
+	}
+
+	// Repack the kept items so the newest still sits just before head
+	var zero T
+	for i := range rb.data {
+		rb.data[i] = zero
+	}
+	head := rb.head.Load()
+	for i, item := range kept {
+		pos := (head - int64(len(kept)) + int64(i)) % rb.capacity
+		if pos < 0 {
+			pos += rb.capacity
 		}
+		rb.data[pos] = item
+	}
+	rb.size.Store(int64(len(kept)))
+	return removed
+}
+
+// oldest returns the backing array index of the oldest item. Caller must hold mu.
+func (rb *RingBuffer[T]) oldest() int64 {
+	pos := (rb.head.Load() - rb.size.Load()) % rb.capacity
+	if pos < 0 {
+		pos += rb.capacity
 	}
+	return pos
 }
 
 // Filter returns all items matching the predicate, ordered oldest to newest.
@@ -163,6 +163,72 @@ func TestRingBuffer_Empty(t *testing.T) {
 	}
 }
 
+func TestRingBuffer_Prune(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := 1; i <= 6; i++ {
+		rb.Push(i) // buffer wraps: 3, 4, 5, 6
+	}
+
+	removed := rb.Prune(func(v int) bool { return v < 5 })
+	if removed != 2 {
+		t.Fatalf("expected 2 pruned, got %d", removed)
+	}
+
+	all := rb.GetAll()
+	if len(all) != 2 || all[0] != 5 || all[1] != 6 {
+		t.Fatalf("expected [5 6], got %v", all)
+	}
+
+	// Pushing after a prune keeps insertion order
+	rb.Push(7)
+	rb.Push(8)
+	rb.Push(9)
+	all = rb.GetAll()
+	expected := []int{6, 7, 8, 9}
+	if len(all) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, all)
+	}
+	for i, v := range expected {
+		if all[i] != v {
+			t.Fatalf("expected %v, got %v", expected, all)
+		}
+	}
+
+	// Pruning stops at the first item that is not expired
+	if removed := rb.Prune(func(v int) bool { return v != 7 }); removed != 1 {
+		t.Fatalf("expected 1 pruned before a live item, got %d", removed)
+	}
+}
+
+func TestRingBuffer_Remove(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := 1; i <= 6; i++ {
+		rb.Push(i) // buffer wraps: 3, 4, 5, 6
+	}
+
+	removed := rb.Remove(func(v int) bool { return v%2 == 0 })
+	if removed != 2 {
+		t.Fatalf("expected 2 removed, got %d", removed)
+	}
+	if all := rb.GetAll(); len(all) != 2 || all[0] != 3 || all[1] != 5 {
+		t.Fatalf("expected [3 5], got %v", all)
+	}
+	if last := rb.GetLast(1); len(last) != 1 || last[0] != 5 {
+		t.Fatalf("expected 5 as the newest item, got %v", last)
+	}
+
+	// Pushing after a removal keeps insertion order
+	rb.Push(7)
+	rb.Push(8)
+	rb.Push(9)
+	if all := rb.GetAll(); len(all) != 4 || all[0] != 5 || all[3] != 9 {
+		t.Fatalf("expected [5 7 8 9], got %v", all)
+	}
+	if rb.Remove(func(int) bool { return false }) != 0 {
+		t.Error("expected nothing removed")
+	}
+}
+
 func TestRingBuffer_ConcurrentPush(t *testing.T) {
 	rb := NewRingBuffer[int](1000)
 	var wg sync.WaitGroup
@@ -184,6 +250,68 @@ func TestRingBuffer_ConcurrentPush(t *testing.T) {
 	}
 }
 
+func TestRingBuffer_ConcurrentPushAndCompact(t *testing.T) {
+	// Capacity exceeds the pushes so nothing is overwritten and every item
+	// must end up either in the buffer or counted by Prune/Remove
+	const writers, perWriter = 4, 1000
+	for round := 0; round < 10; round++ {
+		rb := NewRingBuffer[int](8192)
+
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(start int) {
+				defer wg.Done()
+				for j := 1; j <= perWriter; j++ {
+					rb.Push(start*perWriter + j) // never the zero value
+				}
+			}(i)
+		}
+
+		done := make(chan struct{})
+		var compactors sync.WaitGroup
+		var pruned, removed int
+		compactors.Add(2)
+		go func() {
+			defer compactors.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					pruned += rb.Prune(func(v int) bool { return v%2 == 0 })
+				}
+			}
+		}()
+		go func() {
+			defer compactors.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					removed += rb.Remove(func(v int) bool { return v%5 == 0 })
+				}
+			}
+		}()
+
+		wg.Wait()
+		close(done)
+		compactors.Wait()
+
+		all := rb.GetAll()
+		for i, v := range all {
+			if v == 0 {
+				t.Fatalf("round %d: zero-value item at position %d of %d", round, i, len(all))
+			}
+		}
+		if total := len(all) + pruned + removed; total != writers*perWriter {
+			t.Fatalf("round %d: expected %d items accounted for, got %d (%d live, %d pruned, %d removed)",
+				round, writers*perWriter, total, len(all), pruned, removed)
+		}
+	}
+}
+
 func TestRingBuffer_DefaultCapacity(t *testing.T) {
 	rb := NewRingBuffer[int](0)
 	if rb.capacity != 1024 {
@@ -0,0 +1,467 @@
+package pulse
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultMinuteRollupRetention is how long 1-minute rollups are kept
+	// (a little over Last24h so the full range is always covered).
+	defaultMinuteRollupRetention = 25 * time.Hour
+	// defaultHourRollupRetention is how long 1-hour rollups are kept (covers Last7d).
+	defaultHourRollupRetention = 8 * 24 * time.Hour
+)
+
+// requestRollup aggregates the requests of one route within a rollup bucket.
+type requestRollup struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Count       float64         `json:"count"` // extrapolated from sampled requests
+	ErrorCount  float64         `json:"error_count"`
+	StatusCodes map[int]float64 `json:"status_codes"`
+	Latency     LatencySketch   `json:"latency"` // recorded requests only
+
+	// Where the recorded requests' latency went, in total
+	DBTime         time.Duration `json:"db_time,omitempty"`
+	DependencyTime time.Duration `json:"dependency_time,omitempty"`
+	AppTime        time.Duration `json:"app_time,omitempty"`
+}
+
+// queryRollup aggregates one normalized query pattern within a rollup bucket.
+type queryRollup struct {
+	Pattern    string        `json:"pattern"`
+	Operation  string        `json:"operation"`
+	Table      string        `json:"table"`
+	Count      int64         `json:"count"`
+	ErrorCount int64         `json:"error_count"`
+	Duration   LatencySketch `json:"duration"`
+}
+
+// rollupBucket holds the aggregates for one interval of a rollup tier.
+type rollupBucket struct {
+	Start          time.Time                 `json:"start"`
+	Requests       map[string]*requestRollup `json:"requests"`
+	Queries        map[string]*queryRollup   `json:"queries"`
+	Runtime        *RuntimeMetric            `json:"runtime,omitempty"` // latest sample in the bucket
+	RuntimeSamples int64                     `json:"runtime_samples"`
+}
+
+// rollupTier is a set of fixed-width buckets at one resolution.
+type rollupTier struct {
+	Resolution time.Duration           `json:"resolution"`
+	Retention  time.Duration           `json:"retention"`
+	Buckets    map[int64]*rollupBucket `json:"buckets"` // keyed by bucket start (UnixNano)
+
+	mu sync.RWMutex
+}
+
+// rollupTierJSON has the fields of rollupTier without its methods.
+type rollupTierJSON rollupTier
+
+// MarshalJSON encodes the tier under its read lock, so snapshots can be
+// taken while metrics are folded in.
+func (t *rollupTier) MarshalJSON() ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return json.Marshal((*rollupTierJSON)(t))
+}
+
+// rollupStore continuously downsamples raw request, query and runtime
+// metrics into 1-minute and 1-hour tiers so long time ranges can be served
+// after the raw data has been evicted.
+type rollupStore struct {
+	tiers []*rollupTier // finest first
+
+	// restored is set once tiers are loaded from a snapshot, whose raw
+	// buffers may not reach back as far as the tiers do
+	restored atomic.Bool
+}
+
+// newRollupStore creates a rollup store with the default 1m and 1h tiers.
+func newRollupStore() *rollupStore {
+	return &rollupStore{
+		tiers: []*rollupTier{
+			newRollupTier(time.Minute, defaultMinuteRollupRetention),
+			newRollupTier(time.Hour, defaultHourRollupRetention),
+		},
+	}
+}
+
+func newRollupTier(resolution, retention time.Duration) *rollupTier {
+	return &rollupTier{
+		Resolution: resolution,
+		Retention:  retention,
+		Buckets:    make(map[int64]*rollupBucket),
+	}
+}
+
+// --- Ingestion ---
+
+// addRequest folds a request metric into every tier.
+func (r *rollupStore) addRequest(m RequestMetric) {
+	key := m.Method + " " + m.Path
+	for _, t := range r.tiers {
+		t.update(m.Timestamp, func(b *rollupBucket) {
+			rr, ok := b.Requests[key]
+			if !ok {
+				rr = &requestRollup{Method: m.Method, Path: m.Path, StatusCodes: make(map[int]float64)}
+				b.Requests[key] = rr
+			}
+			w := m.weight()
+			rr.Count += w
+			if m.StatusCode >= 400 {
+				rr.ErrorCount += w
+			}
+			rr.StatusCodes[m.StatusCode] += w
+			rr.Latency.Add(m.Latency)
+			rr.DBTime += m.DBTime
+			rr.DependencyTime += m.DependencyTime
+			rr.AppTime += m.appTime()
+		})
+	}
+}
+
+// addQuery folds a query metric into every tier.
+func (r *rollupStore) addQuery(m QueryMetric) {
+	key := m.NormalizedSQL
+	if key == "" {
+		key = m.SQL
+	}
+	for _, t := range r.tiers {
+		t.update(m.Timestamp, func(b *rollupBucket) {
+			qr, ok := b.Queries[key]
+			if !ok {
+				qr = &queryRollup{Pattern: key, Operation: m.Operation, Table: m.Table}
+				b.Queries[key] = qr
+			}
+			qr.Count++
+			if m.Error != "" {
+				qr.ErrorCount++
+			}
+			qr.Duration.Add(m.Duration)
+		})
+	}
+}
+
+// addRuntime keeps the latest runtime sample per bucket in every tier.
+func (r *rollupStore) addRuntime(m RuntimeMetric) {
+	for _, t := range r.tiers {
+		t.update(m.Timestamp, func(b *rollupBucket) {
+			b.RuntimeSamples++
+			if b.Runtime == nil || !m.Timestamp.Before(b.Runtime.Timestamp) {
+				sample := m
+				b.Runtime = &sample
+			}
+		})
+	}
+}
+
+// reset clears every tier.
+func (r *rollupStore) reset() {
+	for _, t := range r.tiers {
+		t.mu.Lock()
+		t.Buckets = make(map[int64]*rollupBucket)
+		t.mu.Unlock()
+	}
+	r.restored.Store(false)
+}
+
+// restore replaces the buckets of every tier with those of the saved tier at
+// the same resolution, skipping buckets past the tier's retention.
+func (r *rollupStore) restore(saved []*rollupTier) {
+	if len(saved) == 0 {
+		return
+	}
+	for _, st := range saved {
+		for _, t := range r.tiers {
+			if t.Resolution != st.Resolution {
+				continue
+			}
+			cutoff := time.Now().Add(-t.Retention)
+			buckets := make(map[int64]*rollupBucket, len(st.Buckets))
+			for k, b := range st.Buckets {
+				if b == nil || b.Start.Add(t.Resolution).Before(cutoff) {
+					continue
+				}
+				if b.Requests == nil {
+					b.Requests = make(map[string]*requestRollup)
+				}
+				if b.Queries == nil {
+					b.Queries = make(map[string]*queryRollup)
+				}
+				buckets[k] = b
+			}
+			t.mu.Lock()
+			t.Buckets = buckets
+			t.mu.Unlock()
+		}
+	}
+	r.restored.Store(true)
+}
+
+// update applies fn to the bucket containing ts, creating it if needed.
+// Samples older than the tier's retention are ignored.
+func (t *rollupTier) update(ts time.Time, fn func(b *rollupBucket)) {
+	cutoff := time.Now().Add(-t.Retention)
+	if ts.Before(cutoff) {
+		return
+	}
+
+	start := ts.Truncate(t.Resolution)
+	key := start.UnixNano()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.Buckets[key]
+	if !ok {
+		b = &rollupBucket{
+			Start:    start,
+			Requests: make(map[string]*requestRollup),
+			Queries:  make(map[string]*queryRollup),
+		}
+		t.Buckets[key] = b
+
+		// Expire old buckets whenever a new one is opened
+		for k, old := range t.Buckets {
+			if old.Start.Add(t.Resolution).Before(cutoff) {
+				delete(t.Buckets, k)
+			}
+		}
+	}
+	fn(b)
+}
+
+// --- Tier selection ---
+
+// tierFor returns the tier that should serve reads for tr, or nil when the
+// raw data still covers the range. Raw data is incomplete once its buffer has
+// dropped samples and the range reaches back past the oldest one left.
+//
+// Among the tiers whose retention reaches tr.Start, the coarsest one that is
+// at least as fine as ResolutionForRange(tr) is chosen.
+func (r *rollupStore) tierFor(tr TimeRange, rawDropped bool, rawOldest time.Time) *rollupTier {
+	if !rawDropped {
+		return nil
+	}
+	if !rawOldest.IsZero() && !rawOldest.After(tr.Start) {
+		return nil
+	}
+
+	age := time.Since(tr.Start)
+	var covering []*rollupTier
+	for _, t := range r.tiers {
+		if t.Retention >= age {
+			covering = append(covering, t)
+		}
+	}
+	if len(covering) == 0 {
+		// Nothing reaches back far enough; serve what the longest tier has
+		return r.tiers[len(r.tiers)-1]
+	}
+
+	target := ResolutionForRange(tr)
+	choice := covering[0]
+	for _, t := range covering {
+		if t.Resolution <= target {
+			choice = t
+		}
+	}
+	return choice
+}
+
+// rollupTierFor picks the tier for reads over tr given the raw buffer the
+// data would otherwise come from. After a snapshot restore the raw buffer is
+// treated as incomplete, since the tiers may reach back further.
+func rollupTierFor[T any](r *rollupStore, rb *RingBuffer[T], ts func(T) time.Time, tr TimeRange) *rollupTier {
+	var oldest time.Time
+	if item, ok := rb.Oldest(); ok {
+		oldest = ts(item)
+	}
+	return r.tierFor(tr, rb.Dropped() || r.restored.Load(), oldest)
+}
+
+// --- Tier reads ---
+
+// bucketsIn returns the buckets overlapping tr in chronological order.
+// Caller must hold t.mu.
+func (t *rollupTier) bucketsIn(tr TimeRange) []*rollupBucket {
+	start := tr.Start.Truncate(t.Resolution)
+	var result []*rollupBucket
+	for _, b := range t.Buckets {
+		if (tr.Start.IsZero() || !b.Start.Before(start)) && (tr.End.IsZero() || !b.Start.After(tr.End)) {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+// routeStats merges request rollups per route, sorted by request count descending.
+func (t *rollupTier) routeStats(tr TimeRange) []RouteStats {
+	t.mu.RLock()
+	merged := make(map[string]*requestRollup)
+	for _, b := range t.bucketsIn(tr) {
+		for key, rr := range b.Requests {
+			m, ok := merged[key]
+			if !ok {
+				m = &requestRollup{Method: rr.Method, Path: rr.Path, StatusCodes: make(map[int]float64)}
+				merged[key] = m
+			}
+			m.Count += rr.Count
+			m.ErrorCount += rr.ErrorCount
+			for code, n := range rr.StatusCodes {
+				m.StatusCodes[code] += n
+			}
+			m.Latency.Merge(&rr.Latency)
+			m.DBTime += rr.DBTime
+			m.DependencyTime += rr.DependencyTime
+			m.AppTime += rr.AppTime
+		}
+	}
+	t.mu.RUnlock()
+
+	minutes := tr.End.Sub(tr.Start).Minutes()
+	stats := make([]RouteStats, 0, len(merged))
+	for _, m := range merged {
+		rpm := float64(0)
+		if minutes > 0 {
+			rpm = m.Count / minutes
+		}
+		statusCodes := make(map[int]int64, len(m.StatusCodes))
+		for code, n := range m.StatusCodes {
+			statusCodes[code] = int64(math.Round(n))
+		}
+		rs := RouteStats{
+			Method:       m.Method,
+			Path:         m.Path,
+			RequestCount: int64(math.Round(m.Count)),
+			ErrorCount:   int64(math.Round(m.ErrorCount)),
+			ErrorRate:    m.ErrorCount / m.Count * 100,
+			AvgLatency:   m.Latency.Avg(),
+			MinLatency:   m.Latency.Min,
+			MaxLatency:   m.Latency.Max,
+			P50Latency:   m.Latency.Percentile(50),
+			P75Latency:   m.Latency.Percentile(75),
+			P90Latency:   m.Latency.Percentile(90),
+			P95Latency:   m.Latency.Percentile(95),
+			P99Latency:   m.Latency.Percentile(99),
+			RPM:          rpm,
+			StatusCodes:  statusCodes,
+			Trend:        "stable",
+		}
+		timeShares{latency: m.Latency.Sum, db: m.DBTime, dependencies: m.DependencyTime, app: m.AppTime}.apply(&rs)
+		stats = append(stats, rs)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].RequestCount > stats[j].RequestCount
+	})
+	return stats
+}
+
+// queryPatterns merges query rollups per pattern, sorted by total duration descending.
+func (t *rollupTier) queryPatterns(tr TimeRange) []QueryPattern {
+	t.mu.RLock()
+	merged := make(map[string]*queryRollup)
+	for _, b := range t.bucketsIn(tr) {
+		for key, qr := range b.Queries {
+			m, ok := merged[key]
+			if !ok {
+				m = &queryRollup{Pattern: qr.Pattern, Operation: qr.Operation, Table: qr.Table}
+				merged[key] = m
+			}
+			m.Count += qr.Count
+			m.ErrorCount += qr.ErrorCount
+			m.Duration.Merge(&qr.Duration)
+		}
+	}
+	t.mu.RUnlock()
+
+	result := make([]QueryPattern, 0, len(merged))
+	for _, m := range merged {
+		result = append(result, QueryPattern{
+			NormalizedSQL: m.Pattern,
+			Operation:     m.Operation,
+			Table:         m.Table,
+			Count:         m.Count,
+			AvgDuration:   m.Duration.Avg(),
+			MaxDuration:   m.Duration.Max,
+			TotalDuration: m.Duration.Sum,
+			ErrorCount:    m.ErrorCount,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalDuration > result[j].TotalDuration
+	})
+	return result
+}
+
+// requestSeries re-buckets request rollups into time-series buckets of the
+// given resolution (never finer than the tier), filling gaps with zeros.
+func (t *rollupTier) requestSeries(tr TimeRange, resolution time.Duration) []TimeSeriesBucket {
+	if resolution < t.Resolution {
+		resolution = t.Resolution
+	}
+
+	bucketMap := make(map[int64]*TimeSeriesBucket)
+	counts := make(map[int64]*weightedCount)
+	t.mu.RLock()
+	for _, b := range t.bucketsIn(tr) {
+		ts := b.Start.Truncate(resolution)
+		sb, ok := bucketMap[ts.Unix()]
+		if !ok {
+			sb = &TimeSeriesBucket{Timestamp: ts}
+			bucketMap[ts.Unix()] = sb
+			counts[ts.Unix()] = &weightedCount{}
+		}
+		for _, rr := range b.Requests {
+			counts[ts.Unix()].add(rr.Count, rr.ErrorCount)
+			sb.Samples += rr.Latency.Count
+			sb.TotalLatency += rr.Latency.Sum
+		}
+	}
+	t.mu.RUnlock()
+	setBucketCounts(bucketMap, counts)
+
+	return fillTimeSeriesBuckets(bucketMap, tr.Start.Truncate(resolution), tr.End, resolution)
+}
+
+// runtimeSeries returns the latest runtime sample per bucket of the given
+// resolution (never finer than the tier), oldest first.
+func (t *rollupTier) runtimeSeries(tr TimeRange, resolution time.Duration) []RuntimeMetric {
+	if resolution < t.Resolution {
+		resolution = t.Resolution
+	}
+
+	latest := make(map[int64]RuntimeMetric)
+	t.mu.RLock()
+	for _, b := range t.bucketsIn(tr) {
+		if b.Runtime == nil {
+			continue
+		}
+		key := b.Start.Truncate(resolution).Unix()
+		if existing, ok := latest[key]; !ok || b.Runtime.Timestamp.After(existing.Timestamp) {
+			latest[key] = *b.Runtime
+		}
+	}
+	t.mu.RUnlock()
+
+	if len(latest) == 0 {
+		return nil
+	}
+	result := make([]RuntimeMetric, 0, len(latest))
+	for _, m := range latest {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
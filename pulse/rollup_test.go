@@ -0,0 +1,239 @@
+package pulse
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// newEvictingStorage returns a memory storage whose raw buffers hold only a
+// few items, so older data is only available from the rollup tiers.
+func newEvictingStorage(capacity int) *MemoryStorage {
+	s := NewMemoryStorage("test")
+	s.requests = NewRingBuffer[RequestMetric](capacity)
+	s.queries = NewRingBuffer[QueryMetric](capacity)
+	s.runtimeStats = NewRingBuffer[RuntimeMetric](capacity)
+	return s
+}
+
+func TestRollupStore_TierFor(t *testing.T) {
+	r := newRollupStore()
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		tr         TimeRange
+		dropped    bool
+		rawOldest  time.Time
+		resolution time.Duration // 0 means raw
+	}{
+		{"nothing dropped", Last24h(), false, now.Add(-time.Hour), 0},
+		{"raw covers range", Last1h(), true, now.Add(-2 * time.Hour), 0},
+		{"1h range", Last1h(), true, now.Add(-time.Minute), time.Minute},
+		{"24h range", Last24h(), true, now.Add(-time.Minute), time.Minute},
+		{"7d range", Last7d(), true, now.Add(-time.Minute), time.Hour},
+		{"raw empty", Last24h(), true, time.Time{}, time.Minute},
+		{"beyond all tiers", TimeRange{Start: now.Add(-30 * 24 * time.Hour), End: now}, true, now, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier := r.tierFor(tt.tr, tt.dropped, tt.rawOldest)
+			if tt.resolution == 0 {
+				if tier != nil {
+					t.Fatalf("expected raw reads, got %v tier", tier.Resolution)
+				}
+				return
+			}
+			if tier == nil {
+				t.Fatal("expected a rollup tier, got raw")
+			}
+			if tier.Resolution != tt.resolution {
+				t.Errorf("expected %v tier, got %v", tt.resolution, tier.Resolution)
+			}
+		})
+	}
+}
+
+func TestMemoryStorage_RouteStatsFromRollups(t *testing.T) {
+	s := newEvictingStorage(10)
+	now := time.Now()
+
+	// 120 requests over the last 2 hours; only the last 10 stay raw
+	for i := 0; i < 120; i++ {
+		status := 200
+		if i%4 == 0 {
+			status = 500
+		}
+		s.StoreRequest(RequestMetric{
+			Method:     "GET",
+			Path:       "/api/users",
+			StatusCode: status,
+			Latency:    time.Duration(i+1) * time.Millisecond,
+			DBTime:     time.Duration(i+1) * time.Millisecond / 4,
+			Timestamp:  now.Add(-2*time.Hour + time.Duration(i)*time.Minute),
+		})
+	}
+
+	stats, err := s.GetRouteStats(Last24h())
+	if err != nil {
+		t.Fatalf("GetRouteStats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(stats))
+	}
+	st := stats[0]
+	if st.RequestCount != 120 {
+		t.Errorf("expected 120 requests from rollups, got %d", st.RequestCount)
+	}
+	if st.ErrorCount != 30 {
+		t.Errorf("expected 30 errors, got %d", st.ErrorCount)
+	}
+	if st.StatusCodes[200] != 90 || st.StatusCodes[500] != 30 {
+		t.Errorf("unexpected status codes: %v", st.StatusCodes)
+	}
+	if st.MinLatency != time.Millisecond || st.MaxLatency != 120*time.Millisecond {
+		t.Errorf("expected min 1ms / max 120ms, got %v / %v", st.MinLatency, st.MaxLatency)
+	}
+	if !withinRelative(st.P95Latency, 114*time.Millisecond, 0.02) {
+		t.Errorf("expected p95 ~114ms, got %v", st.P95Latency)
+	}
+	if math.Abs(st.DBShare-25) > 1e-9 || math.Abs(st.AppShare-75) > 1e-9 {
+		t.Errorf("expected 25%% of latency in the database, got %.2f%% (app %.2f%%)", st.DBShare, st.AppShare)
+	}
+
+	// A range the raw buffer still covers is served from raw data
+	recent, _ := s.GetRouteStats(TimeRange{Start: now.Add(-5 * time.Minute), End: now})
+	if len(recent) != 1 || recent[0].RequestCount != 5 {
+		t.Errorf("expected 5 raw requests in the last 5m, got %+v", recent)
+	}
+}
+
+func TestMemoryStorage_QueryPatternsFromRollups(t *testing.T) {
+	s := newEvictingStorage(5)
+	now := time.Now()
+
+	for i := 0; i < 50; i++ {
+		m := QueryMetric{
+			SQL:           "SELECT * FROM users WHERE id = 1",
+			NormalizedSQL: "SELECT * FROM users WHERE id = ?",
+			Operation:     "SELECT",
+			Table:         "users",
+			Duration:      10 * time.Millisecond,
+			Timestamp:     now.Add(-time.Hour + time.Duration(i)*time.Minute),
+		}
+		if i%10 == 0 {
+			m.Error = "timeout"
+		}
+		s.StoreQuery(m)
+	}
+
+	patterns, err := s.GetQueryPatterns(Last24h())
+	if err != nil {
+		t.Fatalf("GetQueryPatterns: %v", err)
+	}
+	if len(patterns) != 1 {
+		t.Fatalf("expected 1 pattern, got %d", len(patterns))
+	}
+	if patterns[0].Count != 50 || patterns[0].ErrorCount != 5 {
+		t.Errorf("expected 50 queries / 5 errors, got %d / %d", patterns[0].Count, patterns[0].ErrorCount)
+	}
+	if patterns[0].TotalDuration != 500*time.Millisecond {
+		t.Errorf("expected total 500ms, got %v", patterns[0].TotalDuration)
+	}
+}
+
+func TestRollupRequests_FromRollups(t *testing.T) {
+	p := setupAggregatorPulse(t)
+	p.storage = newEvictingStorage(10)
+
+	now := time.Now()
+	injectRequests(p, 100, "GET", "/api/data", now.Add(-3*time.Hour), time.Minute, 200, 40*time.Millisecond)
+
+	tr := Last24h()
+	buckets := rollupRequests(p.storage, tr, 30*time.Second)
+	if len(buckets) < 2 {
+		t.Fatalf("expected multiple buckets, got %d", len(buckets))
+	}
+	if step := buckets[1].Timestamp.Sub(buckets[0].Timestamp); step != time.Minute {
+		t.Errorf("expected buckets no finer than the 1m tier, got %v", step)
+	}
+
+	var total int64
+	for _, b := range buckets {
+		total += b.Count
+		if b.Count > 0 && b.TotalLatency/time.Duration(b.Count) != 40*time.Millisecond {
+			t.Errorf("expected avg latency 40ms, got %v", b.TotalLatency/time.Duration(b.Count))
+		}
+	}
+	if total != 100 {
+		t.Errorf("expected 100 requests across buckets, got %d", total)
+	}
+}
+
+func TestRollupRuntime_FromRollups(t *testing.T) {
+	s := newEvictingStorage(5)
+	now := time.Now()
+
+	for i := 0; i < 120; i++ {
+		s.StoreRuntime(RuntimeMetric{
+			NumGoroutine: i,
+			Timestamp:    now.Add(-2*time.Hour + time.Duration(i)*30*time.Second),
+		})
+	}
+
+	result := RollupRuntime(s, Last24h(), 5*time.Minute)
+	if len(result) < 12 || len(result) > 14 {
+		t.Fatalf("expected ~13 five-minute buckets, got %d", len(result))
+	}
+	for i := 1; i < len(result); i++ {
+		if !result[i].Timestamp.After(result[i-1].Timestamp) {
+			t.Fatal("expected runtime samples in chronological order")
+		}
+	}
+	if last := result[len(result)-1]; last.NumGoroutine != 119 {
+		t.Errorf("expected latest sample in last bucket, got goroutines=%d", last.NumGoroutine)
+	}
+}
+
+func TestRollupTier_IgnoresAndExpiresOldBuckets(t *testing.T) {
+	tier := newRollupTier(time.Minute, time.Hour)
+	now := time.Now()
+
+	tier.update(now.Add(-2*time.Hour), func(b *rollupBucket) { b.RuntimeSamples++ })
+	if len(tier.Buckets) != 0 {
+		t.Fatalf("expected sample beyond retention to be ignored, got %d buckets", len(tier.Buckets))
+	}
+
+	tier.update(now, func(b *rollupBucket) { b.RuntimeSamples++ })
+	stale := now.Add(-3 * time.Hour).Truncate(time.Minute)
+	tier.Buckets[stale.UnixNano()] = &rollupBucket{Start: stale}
+
+	tier.update(now.Add(time.Minute), func(b *rollupBucket) { b.RuntimeSamples++ })
+	if _, ok := tier.Buckets[stale.UnixNano()]; ok {
+		t.Error("expected stale bucket to be expired when a new bucket opens")
+	}
+	if len(tier.Buckets) != 2 {
+		t.Errorf("expected 2 live buckets, got %d", len(tier.Buckets))
+	}
+}
+
+func TestMemoryStorage_ResetClearsRollups(t *testing.T) {
+	s := newEvictingStorage(2)
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/", StatusCode: 200, Timestamp: now.Add(-time.Duration(10-i) * time.Minute)})
+	}
+
+	s.Reset()
+
+	for _, tier := range s.rollups.tiers {
+		if len(tier.Buckets) != 0 {
+			t.Errorf("expected %v tier to be empty after Reset", tier.Resolution)
+		}
+	}
+	stats, _ := s.GetRouteStats(Last24h())
+	if len(stats) != 0 {
+		t.Errorf("expected no route stats after Reset, got %d", len(stats))
+	}
+}
@@ -107,6 +107,7 @@ func (rs *RuntimeSampler) sample() {
 		GCPauseNs:     lastGCPauseNs,
 		NumGC:         memStats.NumGC,
 		GCCPUFraction: memStats.GCCPUFraction,
+		Instance:      rs.pulse.config.Instance,
 		Timestamp:     time.Now(),
 	}
 
@@ -114,6 +115,12 @@ func (rs *RuntimeSampler) sample() {
 	if err := rs.pulse.storage.StoreRuntime(metric); err != nil && rs.pulse.config.DevMode {
 		rs.pulse.logger.Printf("[pulse] failed to store runtime metric: %v", err)
 	}
+	if rs.pulse.agent != nil {
+		rs.pulse.agent.addRuntime(metric)
+	}
+	if rs.pulse.otlp != nil {
+		rs.pulse.otlp.addRuntime(metric)
+	}
 
 	// Broadcast runtime metrics to WebSocket clients
 	rs.pulse.BroadcastRuntime(metric)
@@ -0,0 +1,199 @@
+package pulse
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SamplingRule sets the sample rate of the finished requests it matches.
+// Empty fields match anything; a rule matches when every set field does.
+//
+// Rules also apply to errors and slow requests, so a rule that should only
+// thin out successful requests needs a StatusClass.
+type SamplingRule struct {
+	// Method matches the request method, e.g. "POST".
+	Method string
+	// Route matches the route pattern (e.g. "/users/:id") with a glob, or a
+	// prefix when it ends in "/*". Unrouted requests match by path.
+	Route string
+	// StatusClass matches the response status class: 2 for 2xx up to 5 for 5xx.
+	StatusClass int
+	// MinLatency matches requests that took at least this long.
+	MinLatency time.Duration
+	// Headers match request header values; "*" only requires the header.
+	Headers map[string]string
+	// SampleRate is the fraction of matching requests to record, 0.0-1.0.
+	// Unlike TracingConfig.SampleRate, 0 records none.
+	SampleRate float64
+}
+
+// matches reports whether the finished request satisfies every set field.
+func (r SamplingRule) matches(s sampleCandidate) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, s.method) {
+		return false
+	}
+	if r.Route != "" && !matchPathPattern(s.route, r.Route) {
+		return false
+	}
+	if r.StatusClass != 0 && s.status/100 != r.StatusClass {
+		return false
+	}
+	if s.latency < r.MinLatency {
+		return false
+	}
+	for name, want := range r.Headers {
+		got := s.header.Get(name)
+		if got == "" || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleCandidate describes a finished request for the sampling decision.
+type sampleCandidate struct {
+	method  string
+	route   string
+	status  int
+	latency time.Duration
+	header  http.Header
+}
+
+// requestSampler decides which requests are recorded.
+//
+// Without rules or an adaptive target the decision is made when the request
+// starts (head sampling): errors and slow requests are always kept, others
+// at TracingConfig.SampleRate. With either configured it is made after the
+// request finishes (tail sampling): the first matching rule sets the rate,
+// falling back to the head sampling behavior, and the adaptive target then
+// scales the rate of requests that are neither errors nor slow.
+type requestSampler struct {
+	rate     float64
+	slow     time.Duration
+	rules    []SamplingRule
+	adaptive *adaptiveSampler // nil without a target
+}
+
+func newRequestSampler(cfg TracingConfig) *requestSampler {
+	s := &requestSampler{
+		rate:  float64Value(cfg.SampleRate),
+		slow:  cfg.SlowRequestThreshold,
+		rules: cfg.SamplingRules,
+	}
+	if cfg.MaxRecordedPerSecond > 0 {
+		s.adaptive = newAdaptiveSampler(cfg.MaxRecordedPerSecond)
+	}
+	return s
+}
+
+// tail reports whether the decision is made after the request finishes.
+func (s *requestSampler) tail() bool {
+	return len(s.rules) > 0 || s.adaptive != nil
+}
+
+// headSampled decides whether a trace this service starts is sampled. Under
+// tail sampling the flag is always set since the decision is not known yet.
+func (s *requestSampler) headSampled() bool {
+	return s.tail() || shouldSample(s.rate)
+}
+
+// sampleRate returns the probability with which a finished request is
+// recorded under tail sampling.
+func (s *requestSampler) sampleRate(c sampleCandidate, now time.Time) float64 {
+	forced := c.status >= 400 || c.latency >= s.slow
+
+	rate := -1.0
+	for _, rule := range s.rules {
+		if rule.matches(c) {
+			rate = rule.SampleRate
+			break
+		}
+	}
+	if rate < 0 {
+		rate = s.rate
+		if forced {
+			rate = 1
+		}
+	}
+	rate = clampRate(rate)
+
+	if s.adaptive != nil {
+		if forced {
+			s.adaptive.observe(now, rate, 0)
+		} else {
+			rate *= s.adaptive.observe(now, 0, rate)
+		}
+	}
+	return rate
+}
+
+func clampRate(r float64) float64 {
+	if r < 0 {
+		return 0
+	}
+	if r > 1 {
+		return 1
+	}
+	return r
+}
+
+// adaptiveSampler scales sample rates so that about target requests per
+// second are recorded. Each one-second window measures the expected number
+// of recorded requests before scaling; the next window's factor leaves room
+// for requests that are always kept and shares the rest among the others.
+type adaptiveSampler struct {
+	target float64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	forced      float64 // expected recordings exempt from scaling
+	scalable    float64 // expected recordings before scaling
+	factor      float64
+}
+
+func newAdaptiveSampler(target float64) *adaptiveSampler {
+	return &adaptiveSampler{target: target, factor: 1}
+}
+
+// observe counts a request's expected recordings and returns the current
+// scaling factor.
+func (a *adaptiveSampler) observe(now time.Time, forced, scalable float64) float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.windowStart.IsZero() {
+		a.windowStart = now
+	}
+	if elapsed := now.Sub(a.windowStart).Seconds(); elapsed >= 1 {
+		budget := a.target - a.forced/elapsed
+		switch perSecond := a.scalable / elapsed; {
+		case perSecond <= 0:
+			a.factor = 1
+		case budget <= 0:
+			a.factor = 0
+		default:
+			a.factor = clampRate(budget / perSecond)
+		}
+		a.windowStart, a.forced, a.scalable = now, 0, 0
+	}
+
+	a.forced += forced
+	a.scalable += scalable
+	return a.factor
+}
+
+// weight returns how many requests the recorded request stands for.
+func (m RequestMetric) weight() float64 {
+	return sampleWeight(m.SampleRate)
+}
+
+// sampleWeight returns how many requests a recorded request stands for:
+// the inverse of the rate it was recorded at.
+func sampleWeight(rate float64) float64 {
+	if rate <= 0 || rate >= 1 {
+		return 1
+	}
+	return 1 / rate
+}
@@ -0,0 +1,201 @@
+package pulse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSamplingRule_Matches(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Debug", "1")
+	c := sampleCandidate{method: "POST", route: "/api/orders/:id", status: 502, latency: 300 * time.Millisecond, header: h}
+
+	tests := []struct {
+		name string
+		rule SamplingRule
+		want bool
+	}{
+		{"empty rule", SamplingRule{}, true},
+		{"method", SamplingRule{Method: "post"}, true},
+		{"other method", SamplingRule{Method: "GET"}, false},
+		{"route glob", SamplingRule{Route: "/api/orders/*"}, true},
+		{"route prefix", SamplingRule{Route: "/api/*"}, true},
+		{"other route", SamplingRule{Route: "/users/*"}, false},
+		{"status class", SamplingRule{StatusClass: 5}, true},
+		{"other status class", SamplingRule{StatusClass: 2}, false},
+		{"min latency", SamplingRule{MinLatency: 250 * time.Millisecond}, true},
+		{"min latency not reached", SamplingRule{MinLatency: time.Second}, false},
+		{"header value", SamplingRule{Headers: map[string]string{"x-debug": "1"}}, true},
+		{"header present", SamplingRule{Headers: map[string]string{"X-Debug": "*"}}, true},
+		{"header mismatch", SamplingRule{Headers: map[string]string{"X-Debug": "0"}}, false},
+		{"header missing", SamplingRule{Headers: map[string]string{"X-Tenant": "*"}}, false},
+		{"all fields", SamplingRule{Method: "POST", Route: "/api/*", StatusClass: 5, MinLatency: time.Millisecond}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.matches(c); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRequestSampler_SampleRate(t *testing.T) {
+	s := newRequestSampler(TracingConfig{
+		SampleRate:           float64Ptr(0.5),
+		SlowRequestThreshold: time.Second,
+		SamplingRules: []SamplingRule{
+			{Route: "/healthz", StatusClass: 2, SampleRate: 0},
+			{Route: "/healthz", SampleRate: 0.25},
+			{Method: "POST", Route: "/checkout", SampleRate: 1},
+		},
+	})
+	if !s.tail() || !s.headSampled() {
+		t.Fatal("expected tail sampling with the sampled flag set")
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		c    sampleCandidate
+		want float64
+	}{
+		{"first matching rule wins", sampleCandidate{method: "GET", route: "/healthz", status: 200}, 0},
+		{"later rule", sampleCandidate{method: "GET", route: "/healthz", status: 503}, 0.25},
+		{"kept route", sampleCandidate{method: "POST", route: "/checkout", status: 201}, 1},
+		{"no rule", sampleCandidate{method: "GET", route: "/users", status: 200}, 0.5},
+		{"no rule, error", sampleCandidate{method: "GET", route: "/users", status: 500}, 1},
+		{"no rule, slow", sampleCandidate{method: "GET", route: "/users", status: 200, latency: 2 * time.Second}, 1},
+	}
+	for _, tt := range tests {
+		if got := s.sampleRate(tt.c, now); got != tt.want {
+			t.Errorf("%s: expected rate %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// Without rules or a target the decision stays at the head
+	if newRequestSampler(TracingConfig{SampleRate: float64Ptr(1)}).tail() {
+		t.Error("expected head sampling without rules")
+	}
+}
+
+func TestAdaptiveSampler_TargetsRate(t *testing.T) {
+	s := newRequestSampler(TracingConfig{SampleRate: float64Ptr(1), SlowRequestThreshold: time.Second, MaxRecordedPerSecond: 10})
+	ok := sampleCandidate{method: "GET", route: "/users", status: 200}
+	failed := sampleCandidate{method: "GET", route: "/users", status: 500}
+
+	// First window: 100 successful and 5 failed requests per second
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if rate := s.sampleRate(ok, start.Add(time.Duration(i)*10*time.Millisecond)); rate != 1 {
+			t.Fatalf("expected full rate before the first window closes, got %v", rate)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		s.sampleRate(failed, start.Add(990*time.Millisecond))
+	}
+
+	// Next window: the 5 errors per second leave room for 5 of the 100
+	next := start.Add(time.Second)
+	if rate := s.sampleRate(ok, next); rate < 0.049 || rate > 0.051 {
+		t.Errorf("expected successful requests scaled to ~0.05, got %v", rate)
+	}
+	if rate := s.sampleRate(failed, next); rate != 1 {
+		t.Errorf("expected errors to be kept, got %v", rate)
+	}
+}
+
+func TestSampleWeight(t *testing.T) {
+	for rate, want := range map[float64]float64{0: 1, 1: 1, 0.5: 2, 0.1: 10} {
+		if got := sampleWeight(rate); got < want-1e-9 || got > want+1e-9 {
+			t.Errorf("sampleWeight(%v): expected %v, got %v", rate, want, got)
+		}
+	}
+}
+
+func TestMiddleware_SamplingRules(t *testing.T) {
+	router, p := setupTestRouter(Config{Tracing: TracingConfig{
+		SampleRate: float64Ptr(0.5),
+		SamplingRules: []SamplingRule{
+			{Method: "GET", Route: "/healthz", StatusClass: 2, SampleRate: 0},
+			{Method: "POST", Route: "/users", SampleRate: 1},
+		},
+	}})
+	defer p.Shutdown()
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 20; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	}
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users", nil))
+	}
+
+	reqs := waitForRequests(t, p, 3)
+	if len(reqs) != 3 {
+		t.Fatalf("expected only the POST requests to be recorded, got %d", len(reqs))
+	}
+	for _, r := range reqs {
+		if r.Path != "/users" || r.SampleRate != 1 {
+			t.Errorf("unexpected recorded request: %s %s at rate %v", r.Method, r.Path, r.SampleRate)
+		}
+	}
+}
+
+func TestMiddleware_SamplingRulesApplyToContinuedTraces(t *testing.T) {
+	router, p := setupTestRouter(Config{Tracing: TracingConfig{
+		SamplingRules: []SamplingRule{
+			{Route: "/healthz", SampleRate: 0},
+			{Method: "POST", Route: "/users", SampleRate: 1},
+		},
+	}})
+	defer p.Shutdown()
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Neither a sampled traceparent nor a legacy trace ID overrides a rule
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest("GET", "/healthz", nil)
+		if i%2 == 0 {
+			req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+		} else {
+			req.Header.Set(TraceIDHeader, testTraceID)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// An unsampled caller does not drop what a rule keeps
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-00")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	reqs := waitForRequests(t, p, 1)
+	if len(reqs) != 1 || reqs[0].Path != "/users" || reqs[0].SampleRate != 1 {
+		t.Fatalf("expected only the POST recorded at rate 1, got %+v", reqs)
+	}
+}
+
+func TestRollupRequests_Extrapolates(t *testing.T) {
+	s := NewMemoryStorage("test")
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/feed", StatusCode: 200, Latency: 10 * time.Millisecond, SampleRate: 0.25, Timestamp: now})
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now}
+	var count, samples int64
+	for _, b := range rollupRequests(s, tr, time.Minute) {
+		count += b.Count
+		samples += b.Samples
+	}
+	if count != 16 || samples != 4 {
+		t.Errorf("expected 16 requests from 4 samples, got %d from %d", count, samples)
+	}
+
+	_, _, latency := requestTimeSeries(s, tr)
+	for _, pt := range latency {
+		if pt.Value != 0 && pt.Value != 10 {
+			t.Errorf("expected 10ms average latency over recorded requests, got %v", pt.Value)
+		}
+	}
+}
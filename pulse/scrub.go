@@ -0,0 +1,401 @@
+package pulse
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ScrubDetector names a built-in detector of personal data or secrets.
+type ScrubDetector string
+
+const (
+	// DetectEmail replaces email addresses with "[EMAIL]".
+	DetectEmail ScrubDetector = "email"
+	// DetectCreditCard replaces card numbers passing the Luhn check with "[CARD]".
+	DetectCreditCard ScrubDetector = "credit_card"
+	// DetectJWT replaces JSON Web Tokens with "[JWT]".
+	DetectJWT ScrubDetector = "jwt"
+	// DetectBearerToken replaces bearer tokens with "Bearer [REDACTED]".
+	DetectBearerToken ScrubDetector = "bearer_token"
+	// DetectIP replaces IPv4 and IPv6 addresses with "[IP]".
+	DetectIP ScrubDetector = "ip"
+)
+
+// Scrubber rewrites a string before it is stored, e.g. to remove account
+// numbers in a custom format. It must be safe for concurrent use.
+type Scrubber func(s string) string
+
+// redacted replaces masked values.
+const redacted = "[REDACTED]"
+
+// scrubDetector finds and replaces one kind of sensitive value.
+type scrubDetector struct {
+	hint    string // substring every match contains, to skip the regexp
+	re      *regexp.Regexp
+	replace func(match string) string
+}
+
+// scrubDetectorOrder runs bearer tokens before JWTs, so a bearer JWT is
+// reported as a bearer token.
+var scrubDetectorOrder = []ScrubDetector{DetectBearerToken, DetectJWT, DetectEmail, DetectCreditCard, DetectIP}
+
+var scrubDetectors = map[ScrubDetector]scrubDetector{
+	DetectBearerToken: {
+		re:      regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		replace: func(string) string { return "Bearer " + redacted },
+	},
+	DetectJWT: {
+		hint:    "eyJ",
+		re:      regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		replace: func(string) string { return "[JWT]" },
+	},
+	DetectEmail: {
+		hint:    "@",
+		re:      regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+		replace: func(string) string { return "[EMAIL]" },
+	},
+	DetectCreditCard: {
+		// 13 to 19 digits starting like a card network's numbers, optionally
+		// grouped with spaces or dashes
+		re: regexp.MustCompile(`\b[2-6]\d{3}(?:[ -]?\d){9,15}\b`),
+		replace: func(match string) string {
+			if luhnValid(match) {
+				return "[CARD]"
+			}
+			return match
+		},
+	},
+	DetectIP: {
+		re: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|\b[0-9A-Fa-f]{1,4}(?::[0-9A-Fa-f]{0,4}){2,7}`),
+		replace: func(match string) string {
+			if ip := net.ParseIP(match); ip != nil && !ip.IsUnspecified() {
+				return "[IP]"
+			}
+			return match
+		},
+	},
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// scrubber removes personal data and secrets from metrics before they are
+// stored, exported or broadcast. A nil scrubber leaves them unchanged.
+type scrubber struct {
+	detectors []scrubDetector
+	fields    [][]string     // lower-cased mask field paths
+	rawFields *regexp.Regexp // mask field values in JSON that doesn't parse
+	custom    []Scrubber
+}
+
+// newScrubber returns nil when scrubbing is disabled.
+func newScrubber(cfg PrivacyConfig) *scrubber {
+	if !boolValue(cfg.Scrub) {
+		return nil
+	}
+	s := &scrubber{custom: cfg.Scrubbers}
+
+	enabled := make(map[ScrubDetector]bool, len(cfg.Detectors))
+	for _, d := range cfg.Detectors {
+		enabled[d] = true
+	}
+	for _, name := range scrubDetectorOrder {
+		if enabled[name] {
+			s.detectors = append(s.detectors, scrubDetectors[name])
+		}
+	}
+	var names []string
+	for _, field := range cfg.MaskFields {
+		if field = strings.TrimSpace(field); field != "" {
+			path := strings.Split(strings.ToLower(field), ".")
+			s.fields = append(s.fields, path)
+			if name := path[len(path)-1]; name != "*" {
+				names = append(names, regexp.QuoteMeta(name))
+			}
+		}
+	}
+	if len(names) > 0 {
+		// A truncated body no longer parses, so match "name": value pairs
+		// by their last path segment instead
+		s.rawFields = regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)(?:"(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+	return s
+}
+
+// scrub returns a copy of a metric with its free text scrubbed. Metrics
+// without free text are returned unchanged.
+func (s *scrubber) scrub(item interface{}) interface{} {
+	if s == nil {
+		return item
+	}
+	switch m := item.(type) {
+	case RequestMetric:
+		// Unmatched requests are grouped by their templated path
+		m.Path = s.text(m.Path)
+		m.Error = s.text(m.Error)
+		if m.Capture != nil {
+			c := *m.Capture
+			c.RequestBody = s.body(c.RequestBody, c.RequestHeaders["Content-Type"])
+			c.RequestHeaders = s.headers(c.RequestHeaders)
+			c.ResponseBody = s.body(c.ResponseBody, c.ResponseHeaders["Content-Type"])
+			c.ResponseHeaders = s.headers(c.ResponseHeaders)
+			m.Capture = &c
+		}
+		return m
+	case QueryMetric:
+		m.SQL = s.text(m.SQL)
+		m.Error = s.text(m.Error)
+		return m
+	case DependencyMetric:
+		m.URL = s.url(m.URL)
+		m.Error = s.text(m.Error)
+		return m
+	case ErrorRecord:
+		return s.errorRecord(m)
+	case ConnectionMetric:
+		m.Path = s.text(m.Path)
+		return m
+	case Span:
+		m.Name = s.text(m.Name)
+		m.StatusMessage = s.text(m.StatusMessage)
+		if len(m.Attributes) > 0 {
+			attrs := make(map[string]string, len(m.Attributes))
+			for k, v := range m.Attributes {
+				if k == "url.full" {
+					attrs[k] = s.url(v)
+				} else {
+					attrs[k] = s.text(v)
+				}
+			}
+			m.Attributes = attrs
+		}
+		return m
+	}
+	return item
+}
+
+// errorRecord scrubs an error record. Errors whose message or route changed
+// are fingerprinted again, so occurrences differing only in scrubbed values
+// are grouped together.
+func (s *scrubber) errorRecord(e ErrorRecord) ErrorRecord {
+	if s == nil {
+		return e
+	}
+	msg, route := s.text(e.ErrorMessage), s.text(e.Route)
+	if msg != e.ErrorMessage || route != e.Route {
+		e.ErrorMessage, e.Route = msg, route
+		e.Fingerprint = generateFingerprint(e.Method, route, msg)
+	}
+	if e.RequestContext != nil {
+		rc := *e.RequestContext
+		rc.Path = s.text(rc.Path)
+		rc.Query = s.query(rc.Query)
+		rc.Headers = s.headers(rc.Headers)
+		rc.Body = s.body(rc.Body, rc.ContentType)
+		e.RequestContext = &rc
+	}
+	return e
+}
+
+// text runs the detectors and custom scrubbers over free text.
+func (s *scrubber) text(v string) string {
+	if v == "" {
+		return v
+	}
+	for _, d := range s.detectors {
+		if d.hint != "" && !strings.Contains(v, d.hint) {
+			continue
+		}
+		v = d.re.ReplaceAllStringFunc(v, d.replace)
+	}
+	for _, scrub := range s.custom {
+		v = scrub(v)
+	}
+	return v
+}
+
+// masked reports whether the field at path is a mask field.
+func (s *scrubber) masked(path []string) bool {
+	for _, field := range s.fields {
+		if len(field) == 1 {
+			if strings.EqualFold(path[len(path)-1], field[0]) {
+				return true
+			}
+			continue
+		}
+		if len(field) != len(path) {
+			continue
+		}
+		match := true
+		for i, name := range field {
+			if name != "*" && !strings.EqualFold(path[i], name) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// body scrubs a request or response body: mask fields in JSON and form
+// bodies, and the detectors everywhere.
+func (s *scrubber) body(v, contentType string) string {
+	if v == "" {
+		return v
+	}
+	if scrubbed, ok := s.json(v); ok {
+		return scrubbed
+	}
+	if trimmed := strings.TrimSpace(v); s.rawFields != nil && trimmed != "" && (trimmed[0] == '{' || trimmed[0] == '[') {
+		v = s.rawFields.ReplaceAllString(v, `${1}"`+redacted+`"`)
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return s.query(v)
+	}
+	return s.text(v)
+}
+
+// json scrubs a JSON document. It reports false when v is not JSON, such as
+// a truncated body.
+func (s *scrubber) json(v string) (string, bool) {
+	trimmed := strings.TrimSpace(v)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return "", false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return "", false
+	}
+
+	changed := false
+	doc = s.jsonValue(doc, nil, &changed)
+	if !changed {
+		return v, true
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+func (s *scrubber) jsonValue(v interface{}, path []string, changed *bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			p := append(path[:len(path):len(path)], k)
+			if s.masked(p) {
+				t[k] = redacted
+				*changed = true
+				continue
+			}
+			t[k] = s.jsonValue(child, p, changed)
+		}
+	case []interface{}:
+		for i, child := range t {
+			t[i] = s.jsonValue(child, append(path[:len(path):len(path)], strconv.Itoa(i)), changed)
+		}
+	case string:
+		if scrubbed := s.text(t); scrubbed != t {
+			*changed = true
+			return scrubbed
+		}
+	}
+	return v
+}
+
+// query scrubs a query string or form body, keeping its order and encoding
+// except for the values it replaces.
+func (s *scrubber) query(v string) string {
+	if v == "" {
+		return v
+	}
+	pairs := strings.Split(v, "&")
+	for i, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if s.masked([]string{name}) {
+			pairs[i] = key + "=" + redacted
+			continue
+		}
+		raw, err := url.QueryUnescape(value)
+		if err != nil {
+			raw = value
+		}
+		if scrubbed := s.text(raw); scrubbed != raw {
+			pairs[i] = key + "=" + scrubbed
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// url scrubs a URL: the password in its user info, its query string and
+// the detectors over the rest.
+func (s *scrubber) url(v string) string {
+	base, query, hasQuery := strings.Cut(v, "?")
+	if i := strings.Index(base, "://"); i >= 0 {
+		rest := base[i+3:]
+		if at := strings.IndexByte(rest, '@'); at >= 0 && !strings.Contains(rest[:at], "/") {
+			base = base[:i+3] + redacted + rest[at:]
+		}
+	}
+	base = s.text(base)
+	if !hasQuery {
+		return base
+	}
+	return base + "?" + s.query(query)
+}
+
+// headers scrubs header values, masking headers named like a mask field.
+func (s *scrubber) headers(h map[string]string) map[string]string {
+	if len(h) == 0 {
+		return h
+	}
+	scrubbed := make(map[string]string, len(h))
+	for k, v := range h {
+		if s.masked([]string{k}) {
+			scrubbed[k] = redacted
+		} else {
+			scrubbed[k] = s.text(v)
+		}
+	}
+	return scrubbed
+}
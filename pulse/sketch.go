@@ -0,0 +1,135 @@
+package pulse
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// sketchGamma sets the bucket growth factor of LatencySketch. Values in a
+// bucket are within (gamma-1)/(gamma+1) ≈ 1% of the bucket's representative
+// value.
+const sketchGamma = 1.02
+
+var sketchLogGamma = math.Log(sketchGamma)
+
+// LatencySketch is a mergeable latency histogram with logarithmically sized
+// buckets. It answers percentile queries with ~1% relative error, and two
+// sketches can be merged without losing accuracy, so per-minute sketches can
+// be combined into hourly or multi-day percentiles.
+type LatencySketch struct {
+	Buckets map[int]int64 `json:"buckets"`
+	Zero    int64         `json:"zero"`
+	Count   int64         `json:"count"`
+	Sum     time.Duration `json:"sum"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+}
+
+// Add records a single latency.
+func (s *LatencySketch) Add(d time.Duration) {
+	if s.Count == 0 || d < s.Min {
+		s.Min = d
+	}
+	if s.Count == 0 || d > s.Max {
+		s.Max = d
+	}
+	s.Count++
+	s.Sum += d
+
+	if d <= 0 {
+		s.Zero++
+		return
+	}
+	if s.Buckets == nil {
+		s.Buckets = make(map[int]int64)
+	}
+	s.Buckets[sketchIndex(d)]++
+}
+
+// Merge folds other into s.
+func (s *LatencySketch) Merge(other *LatencySketch) {
+	if other == nil || other.Count == 0 {
+		return
+	}
+	if s.Count == 0 || other.Min < s.Min {
+		s.Min = other.Min
+	}
+	if s.Count == 0 || other.Max > s.Max {
+		s.Max = other.Max
+	}
+	s.Count += other.Count
+	s.Sum += other.Sum
+	s.Zero += other.Zero
+
+	if len(other.Buckets) > 0 && s.Buckets == nil {
+		s.Buckets = make(map[int]int64, len(other.Buckets))
+	}
+	for k, n := range other.Buckets {
+		s.Buckets[k] += n
+	}
+}
+
+// Avg returns the mean latency.
+func (s *LatencySketch) Avg() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Percentile returns the approximate p-th percentile (0–100). The rank is
+// chosen like Percentile's lower neighbour; sketches do not interpolate.
+func (s *LatencySketch) Percentile(p float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return s.Min
+	}
+	if p >= 100 {
+		return s.Max
+	}
+
+	rank := int64(math.Floor((p / 100.0) * float64(s.Count-1)))
+	if rank < s.Zero {
+		return 0
+	}
+
+	keys := make([]int, 0, len(s.Buckets))
+	for k := range s.Buckets {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	seen := s.Zero
+	for _, k := range keys {
+		seen += s.Buckets[k]
+		if seen > rank {
+			return s.clamp(sketchValue(k))
+		}
+	}
+	return s.Max
+}
+
+// clamp keeps an estimate within the observed range.
+func (s *LatencySketch) clamp(d time.Duration) time.Duration {
+	if d < s.Min {
+		return s.Min
+	}
+	if d > s.Max {
+		return s.Max
+	}
+	return d
+}
+
+// sketchIndex returns the bucket index for a positive duration.
+func sketchIndex(d time.Duration) int {
+	return int(math.Ceil(math.Log(float64(d)) / sketchLogGamma))
+}
+
+// sketchValue returns the representative duration of bucket k, the point with
+// equal relative error to both bucket bounds.
+func sketchValue(k int) time.Duration {
+	return time.Duration(2 * math.Pow(sketchGamma, float64(k)) / (sketchGamma + 1))
+}
@@ -0,0 +1,94 @@
+package pulse
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func withinRelative(got, want time.Duration, tolerance float64) bool {
+	if want == 0 {
+		return got == 0
+	}
+	return math.Abs(float64(got-want))/float64(want) <= tolerance
+}
+
+func TestLatencySketch_Empty(t *testing.T) {
+	var s LatencySketch
+	if got := s.Percentile(50); got != 0 {
+		t.Errorf("expected 0 for empty sketch, got %v", got)
+	}
+	if got := s.Avg(); got != 0 {
+		t.Errorf("expected 0 avg for empty sketch, got %v", got)
+	}
+}
+
+func TestLatencySketch_Percentiles(t *testing.T) {
+	var s LatencySketch
+	sorted := make([]time.Duration, 1000)
+	for i := 0; i < 1000; i++ {
+		d := time.Duration(i+1) * time.Millisecond
+		sorted[i] = d
+		s.Add(d)
+	}
+
+	if s.Count != 1000 {
+		t.Fatalf("expected count 1000, got %d", s.Count)
+	}
+	if s.Min != time.Millisecond || s.Max != time.Second {
+		t.Errorf("expected min 1ms / max 1s, got %v / %v", s.Min, s.Max)
+	}
+	if s.Avg() != 500500*time.Microsecond {
+		t.Errorf("expected avg 500.5ms, got %v", s.Avg())
+	}
+
+	for _, p := range []float64{50, 75, 90, 95, 99} {
+		want := Percentile(sorted, p)
+		got := s.Percentile(p)
+		if !withinRelative(got, want, 0.02) {
+			t.Errorf("p%.0f: got %v, want ~%v", p, got, want)
+		}
+	}
+	if s.Percentile(0) != time.Millisecond || s.Percentile(100) != time.Second {
+		t.Error("expected p0/p100 to return exact min/max")
+	}
+}
+
+func TestLatencySketch_Merge(t *testing.T) {
+	var a, b, all LatencySketch
+	for i := 1; i <= 500; i++ {
+		d := time.Duration(i) * time.Millisecond
+		a.Add(d)
+		all.Add(d)
+	}
+	for i := 501; i <= 1000; i++ {
+		d := time.Duration(i) * time.Millisecond
+		b.Add(d)
+		all.Add(d)
+	}
+
+	a.Merge(&b)
+	if a.Count != all.Count || a.Sum != all.Sum || a.Min != all.Min || a.Max != all.Max {
+		t.Fatalf("merged totals differ: %+v vs %+v", a, all)
+	}
+	for _, p := range []float64{50, 95, 99} {
+		if a.Percentile(p) != all.Percentile(p) {
+			t.Errorf("p%.0f: merged %v != direct %v", p, a.Percentile(p), all.Percentile(p))
+		}
+	}
+}
+
+func TestLatencySketch_ZeroDurations(t *testing.T) {
+	var s LatencySketch
+	s.Add(0)
+	s.Add(0)
+	s.Add(10 * time.Millisecond)
+	s.Add(10 * time.Millisecond)
+
+	if got := s.Percentile(25); got != 0 {
+		t.Errorf("expected p25 of 0, got %v", got)
+	}
+	if got := s.Percentile(99); !withinRelative(got, 10*time.Millisecond, 0.02) {
+		t.Errorf("expected p99 ~10ms, got %v", got)
+	}
+}
@@ -0,0 +1,278 @@
+package pulse
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// snapshotVersion is bumped when the snapshot format changes incompatibly.
+const snapshotVersion = 1
+
+// memorySnapshot is the on-disk form of a MemoryStorage: gzip-compressed JSON.
+type memorySnapshot struct {
+	Version      int                            `json:"version"`
+	AppName      string                         `json:"app_name"`
+	TakenAt      time.Time                      `json:"taken_at"`
+	Requests     []RequestMetric                `json:"requests"`
+	Queries      []QueryMetric                  `json:"queries"`
+	Runtime      []RuntimeMetric                `json:"runtime"`
+	Dependencies []DependencyMetric             `json:"dependencies"`
+	PoolStats    []PoolStats                    `json:"pool_stats"`
+	Errors       []ErrorRecord                  `json:"errors"`
+	Health       map[string][]HealthCheckResult `json:"health"`
+	Alerts       []AlertRecord                  `json:"alerts"`
+	N1Detections []N1Detection                  `json:"n1_detections"`
+	Spans        []Span                         `json:"spans,omitempty"`
+	Connections  []ConnectionMetric             `json:"connections,omitempty"`
+	Rollups      []*rollupTier                  `json:"rollups,omitempty"` // finest first
+}
+
+// --- MemoryStorage ---
+
+// SaveSnapshot writes the ring buffers (including pool stats, spans and
+// connections), the rollup tiers with their latency sketches, error groups
+// with their muted and resolved flags, alert history, health history and N+1
+// detections to w.
+func (s *MemoryStorage) SaveSnapshot(w io.Writer) error {
+	snap := memorySnapshot{
+		Version:      snapshotVersion,
+		AppName:      s.appName,
+		TakenAt:      time.Now(),
+		Requests:     s.requests.GetAll(),
+		Queries:      s.queries.GetAll(),
+		Runtime:      s.runtimeStats.GetAll(),
+		Dependencies: s.dependencies.GetAll(),
+		PoolStats:    s.poolStats.GetAll(),
+		Spans:        s.spans.GetAll(),
+		Connections:  s.connections.GetAll(),
+		Rollups:      s.rollups.tiers,
+	}
+
+	s.errorsMu.RLock()
+	snap.Errors = make([]ErrorRecord, 0, len(s.errors))
+	for _, e := range s.errors {
+		snap.Errors = append(snap.Errors, *e)
+	}
+	s.errorsMu.RUnlock()
+
+	s.healthMu.RLock()
+	snap.Health = make(map[string][]HealthCheckResult, len(s.healthResults))
+	for name, rb := range s.healthResults {
+		snap.Health[name] = rb.GetAll()
+	}
+	s.healthMu.RUnlock()
+
+	s.alertsMu.RLock()
+	snap.Alerts = append([]AlertRecord(nil), s.alerts...)
+	s.alertsMu.RUnlock()
+
+	s.n1Mu.RLock()
+	snap.N1Detections = append([]N1Detection(nil), s.n1Detections...)
+	s.n1Mu.RUnlock()
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(&snap); err != nil {
+		zw.Close()
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return zw.Close()
+}
+
+// LoadSnapshot replaces the storage contents with a snapshot written by
+// SaveSnapshot. Rollup tiers come from the snapshot, or are rebuilt from the
+// restored raw data when it has none.
+func (s *MemoryStorage) LoadSnapshot(r io.Reader) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	defer zr.Close()
+
+	var snap memorySnapshot
+	if err := json.NewDecoder(zr).Decode(&snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	s.Reset()
+
+	for _, m := range snap.Requests {
+		s.StoreRequest(m)
+	}
+	for _, m := range snap.Queries {
+		s.StoreQuery(m)
+	}
+	for _, m := range snap.Runtime {
+		s.StoreRuntime(m)
+	}
+	for _, m := range snap.Dependencies {
+		s.StoreDependencyMetric(m)
+	}
+	for _, m := range snap.PoolStats {
+		s.StorePoolStats(m)
+	}
+	s.StoreSpans(snap.Spans)
+	s.StoreConnections(snap.Connections)
+	s.rollups.restore(snap.Rollups)
+
+	s.errorsMu.Lock()
+	for i := range snap.Errors {
+		e := snap.Errors[i]
+		s.errors[e.Fingerprint] = &e
+	}
+	s.errorsMu.Unlock()
+
+	for _, results := range snap.Health {
+		for _, r := range results {
+			s.StoreHealthResult(r)
+		}
+	}
+
+	s.alertsMu.Lock()
+	s.alerts = append(s.alerts, snap.Alerts...)
+	s.alertsMu.Unlock()
+
+	s.n1Mu.Lock()
+	s.n1Detections = append(s.n1Detections, snap.N1Detections...)
+	s.n1Mu.Unlock()
+
+	return nil
+}
+
+// SaveSnapshotFile writes a snapshot to path atomically: the data goes to a
+// temporary file in the same directory which then replaces path.
+func (s *MemoryStorage) SaveSnapshotFile(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.SaveSnapshot(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshotFile restores a snapshot from path. A missing file returns an
+// error wrapping os.ErrNotExist.
+func (s *MemoryStorage) LoadSnapshotFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.LoadSnapshot(f)
+}
+
+// --- Snapshotter ---
+
+// SnapshotStatus describes the most recent snapshot written by Pulse.
+type SnapshotStatus struct {
+	Path      string    `json:"path"`
+	Restored  bool      `json:"restored"`
+	LastSaved time.Time `json:"last_saved,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Snapshotter restores MemoryStorage from StorageConfig.SnapshotPath on
+// Mount and writes it back on Shutdown and every SnapshotInterval.
+type Snapshotter struct {
+	pulse   *Pulse
+	storage snapshotStore
+	path    string
+
+	mu     sync.Mutex
+	status SnapshotStatus
+}
+
+// newSnapshotter restores the last snapshot and starts the periodic writer.
+// It returns nil when snapshots are disabled or the storage cannot snapshot.
+func newSnapshotter(p *Pulse) *Snapshotter {
+	path := p.config.Storage.SnapshotPath
+	if path == "" {
+		return nil
+	}
+	store, ok := p.storage.(snapshotStore)
+	if !ok {
+		p.logger.Printf("[pulse] warning: storage snapshots are only supported by the memory driver (ignoring SnapshotPath)")
+		return nil
+	}
+
+	sn := &Snapshotter{pulse: p, storage: store, path: path}
+	sn.status.Path = path
+
+	if err := store.LoadSnapshotFile(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			p.logger.Printf("[pulse] warning: failed to restore snapshot %q: %v", path, err)
+		}
+	} else {
+		sn.status.Restored = true
+		if p.config.DevMode {
+			p.logger.Printf("[pulse] restored storage snapshot from %s", path)
+		}
+	}
+
+	if interval := p.config.Storage.SnapshotInterval; interval > 0 {
+		p.startBackground("snapshot", func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					sn.save()
+				}
+			}
+		})
+	}
+
+	return sn
+}
+
+// save writes a snapshot and records the outcome.
+func (sn *Snapshotter) save() error {
+	err := sn.storage.SaveSnapshotFile(sn.path)
+
+	sn.mu.Lock()
+	sn.status.Error = ""
+	if err != nil {
+		sn.status.Error = err.Error()
+	} else {
+		sn.status.LastSaved = time.Now()
+	}
+	sn.mu.Unlock()
+
+	if err != nil {
+		sn.pulse.logger.Printf("[pulse] failed to write snapshot %q: %v", sn.path, err)
+	}
+	return err
+}
+
+// Status returns the outcome of the most recent restore and save.
+func (sn *Snapshotter) Status() SnapshotStatus {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	return sn.status
+}
@@ -0,0 +1,240 @@
+package pulse
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// populateSnapshotStorage fills s with one of everything a snapshot covers.
+func populateSnapshotStorage(s *MemoryStorage) {
+	now := time.Now()
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/users", StatusCode: 200, Latency: 5 * time.Millisecond, Timestamp: now})
+	s.StoreRequest(RequestMetric{Method: "POST", Path: "/users", StatusCode: 500, Latency: 9 * time.Millisecond, Timestamp: now})
+	s.StoreQuery(QueryMetric{SQL: "SELECT 1", NormalizedSQL: "SELECT ?", Duration: time.Millisecond, Timestamp: now})
+	s.StoreRuntime(RuntimeMetric{NumGoroutine: 42, Timestamp: now})
+	s.StoreDependencyMetric(DependencyMetric{Name: "stripe", Method: "GET", StatusCode: 200, Timestamp: now})
+	s.StoreError(ErrorRecord{ID: "err-1", Fingerprint: "fp-1", ErrorMessage: "boom", Count: 3, FirstSeen: now, LastSeen: now})
+	s.StoreError(ErrorRecord{ID: "err-2", Fingerprint: "fp-2", ErrorMessage: "bang", Count: 1, FirstSeen: now, LastSeen: now})
+	s.UpdateError("err-1", map[string]interface{}{"muted": true})
+	s.UpdateError("err-2", map[string]interface{}{"resolved": true})
+	s.StoreHealthResult(HealthCheckResult{Name: "db", Status: "healthy", Timestamp: now})
+	s.StoreAlert(AlertRecord{ID: "alert-1", RuleName: "high_latency", State: AlertStateFiring, FiredAt: now})
+	s.StoreN1Detection(N1Detection{Pattern: "SELECT ?", Count: 12, DetectedAt: now})
+}
+
+func TestMemoryStorage_SnapshotRoundTrip(t *testing.T) {
+	src := NewMemoryStorage("test")
+	populateSnapshotStorage(src)
+
+	var buf bytes.Buffer
+	if err := src.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	dst := NewMemoryStorage("test")
+	dst.StoreRequest(RequestMetric{Method: "DELETE", Path: "/stale", Timestamp: time.Now()})
+	if err := dst.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+
+	reqs, _ := dst.GetRequests(RequestFilter{})
+	if len(reqs) != 2 {
+		t.Errorf("expected 2 requests (existing data replaced), got %d", len(reqs))
+	}
+	if dst.queries.Len() != 1 || dst.runtimeStats.Len() != 1 || dst.dependencies.Len() != 1 {
+		t.Errorf("expected ring buffers restored, got queries=%d runtime=%d deps=%d",
+			dst.queries.Len(), dst.runtimeStats.Len(), dst.dependencies.Len())
+	}
+
+	e1, err := dst.getErrorByID("err-1")
+	if err != nil {
+		t.Fatalf("expected err-1 restored: %v", err)
+	}
+	if !e1.Muted || e1.Count != 3 {
+		t.Errorf("expected err-1 muted with count 3, got muted=%v count=%d", e1.Muted, e1.Count)
+	}
+	e2, err := dst.getErrorByID("err-2")
+	if err != nil || !e2.Resolved {
+		t.Errorf("expected err-2 restored as resolved, got %+v (%v)", e2, err)
+	}
+
+	health, _ := dst.GetHealthHistory("db", 10)
+	if len(health) != 1 {
+		t.Errorf("expected 1 health result, got %d", len(health))
+	}
+	alerts, _ := dst.GetAlerts(AlertFilter{})
+	if len(alerts) != 1 || alerts[0].ID != "alert-1" {
+		t.Errorf("expected alert-1 restored, got %+v", alerts)
+	}
+	n1, _ := dst.GetN1Detections(Last1h())
+	if len(n1) != 1 || n1[0].Count != 12 {
+		t.Errorf("expected N+1 detection restored, got %+v", n1)
+	}
+}
+
+func TestMemoryStorage_SnapshotRestoresRollups(t *testing.T) {
+	src := newEvictingStorage(10)
+	now := time.Now()
+
+	// 120 requests and queries over the last 2 hours; only the last 10 stay raw
+	for i := 0; i < 120; i++ {
+		ts := now.Add(-2*time.Hour + time.Duration(i)*time.Minute)
+		src.StoreRequest(RequestMetric{Method: "GET", Path: "/api/users", StatusCode: 200, Latency: time.Duration(i+1) * time.Millisecond, Timestamp: ts})
+		src.StoreQuery(QueryMetric{SQL: "SELECT 1", NormalizedSQL: "SELECT ?", Duration: time.Duration(i+1) * time.Millisecond, Timestamp: ts})
+	}
+	want, _ := src.GetRouteStats(Last24h())
+
+	var buf bytes.Buffer
+	if err := src.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	dst := NewMemoryStorage("test")
+	if err := dst.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if dst.requests.Len() != 10 {
+		t.Fatalf("expected the 10 raw requests restored, got %d", dst.requests.Len())
+	}
+
+	for _, tr := range []TimeRange{Last24h(), Last7d()} {
+		stats, _ := dst.GetRouteStats(tr)
+		if len(stats) != 1 || stats[0].RequestCount != 120 {
+			t.Fatalf("expected all 120 requests from the restored rollups, got %+v", stats)
+		}
+	}
+	got, _ := dst.GetRouteStats(Last24h())
+	if got[0].P99Latency != want[0].P99Latency || got[0].P50Latency != want[0].P50Latency {
+		t.Errorf("expected restored sketch percentiles p50=%v p99=%v, got p50=%v p99=%v",
+			want[0].P50Latency, want[0].P99Latency, got[0].P50Latency, got[0].P99Latency)
+	}
+
+	patterns, _ := dst.GetQueryPatterns(Last24h())
+	if len(patterns) != 1 || patterns[0].Count != 120 {
+		t.Errorf("expected 120 queries from the restored rollups, got %+v", patterns)
+	}
+}
+
+func TestMemoryStorage_LoadSnapshotRejectsGarbage(t *testing.T) {
+	s := NewMemoryStorage("test")
+	if err := s.LoadSnapshot(bytes.NewReader([]byte("not a snapshot"))); err == nil {
+		t.Fatal("expected error for invalid snapshot")
+	}
+}
+
+func TestMemoryStorage_SnapshotFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "pulse.snapshot")
+
+	s := NewMemoryStorage("test")
+	if err := s.LoadSnapshotFile(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist for missing snapshot, got %v", err)
+	}
+
+	populateSnapshotStorage(s)
+	if err := s.SaveSnapshotFile(path); err != nil {
+		t.Fatalf("SaveSnapshotFile: %v", err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the snapshot file (no temp files), got %d entries", len(entries))
+	}
+
+	restored := NewMemoryStorage("test")
+	if err := restored.LoadSnapshotFile(path); err != nil {
+		t.Fatalf("LoadSnapshotFile: %v", err)
+	}
+	if restored.requests.Len() != 2 {
+		t.Errorf("expected 2 requests, got %d", restored.requests.Len())
+	}
+}
+
+func TestMount_SnapshotSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pulse.snapshot")
+	cfg := Config{Storage: StorageConfig{SnapshotPath: path}}
+
+	_, p := setupTestRouter(cfg)
+	if p.snapshotter == nil {
+		t.Fatal("expected snapshotter to be enabled")
+	}
+	if p.snapshotter.Status().Restored {
+		t.Error("expected nothing to restore on first start")
+	}
+	now := time.Now()
+	p.storage.StoreError(ErrorRecord{ID: "err-1", Fingerprint: "fp-1", ErrorMessage: "boom", Count: 1, FirstSeen: now, LastSeen: now})
+	p.storage.UpdateError("err-1", map[string]interface{}{"muted": true})
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	_, p2 := setupTestRouter(cfg)
+	defer p2.Shutdown()
+	if !p2.snapshotter.Status().Restored {
+		t.Fatal("expected snapshot to be restored on Mount")
+	}
+	errs, _ := p2.storage.GetErrors(ErrorFilter{})
+	if len(errs) != 1 || !errs[0].Muted {
+		t.Errorf("expected muted error to survive restart, got %+v", errs)
+	}
+}
+
+func TestSnapshotter_PeriodicSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pulse.snapshot")
+	_, p := setupTestRouter(Config{Storage: StorageConfig{
+		SnapshotPath:     path,
+		SnapshotInterval: 20 * time.Millisecond,
+	}})
+	defer p.Shutdown()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for p.snapshotter.Status().LastSaved.IsZero() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.snapshotter.Status().LastSaved.IsZero() {
+		t.Fatal("expected a periodic snapshot to be written")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected snapshot file: %v", err)
+	}
+}
+
+func TestSnapshotter_DisabledForOtherDrivers(t *testing.T) {
+	p := newPulse(applyDefaults(Config{Storage: StorageConfig{SnapshotPath: "unused"}}))
+	p.storage = newTestSQLiteStorage(t)
+	if sn := newSnapshotter(p); sn != nil {
+		t.Error("expected no snapshotter for non-memory storage")
+	}
+}
+
+func TestAPI_DataDeleteRewritesSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pulse.snapshot")
+	router, p := setupTestRouter(Config{
+		Dashboard: DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"},
+		Storage:   StorageConfig{SnapshotPath: path},
+	})
+	defer p.Shutdown()
+	token := loginAndGetToken(t, router)
+
+	p.storage.StoreRequest(RequestMetric{Method: "GET", Path: "/me", StatusCode: 200, Tags: map[string]string{"user": "ada"}, Timestamp: time.Now()})
+	if err := p.snapshotter.save(); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("POST", "/pulse/api/data/delete", token, `{"tags": {"user": "ada"}}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	restored := NewMemoryStorage("test")
+	if err := restored.LoadSnapshotFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if reqs, _ := restored.GetRequests(RequestFilter{}); len(reqs) != 0 {
+		t.Errorf("expected the deleted request gone from the snapshot, got %+v", reqs)
+	}
+}
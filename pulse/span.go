@@ -0,0 +1,335 @@
+package pulse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SpanKind describes the role of a span in a trace.
+type SpanKind string
+
+const (
+	SpanKindServer   SpanKind = "server"   // handling of an incoming request
+	SpanKindClient   SpanKind = "client"   // an outbound call or database query
+	SpanKindInternal SpanKind = "internal" // work within the service
+)
+
+// SpanStatus is the outcome of a span.
+type SpanStatus string
+
+const (
+	SpanStatusUnset SpanStatus = "unset"
+	SpanStatusOK    SpanStatus = "ok"
+	SpanStatusError SpanStatus = "error"
+)
+
+// Span is a timed operation within a trace: the handling of a request, a
+// database query or an outbound HTTP call.
+type Span struct {
+	TraceID       string            `json:"trace_id"`
+	SpanID        string            `json:"span_id"`
+	ParentID      string            `json:"parent_id,omitempty"`
+	Name          string            `json:"name"`
+	Kind          SpanKind          `json:"kind"`
+	StartTime     time.Time         `json:"start_time"`
+	Duration      time.Duration     `json:"duration"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+	Status        SpanStatus        `json:"status"`
+	StatusMessage string            `json:"status_message,omitempty"`
+	Instance      string            `json:"instance,omitempty"`
+
+	// handle is set on spans started with StartSpan until they end
+	handle *spanHandle
+}
+
+// EndTime returns when the span finished.
+func (s Span) EndTime() time.Time {
+	return s.StartTime.Add(s.Duration)
+}
+
+// TraceNode is a span with its child spans, ordered by start time.
+type TraceNode struct {
+	Span
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+// Trace is the assembled span tree of one trace.
+type Trace struct {
+	TraceID   string        `json:"trace_id"`
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	SpanCount int           `json:"span_count"`
+	// Roots are spans whose parent is not part of this trace's stored
+	// spans, typically the server span (whose parent lives in the caller).
+	Roots []*TraceNode `json:"roots"`
+}
+
+// buildTrace assembles spans into a tree. It returns nil for no spans.
+func buildTrace(traceID string, spans []Span) *Trace {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+
+	nodes := make(map[string]*TraceNode, len(spans))
+	ordered := make([]*TraceNode, len(spans))
+	for i := range spans {
+		n := &TraceNode{Span: spans[i]}
+		ordered[i] = n
+		nodes[n.SpanID] = n
+	}
+
+	trace := &Trace{
+		TraceID:   traceID,
+		StartTime: spans[0].StartTime,
+		SpanCount: len(spans),
+	}
+	var end time.Time
+	for _, n := range ordered {
+		if parent, ok := nodes[n.ParentID]; ok && parent != n {
+			parent.Children = append(parent.Children, n)
+		} else {
+			trace.Roots = append(trace.Roots, n)
+		}
+		if e := n.EndTime(); e.After(end) {
+			end = e
+		}
+	}
+	trace.Duration = end.Sub(trace.StartTime)
+	return trace
+}
+
+// --- Recording ---
+
+// maxSpansPerRequest bounds the child spans buffered for one request so a
+// query loop cannot grow the buffer without limit.
+const maxSpansPerRequest = 1000
+
+const spanRecorderKey contextKey = "pulse_span_recorder"
+
+// spanRecorder buffers the child spans of a request until the tracing
+// middleware decides whether the request is recorded. Spans finished after
+// that decision are stored directly if the request was kept.
+type spanRecorder struct {
+	mu      sync.Mutex
+	spans   []Span
+	dropped int
+	done    bool
+	keep    bool
+}
+
+// add buffers a span. handled is false once the request has finished, in
+// which case keep tells whether the span should still be stored.
+func (r *spanRecorder) add(s Span) (handled, keep bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return false, r.keep
+	}
+	if len(r.spans) >= maxSpansPerRequest {
+		r.dropped++
+		return true, false
+	}
+	r.spans = append(r.spans, s)
+	return true, false
+}
+
+// finish ends buffering and returns the buffered spans, or nil when the
+// request is not recorded.
+func (r *spanRecorder) finish(keep bool) ([]Span, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done, r.keep = true, keep
+	spans, dropped := r.spans, r.dropped
+	r.spans = nil
+	if !keep {
+		return nil, 0
+	}
+	return spans, dropped
+}
+
+func contextWithSpanRecorder(ctx context.Context, r *spanRecorder) context.Context {
+	return context.WithValue(ctx, spanRecorderKey, r)
+}
+
+// recordSpan stores a finished span. Within a traced request the span is
+// buffered with the request; otherwise it is ingested directly.
+func (p *Pulse) recordSpan(ctx context.Context, s Span) {
+	if r, ok := ctx.Value(spanRecorderKey).(*spanRecorder); ok {
+		handled, keep := r.add(s)
+		if handled || !keep {
+			return
+		}
+	}
+	p.ingest(s)
+}
+
+// childSpanContext returns the trace context for a new span under the
+// current span in ctx. ok is false when ctx is not part of a trace.
+func childSpanContext(ctx context.Context) (parent, child TraceContext, ok bool) {
+	parent, ok = TraceContextFromContext(ctx)
+	if !ok {
+		traceID := TraceIDFromContext(ctx)
+		if traceID == "" {
+			return TraceContext{}, TraceContext{}, false
+		}
+		parent = TraceContext{TraceID: traceID, Flags: traceFlagSampled}
+	}
+	child = parent
+	child.ParentSpanID = parent.SpanID
+	child.SpanID = GenerateSpanID()
+	return parent, child, true
+}
+
+// spanStatus returns the error status for a non-empty error message.
+func spanStatus(errMsg string) SpanStatus {
+	if errMsg != "" {
+		return SpanStatusError
+	}
+	return SpanStatusOK
+}
+
+// --- Custom spans ---
+
+// spanHandle ties a span started with StartSpan to the Pulse instance and
+// request context it is recorded with.
+type spanHandle struct {
+	p    *Pulse
+	ctx  context.Context
+	once sync.Once
+}
+
+// StartSpan starts a span named name under the current span in ctx. The
+// returned context carries the new span, so queries, HTTP calls and spans
+// started with it become its children. The trace is taken from ctx, as set
+// by the tracing middleware or ContextWithTraceID, and the span is recorded
+// on End by the Pulse instance attached to ctx (see ContextWithPulse).
+//
+// Outside a trace the returned span is never recorded. A span must not be
+// used from several goroutines at once.
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	_, tc, ok := childSpanContext(ctx)
+	if !ok {
+		return ctx, &Span{Name: name, Kind: SpanKindInternal, StartTime: time.Now(), Status: SpanStatusUnset}
+	}
+
+	span := &Span{
+		TraceID:   tc.TraceID,
+		SpanID:    tc.SpanID,
+		ParentID:  tc.ParentSpanID,
+		Name:      name,
+		Kind:      SpanKindInternal,
+		StartTime: time.Now(),
+		Status:    SpanStatusUnset,
+	}
+	if p := PulseFromContext(ctx); p != nil {
+		span.handle = &spanHandle{p: p, ctx: ctx}
+	}
+	return ContextWithTraceContext(ctx, tc), span
+}
+
+// SetAttribute sets an attribute on the span. Values are stored as strings.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = fmt.Sprint(value)
+}
+
+// RecordError marks the span as failed with err's message. A nil error is
+// ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.Status = SpanStatusError
+	s.StatusMessage = err.Error()
+}
+
+// End finishes the span and records it. Calls after the first are ignored.
+func (s *Span) End() {
+	h := s.handle
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		s.Duration = time.Since(s.StartTime)
+		if s.Status == SpanStatusUnset {
+			s.Status = SpanStatusOK
+		}
+
+		// Record a copy so later changes to s don't reach storage
+		span := *s
+		span.handle = nil
+		if s.Attributes != nil {
+			span.Attributes = make(map[string]string, len(s.Attributes))
+			for k, v := range s.Attributes {
+				span.Attributes[k] = v
+			}
+		}
+		h.p.recordSpan(h.ctx, span)
+	})
+}
+
+// --- Statistics ---
+
+// SpanStats holds latency statistics for spans sharing a name and kind.
+type SpanStats struct {
+	Name          string        `json:"name"`
+	Kind          SpanKind      `json:"kind"`
+	Count         int64         `json:"count"`
+	ErrorCount    int64         `json:"error_count"`
+	ErrorRate     float64       `json:"error_rate"`
+	TotalDuration time.Duration `json:"total_duration"`
+	AvgLatency    time.Duration `json:"avg_latency"`
+	P50Latency    time.Duration `json:"p50_latency"`
+	P95Latency    time.Duration `json:"p95_latency"`
+	P99Latency    time.Duration `json:"p99_latency"`
+	MaxLatency    time.Duration `json:"max_latency"`
+}
+
+// buildSpanStats groups spans by name and kind, sorted by total duration
+// descending.
+func buildSpanStats(spans []Span) []SpanStats {
+	type spanKey struct {
+		name string
+		kind SpanKind
+	}
+
+	groups := make(map[spanKey][]Span)
+	for _, span := range spans {
+		key := spanKey{span.Name, span.Kind}
+		groups[key] = append(groups[key], span)
+	}
+
+	result := make([]SpanStats, 0, len(groups))
+	for key, group := range groups {
+		stats := SpanStats{Name: key.name, Kind: key.kind, Count: int64(len(group))}
+		latencies := make([]time.Duration, len(group))
+		for i, span := range group {
+			latencies[i] = span.Duration
+			stats.TotalDuration += span.Duration
+			if span.Status == SpanStatusError {
+				stats.ErrorCount++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		stats.ErrorRate = float64(stats.ErrorCount) / float64(stats.Count) * 100
+		stats.AvgLatency = ComputeAvg(latencies)
+		stats.P50Latency = Percentile(latencies, 50)
+		stats.P95Latency = Percentile(latencies, 95)
+		stats.P99Latency = Percentile(latencies, 99)
+		stats.MaxLatency = latencies[len(latencies)-1]
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalDuration > result[j].TotalDuration
+	})
+	return result
+}
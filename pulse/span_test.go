@@ -0,0 +1,315 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBuildTrace(t *testing.T) {
+	start := time.Now()
+	spans := []Span{
+		{SpanID: "c2", ParentID: "s1", Name: "second", StartTime: start.Add(30 * time.Millisecond), Duration: 10 * time.Millisecond},
+		{SpanID: "s1", ParentID: "remote", Name: "server", StartTime: start, Duration: 50 * time.Millisecond},
+		{SpanID: "c1", ParentID: "s1", Name: "first", StartTime: start.Add(5 * time.Millisecond), Duration: 20 * time.Millisecond},
+		{SpanID: "g1", ParentID: "c1", Name: "nested", StartTime: start.Add(6 * time.Millisecond), Duration: 70 * time.Millisecond},
+	}
+
+	trace := buildTrace("t1", spans)
+	if trace == nil || len(trace.Roots) != 1 {
+		t.Fatalf("expected a single root, got %+v", trace)
+	}
+	root := trace.Roots[0]
+	if root.Name != "server" || len(root.Children) != 2 {
+		t.Fatalf("expected server root with 2 children, got %s with %d", root.Name, len(root.Children))
+	}
+	if root.Children[0].Name != "first" || root.Children[1].Name != "second" {
+		t.Errorf("expected children ordered by start time, got %s, %s", root.Children[0].Name, root.Children[1].Name)
+	}
+	if len(root.Children[0].Children) != 1 {
+		t.Errorf("expected nested span under first child")
+	}
+	if trace.SpanCount != 4 || trace.Duration != 76*time.Millisecond {
+		t.Errorf("expected 4 spans over 76ms, got %d over %v", trace.SpanCount, trace.Duration)
+	}
+
+	if buildTrace("t2", nil) != nil {
+		t.Error("expected nil trace without spans")
+	}
+}
+
+func TestSpanRecorder_KeepsSpansWithRequest(t *testing.T) {
+	p := newPulse(applyDefaults(Config{}))
+	p.storage = NewMemoryStorage("test")
+	defer p.Shutdown()
+
+	// Dropped request: buffered and late spans are discarded
+	r := &spanRecorder{}
+	ctx := contextWithSpanRecorder(context.Background(), r)
+	p.recordSpan(ctx, Span{TraceID: "dropped", SpanID: "a"})
+	if spans, _ := r.finish(false); spans != nil {
+		t.Fatal("expected no spans for a dropped request")
+	}
+	p.recordSpan(ctx, Span{TraceID: "dropped", SpanID: "b"})
+	if spans, _ := p.storage.(*MemoryStorage).GetTrace("dropped"); len(spans) != 0 {
+		t.Errorf("expected late span of a dropped request to be discarded, got %d", len(spans))
+	}
+
+	// Kept request: late spans are stored directly
+	r = &spanRecorder{}
+	ctx = contextWithSpanRecorder(context.Background(), r)
+	p.recordSpan(ctx, Span{TraceID: "kept", SpanID: "a"})
+	if spans, _ := r.finish(true); len(spans) != 1 {
+		t.Fatalf("expected 1 buffered span, got %d", len(spans))
+	}
+	p.recordSpan(ctx, Span{TraceID: "kept", SpanID: "b"})
+	if spans, _ := p.storage.(*MemoryStorage).GetTrace("kept"); len(spans) != 1 || spans[0].SpanID != "b" {
+		t.Errorf("expected late span to be stored, got %+v", spans)
+	}
+}
+
+func TestSpanRecorder_CapsSpansPerRequest(t *testing.T) {
+	r := &spanRecorder{}
+	for i := 0; i < maxSpansPerRequest+5; i++ {
+		r.add(Span{})
+	}
+	spans, dropped := r.finish(true)
+	if len(spans) != maxSpansPerRequest || dropped != 5 {
+		t.Errorf("expected %d spans and 5 dropped, got %d and %d", maxSpansPerRequest, len(spans), dropped)
+	}
+}
+
+func TestTraceAPI_AssemblesRequestTree(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "app.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&TestUser{})
+
+	payments := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+	}))
+	defer payments.Close()
+
+	router := gin.New()
+	p := Mount(router, db, Config{
+		Dashboard: DashboardConfig{Username: "admin", Password: "testpass", SecretKey: "test-secret-key-for-jwt"},
+	})
+	defer p.Shutdown()
+	client := WrapHTTPClient(p, nil, "payments")
+
+	router.GET("/checkout/:id", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var users []TestUser
+		db.WithContext(ctx).Find(&users)
+		req, _ := http.NewRequestWithContext(ctx, "POST", payments.URL+"/charge?key=secret", nil)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/checkout/42", nil))
+	traceID := w.Header().Get(TraceIDHeader)
+
+	waitForSpans(t, p, traceID, 3)
+
+	token := loginAndGetToken(t, router)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/traces/"+traceID, token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var trace Trace
+	if err := json.Unmarshal(w.Body.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Roots) != 1 {
+		t.Fatalf("expected one root span, got %d", len(trace.Roots))
+	}
+	root := trace.Roots[0]
+	if root.Kind != SpanKindServer || root.Name != "GET /checkout/:id" || root.Attributes["http.response.status_code"] != "200" {
+		t.Errorf("unexpected server span: %+v", root.Span)
+	}
+	if len(root.Children) != 2 {
+		t.Fatalf("expected query and call under the server span, got %d children", len(root.Children))
+	}
+
+	query, call := root.Children[0], root.Children[1]
+	if query.Name != "SELECT test_users" || query.Attributes["db.system"] != "sqlite" || query.Status != SpanStatusOK {
+		t.Errorf("unexpected query span: %+v", query.Span)
+	}
+	if call.Name != "POST payments" || call.Status != SpanStatusError || call.Attributes["http.response.status_code"] != "402" {
+		t.Errorf("unexpected dependency span: %+v", call.Span)
+	}
+	if call.Attributes["url.full"] != payments.URL+"/charge" {
+		t.Errorf("expected query string stripped from span URL, got %q", call.Attributes["url.full"])
+	}
+
+	// Dependency calls carry the trace ID too
+	stats := p.storage.(*MemoryStorage).dependencies.GetAll()
+	if len(stats) != 1 || stats[0].TraceID != traceID {
+		t.Errorf("expected dependency metric linked to the trace, got %+v", stats)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/traces/"+GenerateTraceID(), token, ""))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown trace, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_DiscardsSpansOfUnsampledRequests(t *testing.T) {
+	rate := 0.0
+	router, p := setupTestRouter(Config{Tracing: TracingConfig{SampleRate: &rate}})
+	defer p.Shutdown()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	traceID := w.Header().Get(TraceIDHeader)
+
+	// Errors are kept, so waiting for one makes sure the queue was drained
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/error", nil))
+	waitForSpans(t, p, w.Header().Get(TraceIDHeader), 1)
+
+	if spans, _ := p.storage.(traceStore).GetTrace(traceID); len(spans) != 0 {
+		t.Errorf("expected no spans for an unsampled request, got %d", len(spans))
+	}
+}
+
+func TestStartSpan_NestsUnderRequest(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	router.GET("/render", func(c *gin.Context) {
+		ctx, span := StartSpan(c.Request.Context(), "render")
+		span.SetAttribute("template", "index")
+		_, lookup := StartSpan(ctx, "cache.lookup")
+		lookup.SetAttribute("cache.hit", false)
+		lookup.RecordError(errors.New("cache unavailable"))
+		lookup.End()
+		span.End()
+		span.End()
+		span.SetAttribute("late", true)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/render", nil))
+	traceID := w.Header().Get(TraceIDHeader)
+
+	trace := buildTrace(traceID, waitForSpans(t, p, traceID, 3))
+	if trace == nil || len(trace.Roots) != 1 || len(trace.Roots[0].Children) != 1 {
+		t.Fatalf("expected server span with one child, got %+v", trace)
+	}
+	render := trace.Roots[0].Children[0]
+	if render.Name != "render" || render.Kind != SpanKindInternal || render.Status != SpanStatusOK {
+		t.Errorf("unexpected render span: %+v", render.Span)
+	}
+	if render.Attributes["template"] != "index" || render.Attributes["late"] != "" {
+		t.Errorf("expected attributes set before End only, got %v", render.Attributes)
+	}
+	if len(render.Children) != 1 {
+		t.Fatalf("expected cache lookup under render, got %d children", len(render.Children))
+	}
+	lookup := render.Children[0]
+	if lookup.Status != SpanStatusError || lookup.StatusMessage != "cache unavailable" || lookup.Attributes["cache.hit"] != "false" {
+		t.Errorf("unexpected lookup span: %+v", lookup.Span)
+	}
+}
+
+func TestStartSpan_UsesTraceIDFromContext(t *testing.T) {
+	p := newPulse(applyDefaults(Config{}))
+	p.storage = NewMemoryStorage("test")
+	defer p.Shutdown()
+
+	// Background jobs carry a trace ID and the Pulse instance, no request
+	ctx := ContextWithPulse(ContextWithTraceID(context.Background(), testTraceID), p)
+	ctx, job := StartSpan(ctx, "job")
+	if TraceIDFromContext(ctx) != testTraceID {
+		t.Errorf("expected trace ID to be kept, got %s", TraceIDFromContext(ctx))
+	}
+	_, step := StartSpan(ctx, "step")
+	step.End()
+	job.End()
+
+	spans := waitForSpans(t, p, testTraceID, 2)
+	trace := buildTrace(testTraceID, spans)
+	if len(trace.Roots) != 1 || trace.Roots[0].Name != "job" || len(trace.Roots[0].Children) != 1 {
+		t.Errorf("expected step nested under job, got %+v", trace.Roots)
+	}
+
+	// Without a trace the span is a no-op
+	ctx = ContextWithPulse(context.Background(), p)
+	got, span := StartSpan(ctx, "orphan")
+	span.SetAttribute("k", "v")
+	span.End()
+	if got != ctx || span.TraceID != "" {
+		t.Errorf("expected an unrecorded span outside a trace, got %+v", span)
+	}
+}
+
+func TestSpanStatsAPI(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	token := loginAndGetToken(t, router)
+
+	now := time.Now()
+	p.storage.(traceStore).StoreSpans([]Span{
+		{TraceID: "t1", SpanID: "a", Name: "render", Kind: SpanKindInternal, StartTime: now, Duration: 10 * time.Millisecond, Status: SpanStatusOK},
+		{TraceID: "t2", SpanID: "b", Name: "render", Kind: SpanKindInternal, StartTime: now, Duration: 30 * time.Millisecond, Status: SpanStatusError},
+		{TraceID: "t2", SpanID: "c", Name: "GET /users", Kind: SpanKindServer, StartTime: now, Duration: 5 * time.Millisecond, Status: SpanStatusOK},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/spans?range=1h&kind=internal", token, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var stats []SpanStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected only internal spans, got %+v", stats)
+	}
+	render := stats[0]
+	if render.Name != "render" || render.Count != 2 || render.ErrorCount != 1 || render.MaxLatency != 30*time.Millisecond {
+		t.Errorf("unexpected render stats: %+v", render)
+	}
+	if render.AvgLatency != 20*time.Millisecond {
+		t.Errorf("expected 20ms average, got %v", render.AvgLatency)
+	}
+}
+
+// waitForSpans polls until at least n spans of the trace are stored.
+func waitForSpans(t *testing.T, p *Pulse, traceID string, n int) []Span {
+	t.Helper()
+	store := p.storage.(traceStore)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		spans, _ := store.GetTrace(traceID)
+		if len(spans) >= n {
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d spans for trace %s, got %d", n, traceID, len(spans))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -15,7 +15,12 @@ type Storage interface {
 	GetSlowQueries(threshold time.Duration, limit int) ([]QueryMetric, error)
 	GetQueryPatterns(timeRange TimeRange) ([]QueryPattern, error)
 	GetN1Detections(timeRange TimeRange) ([]N1Detection, error)
+	StoreN1Detection(d N1Detection) error
+
+	// Connection pool
+	StorePoolStats(stats PoolStats) error
 	GetConnectionPoolStats() (*PoolStats, error)
+	GetPoolStatsHistory(timeRange TimeRange) ([]PoolStats, error)
 
 	// Runtime metrics
 	StoreRuntime(m RuntimeMetric) error
@@ -30,6 +35,7 @@ type Storage interface {
 	// Health
 	StoreHealthResult(r HealthCheckResult) error
 	GetHealthHistory(name string, limit int) ([]HealthCheckResult, error)
+	GetLatestHealthResults() (map[string]HealthCheckResult, error)
 
 	// Alerts
 	StoreAlert(a AlertRecord) error
@@ -47,3 +53,62 @@ type Storage interface {
 	Reset() error
 	Close() error
 }
+
+// The following optional interfaces are implemented by the built-in backends
+// for write paths and lookups that are not part of Storage. Callers check for
+// them with a type assertion so custom backends can opt in.
+
+// errorRecordStore looks up and deletes individual error records.
+type errorRecordStore interface {
+	getErrorByID(id string) (*ErrorRecord, error)
+	deleteError(id string) error
+}
+
+// retentionPruner deletes expired data per data type and reports what was
+// removed. Backends without it are trimmed with Cleanup.
+type retentionPruner interface {
+	Prune(retention RetentionConfig) (PruneResult, error)
+}
+
+// subjectDeleter deletes the records of one data subject and reports what
+// was removed. Backends without it cannot serve erasure requests.
+type subjectDeleter interface {
+	DeleteSubject(filter SubjectFilter) (PruneResult, error)
+}
+
+// snapshotStore saves and restores the full storage contents to a file.
+type snapshotStore interface {
+	SaveSnapshotFile(path string) error
+	LoadSnapshotFile(path string) error
+}
+
+// batchStorer writes a batch of metrics in one operation.
+type batchStorer interface {
+	StoreBatch(b MetricBatch) error
+}
+
+// rollupReader returns the downsampled tier that should serve a time range
+// the raw data no longer covers, or nil to read raw data.
+type rollupReader interface {
+	requestRollupTier(tr TimeRange) *rollupTier
+	runtimeRollupTier(tr TimeRange) *rollupTier
+}
+
+// traceStore stores spans and returns every span of a trace. Backends
+// without it do not keep traces.
+type traceStore interface {
+	StoreSpans(spans []Span) error
+	GetTrace(traceID string) ([]Span, error)
+}
+
+// connectionStore stores long-lived connections. Backends without it do not
+// keep them.
+type connectionStore interface {
+	StoreConnections(conns []ConnectionMetric) error
+	GetConnections(timeRange TimeRange) ([]ConnectionMetric, error)
+}
+
+// spanStatsReader returns per-name span statistics within a time range.
+type spanStatsReader interface {
+	GetSpanStats(timeRange TimeRange) ([]SpanStats, error)
+}
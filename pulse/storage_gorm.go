@@ -0,0 +1,1451 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// --- Table models ---
+//
+// Each table stores the columns needed for filtering and aggregation plus a
+// JSON "data" column holding the full record. Timestamps are UnixNano
+// integers so range queries behave identically on every dialect.
+
+type gormRequestRow struct {
+	ID             uint   `gorm:"primaryKey"`
+	Method         string `gorm:"size:16;index:idx_pulse_requests_route,priority:1"`
+	Path           string `gorm:"size:512;index:idx_pulse_requests_route,priority:2"`
+	StatusCode     int
+	Latency        int64
+	DBTime         int64   `gorm:"default:0"`
+	DependencyTime int64   `gorm:"default:0"`
+	Timestamp      int64   `gorm:"index;index:idx_pulse_requests_route,priority:3"`
+	Instance       string  `gorm:"size:255;index"`
+	Weight         float64 `gorm:"default:1"` // requests the row stands for under sampling
+	Tags           string  // encoded by gormTags for LIKE matching
+	TraceID        string  `gorm:"size:64;index"`
+	Data           string
+}
+
+func (gormRequestRow) TableName() string { return "pulse_requests" }
+
+type gormQueryRow struct {
+	ID         uint `gorm:"primaryKey"`
+	Pattern    string
+	Operation  string `gorm:"size:16"`
+	QueryTable string `gorm:"column:query_table;size:128"`
+	Duration   int64  `gorm:"index"`
+	Failed     bool
+	Timestamp  int64 `gorm:"index"`
+	Data       string
+}
+
+func (gormQueryRow) TableName() string { return "pulse_queries" }
+
+type gormRuntimeRow struct {
+	ID        uint  `gorm:"primaryKey"`
+	Timestamp int64 `gorm:"index"`
+	Data      string
+}
+
+func (gormRuntimeRow) TableName() string { return "pulse_runtime" }
+
+type gormErrorRow struct {
+	Fingerprint string `gorm:"primaryKey;size:64"`
+	ErrorID     string `gorm:"column:error_id;size:64;uniqueIndex"`
+	Count       int64
+	FirstSeen   int64
+	LastSeen    int64 `gorm:"index"`
+	Muted       bool
+	Resolved    bool
+	Data        string
+}
+
+func (gormErrorRow) TableName() string { return "pulse_errors" }
+
+type gormHealthRow struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:128;index"`
+	Timestamp int64  `gorm:"index"`
+	Data      string
+}
+
+func (gormHealthRow) TableName() string { return "pulse_health_results" }
+
+type gormAlertRow struct {
+	ID       uint   `gorm:"primaryKey"`
+	State    string `gorm:"size:16;index"`
+	Severity string `gorm:"size:16"`
+	FiredAt  int64  `gorm:"index"`
+	Data     string
+}
+
+func (gormAlertRow) TableName() string { return "pulse_alerts" }
+
+type gormDependencyRow struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"size:128;index"`
+	StatusCode int
+	Latency    int64
+	Failed     bool
+	Timestamp  int64 `gorm:"index"`
+	Data       string
+}
+
+func (gormDependencyRow) TableName() string { return "pulse_dependencies" }
+
+type gormN1Row struct {
+	ID         uint  `gorm:"primaryKey"`
+	DetectedAt int64 `gorm:"index"`
+	Data       string
+}
+
+func (gormN1Row) TableName() string { return "pulse_n1_detections" }
+
+type gormPoolStatsRow struct {
+	ID        uint  `gorm:"primaryKey"`
+	Timestamp int64 `gorm:"index"`
+	Data      string
+}
+
+func (gormPoolStatsRow) TableName() string { return "pulse_pool_stats" }
+
+type gormSpanRow struct {
+	ID        uint   `gorm:"primaryKey"`
+	TraceID   string `gorm:"size:64;index"`
+	StartTime int64  `gorm:"index"`
+	Data      string
+}
+
+func (gormSpanRow) TableName() string { return "pulse_spans" }
+
+type gormConnectionRow struct {
+	ID        uint  `gorm:"primaryKey"`
+	Timestamp int64 `gorm:"index"`
+	Data      string
+}
+
+func (gormConnectionRow) TableName() string { return "pulse_connections" }
+
+// gormModels lists every Pulse table model, used for migration and maintenance.
+var gormModels = []interface{}{
+	&gormRequestRow{},
+	&gormQueryRow{},
+	&gormRuntimeRow{},
+	&gormErrorRow{},
+	&gormHealthRow{},
+	&gormAlertRow{},
+	&gormDependencyRow{},
+	&gormN1Row{},
+	&gormPoolStatsRow{},
+	&gormSpanRow{},
+	&gormConnectionRow{},
+}
+
+// GormStorage is a Storage implementation that writes Pulse data into
+// pulse_* tables through GORM, so any dialect GORM supports (Postgres,
+// MySQL, SQLite, ...) can hold metrics. Route, query and dependency
+// aggregates are computed in SQL. Every statement is tagged as internal so
+// PulsePlugin does not record Pulse's own queries.
+type GormStorage struct {
+	db *gorm.DB
+
+	// ownsDB is true when the connection was opened by Pulse and should be
+	// closed with the storage.
+	ownsDB bool
+
+	// errMu serializes error deduplication (read-modify-write by fingerprint)
+	errMu sync.Mutex
+
+	// Config
+	appName   string
+	startTime time.Time
+}
+
+// NewGormStorage creates a GORM-backed storage on db and auto-migrates the
+// Pulse tables. The connection is shared with the caller and is not closed
+// by Close.
+func NewGormStorage(db *gorm.DB, appName string) (*GormStorage, error) {
+	if db == nil {
+		return nil, errors.New("gorm storage requires a database")
+	}
+
+	s := &GormStorage{
+		db: db.Session(&gorm.Session{
+			NewDB:   true,
+			Context: withInternalQuery(context.Background()),
+			Logger:  db.Logger.LogMode(logger.Silent),
+		}),
+		appName:   appName,
+		startTime: time.Now(),
+	}
+
+	if err := s.db.AutoMigrate(gormModels...); err != nil {
+		return nil, fmt.Errorf("migrate pulse tables: %w", err)
+	}
+
+	return s, nil
+}
+
+// OpenGormStorage opens a dedicated connection with the given dialector
+// (e.g. postgres.Open(dsn)) and creates a GormStorage on it. The connection
+// is closed by Close.
+func OpenGormStorage(dialector gorm.Dialector, appName string) (*GormStorage, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("open pulse database: %w", err)
+	}
+
+	s, err := NewGormStorage(db, appName)
+	if err != nil {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			sqlDB.Close()
+		}
+		return nil, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
+
+// --- Request Metrics ---
+
+// StoreRequest stores a request metric.
+func (s *GormStorage) StoreRequest(m RequestMetric) error {
+	row, err := newGormRequestRow(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&row).Error
+}
+
+func newGormRequestRow(m RequestMetric) (gormRequestRow, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return gormRequestRow{}, err
+	}
+	return gormRequestRow{
+		Method:         m.Method,
+		Path:           m.Path,
+		StatusCode:     m.StatusCode,
+		Latency:        int64(m.Latency),
+		DBTime:         int64(m.DBTime),
+		DependencyTime: int64(m.DependencyTime),
+		Timestamp:      m.Timestamp.UnixNano(),
+		Instance:       m.Instance,
+		Weight:         m.weight(),
+		Tags:           gormTags(m.Tags),
+		TraceID:        m.TraceID,
+		Data:           string(data),
+	}, nil
+}
+
+// gormTags encodes tags as ",key=value," entries, sorted and query-escaped,
+// so a tag can be matched with a portable LIKE.
+func gormTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	entries := make([]string, 0, len(tags))
+	for k, v := range tags {
+		entries = append(entries, gormTagEntry(k, v))
+	}
+	sort.Strings(entries)
+	return "," + strings.Join(entries, ",") + ","
+}
+
+func gormTagEntry(key, value string) string {
+	return url.QueryEscape(key) + "=" + url.QueryEscape(value)
+}
+
+// gormTagPattern returns the LIKE pattern matching a tag in the encoded
+// column, escaped with '!'.
+func gormTagPattern(key, value string) string {
+	escaper := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return "%," + escaper.Replace(gormTagEntry(key, value)) + ",%"
+}
+
+// GetRequests returns requests matching the filter.
+func (s *GormStorage) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
+	tx := gormTimeRange(s.db.Model(&gormRequestRow{}), "timestamp", filter.TimeRange)
+	if filter.Method != "" {
+		tx = tx.Where("method = ?", filter.Method)
+	}
+	if filter.Path != "" {
+		tx = tx.Where("path = ?", filter.Path)
+	}
+	if filter.StatusCode != 0 {
+		tx = tx.Where("status_code = ?", filter.StatusCode)
+	}
+	if filter.MinLatency > 0 {
+		tx = tx.Where("latency >= ?", int64(filter.MinLatency))
+	}
+	if filter.Instance != "" {
+		tx = tx.Where("instance = ?", filter.Instance)
+	}
+	for key, value := range filter.Tags {
+		tx = tx.Where("tags LIKE ? ESCAPE '!'", gormTagPattern(key, value))
+	}
+	if filter.TraceID != "" {
+		tx = tx.Where("trace_id = ?", filter.TraceID)
+	}
+	tx = tx.Order("timestamp, id")
+
+	// OFFSET without LIMIT is not portable (MySQL rejects it)
+	if filter.Limit <= 0 {
+		result, err := gormSelect[RequestMetric](tx)
+		if err != nil {
+			return nil, err
+		}
+		return paginate(result, filter.Offset, 0), nil
+	}
+
+	result, err := gormSelect[RequestMetric](tx.Offset(filter.Offset).Limit(filter.Limit))
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRouteStats returns aggregated stats per route within the time range.
+func (s *GormStorage) GetRouteStats(timeRange TimeRange) ([]RouteStats, error) {
+	return s.routeStats(timeRange, "", "")
+}
+
+// GetRouteDetail returns detailed stats for a specific route.
+func (s *GormStorage) GetRouteDetail(method, path string, timeRange TimeRange) (*RouteDetail, error) {
+	stats, err := s.routeStats(timeRange, method, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, nil
+	}
+
+	// Get recent requests (last 50), oldest first
+	tx := gormTimeRange(s.db.Model(&gormRequestRow{}), "timestamp", timeRange).
+		Where("method = ? AND path = ?", method, path).
+		Order("timestamp DESC, id DESC").
+		Limit(50)
+	recent, err := gormSelect[RequestMetric](tx)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(recent)-1; i < j; i, j = i+1, j-1 {
+		recent[i], recent[j] = recent[j], recent[i]
+	}
+
+	// Get related errors
+	errors, _ := s.GetErrors(ErrorFilter{
+		TimeRange: timeRange,
+		Route:     fmt.Sprintf("%s %s", method, path),
+		Limit:     20,
+	})
+
+	// Get related queries
+	patterns, _ := s.GetQueryPatterns(timeRange)
+
+	detail := &RouteDetail{
+		RouteStats:     stats[0],
+		RecentRequests: recent,
+		RecentErrors:   errors,
+		TopQueries:     patterns,
+	}
+
+	// Phase timings live in the JSON payload, so they are aggregated here
+	reqs, err := gormSelect[RequestMetric](gormTimeRange(s.db.Model(&gormRequestRow{}), "timestamp", timeRange).
+		Where("method = ? AND path = ?", method, path))
+	if err != nil {
+		return nil, err
+	}
+	addPhaseStats(detail, reqs)
+	return detail, nil
+}
+
+// routeStats aggregates requests per route in SQL. When method and path are
+// set only that route is aggregated. Counts are extrapolated from sampled
+// requests by their weight; latencies cover the recorded rows.
+func (s *GormStorage) routeStats(timeRange TimeRange, method, path string) ([]RouteStats, error) {
+	scope := func() *gorm.DB {
+		tx := gormTimeRange(s.db.Model(&gormRequestRow{}), "timestamp", timeRange)
+		if method != "" {
+			tx = tx.Where("method = ? AND path = ?", method, path)
+		}
+		return tx
+	}
+
+	var aggs []struct {
+		Method         string
+		Path           string
+		RequestCount   float64
+		RowCount       int64
+		ErrorCount     float64
+		TotalLatency   float64
+		MinLatency     int64
+		MaxLatency     int64
+		DBTime         float64
+		DependencyTime float64
+		AppTime        float64
+	}
+	err := scope().
+		Select("method, path, SUM(weight) AS request_count, COUNT(*) AS row_count, " +
+			"SUM(CASE WHEN status_code >= 400 THEN weight ELSE 0 END) AS error_count, " +
+			"SUM(latency) AS total_latency, MIN(latency) AS min_latency, MAX(latency) AS max_latency, " +
+			"SUM(db_time) AS db_time, SUM(dependency_time) AS dependency_time, " +
+			"SUM(CASE WHEN latency > db_time + dependency_time THEN latency - db_time - dependency_time ELSE 0 END) AS app_time").
+		Group("method, path").
+		Order("request_count DESC").
+		Scan(&aggs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	var codes []struct {
+		Method     string
+		Path       string
+		StatusCode int
+		Count      float64
+	}
+	err = scope().
+		Select("method, path, status_code, SUM(weight) AS count").
+		Group("method, path, status_code").
+		Scan(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type routeKey struct{ method, path string }
+	statusCodes := make(map[routeKey]map[int]int64, len(aggs))
+	for _, c := range codes {
+		key := routeKey{c.Method, c.Path}
+		if statusCodes[key] == nil {
+			statusCodes[key] = make(map[int]int64)
+		}
+		statusCodes[key][c.StatusCode] = int64(math.Round(c.Count))
+	}
+
+	percentiles, err := gormGroupPercentiles(scope(), []string{"method", "path"}, "latency", 50, 75, 90, 95, 99)
+	if err != nil {
+		return nil, err
+	}
+
+	minutes := timeRange.End.Sub(timeRange.Start).Minutes()
+	stats := make([]RouteStats, 0, len(aggs))
+	for _, a := range aggs {
+		pcts := percentiles.get(a.Method, a.Path)
+
+		rpm := float64(0)
+		if minutes > 0 {
+			rpm = a.RequestCount / minutes
+		}
+
+		rs := RouteStats{
+			Method:       a.Method,
+			Path:         a.Path,
+			RequestCount: int64(math.Round(a.RequestCount)),
+			ErrorCount:   int64(math.Round(a.ErrorCount)),
+			ErrorRate:    a.ErrorCount / a.RequestCount * 100,
+			AvgLatency:   time.Duration(a.TotalLatency / float64(a.RowCount)),
+			MinLatency:   time.Duration(a.MinLatency),
+			MaxLatency:   time.Duration(a.MaxLatency),
+			P50Latency:   pcts[0],
+			P75Latency:   pcts[1],
+			P90Latency:   pcts[2],
+			P95Latency:   pcts[3],
+			P99Latency:   pcts[4],
+			RPM:          rpm,
+			StatusCodes:  statusCodes[routeKey{a.Method, a.Path}],
+			Trend:        "stable",
+		}
+		timeShares{
+			latency:      time.Duration(a.TotalLatency),
+			db:           time.Duration(a.DBTime),
+			dependencies: time.Duration(a.DependencyTime),
+			app:          time.Duration(a.AppTime),
+		}.apply(&rs)
+		stats = append(stats, rs)
+	}
+
+	return stats, nil
+}
+
+// --- Query Metrics ---
+
+// StoreQuery stores a query metric.
+func (s *GormStorage) StoreQuery(m QueryMetric) error {
+	row, err := newGormQueryRow(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&row).Error
+}
+
+func newGormQueryRow(m QueryMetric) (gormQueryRow, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return gormQueryRow{}, err
+	}
+	pattern := m.NormalizedSQL
+	if pattern == "" {
+		pattern = m.SQL
+	}
+	return gormQueryRow{
+		Pattern:    pattern,
+		Operation:  m.Operation,
+		QueryTable: m.Table,
+		Duration:   int64(m.Duration),
+		Failed:     m.Error != "",
+		Timestamp:  m.Timestamp.UnixNano(),
+		Data:       string(data),
+	}, nil
+}
+
+// GetSlowQueries returns queries slower than the threshold, slowest first.
+func (s *GormStorage) GetSlowQueries(threshold time.Duration, limit int) ([]QueryMetric, error) {
+	tx := s.db.Model(&gormQueryRow{}).Where("duration >= ?", int64(threshold)).Order("duration DESC")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	return gormSelect[QueryMetric](tx)
+}
+
+// GetQueryPatterns returns query patterns aggregated in SQL, sorted by total
+// duration descending.
+func (s *GormStorage) GetQueryPatterns(timeRange TimeRange) ([]QueryPattern, error) {
+	var rows []struct {
+		Pattern       string
+		Operation     string
+		QueryTable    string
+		Count         int64
+		TotalDuration float64
+		MaxDuration   int64
+		ErrorCount    float64
+	}
+	err := gormTimeRange(s.db.Model(&gormQueryRow{}), "timestamp", timeRange).
+		Select("pattern, MAX(operation) AS operation, MAX(query_table) AS query_table, COUNT(*) AS count, " +
+			"SUM(duration) AS total_duration, MAX(duration) AS max_duration, " +
+			"SUM(CASE WHEN failed THEN 1 ELSE 0 END) AS error_count").
+		Group("pattern").
+		Order("total_duration DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]QueryPattern, 0, len(rows))
+	for _, r := range rows {
+		total := time.Duration(r.TotalDuration)
+		result = append(result, QueryPattern{
+			NormalizedSQL: r.Pattern,
+			Operation:     r.Operation,
+			Table:         r.QueryTable,
+			Count:         r.Count,
+			AvgDuration:   total / time.Duration(r.Count),
+			MaxDuration:   time.Duration(r.MaxDuration),
+			TotalDuration: total,
+			ErrorCount:    int64(r.ErrorCount),
+		})
+	}
+	return result, nil
+}
+
+// GetN1Detections returns detected N+1 query issues.
+func (s *GormStorage) GetN1Detections(timeRange TimeRange) ([]N1Detection, error) {
+	tx := gormTimeRange(s.db.Model(&gormN1Row{}), "detected_at", timeRange).Order("id")
+	result, err := gormSelect[N1Detection](tx)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StoreN1Detection stores an N+1 detection.
+func (s *GormStorage) StoreN1Detection(d N1Detection) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&gormN1Row{DetectedAt: d.DetectedAt.UnixNano(), Data: string(data)}).Error
+}
+
+// --- Connection Pool ---
+
+// GetConnectionPoolStats returns the latest connection pool stats.
+func (s *GormStorage) GetConnectionPoolStats() (*PoolStats, error) {
+	result, err := gormSelect[PoolStats](s.db.Model(&gormPoolStatsRow{}).Order("id DESC").Limit(1))
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+// StorePoolStats records a connection pool stats sample.
+func (s *GormStorage) StorePoolStats(stats PoolStats) error {
+	if stats.Timestamp.IsZero() {
+		stats.Timestamp = time.Now()
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&gormPoolStatsRow{Timestamp: stats.Timestamp.UnixNano(), Data: string(data)}).Error
+}
+
+// UpdatePoolStats records a connection pool stats sample, ignoring errors.
+//
+// Deprecated: Use StorePoolStats.
+func (s *GormStorage) UpdatePoolStats(stats PoolStats) {
+	_ = s.StorePoolStats(stats)
+}
+
+// GetPoolStatsHistory returns pool stats samples within the time range, oldest first.
+func (s *GormStorage) GetPoolStatsHistory(timeRange TimeRange) ([]PoolStats, error) {
+	tx := gormTimeRange(s.db.Model(&gormPoolStatsRow{}), "timestamp", timeRange).Order("timestamp, id")
+	return gormSelect[PoolStats](tx)
+}
+
+// --- Runtime Metrics ---
+
+// StoreRuntime stores a runtime metric snapshot.
+func (s *GormStorage) StoreRuntime(m RuntimeMetric) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&gormRuntimeRow{Timestamp: m.Timestamp.UnixNano(), Data: string(data)}).Error
+}
+
+// GetRuntimeHistory returns runtime metrics within the time range.
+func (s *GormStorage) GetRuntimeHistory(timeRange TimeRange) ([]RuntimeMetric, error) {
+	tx := gormTimeRange(s.db.Model(&gormRuntimeRow{}), "timestamp", timeRange).Order("timestamp, id")
+	result, err := gormSelect[RuntimeMetric](tx)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// --- Batches ---
+
+// StoreBatch inserts a batch of metrics in one transaction. Errors are
+// written individually because they are deduplicated by fingerprint.
+func (s *GormStorage) StoreBatch(b MetricBatch) error {
+	requests, err := gormRows(b.Requests, newGormRequestRow)
+	if err != nil {
+		return err
+	}
+	queries, err := gormRows(b.Queries, newGormQueryRow)
+	if err != nil {
+		return err
+	}
+	deps, err := gormRows(b.Dependencies, newGormDependencyRow)
+	if err != nil {
+		return err
+	}
+	spans, err := gormRows(b.Spans, newGormSpanRow)
+	if err != nil {
+		return err
+	}
+	conns, err := gormRows(b.Connections, newGormConnectionRow)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if len(requests) > 0 {
+			if err := tx.CreateInBatches(requests, gormInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(queries) > 0 {
+			if err := tx.CreateInBatches(queries, gormInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(deps) > 0 {
+			if err := tx.CreateInBatches(deps, gormInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(spans) > 0 {
+			if err := tx.CreateInBatches(spans, gormInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(conns) > 0 {
+			if err := tx.CreateInBatches(conns, gormInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	for _, e := range b.Errors {
+		if storeErr := s.StoreError(e); storeErr != nil && err == nil {
+			err = storeErr
+		}
+	}
+	return err
+}
+
+// --- Error Records ---
+
+// StoreError stores or deduplicates an error record by fingerprint.
+func (s *GormStorage) StoreError(e ErrorRecord) error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var row gormErrorRow
+		err := tx.Where("fingerprint = ?", e.Fingerprint).Limit(1).Find(&row).Error
+		if err != nil {
+			return err
+		}
+
+		if row.Fingerprint == "" {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			return tx.Create(&gormErrorRow{
+				Fingerprint: e.Fingerprint,
+				ErrorID:     e.ID,
+				Count:       e.Count,
+				FirstSeen:   e.FirstSeen.UnixNano(),
+				LastSeen:    e.LastSeen.UnixNano(),
+				Muted:       e.Muted,
+				Resolved:    e.Resolved,
+				Data:        string(data),
+			}).Error
+		}
+
+		// Deduplicate: increment count and update LastSeen
+		existing, err := row.record()
+		if err != nil {
+			return err
+		}
+		existing.Count++
+		existing.LastSeen = e.LastSeen
+		if e.StackTrace != "" {
+			existing.StackTrace = e.StackTrace
+		}
+		if e.RequestContext != nil {
+			existing.RequestContext = e.RequestContext
+		}
+		existing.Instances = mergeInstances(existing.Instances, e.Instances)
+		existing.Tags = mergeTagValues(existing.Tags, e.Tags)
+		existing.TraceIDs = mergeTraceIDs(existing.TraceIDs, e.TraceIDs)
+		data, err := json.Marshal(existing)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&gormErrorRow{}).Where("fingerprint = ?", e.Fingerprint).Updates(map[string]interface{}{
+			"count":     existing.Count,
+			"last_seen": existing.LastSeen.UnixNano(),
+			"data":      string(data),
+		}).Error
+	})
+}
+
+// GetErrors returns errors matching the filter.
+func (s *GormStorage) GetErrors(filter ErrorFilter) ([]ErrorRecord, error) {
+	all, err := s.allErrors()
+	if err != nil {
+		return nil, err
+	}
+	return filterErrorRecords(all, filter), nil
+}
+
+// GetErrorGroups returns error groups for the dashboard.
+func (s *GormStorage) GetErrorGroups(timeRange TimeRange) ([]ErrorGroup, error) {
+	all, err := s.allErrors()
+	if err != nil {
+		return nil, err
+	}
+	return buildErrorGroups(all, timeRange), nil
+}
+
+// UpdateError updates specific fields on an error record.
+func (s *GormStorage) UpdateError(id string, updates map[string]interface{}) error {
+	record, err := s.getErrorByID(id)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := updates["muted"]; ok {
+		if b, ok := v.(bool); ok {
+			record.Muted = b
+		}
+	}
+	if v, ok := updates["resolved"]; ok {
+		if b, ok := v.(bool); ok {
+			record.Resolved = b
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&gormErrorRow{}).Where("error_id = ?", id).Updates(map[string]interface{}{
+		"muted":    record.Muted,
+		"resolved": record.Resolved,
+		"data":     string(data),
+	}).Error
+}
+
+// allErrors loads every error record.
+func (s *GormStorage) allErrors() ([]ErrorRecord, error) {
+	var rows []gormErrorRow
+	if err := s.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]ErrorRecord, 0, len(rows))
+	for _, row := range rows {
+		e, err := row.record()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *e)
+	}
+	return result, nil
+}
+
+// deleteError removes an error record by ID.
+func (s *GormStorage) deleteError(id string) error {
+	res := s.db.Where("error_id = ?", id).Delete(&gormErrorRow{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("error record not found: %s", id)
+	}
+	return nil
+}
+
+// getErrorByID retrieves a single error record by ID.
+func (s *GormStorage) getErrorByID(id string) (*ErrorRecord, error) {
+	var rows []gormErrorRow
+	if err := s.db.Where("error_id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("error record not found: %s", id)
+	}
+	return rows[0].record()
+}
+
+// record decodes the row, with the count and triage columns taking
+// precedence over the JSON payload.
+func (r gormErrorRow) record() (*ErrorRecord, error) {
+	var e ErrorRecord
+	if err := json.Unmarshal([]byte(r.Data), &e); err != nil {
+		return nil, err
+	}
+	e.Count = r.Count
+	e.Muted = r.Muted
+	e.Resolved = r.Resolved
+	return &e, nil
+}
+
+// --- Health Results ---
+
+// StoreHealthResult stores a health check result.
+func (s *GormStorage) StoreHealthResult(r HealthCheckResult) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&gormHealthRow{Name: r.Name, Timestamp: r.Timestamp.UnixNano(), Data: string(data)}).Error
+}
+
+// GetHealthHistory returns the history for a specific health check. With a
+// limit the most recent results are returned first, matching MemoryStorage.
+func (s *GormStorage) GetHealthHistory(name string, limit int) ([]HealthCheckResult, error) {
+	tx := s.db.Model(&gormHealthRow{}).Where("name = ?", name)
+	if limit > 0 {
+		tx = tx.Order("id DESC").Limit(limit)
+	} else {
+		tx = tx.Order("id")
+	}
+	return gormSelect[HealthCheckResult](tx)
+}
+
+// GetLatestHealthResults returns the latest health check result for each check.
+func (s *GormStorage) GetLatestHealthResults() (map[string]HealthCheckResult, error) {
+	latestIDs := s.db.Model(&gormHealthRow{}).Select("MAX(id)").Group("name")
+	latest, err := gormSelect[HealthCheckResult](s.db.Model(&gormHealthRow{}).Where("id IN (?)", latestIDs))
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]HealthCheckResult, len(latest))
+	for _, r := range latest {
+		results[r.Name] = r
+	}
+	return results, nil
+}
+
+// --- Alerts ---
+
+// StoreAlert stores an alert record.
+func (s *GormStorage) StoreAlert(a AlertRecord) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&gormAlertRow{
+		State:    string(a.State),
+		Severity: a.Severity,
+		FiredAt:  a.FiredAt.UnixNano(),
+		Data:     string(data),
+	}).Error
+}
+
+// GetAlerts returns alerts matching the filter.
+func (s *GormStorage) GetAlerts(filter AlertFilter) ([]AlertRecord, error) {
+	tx := gormTimeRange(s.db.Model(&gormAlertRow{}), "fired_at", filter.TimeRange).Order("id")
+	alerts, err := gormSelect[AlertRecord](tx)
+	if err != nil {
+		return nil, err
+	}
+	return filterAlertRecords(alerts, filter), nil
+}
+
+// --- Dependencies ---
+
+// StoreDependencyMetric stores a dependency metric.
+func (s *GormStorage) StoreDependencyMetric(m DependencyMetric) error {
+	row, err := newGormDependencyRow(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Create(&row).Error
+}
+
+func newGormDependencyRow(m DependencyMetric) (gormDependencyRow, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return gormDependencyRow{}, err
+	}
+	return gormDependencyRow{
+		Name:       m.Name,
+		StatusCode: m.StatusCode,
+		Latency:    int64(m.Latency),
+		Failed:     m.Error != "",
+		Timestamp:  m.Timestamp.UnixNano(),
+		Data:       string(data),
+	}, nil
+}
+
+// GetDependencyStats returns per-dependency stats aggregated in SQL, sorted
+// by request count descending.
+func (s *GormStorage) GetDependencyStats(timeRange TimeRange) ([]DependencyStats, error) {
+	var aggs []struct {
+		Name         string
+		RequestCount int64
+		ErrorCount   float64
+		TotalLatency float64
+	}
+	err := gormTimeRange(s.db.Model(&gormDependencyRow{}), "timestamp", timeRange).
+		Select("name, COUNT(*) AS request_count, " +
+			"SUM(CASE WHEN failed OR status_code >= 500 THEN 1 ELSE 0 END) AS error_count, " +
+			"SUM(latency) AS total_latency").
+		Group("name").
+		Order("request_count DESC").
+		Scan(&aggs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	percentiles, err := gormGroupPercentiles(gormTimeRange(s.db.Model(&gormDependencyRow{}), "timestamp", timeRange),
+		[]string{"name"}, "latency", 50, 95, 99)
+	if err != nil {
+		return nil, err
+	}
+
+	minutes := timeRange.End.Sub(timeRange.Start).Minutes()
+	result := make([]DependencyStats, 0, len(aggs))
+	for _, a := range aggs {
+		pcts := percentiles.get(a.Name)
+
+		var last gormDependencyRow
+		if err := gormTimeRange(s.db.Model(&gormDependencyRow{}), "timestamp", timeRange).Where("name = ?", a.Name).Order("timestamp DESC, id DESC").Limit(1).Find(&last).Error; err != nil {
+			return nil, err
+		}
+
+		errCount := int64(a.ErrorCount)
+		errRate := float64(errCount) / float64(a.RequestCount) * 100
+
+		lastStatus := "healthy"
+		if last.StatusCode >= 500 {
+			lastStatus = "unhealthy"
+		}
+
+		rpm := float64(0)
+		if minutes > 0 {
+			rpm = float64(a.RequestCount) / minutes
+		}
+
+		result = append(result, DependencyStats{
+			Name:         a.Name,
+			RequestCount: a.RequestCount,
+			ErrorCount:   errCount,
+			ErrorRate:    errRate,
+			AvgLatency:   time.Duration(a.TotalLatency / float64(a.RequestCount)),
+			P50Latency:   pcts[0],
+			P95Latency:   pcts[1],
+			P99Latency:   pcts[2],
+			RPM:          rpm,
+			Availability: 100 - errRate,
+			LastStatus:   lastStatus,
+			LastChecked:  time.Unix(0, last.Timestamp),
+		})
+	}
+	return result, nil
+}
+
+// --- Overview ---
+
+// GetOverview computes the top-level dashboard snapshot.
+func (s *GormStorage) GetOverview(timeRange TimeRange) (*Overview, error) {
+	reqs, err := gormSelect[RequestMetric](
+		gormTimeRange(s.db.Model(&gormRequestRow{}), "timestamp", timeRange).Order("timestamp, id"))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *RuntimeMetric
+	runtimeStats, err := gormSelect[RuntimeMetric](s.db.Model(&gormRuntimeRow{}).Order("timestamp DESC, id DESC").Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(runtimeStats) > 0 {
+		latest = &runtimeStats[0]
+	}
+
+	// Active alerts count
+	var activeAlerts int64
+	if err := s.db.Model(&gormAlertRow{}).Where("state = ?", string(AlertStateFiring)).Count(&activeAlerts).Error; err != nil {
+		return nil, err
+	}
+
+	// Recent errors
+	recentErrors, _ := s.GetErrors(ErrorFilter{
+		TimeRange: timeRange,
+		Limit:     5,
+	})
+
+	return buildOverview(s.appName, time.Since(s.startTime), timeRange, reqs, latest, int(activeAlerts), recentErrors), nil
+}
+
+// --- Traces ---
+
+// StoreSpans stores trace spans.
+func (s *GormStorage) StoreSpans(spans []Span) error {
+	rows, err := gormRows(spans, newGormSpanRow)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return s.db.CreateInBatches(rows, gormInsertBatchSize).Error
+}
+
+func newGormSpanRow(m Span) (gormSpanRow, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return gormSpanRow{}, err
+	}
+	return gormSpanRow{TraceID: m.TraceID, StartTime: m.StartTime.UnixNano(), Data: string(data)}, nil
+}
+
+// GetTrace returns every stored span of the trace, ordered by start time.
+func (s *GormStorage) GetTrace(traceID string) ([]Span, error) {
+	return gormSelect[Span](s.db.Model(&gormSpanRow{}).Where("trace_id = ?", traceID).Order("start_time, id"))
+}
+
+// GetSpanStats returns latency stats per span name within the time range.
+func (s *GormStorage) GetSpanStats(timeRange TimeRange) ([]SpanStats, error) {
+	spans, err := gormSelect[Span](gormTimeRange(s.db.Model(&gormSpanRow{}), "start_time", timeRange))
+	if err != nil {
+		return nil, err
+	}
+	return buildSpanStats(spans), nil
+}
+
+// --- Connections ---
+
+// StoreConnections stores long-lived connections.
+func (s *GormStorage) StoreConnections(conns []ConnectionMetric) error {
+	rows, err := gormRows(conns, newGormConnectionRow)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return s.db.CreateInBatches(rows, gormInsertBatchSize).Error
+}
+
+func newGormConnectionRow(m ConnectionMetric) (gormConnectionRow, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return gormConnectionRow{}, err
+	}
+	return gormConnectionRow{Timestamp: m.Timestamp.UnixNano(), Data: string(data)}, nil
+}
+
+// GetConnections returns the connections opened within the time range.
+func (s *GormStorage) GetConnections(timeRange TimeRange) ([]ConnectionMetric, error) {
+	return gormSelect[ConnectionMetric](gormTimeRange(s.db.Model(&gormConnectionRow{}), "timestamp", timeRange).Order("timestamp, id"))
+}
+
+// --- Maintenance ---
+
+// Cleanup deletes data older than the retention period.
+func (s *GormStorage) Cleanup(retention time.Duration) error {
+	_, err := s.Prune(uniformRetention(retention))
+	return err
+}
+
+// Prune deletes data older than each data type's retention period and
+// reports how many rows were removed.
+func (s *GormStorage) Prune(retention RetentionConfig) (PruneResult, error) {
+	var result PruneResult
+	now := time.Now()
+	deletes := []struct {
+		model     interface{}
+		column    string
+		retention time.Duration
+		count     *int64
+	}{
+		{&gormRequestRow{}, "timestamp", retention.Requests, &result.Requests},
+		{&gormQueryRow{}, "timestamp", retention.Queries, &result.Queries},
+		{&gormN1Row{}, "detected_at", retention.Queries, &result.N1Detections},
+		{&gormRuntimeRow{}, "timestamp", retention.Runtime, &result.Runtime},
+		{&gormPoolStatsRow{}, "timestamp", retention.Runtime, &result.PoolStats},
+		{&gormErrorRow{}, "last_seen", retention.Errors, &result.Errors},
+		{&gormAlertRow{}, "fired_at", retention.Alerts, &result.Alerts},
+		{&gormHealthRow{}, "timestamp", retention.Health, &result.Health},
+		{&gormDependencyRow{}, "timestamp", retention.Dependencies, &result.Dependencies},
+		{&gormSpanRow{}, "start_time", retention.Requests, &result.Spans},
+		{&gormConnectionRow{}, "timestamp", retention.Requests, &result.Connections},
+	}
+	for _, d := range deletes {
+		res := s.db.Where(d.column+" < ?", now.Add(-d.retention).UnixNano()).Delete(d.model)
+		if res.Error != nil {
+			return result, fmt.Errorf("cleanup: %w", res.Error)
+		}
+		*d.count = res.RowsAffected
+	}
+	return result, nil
+}
+
+// DeleteSubject deletes the records of one data subject. Rows are matched on
+// their decoded payload, so every backend agrees on what a filter matches.
+func (s *GormStorage) DeleteSubject(filter SubjectFilter) (PruneResult, error) {
+	var result PruneResult
+	if filter.IsZero() {
+		return result, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		traces := newSubjectTraces(filter)
+		if result.Requests, err = gormDeleteMatching(tx, &gormRequestRow{}, "id", func(m RequestMetric) bool {
+			if filter.matchRequest(m) {
+				traces.add(m.TraceID)
+				return true
+			}
+			return false
+		}); err != nil {
+			return fmt.Errorf("delete requests: %w", err)
+		}
+		if result.Connections, err = gormDeleteMatching(tx, &gormConnectionRow{}, "id", func(c ConnectionMetric) bool {
+			if filter.matchConnection(c) {
+				traces.add(c.TraceID)
+				return true
+			}
+			return false
+		}); err != nil {
+			return fmt.Errorf("delete connections: %w", err)
+		}
+		if result.Errors, err = gormEraseErrors(tx, filter, traces); err != nil {
+			return fmt.Errorf("delete errors: %w", err)
+		}
+		if len(traces) == 0 {
+			return nil
+		}
+		if result.Spans, err = gormDeleteMatching(tx, &gormSpanRow{}, "id", func(span Span) bool { return traces[span.TraceID] }); err != nil {
+			return fmt.Errorf("delete spans: %w", err)
+		}
+		if result.Queries, err = gormDeleteMatching(tx, &gormQueryRow{}, "id", func(m QueryMetric) bool { return traces[m.RequestTraceID] }); err != nil {
+			return fmt.Errorf("delete queries: %w", err)
+		}
+		if result.Dependencies, err = gormDeleteMatching(tx, &gormDependencyRow{}, "id", func(m DependencyMetric) bool { return traces[m.TraceID] }); err != nil {
+			return fmt.Errorf("delete dependencies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return PruneResult{}, err
+	}
+	return result, nil
+}
+
+// gormDeleteMatching deletes the rows of model whose decoded data matches,
+// by their key column, and returns how many were removed.
+func gormDeleteMatching[T any](tx *gorm.DB, model interface{}, key string, match func(T) bool) (int64, error) {
+	rows, err := tx.Model(model).Select(key, "data").Rows()
+	if err != nil {
+		return 0, err
+	}
+	var keys []interface{}
+	for rows.Next() {
+		var k interface{}
+		var data string
+		if err := rows.Scan(&k, &data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		var item T
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if match(item) {
+			keys = append(keys, k)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Chunked to stay below the drivers' bound parameter limits
+	const chunk = 500
+	var deleted int64
+	for i := 0; i < len(keys); i += chunk {
+		res := tx.Where(key+" IN ?", keys[i:min(i+chunk, len(keys))]).Delete(model)
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		deleted += res.RowsAffected
+	}
+	return deleted, nil
+}
+
+// gormEraseErrors removes a data subject from the error groups, deleting
+// the groups left empty, and returns how many groups were changed.
+func gormEraseErrors(tx *gorm.DB, filter SubjectFilter, traces subjectTraces) (int64, error) {
+	var rows []gormErrorRow
+	if err := tx.Select("fingerprint", "data").Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	var erased int64
+	for _, row := range rows {
+		var e ErrorRecord
+		if err := json.Unmarshal([]byte(row.Data), &e); err != nil {
+			return 0, err
+		}
+		e, matched, empty := filter.eraseError(e, traces)
+		if !matched {
+			continue
+		}
+		q := tx.Where("fingerprint = ?", row.Fingerprint)
+		if empty {
+			if err := q.Delete(&gormErrorRow{}).Error; err != nil {
+				return 0, err
+			}
+		} else {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return 0, err
+			}
+			if err := q.Model(&gormErrorRow{}).Update("data", string(data)).Error; err != nil {
+				return 0, err
+			}
+		}
+		erased++
+	}
+	return erased, nil
+}
+
+// Reset clears all stored data.
+func (s *GormStorage) Reset() error {
+	for _, model := range gormModels {
+		if err := s.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error; err != nil {
+			return fmt.Errorf("reset: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the database connection if it was opened by OpenGormStorage.
+// A connection shared with the application is left open.
+func (s *GormStorage) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// --- Helpers ---
+
+// gormTimeRange adds an inclusive range condition over a UnixNano column.
+// Zero bounds are treated as open-ended.
+func gormTimeRange(tx *gorm.DB, column string, tr TimeRange) *gorm.DB {
+	if !tr.Start.IsZero() {
+		tx = tx.Where(column+" >= ?", tr.Start.UnixNano())
+	}
+	if !tr.End.IsZero() {
+		tx = tx.Where(column+" <= ?", tr.End.UnixNano())
+	}
+	return tx
+}
+
+// gormInsertBatchSize caps the rows per INSERT statement, keeping batches
+// under the bind-parameter limits of every dialect.
+const gormInsertBatchSize = 100
+
+// gormRows converts metrics to table rows.
+func gormRows[M, R any](items []M, build func(M) (R, error)) ([]R, error) {
+	rows := make([]R, 0, len(items))
+	for _, m := range items {
+		row, err := build(m)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// gormSelect plucks the JSON "data" column and decodes each row into T.
+func gormSelect[T any](tx *gorm.DB) ([]T, error) {
+	var data []string
+	if err := tx.Pluck("data", &data).Error; err != nil {
+		return nil, err
+	}
+
+	var result []T
+	for _, d := range data {
+		var item T
+		if err := json.Unmarshal([]byte(d), &item); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// gormPercentileGroups holds percentiles per group, keyed by the group
+// column values joined with a NUL byte.
+type gormPercentileGroups struct {
+	n      int
+	groups map[string][]time.Duration
+}
+
+// get returns the percentiles of the group with the given column values, or
+// zeros when the group had no rows.
+func (g gormPercentileGroups) get(values ...string) []time.Duration {
+	if pcts, ok := g.groups[strings.Join(values, "\x00")]; ok {
+		return pcts
+	}
+	return make([]time.Duration, g.n)
+}
+
+// gormGroupPercentiles computes percentiles of column for every group of the
+// rows selected by tx in a single query, interpolating exactly like
+// Percentile. Postgres computes them with PERCENTILE_CONT; other dialects
+// stream the rows once, ordered by group and value.
+func gormGroupPercentiles(tx *gorm.DB, group []string, column string, ps ...float64) (gormPercentileGroups, error) {
+	result := gormPercentileGroups{n: len(ps), groups: make(map[string][]time.Duration)}
+	cols := strings.Join(group, ", ")
+
+	keys := make([]string, len(group))
+	dest := make([]any, 0, len(group)+len(ps))
+	for i := range keys {
+		dest = append(dest, &keys[i])
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		selects := []string{cols}
+		values := make([]float64, len(ps))
+		for i, p := range ps {
+			selects = append(selects, fmt.Sprintf("PERCENTILE_CONT(%g) WITHIN GROUP (ORDER BY %s)", p/100, column))
+			dest = append(dest, &values[i])
+		}
+		rows, err := tx.Select(strings.Join(selects, ", ")).Group(cols).Rows()
+		if err != nil {
+			return result, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return result, err
+			}
+			pcts := make([]time.Duration, len(ps))
+			for i, v := range values {
+				pcts[i] = time.Duration(v)
+			}
+			result.groups[strings.Join(keys, "\x00")] = pcts
+		}
+		return result, rows.Err()
+	}
+
+	rows, err := tx.Select(cols + ", " + column).Order(cols + ", " + column).Rows()
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	var (
+		current string
+		sorted  []time.Duration
+		value   int64
+	)
+	flush := func() {
+		if len(sorted) == 0 {
+			return
+		}
+		pcts := make([]time.Duration, len(ps))
+		for i, p := range ps {
+			pcts[i] = Percentile(sorted, p)
+		}
+		result.groups[current] = pcts
+		sorted = sorted[:0]
+	}
+	dest = append(dest, &value)
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return result, err
+		}
+		if key := strings.Join(keys, "\x00"); key != current {
+			flush()
+			current = key
+		}
+		sorted = append(sorted, time.Duration(value))
+	}
+	if err := rows.Err(); err != nil {
+		return result, err
+	}
+	flush()
+	return result, nil
+}
+
+// Ensure GormStorage satisfies the Storage interface at compile time.
+var _ Storage = (*GormStorage)(nil)
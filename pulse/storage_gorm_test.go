@@ -0,0 +1,446 @@
+package pulse
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestGormDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "app.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func newTestGormStorage(t *testing.T) *GormStorage {
+	t.Helper()
+	s, err := NewGormStorage(openTestGormDB(t), "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGormStorage_CreatesNamespacedTables(t *testing.T) {
+	db := openTestGormDB(t)
+	if _, err := NewGormStorage(db, "test-app"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{"pulse_requests", "pulse_queries", "pulse_errors", "pulse_alerts", "pulse_pool_stats"} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("expected table %s to exist", table)
+		}
+	}
+}
+
+func TestGormStorage_GetRequests(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 20; i++ {
+		method := "GET"
+		if i%4 == 0 {
+			method = "POST"
+		}
+		s.StoreRequest(RequestMetric{
+			Method:     method,
+			Path:       "/users",
+			StatusCode: 200,
+			Latency:    time.Duration(i+1) * time.Millisecond,
+			Timestamp:  now.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+
+	reqs, _ := s.GetRequests(RequestFilter{TimeRange: tr, Method: "POST"})
+	if len(reqs) != 5 {
+		t.Fatalf("expected 5 POST requests, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, MinLatency: 15 * time.Millisecond})
+	if len(reqs) != 6 {
+		t.Fatalf("expected 6 slow requests, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, Offset: 15, Limit: 10})
+	if len(reqs) != 5 {
+		t.Fatalf("expected 5 with offset 15, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, Offset: 18})
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 with offset 18 and no limit, got %d", len(reqs))
+	}
+}
+
+func TestGormStorage_RouteStatsMatchMemory(t *testing.T) {
+	s := newTestGormStorage(t)
+	ms := newTestStorage()
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		status := 200
+		if i%10 == 0 {
+			status = 500
+		}
+		path := "/users"
+		if i%3 == 0 {
+			path = "/posts"
+		}
+		m := RequestMetric{
+			Method:     "GET",
+			Path:       path,
+			StatusCode: status,
+			Latency:    time.Duration((i*37)%100+1) * time.Millisecond,
+			Timestamp:  now,
+		}
+		s.StoreRequest(m)
+		ms.StoreRequest(m)
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	got, err := s.GetRouteStats(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ms.GetRouteStats(tr)
+
+	if len(got) != 2 || len(want) != 2 {
+		t.Fatalf("expected 2 routes from each backend, got %d and %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Path != w.Path || g.RequestCount != w.RequestCount || g.ErrorCount != w.ErrorCount {
+			t.Fatalf("route %d differs: sqlite %s %d/%d, memory %s %d/%d",
+				i, g.Path, g.RequestCount, g.ErrorCount, w.Path, w.RequestCount, w.ErrorCount)
+		}
+		if g.AvgLatency != w.AvgLatency || g.MinLatency != w.MinLatency || g.MaxLatency != w.MaxLatency {
+			t.Fatalf("latency summary differs for %s: %v/%v/%v vs %v/%v/%v",
+				g.Path, g.AvgLatency, g.MinLatency, g.MaxLatency, w.AvgLatency, w.MinLatency, w.MaxLatency)
+		}
+		if g.P50Latency != w.P50Latency || g.P90Latency != w.P90Latency || g.P99Latency != w.P99Latency {
+			t.Fatalf("percentiles differ for %s: %v/%v/%v vs %v/%v/%v",
+				g.Path, g.P50Latency, g.P90Latency, g.P99Latency, w.P50Latency, w.P90Latency, w.P99Latency)
+		}
+		if g.StatusCodes[500] != w.StatusCodes[500] || g.StatusCodes[200] != w.StatusCodes[200] {
+			t.Fatalf("status codes differ for %s: %v vs %v", g.Path, g.StatusCodes, w.StatusCodes)
+		}
+	}
+}
+
+func TestGormStorage_RouteDetail(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 60; i++ {
+		s.StoreRequest(RequestMetric{
+			Method:     "GET",
+			Path:       "/users/:id",
+			StatusCode: 200,
+			TraceID:    fmt.Sprintf("trace-%d", i),
+			Timestamp:  now.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	detail, err := s.GetRouteDetail("GET", "/users/:id", tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail == nil || detail.RequestCount != 60 {
+		t.Fatalf("expected detail with 60 requests, got %+v", detail)
+	}
+	if len(detail.RecentRequests) != 50 {
+		t.Fatalf("expected 50 recent requests, got %d", len(detail.RecentRequests))
+	}
+	if detail.RecentRequests[49].TraceID != "trace-59" {
+		t.Fatalf("expected most recent request last, got %s", detail.RecentRequests[49].TraceID)
+	}
+
+	detail, _ = s.GetRouteDetail("DELETE", "/users/:id", tr)
+	if detail != nil {
+		t.Fatal("expected nil detail for unknown route")
+	}
+}
+
+func TestGormStorage_QueryPatterns(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	for i, d := range []time.Duration{500 * time.Millisecond, 300 * time.Millisecond, 10 * time.Millisecond} {
+		m := QueryMetric{
+			SQL:           fmt.Sprintf("SELECT * FROM users WHERE id = %d", i),
+			NormalizedSQL: "select * from users where id = ?",
+			Duration:      d,
+			Operation:     "SELECT",
+			Table:         "users",
+			Timestamp:     now,
+		}
+		if i == 2 {
+			m.Error = "no such table"
+		}
+		s.StoreQuery(m)
+	}
+	s.StoreQuery(QueryMetric{NormalizedSQL: "delete from posts", Duration: time.Millisecond, Operation: "DELETE", Table: "posts", Timestamp: now})
+
+	slow, _ := s.GetSlowQueries(200*time.Millisecond, 10)
+	if len(slow) != 2 || slow[0].Duration < slow[1].Duration {
+		t.Fatalf("expected 2 slow queries slowest first, got %+v", slow)
+	}
+
+	patterns, err := s.GetQueryPatterns(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 2 {
+		t.Fatalf("expected 2 patterns, got %d", len(patterns))
+	}
+	p := patterns[0]
+	if p.NormalizedSQL != "select * from users where id = ?" || p.Count != 3 || p.ErrorCount != 1 {
+		t.Fatalf("unexpected top pattern: %+v", p)
+	}
+	if p.TotalDuration != 810*time.Millisecond || p.MaxDuration != 500*time.Millisecond || p.AvgDuration != 270*time.Millisecond {
+		t.Fatalf("unexpected durations: total=%v max=%v avg=%v", p.TotalDuration, p.MaxDuration, p.AvgDuration)
+	}
+	if p.Table != "users" || p.Operation != "SELECT" {
+		t.Fatalf("expected users/SELECT, got %s/%s", p.Table, p.Operation)
+	}
+}
+
+func TestGormStorage_Errors(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		s.StoreError(ErrorRecord{
+			ID:           fmt.Sprintf("err-%d", i),
+			Fingerprint:  "fp-1",
+			Route:        "POST /users",
+			ErrorMessage: "validation failed",
+			Count:        1,
+			FirstSeen:    now,
+			LastSeen:     now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	errors, _ := s.GetErrors(ErrorFilter{})
+	if len(errors) != 1 || errors[0].Count != 3 || errors[0].ID != "err-0" {
+		t.Fatalf("expected 1 deduplicated error with count 3, got %+v", errors)
+	}
+
+	if err := s.UpdateError("err-0", map[string]interface{}{"muted": true}); err != nil {
+		t.Fatal(err)
+	}
+	s.StoreError(ErrorRecord{ID: "err-9", Fingerprint: "fp-1", Count: 1, FirstSeen: now, LastSeen: now})
+
+	rec, err := s.getErrorByID("err-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Muted || rec.Count != 4 {
+		t.Fatalf("expected muted error with count 4, got muted=%v count=%d", rec.Muted, rec.Count)
+	}
+
+	if err := s.UpdateError("nonexistent", map[string]interface{}{"muted": true}); err == nil {
+		t.Fatal("expected error for nonexistent ID")
+	}
+	if err := s.deleteError("err-0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.deleteError("err-0"); err == nil {
+		t.Fatal("expected error for nonexistent delete")
+	}
+}
+
+func TestGormStorage_HealthAlertsAndDependencies(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		s.StoreHealthResult(HealthCheckResult{Name: "postgres", Status: "healthy", Latency: time.Duration(i+1) * time.Millisecond, Timestamp: now})
+	}
+	s.StoreHealthResult(HealthCheckResult{Name: "redis", Status: "unhealthy", Timestamp: now})
+
+	history, _ := s.GetHealthHistory("postgres", 3)
+	if len(history) != 3 || history[0].Latency != 5*time.Millisecond {
+		t.Fatalf("expected 3 results, most recent first, got %+v", history)
+	}
+	latest, _ := s.GetLatestHealthResults()
+	if len(latest) != 2 || latest["redis"].Status != "unhealthy" {
+		t.Fatalf("unexpected latest results: %+v", latest)
+	}
+
+	s.StoreAlert(AlertRecord{ID: "a1", State: AlertStateFiring, Severity: "critical", FiredAt: now})
+	s.StoreAlert(AlertRecord{ID: "a2", State: AlertStateResolved, Severity: "warning", FiredAt: now})
+	alerts, _ := s.GetAlerts(AlertFilter{State: AlertStateFiring})
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 firing alert, got %d", len(alerts))
+	}
+
+	for i := 0; i < 10; i++ {
+		status := 200
+		if i == 9 {
+			status = 503
+		}
+		s.StoreDependencyMetric(DependencyMetric{
+			Name:       "stripe",
+			StatusCode: status,
+			Latency:    time.Duration(i+1) * 10 * time.Millisecond,
+			Timestamp:  now.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+	deps, err := s.GetDependencyStats(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0].RequestCount != 10 || deps[0].ErrorCount != 1 {
+		t.Fatalf("unexpected dependency stats: %+v", deps)
+	}
+	if deps[0].LastStatus != "unhealthy" {
+		t.Fatalf("expected last status unhealthy, got %s", deps[0].LastStatus)
+	}
+	if deps[0].P50Latency != 55*time.Millisecond {
+		t.Fatalf("expected p50 55ms, got %v", deps[0].P50Latency)
+	}
+}
+
+func TestGormStorage_N1AndPoolStats(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+
+	if err := s.StoreN1Detection(N1Detection{Pattern: "select * from users where id = ?", Count: 10, DetectedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	detections, _ := s.GetN1Detections(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if len(detections) != 1 {
+		t.Fatalf("expected 1 detection, got %d", len(detections))
+	}
+
+	s.StorePoolStats(PoolStats{InUse: 1, Timestamp: now.Add(-2 * time.Hour)})
+	s.StorePoolStats(PoolStats{InUse: 5, Timestamp: now.Add(-time.Minute)})
+	s.StorePoolStats(PoolStats{InUse: 7, Timestamp: now})
+
+	stats, _ := s.GetConnectionPoolStats()
+	if stats == nil || stats.InUse != 7 {
+		t.Fatalf("expected latest pool stats, got %+v", stats)
+	}
+	history, _ := s.GetPoolStatsHistory(TimeRange{Start: now.Add(-time.Hour), End: now.Add(time.Minute)})
+	if len(history) != 2 || history[0].InUse != 5 || history[1].InUse != 7 {
+		t.Fatalf("expected 2 samples oldest first, got %+v", history)
+	}
+}
+
+func TestGormStorage_OverviewCleanupReset(t *testing.T) {
+	s := newTestGormStorage(t)
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	for i := 0; i < 10; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/users", StatusCode: 200 + (i%2)*300, Timestamp: now})
+	}
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/old", Timestamp: old})
+	s.StoreRuntime(RuntimeMetric{NumGoroutine: 42, Timestamp: now})
+	s.StoreAlert(AlertRecord{ID: "a1", State: AlertStateFiring, FiredAt: now})
+	s.StoreError(ErrorRecord{ID: "old", Fingerprint: "fp-old", Count: 1, FirstSeen: old, LastSeen: old})
+
+	overview, err := s.GetOverview(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overview.TotalRequests != 10 || overview.TotalErrors != 5 || overview.ActiveGoroutines != 42 || overview.ActiveAlerts != 1 {
+		t.Fatalf("unexpected overview: %+v", overview)
+	}
+
+	if err := s.Cleanup(24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	reqs, _ := s.GetRequests(RequestFilter{})
+	errors, _ := s.GetErrors(ErrorFilter{})
+	if len(reqs) != 10 || len(errors) != 0 {
+		t.Fatalf("expected old data removed, got %d requests and %d errors", len(reqs), len(errors))
+	}
+
+	if err := s.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	reqs, _ = s.GetRequests(RequestFilter{})
+	if len(reqs) != 0 {
+		t.Fatalf("expected 0 requests after reset, got %d", len(reqs))
+	}
+}
+
+func TestGormStorage_ExcludedFromPluginTracking(t *testing.T) {
+	db := openTestGormDB(t)
+	if err := db.AutoMigrate(&TestUser{}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := newPulse(applyDefaults(Config{}))
+	s, err := NewGormStorage(db, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.storage = s
+	t.Cleanup(func() { p.Shutdown() })
+
+	if err := db.Use(&PulsePlugin{pulse: p, n1Tracker: make(map[string]map[string]int)}); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Create(&TestUser{Name: "alice", Age: 30})
+	time.Sleep(100 * time.Millisecond)
+
+	// Reading the stored metrics issues more queries through the same DB;
+	// none of them may be recorded.
+	for i := 0; i < 3; i++ {
+		s.GetQueryPatterns(TimeRange{})
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	queries, _ := s.GetSlowQueries(0, 0)
+	if len(queries) != 1 {
+		t.Fatalf("expected only the application query to be tracked, got %d", len(queries))
+	}
+	if queries[0].Table != "test_users" {
+		t.Fatalf("expected query on test_users, got %q", queries[0].Table)
+	}
+}
+
+func TestMount_SelectsGormStorage(t *testing.T) {
+	_, p := setupTestRouter(Config{
+		Storage: StorageConfig{
+			Driver:    GORM,
+			Dialector: sqlite.Open(filepath.Join(t.TempDir(), "pulse.db")),
+		},
+	})
+	defer p.Shutdown()
+
+	if _, ok := p.GetStorage().(*GormStorage); !ok {
+		t.Fatalf("expected *GormStorage, got %T", p.GetStorage())
+	}
+}
+
+func TestGormStorage_StoreBatch(t *testing.T) {
+	s := newTestGormStorage(t)
+	if err := s.StoreBatch(testMetricBatch(250)); err != nil {
+		t.Fatalf("StoreBatch: %v", err)
+	}
+	assertBatchStored(t, s, 250)
+}
@@ -2,6 +2,7 @@ package pulse
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -13,6 +14,9 @@ const (
 	defaultRuntimeCapacity  = 10000
 	defaultHealthCapacity   = 1000
 	defaultDependencyCapacity = 50000
+	defaultPoolStatsCapacity  = 10000
+	defaultSpanCapacity       = 200000
+	defaultConnectionCapacity = 10000
 )
 
 // MemoryStorage is an in-memory Storage implementation backed by ring buffers.
@@ -38,9 +42,17 @@ type MemoryStorage struct {
 	n1Detections []N1Detection
 	n1Mu         sync.RWMutex
 
-	// Connection pool stats (updated periodically)
-	poolStats *PoolStats
-	poolMu    sync.RWMutex
+	// Connection pool samples (recorded periodically)
+	poolStats *RingBuffer[PoolStats]
+
+	// Trace spans
+	spans *RingBuffer[Span]
+
+	// Long-lived connections
+	connections *RingBuffer[ConnectionMetric]
+
+	// Downsampled tiers for ranges the ring buffers no longer cover
+	rollups *rollupStore
 
 	// Config
 	appName   string
@@ -54,10 +66,14 @@ func NewMemoryStorage(appName string) *MemoryStorage {
 		queries:       NewRingBuffer[QueryMetric](defaultQueryCapacity),
 		runtimeStats:  NewRingBuffer[RuntimeMetric](defaultRuntimeCapacity),
 		dependencies:  NewRingBuffer[DependencyMetric](defaultDependencyCapacity),
+		poolStats:     NewRingBuffer[PoolStats](defaultPoolStatsCapacity),
+		spans:         NewRingBuffer[Span](defaultSpanCapacity),
+		connections:   NewRingBuffer[ConnectionMetric](defaultConnectionCapacity),
 		errors:        make(map[string]*ErrorRecord),
 		healthResults: make(map[string]*RingBuffer[HealthCheckResult]),
 		alerts:        make([]AlertRecord, 0),
 		n1Detections:  make([]N1Detection, 0),
+		rollups:       newRollupStore(),
 		appName:       appName,
 		startTime:     time.Now(),
 	}
@@ -68,73 +84,27 @@ func NewMemoryStorage(appName string) *MemoryStorage {
 // StoreRequest stores a request metric.
 func (s *MemoryStorage) StoreRequest(m RequestMetric) error {
 	s.requests.Push(m)
+	s.rollups.addRequest(m)
 	return nil
 }
 
 // GetRequests returns requests matching the filter.
 func (s *MemoryStorage) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
 	all := s.requests.Filter(func(m RequestMetric) bool {
-		if !filter.TimeRange.Start.IsZero() && m.Timestamp.Before(filter.TimeRange.Start) {
-			return false
-		}
-		if !filter.TimeRange.End.IsZero() && m.Timestamp.After(filter.TimeRange.End) {
-			return false
-		}
-		if filter.Method != "" && m.Method != filter.Method {
-			return false
-		}
-		if filter.Path != "" && m.Path != filter.Path {
-			return false
-		}
-		if filter.StatusCode != 0 && m.StatusCode != filter.StatusCode {
-			return false
-		}
-		if filter.MinLatency > 0 && m.Latency < filter.MinLatency {
-			return false
-		}
-		return true
+		return matchesRequestFilter(m, filter)
 	})
-
-	// Apply offset and limit
-	if filter.Offset > 0 && filter.Offset < len(all) {
-		all = all[filter.Offset:]
-	} else if filter.Offset >= len(all) {
-		return nil, nil
-	}
-	if filter.Limit > 0 && filter.Limit < len(all) {
-		all = all[:filter.Limit]
-	}
-
-	return all, nil
+	return paginate(all, filter.Offset, filter.Limit), nil
 }
 
 // GetRouteStats returns aggregated stats per route within the time range.
 func (s *MemoryStorage) GetRouteStats(timeRange TimeRange) ([]RouteStats, error) {
-	// Group requests by method+path
-	type routeKey struct{ method, path string }
-	groups := make(map[routeKey][]RequestMetric)
-
-	s.requests.ForEach(func(m RequestMetric) bool {
-		if m.Timestamp.Before(timeRange.Start) || m.Timestamp.After(timeRange.End) {
-			return true
-		}
-		key := routeKey{m.Method, m.Path}
-		groups[key] = append(groups[key], m)
-		return true
-	})
-
-	duration := timeRange.End.Sub(timeRange.Start)
-	stats := make([]RouteStats, 0, len(groups))
-	for key, reqs := range groups {
-		stats = append(stats, computeRouteStats(key.method, key.path, reqs, duration))
+	if tier := s.requestRollupTier(timeRange); tier != nil {
+		return tier.routeStats(timeRange), nil
 	}
-
-	// Sort by request count descending
-	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].RequestCount > stats[j].RequestCount
+	reqs := s.requests.Filter(func(m RequestMetric) bool {
+		return !m.Timestamp.Before(timeRange.Start) && !m.Timestamp.After(timeRange.End)
 	})
-
-	return stats, nil
+	return buildRouteStats(reqs, timeRange.End.Sub(timeRange.Start)), nil
 }
 
 // GetRouteDetail returns detailed stats for a specific route.
@@ -177,6 +147,7 @@ func (s *MemoryStorage) GetRouteDetail(method, path string, timeRange TimeRange)
 		RecentErrors:   errors,
 		TopQueries:     patterns,
 	}
+	addPhaseStats(detail, reqs)
 
 	return detail, nil
 }
@@ -186,6 +157,7 @@ func (s *MemoryStorage) GetRouteDetail(method, path string, timeRange TimeRange)
 // StoreQuery stores a query metric.
 func (s *MemoryStorage) StoreQuery(m QueryMetric) error {
 	s.queries.Push(m)
+	s.rollups.addQuery(m)
 	return nil
 }
 
@@ -209,67 +181,13 @@ func (s *MemoryStorage) GetSlowQueries(threshold time.Duration, limit int) ([]Qu
 
 // GetQueryPatterns returns aggregated query patterns.
 func (s *MemoryStorage) GetQueryPatterns(timeRange TimeRange) ([]QueryPattern, error) {
-	type patternAgg struct {
-		normalized string
-		operation  string
-		table      string
-		durations  []time.Duration
-		errCount   int64
-	}
-
-	patterns := make(map[string]*patternAgg)
-
-	s.queries.ForEach(func(m QueryMetric) bool {
-		if m.Timestamp.Before(timeRange.Start) || m.Timestamp.After(timeRange.End) {
-			return true
-		}
-		key := m.NormalizedSQL
-		if key == "" {
-			key = m.SQL
-		}
-		p, ok := patterns[key]
-		if !ok {
-			p = &patternAgg{
-				normalized: key,
-				operation:  m.Operation,
-				table:      m.Table,
-			}
-			patterns[key] = p
-		}
-		p.durations = append(p.durations, m.Duration)
-		if m.Error != "" {
-			p.errCount++
-		}
-		return true
-	})
-
-	result := make([]QueryPattern, 0, len(patterns))
-	for _, p := range patterns {
-		var total, max time.Duration
-		for _, d := range p.durations {
-			total += d
-			if d > max {
-				max = d
-			}
-		}
-		avg := total / time.Duration(len(p.durations))
-		result = append(result, QueryPattern{
-			NormalizedSQL: p.normalized,
-			Operation:     p.operation,
-			Table:         p.table,
-			Count:         int64(len(p.durations)),
-			AvgDuration:   avg,
-			MaxDuration:   max,
-			TotalDuration: total,
-			ErrorCount:    p.errCount,
-		})
+	if tier := rollupTierFor(s.rollups, s.queries, func(m QueryMetric) time.Time { return m.Timestamp }, timeRange); tier != nil {
+		return tier.queryPatterns(timeRange), nil
 	}
-
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].TotalDuration > result[j].TotalDuration
+	queries := s.queries.Filter(func(m QueryMetric) bool {
+		return !m.Timestamp.Before(timeRange.Start) && !m.Timestamp.After(timeRange.End)
 	})
-
-	return result, nil
+	return buildQueryPatterns(queries), nil
 }
 
 // GetN1Detections returns detected N+1 query issues.
@@ -286,8 +204,8 @@ func (s *MemoryStorage) GetN1Detections(timeRange TimeRange) ([]N1Detection, err
 	return result, nil
 }
 
-// StoreN1Detection stores an N+1 detection. (Not part of Storage interface, used internally.)
-func (s *MemoryStorage) StoreN1Detection(d N1Detection) {
+// StoreN1Detection stores an N+1 detection.
+func (s *MemoryStorage) StoreN1Detection(d N1Detection) error {
 	s.n1Mu.Lock()
 	defer s.n1Mu.Unlock()
 	s.n1Detections = append(s.n1Detections, d)
@@ -295,24 +213,41 @@ func (s *MemoryStorage) StoreN1Detection(d N1Detection) {
 	if len(s.n1Detections) > 1000 {
 		s.n1Detections = s.n1Detections[len(s.n1Detections)-1000:]
 	}
+	return nil
+}
+
+// --- Connection Pool ---
+
+// StorePoolStats records a connection pool stats sample.
+func (s *MemoryStorage) StorePoolStats(stats PoolStats) error {
+	if stats.Timestamp.IsZero() {
+		stats.Timestamp = time.Now()
+	}
+	s.poolStats.Push(stats)
+	return nil
 }
 
 // GetConnectionPoolStats returns the latest connection pool stats.
 func (s *MemoryStorage) GetConnectionPoolStats() (*PoolStats, error) {
-	s.poolMu.RLock()
-	defer s.poolMu.RUnlock()
-	if s.poolStats == nil {
+	latest := s.poolStats.GetLast(1)
+	if len(latest) == 0 {
 		return nil, nil
 	}
-	cp := *s.poolStats
-	return &cp, nil
+	return &latest[0], nil
 }
 
-// UpdatePoolStats updates the connection pool stats. (Not part of Storage interface.)
+// UpdatePoolStats records a connection pool stats sample, ignoring errors.
+//
+// Deprecated: Use StorePoolStats.
 func (s *MemoryStorage) UpdatePoolStats(stats PoolStats) {
-	s.poolMu.Lock()
-	defer s.poolMu.Unlock()
-	s.poolStats = &stats
+	_ = s.StorePoolStats(stats)
+}
+
+// GetPoolStatsHistory returns pool stats samples within the time range, oldest first.
+func (s *MemoryStorage) GetPoolStatsHistory(timeRange TimeRange) ([]PoolStats, error) {
+	return s.poolStats.Filter(func(m PoolStats) bool {
+		return !m.Timestamp.Before(timeRange.Start) && !m.Timestamp.After(timeRange.End)
+	}), nil
 }
 
 // --- Runtime Metrics ---
@@ -320,6 +255,7 @@ func (s *MemoryStorage) UpdatePoolStats(stats PoolStats) {
 // StoreRuntime stores a runtime metric snapshot.
 func (s *MemoryStorage) StoreRuntime(m RuntimeMetric) error {
 	s.runtimeStats.Push(m)
+	s.rollups.addRuntime(m)
 	return nil
 }
 
@@ -348,6 +284,9 @@ func (s *MemoryStorage) StoreError(e ErrorRecord) error {
 		if e.RequestContext != nil {
 			existing.RequestContext = e.RequestContext
 		}
+		existing.Instances = mergeInstances(existing.Instances, e.Instances)
+		existing.Tags = mergeTagValues(existing.Tags, e.Tags)
+		existing.TraceIDs = mergeTraceIDs(existing.TraceIDs, e.TraceIDs)
 	} else {
 		cp := e
 		s.errors[e.Fingerprint] = &cp
@@ -360,44 +299,11 @@ func (s *MemoryStorage) GetErrors(filter ErrorFilter) ([]ErrorRecord, error) {
 	s.errorsMu.RLock()
 	defer s.errorsMu.RUnlock()
 
-	var result []ErrorRecord
+	all := make([]ErrorRecord, 0, len(s.errors))
 	for _, e := range s.errors {
-		if !filter.TimeRange.Start.IsZero() && e.LastSeen.Before(filter.TimeRange.Start) {
-			continue
-		}
-		if !filter.TimeRange.End.IsZero() && e.FirstSeen.After(filter.TimeRange.End) {
-			continue
-		}
-		if filter.ErrorType != "" && e.ErrorType != filter.ErrorType {
-			continue
-		}
-		if filter.Route != "" && e.Route != filter.Route {
-			continue
-		}
-		if filter.Muted != nil && e.Muted != *filter.Muted {
-			continue
-		}
-		if filter.Resolved != nil && e.Resolved != *filter.Resolved {
-			continue
-		}
-		result = append(result, *e)
-	}
-
-	// Sort by last seen descending
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].LastSeen.After(result[j].LastSeen)
-	})
-
-	if filter.Offset > 0 && filter.Offset < len(result) {
-		result = result[filter.Offset:]
-	} else if filter.Offset >= len(result) && filter.Offset > 0 {
-		return nil, nil
-	}
-	if filter.Limit > 0 && filter.Limit < len(result) {
-		result = result[:filter.Limit]
+		all = append(all, *e)
 	}
-
-	return result, nil
+	return filterErrorRecords(all, filter), nil
 }
 
 // GetErrorGroups returns error groups for the dashboard.
@@ -405,30 +311,11 @@ func (s *MemoryStorage) GetErrorGroups(timeRange TimeRange) ([]ErrorGroup, error
 	s.errorsMu.RLock()
 	defer s.errorsMu.RUnlock()
 
-	var groups []ErrorGroup
+	all := make([]ErrorRecord, 0, len(s.errors))
 	for _, e := range s.errors {
-		if e.LastSeen.Before(timeRange.Start) || e.FirstSeen.After(timeRange.End) {
-			continue
-		}
-		groups = append(groups, ErrorGroup{
-			Fingerprint:  e.Fingerprint,
-			ErrorMessage: e.ErrorMessage,
-			ErrorType:    e.ErrorType,
-			Route:        e.Route,
-			Method:       e.Method,
-			Count:        e.Count,
-			FirstSeen:    e.FirstSeen,
-			LastSeen:     e.LastSeen,
-			Muted:        e.Muted,
-			Resolved:     e.Resolved,
-		})
+		all = append(all, *e)
 	}
-
-	sort.Slice(groups, func(i, j int) bool {
-		return groups[i].Count > groups[j].Count
-	})
-
-	return groups, nil
+	return buildErrorGroups(all, timeRange), nil
 }
 
 // UpdateError updates specific fields on an error record.
@@ -507,33 +394,7 @@ func (s *MemoryStorage) StoreAlert(a AlertRecord) error {
 func (s *MemoryStorage) GetAlerts(filter AlertFilter) ([]AlertRecord, error) {
 	s.alertsMu.RLock()
 	defer s.alertsMu.RUnlock()
-
-	var result []AlertRecord
-	for _, a := range s.alerts {
-		if !filter.TimeRange.Start.IsZero() && a.FiredAt.Before(filter.TimeRange.Start) {
-			continue
-		}
-		if !filter.TimeRange.End.IsZero() && a.FiredAt.After(filter.TimeRange.End) {
-			continue
-		}
-		if filter.State != "" && a.State != filter.State {
-			continue
-		}
-		if filter.Severity != "" && a.Severity != filter.Severity {
-			continue
-		}
-		result = append(result, a)
-	}
-
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].FiredAt.After(result[j].FiredAt)
-	})
-
-	if filter.Limit > 0 && filter.Limit < len(result) {
-		result = result[:filter.Limit]
-	}
-
-	return result, nil
+	return filterAlertRecords(s.alerts, filter), nil
 }
 
 // --- Dependencies ---
@@ -546,187 +407,146 @@ func (s *MemoryStorage) StoreDependencyMetric(m DependencyMetric) error {
 
 // GetDependencyStats returns aggregated stats per dependency.
 func (s *MemoryStorage) GetDependencyStats(timeRange TimeRange) ([]DependencyStats, error) {
-	type depAgg struct {
-		latencies []time.Duration
-		errCount  int64
-		total     int64
-		lastTS    time.Time
-		lastCode  int
-	}
+	deps := s.dependencies.Filter(func(m DependencyMetric) bool {
+		return !m.Timestamp.Before(timeRange.Start) && !m.Timestamp.After(timeRange.End)
+	})
+	return buildDependencyStats(deps, timeRange.End.Sub(timeRange.Start)), nil
+}
 
-	groups := make(map[string]*depAgg)
+// --- Overview ---
 
-	s.dependencies.ForEach(func(m DependencyMetric) bool {
-		if m.Timestamp.Before(timeRange.Start) || m.Timestamp.After(timeRange.End) {
-			return true
-		}
-		d, ok := groups[m.Name]
-		if !ok {
-			d = &depAgg{}
-			groups[m.Name] = d
-		}
-		d.latencies = append(d.latencies, m.Latency)
-		d.total++
-		if m.Error != "" || m.StatusCode >= 500 {
-			d.errCount++
-		}
-		if m.Timestamp.After(d.lastTS) {
-			d.lastTS = m.Timestamp
-			d.lastCode = m.StatusCode
-		}
-		return true
+// GetOverview computes the top-level dashboard snapshot.
+func (s *MemoryStorage) GetOverview(timeRange TimeRange) (*Overview, error) {
+	reqs := s.requests.Filter(func(m RequestMetric) bool {
+		return !m.Timestamp.Before(timeRange.Start) && !m.Timestamp.After(timeRange.End)
 	})
 
-	duration := timeRange.End.Sub(timeRange.Start)
-	result := make([]DependencyStats, 0, len(groups))
-	for name, d := range groups {
-		sort.Slice(d.latencies, func(i, j int) bool { return d.latencies[i] < d.latencies[j] })
+	// Latest runtime snapshot
+	var latest *RuntimeMetric
+	if runtimeHistory := s.runtimeStats.GetLast(1); len(runtimeHistory) > 0 {
+		latest = &runtimeHistory[0]
+	}
 
-		errRate := float64(0)
-		if d.total > 0 {
-			errRate = float64(d.errCount) / float64(d.total) * 100
+	// Active alerts count
+	activeAlerts := 0
+	s.alertsMu.RLock()
+	for _, a := range s.alerts {
+		if a.State == AlertStateFiring {
+			activeAlerts++
 		}
+	}
+	s.alertsMu.RUnlock()
 
-		lastStatus := "healthy"
-		if d.lastCode >= 500 {
-			lastStatus = "unhealthy"
-		}
+	// Recent errors
+	recentErrors, _ := s.GetErrors(ErrorFilter{
+		TimeRange: timeRange,
+		Limit:     5,
+	})
 
-		rpm := float64(0)
-		if duration.Minutes() > 0 {
-			rpm = float64(d.total) / duration.Minutes()
-		}
+	return buildOverview(s.appName, time.Since(s.startTime), timeRange, reqs, latest, activeAlerts, recentErrors), nil
+}
 
-		result = append(result, DependencyStats{
-			Name:         name,
-			RequestCount: d.total,
-			ErrorCount:   d.errCount,
-			ErrorRate:    errRate,
-			AvgLatency:   ComputeAvg(d.latencies),
-			P50Latency:   Percentile(d.latencies, 50),
-			P95Latency:   Percentile(d.latencies, 95),
-			P99Latency:   Percentile(d.latencies, 99),
-			RPM:          rpm,
-			Availability: 100 - errRate,
-			LastStatus:   lastStatus,
-			LastChecked:  d.lastTS,
-		})
-	}
+// --- Traces ---
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].RequestCount > result[j].RequestCount
-	})
-
-	return result, nil
+// StoreSpans stores trace spans.
+func (s *MemoryStorage) StoreSpans(spans []Span) error {
+	for _, span := range spans {
+		s.spans.Push(span)
+	}
+	return nil
 }
 
-// --- Overview ---
-
-// GetOverview computes the top-level dashboard snapshot.
-func (s *MemoryStorage) GetOverview(timeRange TimeRange) (*Overview, error) {
-	var totalReqs, totalErrs int64
-	var latencies []time.Duration
+// GetTrace returns every stored span of the trace, ordered by start time.
+func (s *MemoryStorage) GetTrace(traceID string) ([]Span, error) {
+	spans := s.spans.Filter(func(span Span) bool { return span.TraceID == traceID })
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+	return spans, nil
+}
 
-	s.requests.ForEach(func(m RequestMetric) bool {
-		if m.Timestamp.Before(timeRange.Start) || m.Timestamp.After(timeRange.End) {
-			return true
-		}
-		totalReqs++
-		latencies = append(latencies, m.Latency)
-		if m.StatusCode >= 400 {
-			totalErrs++
-		}
-		return true
+// GetSpanStats returns latency stats per span name within the time range.
+func (s *MemoryStorage) GetSpanStats(timeRange TimeRange) ([]SpanStats, error) {
+	spans := s.spans.Filter(func(span Span) bool {
+		return !span.StartTime.Before(timeRange.Start) && !span.StartTime.After(timeRange.End)
 	})
+	return buildSpanStats(spans), nil
+}
 
-	var errRate float64
-	if totalReqs > 0 {
-		errRate = float64(totalErrs) / float64(totalReqs) * 100
-	}
-
-	duration := timeRange.End.Sub(timeRange.Start)
-	rpm := float64(0)
-	if duration.Minutes() > 0 {
-		rpm = float64(totalReqs) / duration.Minutes()
-	}
-
-	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
-	p95 := Percentile(latencies, 95)
+// --- Connections ---
 
-	// Latest runtime snapshot
-	var goroutines int
-	var heapMB float64
-	runtimeHistory := s.runtimeStats.GetLast(1)
-	if len(runtimeHistory) > 0 {
-		goroutines = runtimeHistory[0].NumGoroutine
-		heapMB = float64(runtimeHistory[0].HeapAlloc) / (1024 * 1024)
+// StoreConnections stores long-lived connections.
+func (s *MemoryStorage) StoreConnections(conns []ConnectionMetric) error {
+	for _, c := range conns {
+		s.connections.Push(c)
 	}
+	return nil
+}
 
-	// Active alerts count
-	activeAlerts := 0
-	s.alertsMu.RLock()
-	for _, a := range s.alerts {
-		if a.State == AlertStateFiring {
-			activeAlerts++
-		}
-	}
-	s.alertsMu.RUnlock()
-
-	// Top routes
-	topRoutes, _ := s.GetRouteStats(timeRange)
-	if len(topRoutes) > 10 {
-		topRoutes = topRoutes[:10]
-	}
-
-	// Recent errors
-	recentErrors, _ := s.GetErrors(ErrorFilter{
-		TimeRange: timeRange,
-		Limit:     5,
-	})
-
-	uptime := time.Since(s.startTime)
-
-	return &Overview{
-		AppName:          s.appName,
-		Uptime:           formatDuration(uptime),
-		TotalRequests:    totalReqs,
-		TotalErrors:      totalErrs,
-		ErrorRate:        errRate,
-		AvgLatency:       ComputeAvg(latencies),
-		P95Latency:       p95,
-		RPM:              rpm,
-		ActiveGoroutines: goroutines,
-		HeapAllocMB:      heapMB,
-		ActiveAlerts:     activeAlerts,
-		TopRoutes:        topRoutes,
-		RecentErrors:     recentErrors,
-		Timestamp:        time.Now(),
-	}, nil
+// GetConnections returns the connections opened within the time range.
+func (s *MemoryStorage) GetConnections(timeRange TimeRange) ([]ConnectionMetric, error) {
+	return s.connections.Filter(func(c ConnectionMetric) bool {
+		return !c.Timestamp.Before(timeRange.Start) && !c.Timestamp.After(timeRange.End)
+	}), nil
 }
 
 // --- Maintenance ---
 
 // Cleanup removes data older than the retention period.
 func (s *MemoryStorage) Cleanup(retention time.Duration) error {
-	cutoff := time.Now().Add(-retention)
+	_, err := s.Prune(uniformRetention(retention))
+	return err
+}
+
+// Prune removes data older than each data type's retention period and
+// reports how many records were removed.
+func (s *MemoryStorage) Prune(retention RetentionConfig) (PruneResult, error) {
+	now := time.Now()
+	var result PruneResult
+
+	// Ring buffers are trimmed from the oldest end
+	requestCutoff := now.Add(-retention.Requests)
+	result.Requests = int64(s.requests.Prune(func(m RequestMetric) bool { return m.Timestamp.Before(requestCutoff) }))
+	result.Spans = int64(s.spans.Prune(func(span Span) bool { return span.StartTime.Before(requestCutoff) }))
+	result.Connections = int64(s.connections.Prune(func(c ConnectionMetric) bool { return c.Timestamp.Before(requestCutoff) }))
+	queryCutoff := now.Add(-retention.Queries)
+	result.Queries = int64(s.queries.Prune(func(m QueryMetric) bool { return m.Timestamp.Before(queryCutoff) }))
+	runtimeCutoff := now.Add(-retention.Runtime)
+	result.Runtime = int64(s.runtimeStats.Prune(func(m RuntimeMetric) bool { return m.Timestamp.Before(runtimeCutoff) }))
+	result.PoolStats = int64(s.poolStats.Prune(func(m PoolStats) bool { return m.Timestamp.Before(runtimeCutoff) }))
+	depCutoff := now.Add(-retention.Dependencies)
+	result.Dependencies = int64(s.dependencies.Prune(func(m DependencyMetric) bool { return m.Timestamp.Before(depCutoff) }))
+
+	// Clean health results
+	healthCutoff := now.Add(-retention.Health)
+	s.healthMu.Lock()
+	for name, buf := range s.healthResults {
+		result.Health += int64(buf.Prune(func(r HealthCheckResult) bool { return r.Timestamp.Before(healthCutoff) }))
+		if buf.Len() == 0 {
+			delete(s.healthResults, name)
+		}
+	}
+	s.healthMu.Unlock()
 
 	// Clean errors
+	errorCutoff := now.Add(-retention.Errors)
 	s.errorsMu.Lock()
 	for fp, e := range s.errors {
-		if e.LastSeen.Before(cutoff) {
+		if e.LastSeen.Before(errorCutoff) {
 			delete(s.errors, fp)
+			result.Errors++
 		}
 	}
 	s.errorsMu.Unlock()
 
 	// Clean alerts
+	alertCutoff := now.Add(-retention.Alerts)
 	s.alertsMu.Lock()
 	filtered := s.alerts[:0]
 	for _, a := range s.alerts {
-		if !a.FiredAt.Before(cutoff) {
+		if !a.FiredAt.Before(alertCutoff) {
 			filtered = append(filtered, a)
 		}
 	}
+	result.Alerts = int64(len(s.alerts) - len(filtered))
 	s.alerts = filtered
 	s.alertsMu.Unlock()
 
@@ -734,17 +554,61 @@ func (s *MemoryStorage) Cleanup(retention time.Duration) error {
 	s.n1Mu.Lock()
 	filteredN1 := s.n1Detections[:0]
 	for _, d := range s.n1Detections {
-		if !d.DetectedAt.Before(cutoff) {
+		if !d.DetectedAt.Before(queryCutoff) {
 			filteredN1 = append(filteredN1, d)
 		}
 	}
+	result.N1Detections = int64(len(s.n1Detections) - len(filteredN1))
 	s.n1Detections = filteredN1
 	s.n1Mu.Unlock()
 
-	// Ring buffers handle their own capacity limits; they don't need explicit cleanup
-	// since old entries are naturally overwritten.
+	return result, nil
+}
+
+// DeleteSubject deletes the records of one data subject.
+func (s *MemoryStorage) DeleteSubject(filter SubjectFilter) (PruneResult, error) {
+	var result PruneResult
+	if filter.IsZero() {
+		return result, nil
+	}
 
-	return nil
+	traces := newSubjectTraces(filter)
+	result.Requests = int64(s.requests.Remove(func(m RequestMetric) bool {
+		if filter.matchRequest(m) {
+			traces.add(m.TraceID)
+			return true
+		}
+		return false
+	}))
+	result.Connections = int64(s.connections.Remove(func(c ConnectionMetric) bool {
+		if filter.matchConnection(c) {
+			traces.add(c.TraceID)
+			return true
+		}
+		return false
+	}))
+	if len(traces) > 0 {
+		result.Spans = int64(s.spans.Remove(func(span Span) bool { return traces[span.TraceID] }))
+		result.Queries = int64(s.queries.Remove(func(m QueryMetric) bool { return traces[m.RequestTraceID] }))
+		result.Dependencies = int64(s.dependencies.Remove(func(m DependencyMetric) bool { return traces[m.TraceID] }))
+	}
+
+	s.errorsMu.Lock()
+	for fp, e := range s.errors {
+		erased, matched, empty := filter.eraseError(*e, traces)
+		switch {
+		case !matched:
+			continue
+		case empty:
+			delete(s.errors, fp)
+		default:
+			s.errors[fp] = &erased
+		}
+		result.Errors++
+	}
+	s.errorsMu.Unlock()
+
+	return result, nil
 }
 
 // Reset clears all stored data.
@@ -770,9 +634,11 @@ func (s *MemoryStorage) Reset() error {
 	s.n1Detections = s.n1Detections[:0]
 	s.n1Mu.Unlock()
 
-	s.poolMu.Lock()
-	s.poolStats = nil
-	s.poolMu.Unlock()
+	s.poolStats.Reset()
+	s.spans.Reset()
+	s.connections.Reset()
+
+	s.rollups.reset()
 
 	return nil
 }
@@ -782,39 +648,63 @@ func (s *MemoryStorage) Close() error {
 	return nil
 }
 
+// --- Rollups ---
+
+// requestRollupTier returns the rollup tier that serves request reads over tr,
+// or nil while the request ring buffer still covers it.
+func (s *MemoryStorage) requestRollupTier(tr TimeRange) *rollupTier {
+	return rollupTierFor(s.rollups, s.requests, func(m RequestMetric) time.Time { return m.Timestamp }, tr)
+}
+
+// runtimeRollupTier returns the rollup tier that serves runtime reads over tr,
+// or nil while the runtime ring buffer still covers it.
+func (s *MemoryStorage) runtimeRollupTier(tr TimeRange) *rollupTier {
+	return rollupTierFor(s.rollups, s.runtimeStats, func(m RuntimeMetric) time.Time { return m.Timestamp }, tr)
+}
+
 // --- Helpers ---
 
+// computeRouteStats computes the stats of one route. Counts are
+// extrapolated from sampled requests; latencies cover recorded requests.
 func computeRouteStats(method, path string, reqs []RequestMetric, duration time.Duration) RouteStats {
-	var errCount int64
+	var count, errCount float64
+	var shares timeShares
 	latencies := make([]time.Duration, len(reqs))
-	statusCodes := make(map[int]int64)
+	codes := make(map[int]float64)
 
 	for i, r := range reqs {
+		w := r.weight()
 		latencies[i] = r.Latency
-		statusCodes[r.StatusCode]++
+		shares.add(r)
+		count += w
+		codes[r.StatusCode] += w
 		if r.StatusCode >= 400 {
-			errCount++
+			errCount += w
 		}
 	}
 
-	count := int64(len(reqs))
 	errRate := float64(0)
 	if count > 0 {
-		errRate = float64(errCount) / float64(count) * 100
+		errRate = errCount / count * 100
 	}
 
 	rpm := float64(0)
 	if duration.Minutes() > 0 {
-		rpm = float64(count) / duration.Minutes()
+		rpm = count / duration.Minutes()
+	}
+
+	statusCodes := make(map[int]int64, len(codes))
+	for code, n := range codes {
+		statusCodes[code] = int64(math.Round(n))
 	}
 
 	p50, p75, p90, p95, p99 := ComputePercentiles(latencies)
 
-	return RouteStats{
+	rs := RouteStats{
 		Method:       method,
 		Path:         path,
-		RequestCount: count,
-		ErrorCount:   errCount,
+		RequestCount: int64(math.Round(count)),
+		ErrorCount:   int64(math.Round(errCount)),
 		ErrorRate:    errRate,
 		AvgLatency:   ComputeAvg(latencies),
 		MinLatency:   ComputeMin(latencies),
@@ -828,6 +718,390 @@ func computeRouteStats(method, path string, reqs []RequestMetric, duration time.
 		StatusCodes:  statusCodes,
 		Trend:        "stable",
 	}
+	shares.apply(&rs)
+	return rs
+}
+
+// buildRouteStats groups requests by method+path and computes per-route stats,
+// sorted by request count descending.
+func buildRouteStats(reqs []RequestMetric, duration time.Duration) []RouteStats {
+	type routeKey struct{ method, path string }
+	groups := make(map[routeKey][]RequestMetric)
+	for _, m := range reqs {
+		key := routeKey{m.Method, m.Path}
+		groups[key] = append(groups[key], m)
+	}
+
+	stats := make([]RouteStats, 0, len(groups))
+	for key, group := range groups {
+		stats = append(stats, computeRouteStats(key.method, key.path, group, duration))
+	}
+
+	// Sort by request count descending
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].RequestCount > stats[j].RequestCount
+	})
+
+	return stats
+}
+
+// buildQueryPatterns groups queries by normalized SQL, sorted by total duration descending.
+func buildQueryPatterns(queries []QueryMetric) []QueryPattern {
+	type patternAgg struct {
+		normalized string
+		operation  string
+		table      string
+		durations  []time.Duration
+		errCount   int64
+	}
+
+	patterns := make(map[string]*patternAgg)
+	for _, m := range queries {
+		key := m.NormalizedSQL
+		if key == "" {
+			key = m.SQL
+		}
+		p, ok := patterns[key]
+		if !ok {
+			p = &patternAgg{
+				normalized: key,
+				operation:  m.Operation,
+				table:      m.Table,
+			}
+			patterns[key] = p
+		}
+		p.durations = append(p.durations, m.Duration)
+		if m.Error != "" {
+			p.errCount++
+		}
+	}
+
+	result := make([]QueryPattern, 0, len(patterns))
+	for _, p := range patterns {
+		var total, max time.Duration
+		for _, d := range p.durations {
+			total += d
+			if d > max {
+				max = d
+			}
+		}
+		avg := total / time.Duration(len(p.durations))
+		result = append(result, QueryPattern{
+			NormalizedSQL: p.normalized,
+			Operation:     p.operation,
+			Table:         p.table,
+			Count:         int64(len(p.durations)),
+			AvgDuration:   avg,
+			MaxDuration:   max,
+			TotalDuration: total,
+			ErrorCount:    p.errCount,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalDuration > result[j].TotalDuration
+	})
+
+	return result
+}
+
+// buildDependencyStats groups dependency calls by name, sorted by request count descending.
+func buildDependencyStats(deps []DependencyMetric, duration time.Duration) []DependencyStats {
+	type depAgg struct {
+		latencies []time.Duration
+		errCount  int64
+		total     int64
+		lastTS    time.Time
+		lastCode  int
+	}
+
+	groups := make(map[string]*depAgg)
+	for _, m := range deps {
+		d, ok := groups[m.Name]
+		if !ok {
+			d = &depAgg{}
+			groups[m.Name] = d
+		}
+		d.latencies = append(d.latencies, m.Latency)
+		d.total++
+		if m.Error != "" || m.StatusCode >= 500 {
+			d.errCount++
+		}
+		if m.Timestamp.After(d.lastTS) {
+			d.lastTS = m.Timestamp
+			d.lastCode = m.StatusCode
+		}
+	}
+
+	result := make([]DependencyStats, 0, len(groups))
+	for name, d := range groups {
+		sort.Slice(d.latencies, func(i, j int) bool { return d.latencies[i] < d.latencies[j] })
+
+		errRate := float64(0)
+		if d.total > 0 {
+			errRate = float64(d.errCount) / float64(d.total) * 100
+		}
+
+		lastStatus := "healthy"
+		if d.lastCode >= 500 {
+			lastStatus = "unhealthy"
+		}
+
+		rpm := float64(0)
+		if duration.Minutes() > 0 {
+			rpm = float64(d.total) / duration.Minutes()
+		}
+
+		result = append(result, DependencyStats{
+			Name:         name,
+			RequestCount: d.total,
+			ErrorCount:   d.errCount,
+			ErrorRate:    errRate,
+			AvgLatency:   ComputeAvg(d.latencies),
+			P50Latency:   Percentile(d.latencies, 50),
+			P95Latency:   Percentile(d.latencies, 95),
+			P99Latency:   Percentile(d.latencies, 99),
+			RPM:          rpm,
+			Availability: 100 - errRate,
+			LastStatus:   lastStatus,
+			LastChecked:  d.lastTS,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RequestCount > result[j].RequestCount
+	})
+
+	return result
+}
+
+// matchesRequestFilter reports whether a request satisfies every predicate in the filter.
+func matchesRequestFilter(m RequestMetric, filter RequestFilter) bool {
+	if !filter.TimeRange.Start.IsZero() && m.Timestamp.Before(filter.TimeRange.Start) {
+		return false
+	}
+	if !filter.TimeRange.End.IsZero() && m.Timestamp.After(filter.TimeRange.End) {
+		return false
+	}
+	if filter.Method != "" && m.Method != filter.Method {
+		return false
+	}
+	if filter.Path != "" && m.Path != filter.Path {
+		return false
+	}
+	if filter.StatusCode != 0 && m.StatusCode != filter.StatusCode {
+		return false
+	}
+	if filter.MinLatency > 0 && m.Latency < filter.MinLatency {
+		return false
+	}
+	if filter.Instance != "" && m.Instance != filter.Instance {
+		return false
+	}
+	if !matchTags(m.Tags, filter.Tags) {
+		return false
+	}
+	if filter.TraceID != "" && m.TraceID != filter.TraceID {
+		return false
+	}
+	return true
+}
+
+// filterErrorRecords applies an ErrorFilter to a set of error records, sorting
+// by last seen descending before applying offset and limit.
+func filterErrorRecords(records []ErrorRecord, filter ErrorFilter) []ErrorRecord {
+	var result []ErrorRecord
+	for _, e := range records {
+		if !filter.TimeRange.Start.IsZero() && e.LastSeen.Before(filter.TimeRange.Start) {
+			continue
+		}
+		if !filter.TimeRange.End.IsZero() && e.FirstSeen.After(filter.TimeRange.End) {
+			continue
+		}
+		if filter.ErrorType != "" && e.ErrorType != filter.ErrorType {
+			continue
+		}
+		if filter.Route != "" && e.Route != filter.Route {
+			continue
+		}
+		if filter.Muted != nil && e.Muted != *filter.Muted {
+			continue
+		}
+		if filter.Resolved != nil && e.Resolved != *filter.Resolved {
+			continue
+		}
+		if filter.Instance != "" && !containsString(e.Instances, filter.Instance) {
+			continue
+		}
+		if !matchTagValues(e.Tags, filter.Tags) {
+			continue
+		}
+		result = append(result, e)
+	}
+
+	// Sort by last seen descending
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+
+	return paginate(result, filter.Offset, filter.Limit)
+}
+
+// buildErrorGroups converts error records overlapping the time range into
+// error groups, sorted by count descending.
+func buildErrorGroups(records []ErrorRecord, timeRange TimeRange) []ErrorGroup {
+	var groups []ErrorGroup
+	for _, e := range records {
+		if e.LastSeen.Before(timeRange.Start) || e.FirstSeen.After(timeRange.End) {
+			continue
+		}
+		groups = append(groups, ErrorGroup{
+			Fingerprint:  e.Fingerprint,
+			ErrorMessage: e.ErrorMessage,
+			ErrorType:    e.ErrorType,
+			Route:        e.Route,
+			Method:       e.Method,
+			Count:        e.Count,
+			FirstSeen:    e.FirstSeen,
+			LastSeen:     e.LastSeen,
+			Muted:        e.Muted,
+			Resolved:     e.Resolved,
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+
+	return groups
+}
+
+// filterAlertRecords applies an AlertFilter, sorting by fired time descending.
+func filterAlertRecords(alerts []AlertRecord, filter AlertFilter) []AlertRecord {
+	var result []AlertRecord
+	for _, a := range alerts {
+		if !filter.TimeRange.Start.IsZero() && a.FiredAt.Before(filter.TimeRange.Start) {
+			continue
+		}
+		if !filter.TimeRange.End.IsZero() && a.FiredAt.After(filter.TimeRange.End) {
+			continue
+		}
+		if filter.State != "" && a.State != filter.State {
+			continue
+		}
+		if filter.Severity != "" && a.Severity != filter.Severity {
+			continue
+		}
+		result = append(result, a)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FiredAt.After(result[j].FiredAt)
+	})
+
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+
+	return result
+}
+
+// buildOverview computes the dashboard snapshot from the requests in the time
+// range plus the latest runtime sample, active alert count and recent errors.
+func buildOverview(appName string, uptime time.Duration, timeRange TimeRange, reqs []RequestMetric, latest *RuntimeMetric, activeAlerts int, recentErrors []ErrorRecord) *Overview {
+	var totalReqs, totalErrs float64
+	latencies := make([]time.Duration, 0, len(reqs))
+	for _, m := range reqs {
+		w := m.weight()
+		totalReqs += w
+		latencies = append(latencies, m.Latency)
+		if m.StatusCode >= 400 {
+			totalErrs += w
+		}
+	}
+
+	var errRate float64
+	if totalReqs > 0 {
+		errRate = totalErrs / totalReqs * 100
+	}
+
+	duration := timeRange.End.Sub(timeRange.Start)
+	rpm := float64(0)
+	if duration.Minutes() > 0 {
+		rpm = totalReqs / duration.Minutes()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	p95 := Percentile(latencies, 95)
+
+	goroutines, heapMB := runtimeFigures(latest)
+
+	// Top routes
+	topRoutes := buildRouteStats(reqs, duration)
+	if len(topRoutes) > 10 {
+		topRoutes = topRoutes[:10]
+	}
+
+	return &Overview{
+		AppName:          appName,
+		Uptime:           formatDuration(uptime),
+		TotalRequests:    int64(math.Round(totalReqs)),
+		TotalErrors:      int64(math.Round(totalErrs)),
+		ErrorRate:        errRate,
+		AvgLatency:       ComputeAvg(latencies),
+		P95Latency:       p95,
+		RPM:              rpm,
+		ActiveGoroutines: goroutines,
+		HeapAllocMB:      heapMB,
+		ActiveAlerts:     activeAlerts,
+		TopRoutes:        topRoutes,
+		RecentErrors:     recentErrors,
+		Timestamp:        time.Now(),
+	}
+}
+
+// runtimeFigures returns the goroutine count and heap size in MB an overview
+// reports for a runtime sample, or zeros without one.
+func runtimeFigures(m *RuntimeMetric) (goroutines int, heapMB float64) {
+	if m == nil {
+		return 0, 0
+	}
+	return m.NumGoroutine, float64(m.HeapAlloc) / (1024 * 1024)
+}
+
+// mergeInstances returns the union of two instance lists, keeping the order
+// of first appearance. It never modifies dst.
+func mergeInstances(dst, src []string) []string {
+	merged := dst
+	for _, name := range src {
+		if name != "" && !containsString(merged, name) {
+			merged = append(merged[:len(merged):len(merged)], name)
+		}
+	}
+	return merged
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate applies offset and limit to a slice. It returns nil when the
+// offset is past the end of the slice.
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 && offset < len(items) {
+		items = items[offset:]
+	} else if offset >= len(items) {
+		return nil
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
 }
 
 func formatDuration(d time.Duration) string {
@@ -878,8 +1152,8 @@ func (s *MemoryStorage) getErrorByID(id string) (*ErrorRecord, error) {
 	return nil, fmt.Errorf("error record not found: %s", id)
 }
 
-// getLatestHealthResults returns the latest health check result for each check.
-func (s *MemoryStorage) getLatestHealthResults() map[string]HealthCheckResult {
+// GetLatestHealthResults returns the latest health check result for each check.
+func (s *MemoryStorage) GetLatestHealthResults() (map[string]HealthCheckResult, error) {
 	s.healthMu.RLock()
 	defer s.healthMu.RUnlock()
 
@@ -890,6 +1164,6 @@ func (s *MemoryStorage) getLatestHealthResults() map[string]HealthCheckResult {
 			results[name] = latest[0]
 		}
 	}
-	return results
+	return results, nil
 }
 
@@ -423,7 +423,7 @@ func TestMemoryStorage_PoolStats(t *testing.T) {
 		t.Fatal("expected nil pool stats initially")
 	}
 
-	s.UpdatePoolStats(PoolStats{
+	s.StorePoolStats(PoolStats{
 		MaxOpenConnections: 25,
 		OpenConnections:    10,
 		InUse:              5,
@@ -437,6 +437,32 @@ func TestMemoryStorage_PoolStats(t *testing.T) {
 	if stats.MaxOpenConnections != 25 {
 		t.Fatalf("expected max 25, got %d", stats.MaxOpenConnections)
 	}
+
+	// The deprecated UpdatePoolStats still records samples
+	s.UpdatePoolStats(PoolStats{MaxOpenConnections: 30})
+	if stats, _ = s.GetConnectionPoolStats(); stats == nil || stats.MaxOpenConnections != 30 {
+		t.Fatalf("expected UpdatePoolStats to record a sample, got %+v", stats)
+	}
+}
+
+func TestMemoryStorage_PoolStatsHistory(t *testing.T) {
+	s := newTestStorage()
+	now := time.Now()
+
+	s.StorePoolStats(PoolStats{InUse: 1, Timestamp: now.Add(-2 * time.Hour)})
+	s.StorePoolStats(PoolStats{InUse: 2, Timestamp: now.Add(-10 * time.Minute)})
+	s.StorePoolStats(PoolStats{InUse: 3})
+
+	history, _ := s.GetPoolStatsHistory(TimeRange{Start: now.Add(-time.Hour), End: now.Add(time.Minute)})
+	if len(history) != 2 {
+		t.Fatalf("expected 2 samples in range, got %d", len(history))
+	}
+	if history[0].InUse != 2 || history[1].InUse != 3 {
+		t.Errorf("expected samples oldest first, got %+v", history)
+	}
+	if history[1].Timestamp.IsZero() {
+		t.Error("expected missing timestamp to be set on store")
+	}
 }
 
 func TestMemoryStorage_DeleteError(t *testing.T) {
@@ -0,0 +1,1175 @@
+package pulse
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite" // pure Go SQLite driver, registers "sqlite"
+)
+
+const (
+	// defaultSQLiteBatchSize is the number of buffered metrics that triggers a flush.
+	defaultSQLiteBatchSize = 500
+	// defaultSQLiteFlushInterval is how often buffered metrics are flushed in the background.
+	defaultSQLiteFlushInterval = 1 * time.Second
+	// defaultSQLiteMaxBuffered caps the metrics of each kind kept for retry
+	// while flushes fail; the oldest are dropped first.
+	defaultSQLiteMaxBuffered = 100 * defaultSQLiteBatchSize
+)
+
+// sqliteSchema creates the Pulse tables. Each table stores the indexed columns
+// needed for filtering plus a JSON "data" column holding the full record, so
+// reads return exactly what was written.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS requests (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	method      TEXT NOT NULL,
+	path        TEXT NOT NULL,
+	status_code INTEGER NOT NULL,
+	latency     INTEGER NOT NULL,
+	timestamp   INTEGER NOT NULL,
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_requests_timestamp ON requests(timestamp);
+CREATE INDEX IF NOT EXISTS idx_requests_route ON requests(method, path, timestamp);
+
+CREATE TABLE IF NOT EXISTS queries (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	duration   INTEGER NOT NULL,
+	timestamp  INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_queries_timestamp ON queries(timestamp);
+CREATE INDEX IF NOT EXISTS idx_queries_duration ON queries(duration);
+
+CREATE TABLE IF NOT EXISTS runtime (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_runtime_timestamp ON runtime(timestamp);
+
+CREATE TABLE IF NOT EXISTS errors (
+	fingerprint TEXT PRIMARY KEY,
+	id          TEXT NOT NULL,
+	count       INTEGER NOT NULL,
+	first_seen  INTEGER NOT NULL,
+	last_seen   INTEGER NOT NULL,
+	muted       INTEGER NOT NULL DEFAULT 0,
+	resolved    INTEGER NOT NULL DEFAULT 0,
+	data        TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_errors_id ON errors(id);
+CREATE INDEX IF NOT EXISTS idx_errors_last_seen ON errors(last_seen);
+
+CREATE TABLE IF NOT EXISTS health_results (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	name      TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_health_name ON health_results(name, id);
+CREATE INDEX IF NOT EXISTS idx_health_timestamp ON health_results(timestamp);
+
+CREATE TABLE IF NOT EXISTS alerts (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	state     TEXT NOT NULL,
+	severity  TEXT NOT NULL,
+	fired_at  INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_alerts_fired_at ON alerts(fired_at);
+
+CREATE TABLE IF NOT EXISTS dependencies (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	name      TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_dependencies_timestamp ON dependencies(timestamp);
+
+CREATE TABLE IF NOT EXISTS n1_detections (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	detected_at INTEGER NOT NULL,
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_n1_detected_at ON n1_detections(detected_at);
+
+CREATE TABLE IF NOT EXISTS pool_stats (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_pool_stats_timestamp ON pool_stats(timestamp);
+
+CREATE TABLE IF NOT EXISTS spans (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	trace_id   TEXT NOT NULL,
+	start_time INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_spans_trace_id ON spans(trace_id);
+CREATE INDEX IF NOT EXISTS idx_spans_start_time ON spans(start_time);
+
+CREATE TABLE IF NOT EXISTS connections (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_connections_timestamp ON connections(timestamp);
+`
+
+// SQLiteStorage is a persistent Storage implementation backed by a SQLite
+// database file. High-volume metrics (requests, queries, runtime samples,
+// dependency calls, spans and connections) are buffered and written in batched transactions; all
+// reads flush the buffer first so callers always see their own writes.
+// Aggregations reuse the same helpers as MemoryStorage, so both backends
+// return identical shapes.
+type SQLiteStorage struct {
+	db *sql.DB
+
+	// Write buffer, flushed when batchSize is reached, on a ticker, and before reads
+	bufMu        sync.Mutex
+	requests     []RequestMetric
+	queries      []QueryMetric
+	runtimeStats []RuntimeMetric
+	dependencies []DependencyMetric
+	spans        []Span
+	connections  []ConnectionMetric
+	batchSize    int
+	maxBuffered  int
+
+	// flushMu serializes flushes so batches are committed in order
+	flushMu sync.Mutex
+
+	// Config
+	appName   string
+	startTime time.Time
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewSQLiteStorage opens (or creates) the SQLite database at dsn, applies the
+// schema and starts the background flusher.
+func NewSQLiteStorage(dsn, appName string) (*SQLiteStorage, error) {
+	if dsn == "" {
+		dsn = "pulse.db"
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// SQLite allows a single writer; serializing through one connection
+	// avoids SQLITE_BUSY errors between the flusher and direct writes.
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA synchronous=NORMAL",
+		"PRAGMA busy_timeout=5000",
+	} {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("sqlite %s: %w", pragma, err)
+		}
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create sqlite schema: %w", err)
+	}
+
+	s := &SQLiteStorage{
+		db:          db,
+		batchSize:   defaultSQLiteBatchSize,
+		maxBuffered: defaultSQLiteMaxBuffered,
+		appName:     appName,
+		startTime:   time.Now(),
+		done:        make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(defaultSQLiteFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.flush()
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// --- Write buffering ---
+
+// bufferedLen returns the number of buffered metrics. Caller must hold bufMu.
+func (s *SQLiteStorage) bufferedLen() int {
+	return len(s.requests) + len(s.queries) + len(s.runtimeStats) + len(s.dependencies) + len(s.spans) + len(s.connections)
+}
+
+// enqueue runs add under the buffer lock and flushes if the batch is full.
+func (s *SQLiteStorage) enqueue(add func()) error {
+	s.bufMu.Lock()
+	add()
+	full := s.bufferedLen() >= s.batchSize
+	s.bufMu.Unlock()
+
+	if full {
+		return s.flush()
+	}
+	return nil
+}
+
+// StoreBatch buffers a batch of metrics under a single lock. Errors are
+// written individually because they are deduplicated by fingerprint.
+func (s *SQLiteStorage) StoreBatch(b MetricBatch) error {
+	err := s.enqueue(func() {
+		s.requests = append(s.requests, b.Requests...)
+		s.queries = append(s.queries, b.Queries...)
+		s.dependencies = append(s.dependencies, b.Dependencies...)
+		s.spans = append(s.spans, b.Spans...)
+		s.connections = append(s.connections, b.Connections...)
+	})
+	for _, e := range b.Errors {
+		if storeErr := s.StoreError(e); storeErr != nil && err == nil {
+			err = storeErr
+		}
+	}
+	return err
+}
+
+// flush writes all buffered metrics in a single transaction. Metrics of a
+// failed flush go back to the front of the buffer for the next one.
+func (s *SQLiteStorage) flush() (err error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.bufMu.Lock()
+	requests, queries, runtimeStats, deps, spans, conns := s.requests, s.queries, s.runtimeStats, s.dependencies, s.spans, s.connections
+	s.requests, s.queries, s.runtimeStats, s.dependencies, s.spans, s.connections = nil, nil, nil, nil, nil, nil
+	s.bufMu.Unlock()
+
+	if len(requests)+len(queries)+len(runtimeStats)+len(deps)+len(spans)+len(conns) == 0 {
+		return nil
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		s.bufMu.Lock()
+		s.requests = requeueFront(requests, s.requests, s.maxBuffered)
+		s.queries = requeueFront(queries, s.queries, s.maxBuffered)
+		s.runtimeStats = requeueFront(runtimeStats, s.runtimeStats, s.maxBuffered)
+		s.dependencies = requeueFront(deps, s.dependencies, s.maxBuffered)
+		s.spans = requeueFront(spans, s.spans, s.maxBuffered)
+		s.connections = requeueFront(conns, s.connections, s.maxBuffered)
+		s.bufMu.Unlock()
+	}()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin flush: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertBatch(tx, `INSERT INTO requests (method, path, status_code, latency, timestamp, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		requests, func(m RequestMetric) []interface{} {
+			return []interface{}{m.Method, m.Path, m.StatusCode, int64(m.Latency), m.Timestamp.UnixNano()}
+		}); err != nil {
+		return err
+	}
+	if err := insertBatch(tx, `INSERT INTO queries (duration, timestamp, data) VALUES (?, ?, ?)`,
+		queries, func(m QueryMetric) []interface{} {
+			return []interface{}{int64(m.Duration), m.Timestamp.UnixNano()}
+		}); err != nil {
+		return err
+	}
+	if err := insertBatch(tx, `INSERT INTO runtime (timestamp, data) VALUES (?, ?)`,
+		runtimeStats, func(m RuntimeMetric) []interface{} {
+			return []interface{}{m.Timestamp.UnixNano()}
+		}); err != nil {
+		return err
+	}
+	if err := insertBatch(tx, `INSERT INTO dependencies (name, timestamp, data) VALUES (?, ?, ?)`,
+		deps, func(m DependencyMetric) []interface{} {
+			return []interface{}{m.Name, m.Timestamp.UnixNano()}
+		}); err != nil {
+		return err
+	}
+	if err := insertBatch(tx, `INSERT INTO spans (trace_id, start_time, data) VALUES (?, ?, ?)`,
+		spans, func(m Span) []interface{} {
+			return []interface{}{m.TraceID, m.StartTime.UnixNano()}
+		}); err != nil {
+		return err
+	}
+	if err := insertBatch(tx, `INSERT INTO connections (timestamp, data) VALUES (?, ?)`,
+		conns, func(m ConnectionMetric) []interface{} {
+			return []interface{}{m.Timestamp.UnixNano()}
+		}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// requeueFront puts metrics of a failed flush back in front of those
+// buffered since, keeping the newest limit.
+func requeueFront[T any](failed, buffered []T, limit int) []T {
+	merged := append(failed[:len(failed):len(failed)], buffered...)
+	if over := len(merged) - limit; over > 0 {
+		merged = merged[over:]
+	}
+	return merged
+}
+
+// --- Request Metrics ---
+
+// StoreRequest buffers a request metric for the next batched write.
+func (s *SQLiteStorage) StoreRequest(m RequestMetric) error {
+	return s.enqueue(func() { s.requests = append(s.requests, m) })
+}
+
+// GetRequests returns requests matching the filter.
+func (s *SQLiteStorage) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
+	where, args := timeRangeClause("timestamp", filter.TimeRange)
+	if filter.Method != "" {
+		where += " AND method = ?"
+		args = append(args, filter.Method)
+	}
+	if filter.Path != "" {
+		where += " AND path = ?"
+		args = append(args, filter.Path)
+	}
+	if filter.StatusCode != 0 {
+		where += " AND status_code = ?"
+		args = append(args, filter.StatusCode)
+	}
+	if filter.MinLatency > 0 {
+		where += " AND latency >= ?"
+		args = append(args, int64(filter.MinLatency))
+	}
+	if filter.Instance != "" {
+		// Read from the JSON payload so existing databases need no migration
+		where += " AND json_extract(data, '$.instance') = ?"
+		args = append(args, filter.Instance)
+	}
+	for key, value := range filter.Tags {
+		where += " AND json_extract(data, ?) = ?"
+		args = append(args, sqliteJSONPath("tags", key), value)
+	}
+	if filter.TraceID != "" {
+		where += " AND json_extract(data, '$.trace_id') = ?"
+		args = append(args, filter.TraceID)
+	}
+
+	query := "SELECT data FROM requests WHERE " + where + " ORDER BY timestamp, id"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	} else if filter.Offset > 0 {
+		query += " LIMIT -1 OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	result, err := sqliteSelect[RequestMetric](s, query, args...)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRouteStats returns aggregated stats per route within the time range.
+func (s *SQLiteStorage) GetRouteStats(timeRange TimeRange) ([]RouteStats, error) {
+	reqs, err := s.requestsInRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	return buildRouteStats(reqs, timeRange.End.Sub(timeRange.Start)), nil
+}
+
+// GetRouteDetail returns detailed stats for a specific route.
+func (s *SQLiteStorage) GetRouteDetail(method, path string, timeRange TimeRange) (*RouteDetail, error) {
+	reqs, err := s.GetRequests(RequestFilter{TimeRange: timeRange, Method: method, Path: path})
+	if err != nil {
+		return nil, err
+	}
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	duration := timeRange.End.Sub(timeRange.Start)
+	rs := computeRouteStats(method, path, reqs, duration)
+
+	// Get recent requests (last 50)
+	recent := reqs
+	if len(recent) > 50 {
+		recent = recent[len(recent)-50:]
+	}
+
+	// Get related errors
+	errors, _ := s.GetErrors(ErrorFilter{
+		TimeRange: timeRange,
+		Route:     fmt.Sprintf("%s %s", method, path),
+		Limit:     20,
+	})
+
+	// Get related queries
+	patterns, _ := s.GetQueryPatterns(timeRange)
+
+	detail := &RouteDetail{
+		RouteStats:     rs,
+		RecentRequests: recent,
+		RecentErrors:   errors,
+		TopQueries:     patterns,
+	}
+	addPhaseStats(detail, reqs)
+	return detail, nil
+}
+
+// requestsInRange loads all requests within the time range, oldest first.
+func (s *SQLiteStorage) requestsInRange(timeRange TimeRange) ([]RequestMetric, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	return sqliteSelect[RequestMetric](s, "SELECT data FROM requests WHERE "+where+" ORDER BY timestamp, id", args...)
+}
+
+// --- Query Metrics ---
+
+// StoreQuery buffers a query metric for the next batched write.
+func (s *SQLiteStorage) StoreQuery(m QueryMetric) error {
+	return s.enqueue(func() { s.queries = append(s.queries, m) })
+}
+
+// GetSlowQueries returns queries slower than the threshold, slowest first.
+func (s *SQLiteStorage) GetSlowQueries(threshold time.Duration, limit int) ([]QueryMetric, error) {
+	query := "SELECT data FROM queries WHERE duration >= ? ORDER BY duration DESC"
+	args := []interface{}{int64(threshold)}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return sqliteSelect[QueryMetric](s, query, args...)
+}
+
+// GetQueryPatterns returns aggregated query patterns.
+func (s *SQLiteStorage) GetQueryPatterns(timeRange TimeRange) ([]QueryPattern, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	queries, err := sqliteSelect[QueryMetric](s, "SELECT data FROM queries WHERE "+where+" ORDER BY timestamp, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	return buildQueryPatterns(queries), nil
+}
+
+// GetN1Detections returns detected N+1 query issues.
+func (s *SQLiteStorage) GetN1Detections(timeRange TimeRange) ([]N1Detection, error) {
+	where, args := timeRangeClause("detected_at", timeRange)
+	result, err := sqliteSelect[N1Detection](s, "SELECT data FROM n1_detections WHERE "+where+" ORDER BY id", args...)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StoreN1Detection stores an N+1 detection.
+func (s *SQLiteStorage) StoreN1Detection(d N1Detection) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO n1_detections (detected_at, data) VALUES (?, ?)`, d.DetectedAt.UnixNano(), string(data))
+	return err
+}
+
+// --- Connection Pool ---
+
+// GetConnectionPoolStats returns the latest connection pool stats.
+func (s *SQLiteStorage) GetConnectionPoolStats() (*PoolStats, error) {
+	result, err := sqliteSelect[PoolStats](s, "SELECT data FROM pool_stats ORDER BY id DESC LIMIT 1")
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+// StorePoolStats records a connection pool stats sample.
+func (s *SQLiteStorage) StorePoolStats(stats PoolStats) error {
+	if stats.Timestamp.IsZero() {
+		stats.Timestamp = time.Now()
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO pool_stats (timestamp, data) VALUES (?, ?)`, stats.Timestamp.UnixNano(), string(data))
+	return err
+}
+
+// UpdatePoolStats records a connection pool stats sample, ignoring errors.
+//
+// Deprecated: Use StorePoolStats.
+func (s *SQLiteStorage) UpdatePoolStats(stats PoolStats) {
+	_ = s.StorePoolStats(stats)
+}
+
+// GetPoolStatsHistory returns pool stats samples within the time range, oldest first.
+func (s *SQLiteStorage) GetPoolStatsHistory(timeRange TimeRange) ([]PoolStats, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	return sqliteSelect[PoolStats](s, "SELECT data FROM pool_stats WHERE "+where+" ORDER BY timestamp, id", args...)
+}
+
+// --- Runtime Metrics ---
+
+// StoreRuntime buffers a runtime metric snapshot for the next batched write.
+func (s *SQLiteStorage) StoreRuntime(m RuntimeMetric) error {
+	return s.enqueue(func() { s.runtimeStats = append(s.runtimeStats, m) })
+}
+
+// GetRuntimeHistory returns runtime metrics within the time range.
+func (s *SQLiteStorage) GetRuntimeHistory(timeRange TimeRange) ([]RuntimeMetric, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	result, err := sqliteSelect[RuntimeMetric](s, "SELECT data FROM runtime WHERE "+where+" ORDER BY timestamp, id", args...)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result, nil
+}
+
+// latestRuntime returns the most recent runtime sample, or nil.
+func (s *SQLiteStorage) latestRuntime() (*RuntimeMetric, error) {
+	result, err := sqliteSelect[RuntimeMetric](s, "SELECT data FROM runtime ORDER BY timestamp DESC, id DESC LIMIT 1")
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+// --- Error Records ---
+
+// StoreError stores or deduplicates an error record by fingerprint.
+func (s *SQLiteStorage) StoreError(e ErrorRecord) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var data string
+	var count int64
+	var muted, resolved bool
+	err = tx.QueryRow(`SELECT data, count, muted, resolved FROM errors WHERE fingerprint = ?`, e.Fingerprint).
+		Scan(&data, &count, &muted, &resolved)
+
+	switch {
+	case err == sql.ErrNoRows:
+		encoded, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO errors (fingerprint, id, count, first_seen, last_seen, muted, resolved, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.Fingerprint, e.ID, e.Count, e.FirstSeen.UnixNano(), e.LastSeen.UnixNano(), e.Muted, e.Resolved, string(encoded)); err != nil {
+			return err
+		}
+
+	case err != nil:
+		return err
+
+	default:
+		// Deduplicate: increment count and update LastSeen
+		var existing ErrorRecord
+		if err := json.Unmarshal([]byte(data), &existing); err != nil {
+			return err
+		}
+		existing.Count = count + 1
+		existing.Muted = muted
+		existing.Resolved = resolved
+		existing.LastSeen = e.LastSeen
+		if e.StackTrace != "" {
+			existing.StackTrace = e.StackTrace
+		}
+		if e.RequestContext != nil {
+			existing.RequestContext = e.RequestContext
+		}
+		existing.Instances = mergeInstances(existing.Instances, e.Instances)
+		existing.Tags = mergeTagValues(existing.Tags, e.Tags)
+		existing.TraceIDs = mergeTraceIDs(existing.TraceIDs, e.TraceIDs)
+		encoded, err := json.Marshal(existing)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE errors SET count = ?, last_seen = ?, data = ? WHERE fingerprint = ?`,
+			existing.Count, existing.LastSeen.UnixNano(), string(encoded), e.Fingerprint); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetErrors returns errors matching the filter.
+func (s *SQLiteStorage) GetErrors(filter ErrorFilter) ([]ErrorRecord, error) {
+	all, err := s.allErrors()
+	if err != nil {
+		return nil, err
+	}
+	return filterErrorRecords(all, filter), nil
+}
+
+// GetErrorGroups returns error groups for the dashboard.
+func (s *SQLiteStorage) GetErrorGroups(timeRange TimeRange) ([]ErrorGroup, error) {
+	all, err := s.allErrors()
+	if err != nil {
+		return nil, err
+	}
+	return buildErrorGroups(all, timeRange), nil
+}
+
+// UpdateError updates specific fields on an error record.
+func (s *SQLiteStorage) UpdateError(id string, updates map[string]interface{}) error {
+	record, err := s.getErrorByID(id)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := updates["muted"]; ok {
+		if b, ok := v.(bool); ok {
+			record.Muted = b
+		}
+	}
+	if v, ok := updates["resolved"]; ok {
+		if b, ok := v.(bool); ok {
+			record.Resolved = b
+		}
+	}
+
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE errors SET muted = ?, resolved = ?, data = ? WHERE id = ?`,
+		record.Muted, record.Resolved, string(encoded), id)
+	return err
+}
+
+// allErrors loads every error record, with the count and triage columns
+// taking precedence over the JSON payload.
+func (s *SQLiteStorage) allErrors() ([]ErrorRecord, error) {
+	rows, err := s.db.Query(`SELECT data, count, muted, resolved FROM errors`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []ErrorRecord
+	for rows.Next() {
+		e, err := scanErrorRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *e)
+	}
+	return result, rows.Err()
+}
+
+// deleteError removes an error record by ID.
+func (s *SQLiteStorage) deleteError(id string) error {
+	res, err := s.db.Exec(`DELETE FROM errors WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("error record not found: %s", id)
+	}
+	return nil
+}
+
+// getErrorByID retrieves a single error record by ID.
+func (s *SQLiteStorage) getErrorByID(id string) (*ErrorRecord, error) {
+	rows, err := s.db.Query(`SELECT data, count, muted, resolved FROM errors WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error record not found: %s", id)
+	}
+	return scanErrorRow(rows)
+}
+
+func scanErrorRow(rows *sql.Rows) (*ErrorRecord, error) {
+	var data string
+	var count int64
+	var muted, resolved bool
+	if err := rows.Scan(&data, &count, &muted, &resolved); err != nil {
+		return nil, err
+	}
+	var e ErrorRecord
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return nil, err
+	}
+	e.Count = count
+	e.Muted = muted
+	e.Resolved = resolved
+	return &e, nil
+}
+
+// --- Health Results ---
+
+// StoreHealthResult stores a health check result.
+func (s *SQLiteStorage) StoreHealthResult(r HealthCheckResult) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO health_results (name, timestamp, data) VALUES (?, ?, ?)`,
+		r.Name, r.Timestamp.UnixNano(), string(data))
+	return err
+}
+
+// GetHealthHistory returns the history for a specific health check. With a
+// limit the most recent results are returned first, matching MemoryStorage.
+func (s *SQLiteStorage) GetHealthHistory(name string, limit int) ([]HealthCheckResult, error) {
+	if limit > 0 {
+		return sqliteSelect[HealthCheckResult](s, "SELECT data FROM health_results WHERE name = ? ORDER BY id DESC LIMIT ?", name, limit)
+	}
+	return sqliteSelect[HealthCheckResult](s, "SELECT data FROM health_results WHERE name = ? ORDER BY id", name)
+}
+
+// GetLatestHealthResults returns the latest health check result for each check.
+func (s *SQLiteStorage) GetLatestHealthResults() (map[string]HealthCheckResult, error) {
+	latest, err := sqliteSelect[HealthCheckResult](s,
+		"SELECT data FROM health_results WHERE id IN (SELECT MAX(id) FROM health_results GROUP BY name)")
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]HealthCheckResult, len(latest))
+	for _, r := range latest {
+		results[r.Name] = r
+	}
+	return results, nil
+}
+
+// --- Alerts ---
+
+// StoreAlert stores an alert record.
+func (s *SQLiteStorage) StoreAlert(a AlertRecord) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO alerts (state, severity, fired_at, data) VALUES (?, ?, ?, ?)`,
+		string(a.State), a.Severity, a.FiredAt.UnixNano(), string(data))
+	return err
+}
+
+// GetAlerts returns alerts matching the filter.
+func (s *SQLiteStorage) GetAlerts(filter AlertFilter) ([]AlertRecord, error) {
+	where, args := timeRangeClause("fired_at", filter.TimeRange)
+	alerts, err := sqliteSelect[AlertRecord](s, "SELECT data FROM alerts WHERE "+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	return filterAlertRecords(alerts, filter), nil
+}
+
+// --- Dependencies ---
+
+// StoreDependencyMetric buffers a dependency metric for the next batched write.
+func (s *SQLiteStorage) StoreDependencyMetric(m DependencyMetric) error {
+	return s.enqueue(func() { s.dependencies = append(s.dependencies, m) })
+}
+
+// GetDependencyStats returns aggregated stats per dependency.
+func (s *SQLiteStorage) GetDependencyStats(timeRange TimeRange) ([]DependencyStats, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	deps, err := sqliteSelect[DependencyMetric](s, "SELECT data FROM dependencies WHERE "+where+" ORDER BY timestamp, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	return buildDependencyStats(deps, timeRange.End.Sub(timeRange.Start)), nil
+}
+
+// --- Overview ---
+
+// GetOverview computes the top-level dashboard snapshot.
+func (s *SQLiteStorage) GetOverview(timeRange TimeRange) (*Overview, error) {
+	reqs, err := s.requestsInRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.latestRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	// Active alerts count
+	var activeAlerts int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM alerts WHERE state = ?`, string(AlertStateFiring)).Scan(&activeAlerts); err != nil {
+		return nil, err
+	}
+
+	// Recent errors
+	recentErrors, _ := s.GetErrors(ErrorFilter{
+		TimeRange: timeRange,
+		Limit:     5,
+	})
+
+	return buildOverview(s.appName, time.Since(s.startTime), timeRange, reqs, latest, activeAlerts, recentErrors), nil
+}
+
+// --- Traces ---
+
+// StoreSpans buffers spans for the next batched write.
+func (s *SQLiteStorage) StoreSpans(spans []Span) error {
+	return s.enqueue(func() { s.spans = append(s.spans, spans...) })
+}
+
+// GetTrace returns every stored span of the trace, ordered by start time.
+func (s *SQLiteStorage) GetTrace(traceID string) ([]Span, error) {
+	return sqliteSelect[Span](s, "SELECT data FROM spans WHERE trace_id = ? ORDER BY start_time, id", traceID)
+}
+
+// GetSpanStats returns latency stats per span name within the time range.
+func (s *SQLiteStorage) GetSpanStats(timeRange TimeRange) ([]SpanStats, error) {
+	where, args := timeRangeClause("start_time", timeRange)
+	spans, err := sqliteSelect[Span](s, "SELECT data FROM spans WHERE "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return buildSpanStats(spans), nil
+}
+
+// --- Connections ---
+
+// StoreConnections buffers connections for the next batched write.
+func (s *SQLiteStorage) StoreConnections(conns []ConnectionMetric) error {
+	return s.enqueue(func() { s.connections = append(s.connections, conns...) })
+}
+
+// GetConnections returns the connections opened within the time range.
+func (s *SQLiteStorage) GetConnections(timeRange TimeRange) ([]ConnectionMetric, error) {
+	where, args := timeRangeClause("timestamp", timeRange)
+	return sqliteSelect[ConnectionMetric](s, "SELECT data FROM connections WHERE "+where+" ORDER BY timestamp, id", args...)
+}
+
+// --- Maintenance ---
+
+// Cleanup deletes data older than the retention period.
+func (s *SQLiteStorage) Cleanup(retention time.Duration) error {
+	_, err := s.Prune(uniformRetention(retention))
+	return err
+}
+
+// Prune deletes data older than each data type's retention period and
+// reports how many rows were removed.
+func (s *SQLiteStorage) Prune(retention RetentionConfig) (PruneResult, error) {
+	var result PruneResult
+	if err := s.flush(); err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	deletes := []struct {
+		stmt      string
+		retention time.Duration
+		count     *int64
+	}{
+		{`DELETE FROM requests WHERE timestamp < ?`, retention.Requests, &result.Requests},
+		{`DELETE FROM queries WHERE timestamp < ?`, retention.Queries, &result.Queries},
+		{`DELETE FROM n1_detections WHERE detected_at < ?`, retention.Queries, &result.N1Detections},
+		{`DELETE FROM runtime WHERE timestamp < ?`, retention.Runtime, &result.Runtime},
+		{`DELETE FROM pool_stats WHERE timestamp < ?`, retention.Runtime, &result.PoolStats},
+		{`DELETE FROM errors WHERE last_seen < ?`, retention.Errors, &result.Errors},
+		{`DELETE FROM alerts WHERE fired_at < ?`, retention.Alerts, &result.Alerts},
+		{`DELETE FROM health_results WHERE timestamp < ?`, retention.Health, &result.Health},
+		{`DELETE FROM dependencies WHERE timestamp < ?`, retention.Dependencies, &result.Dependencies},
+		{`DELETE FROM spans WHERE start_time < ?`, retention.Requests, &result.Spans},
+		{`DELETE FROM connections WHERE timestamp < ?`, retention.Requests, &result.Connections},
+	}
+	for _, d := range deletes {
+		res, err := s.db.Exec(d.stmt, now.Add(-d.retention).UnixNano())
+		if err != nil {
+			return result, fmt.Errorf("cleanup: %w", err)
+		}
+		*d.count, _ = res.RowsAffected()
+	}
+	return result, nil
+}
+
+// DeleteSubject deletes the records of one data subject. Rows are matched on
+// their decoded payload, so every backend agrees on what a filter matches.
+func (s *SQLiteStorage) DeleteSubject(filter SubjectFilter) (PruneResult, error) {
+	var result PruneResult
+	if filter.IsZero() {
+		return result, nil
+	}
+	if err := s.flush(); err != nil {
+		return result, err
+	}
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	traces := newSubjectTraces(filter)
+	if result.Requests, err = sqliteDeleteMatching(tx, "requests", func(m RequestMetric) bool {
+		if filter.matchRequest(m) {
+			traces.add(m.TraceID)
+			return true
+		}
+		return false
+	}); err != nil {
+		return result, fmt.Errorf("delete requests: %w", err)
+	}
+	if result.Connections, err = sqliteDeleteMatching(tx, "connections", func(c ConnectionMetric) bool {
+		if filter.matchConnection(c) {
+			traces.add(c.TraceID)
+			return true
+		}
+		return false
+	}); err != nil {
+		return result, fmt.Errorf("delete connections: %w", err)
+	}
+	if result.Errors, err = sqliteEraseErrors(tx, filter, traces); err != nil {
+		return result, fmt.Errorf("delete errors: %w", err)
+	}
+	if len(traces) > 0 {
+		if result.Spans, err = sqliteDeleteMatching(tx, "spans", func(span Span) bool { return traces[span.TraceID] }); err != nil {
+			return result, fmt.Errorf("delete spans: %w", err)
+		}
+		if result.Queries, err = sqliteDeleteMatching(tx, "queries", func(m QueryMetric) bool { return traces[m.RequestTraceID] }); err != nil {
+			return result, fmt.Errorf("delete queries: %w", err)
+		}
+		if result.Dependencies, err = sqliteDeleteMatching(tx, "dependencies", func(m DependencyMetric) bool { return traces[m.TraceID] }); err != nil {
+			return result, fmt.Errorf("delete dependencies: %w", err)
+		}
+	}
+	return result, tx.Commit()
+}
+
+// sqliteDeleteMatching deletes the rows of table whose decoded data matches
+// and returns how many were removed.
+func sqliteDeleteMatching[T any](tx *sql.Tx, table string, match func(T) bool) (int64, error) {
+	rows, err := tx.Query("SELECT id, data FROM " + table)
+	if err != nil {
+		return 0, err
+	}
+	var ids []interface{}
+	for rows.Next() {
+		var id interface{}
+		var data string
+		if err := rows.Scan(&id, &data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		var item T
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if match(item) {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Stay well below SQLite's bound parameter limit
+	const chunk = 500
+	for i := 0; i < len(ids); i += chunk {
+		batch := ids[i:min(i+chunk, len(ids))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE id IN ("+placeholders+")", batch...); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(ids)), nil
+}
+
+// sqliteEraseErrors removes a data subject from the error groups, deleting
+// the groups left empty, and returns how many groups were changed.
+func sqliteEraseErrors(tx *sql.Tx, filter SubjectFilter, traces subjectTraces) (int64, error) {
+	rows, err := tx.Query("SELECT fingerprint, data FROM errors")
+	if err != nil {
+		return 0, err
+	}
+	erased := make(map[string]*ErrorRecord) // nil when left empty
+	for rows.Next() {
+		var fingerprint, data string
+		if err := rows.Scan(&fingerprint, &data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		var e ErrorRecord
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		e, matched, empty := filter.eraseError(e, traces)
+		switch {
+		case empty:
+			erased[fingerprint] = nil
+		case matched:
+			erased[fingerprint] = &e
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for fingerprint, e := range erased {
+		if e == nil {
+			if _, err := tx.Exec("DELETE FROM errors WHERE fingerprint = ?", fingerprint); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE errors SET data = ? WHERE fingerprint = ?", string(data), fingerprint); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(erased)), nil
+}
+
+// Reset clears all stored data.
+func (s *SQLiteStorage) Reset() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.bufMu.Lock()
+	s.requests, s.queries, s.runtimeStats, s.dependencies, s.spans, s.connections = nil, nil, nil, nil, nil, nil
+	s.bufMu.Unlock()
+
+	for _, table := range []string{"requests", "queries", "runtime", "errors", "health_results", "alerts", "dependencies", "n1_detections", "pool_stats", "spans", "connections"} {
+		if _, err := s.db.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("reset %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered metrics, stops the background flusher and closes the database.
+func (s *SQLiteStorage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		err = s.flush()
+		if closeErr := s.db.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	return err
+}
+
+// --- Helpers ---
+
+// sqliteJSONPath returns the JSON path of a key within an object field,
+// quoting the key so dots and brackets in it are taken literally.
+func sqliteJSONPath(field, key string) string {
+	return `$.` + field + `."` + key + `"`
+}
+
+// timeRangeClause builds an inclusive WHERE clause over a UnixNano column.
+// Zero bounds are treated as open-ended.
+func timeRangeClause(column string, tr TimeRange) (string, []interface{}) {
+	where := "1=1"
+	var args []interface{}
+	if !tr.Start.IsZero() {
+		where += " AND " + column + " >= ?"
+		args = append(args, tr.Start.UnixNano())
+	}
+	if !tr.End.IsZero() {
+		where += " AND " + column + " <= ?"
+		args = append(args, tr.End.UnixNano())
+	}
+	return where, args
+}
+
+// insertBatch inserts items with a single prepared statement. columns returns
+// the indexed column values for an item; the JSON-encoded item is appended as
+// the final "data" argument.
+func insertBatch[T any](tx *sql.Tx, query string, items []T, columns func(T) []interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(append(columns(item), string(data))...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqliteSelect flushes pending writes, runs a query selecting a single JSON
+// "data" column, and decodes each row into T.
+func sqliteSelect[T any](s *SQLiteStorage, query string, args ...interface{}) ([]T, error) {
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var item T
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+// Ensure SQLiteStorage satisfies the Storage interface at compile time.
+var _ Storage = (*SQLiteStorage)(nil)
@@ -0,0 +1,603 @@
+package pulse
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteStorage(t *testing.T) *SQLiteStorage {
+	t.Helper()
+	s, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "pulse.db"), "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteStorage_StoreAndGetRequests(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		s.StoreRequest(RequestMetric{
+			Method:     "GET",
+			Path:       "/users",
+			StatusCode: 200,
+			Latency:    time.Duration(i+1) * 10 * time.Millisecond,
+			TraceID:    fmt.Sprintf("trace-%d", i),
+			Timestamp:  now.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	reqs, err := s.GetRequests(RequestFilter{
+		TimeRange: TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 10 {
+		t.Fatalf("expected 10 requests, got %d", len(reqs))
+	}
+	if reqs[0].TraceID != "trace-0" || reqs[9].TraceID != "trace-9" {
+		t.Fatalf("expected requests oldest first, got %s..%s", reqs[0].TraceID, reqs[9].TraceID)
+	}
+	if reqs[3].Latency != 40*time.Millisecond {
+		t.Fatalf("expected latency to round-trip, got %v", reqs[3].Latency)
+	}
+}
+
+func TestSQLiteStorage_GetRequests_WithFilter(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/users", StatusCode: 200, Timestamp: now})
+	s.StoreRequest(RequestMetric{Method: "POST", Path: "/users", StatusCode: 201, Timestamp: now})
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/posts", StatusCode: 500, Latency: time.Second, Timestamp: now})
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+
+	reqs, _ := s.GetRequests(RequestFilter{TimeRange: tr, Method: "GET"})
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 GET requests, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, Path: "/users"})
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 /users requests, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, StatusCode: 500})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 500 request, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, MinLatency: 500 * time.Millisecond})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 slow request, got %d", len(reqs))
+	}
+}
+
+func TestSQLiteStorage_GetRequests_Pagination(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 20; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/test", StatusCode: 200, Timestamp: now})
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+
+	reqs, _ := s.GetRequests(RequestFilter{TimeRange: tr, Limit: 5})
+	if len(reqs) != 5 {
+		t.Fatalf("expected 5 with limit, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, Offset: 15, Limit: 10})
+	if len(reqs) != 5 {
+		t.Fatalf("expected 5 with offset 15, got %d", len(reqs))
+	}
+
+	reqs, _ = s.GetRequests(RequestFilter{TimeRange: tr, Offset: 30})
+	if reqs != nil {
+		t.Fatalf("expected nil past the end, got %d", len(reqs))
+	}
+}
+
+func TestSQLiteStorage_RouteStatsMatchMemory(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	ms := newTestStorage()
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		status := 200
+		if i%10 == 0 {
+			status = 500
+		}
+		m := RequestMetric{
+			Method:     "GET",
+			Path:       "/users",
+			StatusCode: status,
+			Latency:    time.Duration(i+1) * time.Millisecond,
+			Timestamp:  now,
+		}
+		s.StoreRequest(m)
+		ms.StoreRequest(m)
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	got, err := s.GetRouteStats(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ms.GetRouteStats(tr)
+
+	if len(got) != 1 || len(want) != 1 {
+		t.Fatalf("expected 1 route from each backend, got %d and %d", len(got), len(want))
+	}
+	g, w := got[0], want[0]
+	if g.RequestCount != w.RequestCount || g.ErrorCount != w.ErrorCount {
+		t.Fatalf("counts differ: sqlite %d/%d, memory %d/%d", g.RequestCount, g.ErrorCount, w.RequestCount, w.ErrorCount)
+	}
+	if g.P50Latency != w.P50Latency || g.P95Latency != w.P95Latency || g.P99Latency != w.P99Latency {
+		t.Fatalf("percentiles differ: sqlite %v/%v/%v, memory %v/%v/%v",
+			g.P50Latency, g.P95Latency, g.P99Latency, w.P50Latency, w.P95Latency, w.P99Latency)
+	}
+	if g.StatusCodes[500] != 10 || g.StatusCodes[200] != 90 {
+		t.Fatalf("unexpected status codes: %v", g.StatusCodes)
+	}
+}
+
+func TestSQLiteStorage_RouteDetail(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 60; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/users/:id", StatusCode: 200, Timestamp: now})
+	}
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	detail, err := s.GetRouteDetail("GET", "/users/:id", tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail == nil || detail.RequestCount != 60 {
+		t.Fatalf("expected detail with 60 requests, got %+v", detail)
+	}
+	if len(detail.RecentRequests) != 50 {
+		t.Fatalf("expected 50 recent requests, got %d", len(detail.RecentRequests))
+	}
+
+	detail, _ = s.GetRouteDetail("POST", "/users/:id", tr)
+	if detail != nil {
+		t.Fatal("expected nil detail for unknown route")
+	}
+}
+
+func TestSQLiteStorage_Queries(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i, d := range []time.Duration{500 * time.Millisecond, 300 * time.Millisecond, 10 * time.Millisecond} {
+		s.StoreQuery(QueryMetric{
+			SQL:           fmt.Sprintf("SELECT * FROM users WHERE id = %d", i),
+			NormalizedSQL: "select * from users where id = ?",
+			Duration:      d,
+			Operation:     "SELECT",
+			Table:         "users",
+			Timestamp:     now,
+		})
+	}
+
+	slow, _ := s.GetSlowQueries(200*time.Millisecond, 10)
+	if len(slow) != 2 {
+		t.Fatalf("expected 2 slow queries, got %d", len(slow))
+	}
+	if slow[0].Duration < slow[1].Duration {
+		t.Fatal("expected descending duration order")
+	}
+
+	patterns, _ := s.GetQueryPatterns(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if len(patterns) != 1 {
+		t.Fatalf("expected 1 pattern, got %d", len(patterns))
+	}
+	if patterns[0].Count != 3 {
+		t.Fatalf("expected count 3, got %d", patterns[0].Count)
+	}
+}
+
+func TestSQLiteStorage_ErrorDeduplication(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		s.StoreError(ErrorRecord{
+			ID:           fmt.Sprintf("err-%d", i),
+			Fingerprint:  "fp-1",
+			Method:       "POST",
+			Route:        "/users",
+			ErrorMessage: "validation failed",
+			ErrorType:    "validation",
+			Count:        1,
+			FirstSeen:    now,
+			LastSeen:     now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	errors, _ := s.GetErrors(ErrorFilter{})
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 deduplicated error, got %d", len(errors))
+	}
+	if errors[0].Count != 5 {
+		t.Fatalf("expected count 5, got %d", errors[0].Count)
+	}
+	if errors[0].ID != "err-0" {
+		t.Fatalf("expected first ID to be kept, got %s", errors[0].ID)
+	}
+	if !errors[0].LastSeen.Equal(now.Add(4 * time.Minute)) {
+		t.Fatalf("expected LastSeen to advance, got %v", errors[0].LastSeen)
+	}
+
+	groups, _ := s.GetErrorGroups(TimeRange{Start: now.Add(-time.Minute), End: now.Add(10 * time.Minute)})
+	if len(groups) != 1 || groups[0].Count != 5 {
+		t.Fatalf("expected 1 group with count 5, got %+v", groups)
+	}
+}
+
+func TestSQLiteStorage_ErrorMuteResolvePersist(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "pulse.db")
+	s, err := NewSQLiteStorage(dsn, "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	s.StoreError(ErrorRecord{ID: "err-1", Fingerprint: "fp-1", ErrorMessage: "boom", Count: 1, FirstSeen: now, LastSeen: now})
+
+	if err := s.UpdateError("err-1", map[string]interface{}{"muted": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateError("err-1", map[string]interface{}{"resolved": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateError("nonexistent", map[string]interface{}{"muted": true}); err == nil {
+		t.Fatal("expected error for nonexistent ID")
+	}
+
+	// A repeat occurrence must not clear triage flags
+	s.StoreError(ErrorRecord{ID: "err-2", Fingerprint: "fp-1", ErrorMessage: "boom", Count: 1, FirstSeen: now, LastSeen: now})
+	s.Close()
+
+	// Reopen and verify the triage state survived the restart
+	s, err = NewSQLiteStorage(dsn, "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	rec, err := s.getErrorByID("err-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Muted || !rec.Resolved {
+		t.Fatalf("expected muted and resolved after reopen, got muted=%v resolved=%v", rec.Muted, rec.Resolved)
+	}
+	if rec.Count != 2 {
+		t.Fatalf("expected count 2, got %d", rec.Count)
+	}
+}
+
+func TestSQLiteStorage_PersistsAcrossReopen(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "pulse.db")
+	s, err := NewSQLiteStorage(dsn, "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/users", StatusCode: 200, Timestamp: now})
+	s.StoreRuntime(RuntimeMetric{NumGoroutine: 7, Timestamp: now})
+	// Close flushes the write buffer
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewSQLiteStorage(dsn, "test-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tr := TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	reqs, _ := s.GetRequests(RequestFilter{TimeRange: tr})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request after reopen, got %d", len(reqs))
+	}
+	history, _ := s.GetRuntimeHistory(tr)
+	if len(history) != 1 || history[0].NumGoroutine != 7 {
+		t.Fatalf("expected runtime sample after reopen, got %+v", history)
+	}
+}
+
+func TestSQLiteStorage_BatchFlush(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	s.batchSize = 10
+	now := time.Now()
+
+	for i := 0; i < 25; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: "/batch", Timestamp: now})
+	}
+
+	// Two full batches should already be on disk, five still buffered
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM requests`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 20 {
+		t.Fatalf("expected 20 flushed rows, got %d", count)
+	}
+
+	// Reads flush the remainder
+	reqs, _ := s.GetRequests(RequestFilter{})
+	if len(reqs) != 25 {
+		t.Fatalf("expected 25 requests, got %d", len(reqs))
+	}
+}
+
+func TestSQLiteStorage_FailedFlushKeepsBuffer(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	s.batchSize, s.maxBuffered = 100, 3
+	now := time.Now()
+
+	if _, err := s.db.Exec(`ALTER TABLE requests RENAME TO requests_away`); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		s.StoreRequest(RequestMetric{Method: "GET", Path: fmt.Sprintf("/r%d", i), Timestamp: now.Add(time.Duration(i))})
+	}
+	if err := s.flush(); err == nil {
+		t.Fatal("expected the flush to fail without the requests table")
+	}
+	if _, err := s.db.Exec(`ALTER TABLE requests_away RENAME TO requests`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed batch is retried, keeping the newest up to the cap
+	reqs, err := s.GetRequests(RequestFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]bool)
+	for _, r := range reqs {
+		paths[r.Path] = true
+	}
+	if len(reqs) != 3 || !paths["/r2"] || !paths["/r3"] || !paths["/r4"] {
+		t.Errorf("expected the newest 3 requests written on retry, got %+v", reqs)
+	}
+}
+
+func TestSQLiteStorage_HealthResults(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+
+	for i := 0; i < 5; i++ {
+		s.StoreHealthResult(HealthCheckResult{
+			Name:      "postgres",
+			Type:      "database",
+			Status:    "healthy",
+			Latency:   time.Duration(i+1) * time.Millisecond,
+			Timestamp: time.Now(),
+		})
+	}
+	s.StoreHealthResult(HealthCheckResult{Name: "redis", Status: "unhealthy", Timestamp: time.Now()})
+
+	history, _ := s.GetHealthHistory("postgres", 3)
+	if len(history) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(history))
+	}
+	if history[0].Latency != 5*time.Millisecond {
+		t.Fatalf("expected most recent first, got %v", history[0].Latency)
+	}
+
+	history, _ = s.GetHealthHistory("mysql", 3)
+	if history != nil {
+		t.Fatalf("expected nil for non-existent check, got %v", history)
+	}
+
+	latest, _ := s.GetLatestHealthResults()
+	if len(latest) != 2 || latest["redis"].Status != "unhealthy" {
+		t.Fatalf("unexpected latest results: %+v", latest)
+	}
+}
+
+func TestSQLiteStorage_Alerts(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	s.StoreAlert(AlertRecord{ID: "a1", RuleName: "high_latency", State: AlertStateFiring, Severity: "critical", FiredAt: now})
+	s.StoreAlert(AlertRecord{ID: "a2", RuleName: "high_errors", State: AlertStateResolved, Severity: "warning", FiredAt: now})
+
+	alerts, _ := s.GetAlerts(AlertFilter{})
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+
+	alerts, _ = s.GetAlerts(AlertFilter{State: AlertStateFiring})
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 firing alert, got %d", len(alerts))
+	}
+}
+
+func TestSQLiteStorage_Dependencies(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		s.StoreDependencyMetric(DependencyMetric{
+			Name:       "stripe",
+			Method:     "POST",
+			StatusCode: 200,
+			Latency:    time.Duration(i+1) * 10 * time.Millisecond,
+			Timestamp:  now,
+		})
+	}
+
+	stats, _ := s.GetDependencyStats(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 dependency, got %d", len(stats))
+	}
+	if stats[0].RequestCount != 10 {
+		t.Fatalf("expected 10 requests, got %d", stats[0].RequestCount)
+	}
+}
+
+func TestSQLiteStorage_Overview(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	for i := 0; i < 50; i++ {
+		status := 200
+		if i%5 == 0 {
+			status = 500
+		}
+		s.StoreRequest(RequestMetric{
+			Method:     "GET",
+			Path:       "/users",
+			StatusCode: status,
+			Latency:    time.Duration(i+1) * time.Millisecond,
+			Timestamp:  now,
+		})
+	}
+	s.StoreRuntime(RuntimeMetric{HeapAlloc: 1024 * 1024 * 100, NumGoroutine: 42, Timestamp: now})
+	s.StoreAlert(AlertRecord{ID: "a1", State: AlertStateFiring, FiredAt: now})
+
+	overview, err := s.GetOverview(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overview.TotalRequests != 50 {
+		t.Fatalf("expected 50 requests, got %d", overview.TotalRequests)
+	}
+	if overview.TotalErrors != 10 {
+		t.Fatalf("expected 10 errors, got %d", overview.TotalErrors)
+	}
+	if overview.ActiveGoroutines != 42 {
+		t.Fatalf("expected 42 goroutines, got %d", overview.ActiveGoroutines)
+	}
+	if overview.ActiveAlerts != 1 {
+		t.Fatalf("expected 1 active alert, got %d", overview.ActiveAlerts)
+	}
+	if len(overview.TopRoutes) != 1 {
+		t.Fatalf("expected 1 top route, got %d", len(overview.TopRoutes))
+	}
+}
+
+func TestSQLiteStorage_N1AndPoolStats(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	s.StoreN1Detection(N1Detection{Pattern: "select * from users where id = ?", Count: 10, DetectedAt: now})
+	detections, _ := s.GetN1Detections(TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+	if len(detections) != 1 {
+		t.Fatalf("expected 1 detection, got %d", len(detections))
+	}
+
+	stats, _ := s.GetConnectionPoolStats()
+	if stats != nil {
+		t.Fatal("expected nil pool stats initially")
+	}
+	s.StorePoolStats(PoolStats{MaxOpenConnections: 25, InUse: 5})
+	s.StorePoolStats(PoolStats{MaxOpenConnections: 25, InUse: 7})
+	stats, _ = s.GetConnectionPoolStats()
+	if stats == nil || stats.InUse != 7 {
+		t.Fatalf("expected latest pool stats, got %+v", stats)
+	}
+
+	s.StorePoolStats(PoolStats{InUse: 1, Timestamp: now.Add(-2 * time.Hour)})
+	history, _ := s.GetPoolStatsHistory(TimeRange{Start: now.Add(-time.Hour), End: now.Add(time.Minute)})
+	if len(history) != 2 {
+		t.Fatalf("expected 2 samples in range, got %d", len(history))
+	}
+	if history[0].InUse != 5 || history[1].InUse != 7 {
+		t.Errorf("expected samples oldest first, got %+v", history)
+	}
+}
+
+func TestSQLiteStorage_CleanupAndReset(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now()
+
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/old", Timestamp: old})
+	s.StoreRequest(RequestMetric{Method: "GET", Path: "/new", Timestamp: recent})
+	s.StoreError(ErrorRecord{ID: "old", Fingerprint: "fp-old", Count: 1, FirstSeen: old, LastSeen: old})
+	s.StoreError(ErrorRecord{ID: "new", Fingerprint: "fp-new", Count: 1, FirstSeen: recent, LastSeen: recent})
+	s.StoreAlert(AlertRecord{ID: "a-old", FiredAt: old})
+	s.StoreAlert(AlertRecord{ID: "a-new", FiredAt: recent})
+
+	if err := s.Cleanup(24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, _ := s.GetRequests(RequestFilter{})
+	if len(reqs) != 1 || reqs[0].Path != "/new" {
+		t.Fatalf("expected only the recent request after cleanup, got %+v", reqs)
+	}
+	errors, _ := s.GetErrors(ErrorFilter{})
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error after cleanup, got %d", len(errors))
+	}
+	alerts, _ := s.GetAlerts(AlertFilter{})
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert after cleanup, got %d", len(alerts))
+	}
+
+	if err := s.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	reqs, _ = s.GetRequests(RequestFilter{})
+	errors, _ = s.GetErrors(ErrorFilter{})
+	if len(reqs) != 0 || len(errors) != 0 {
+		t.Fatalf("expected empty storage after reset, got %d requests and %d errors", len(reqs), len(errors))
+	}
+}
+
+func TestSQLiteStorage_DeleteError(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	now := time.Now()
+
+	s.StoreError(ErrorRecord{ID: "e1", Fingerprint: "fp-1", Count: 1, FirstSeen: now, LastSeen: now})
+
+	if err := s.deleteError("e1"); err != nil {
+		t.Fatal(err)
+	}
+	errors, _ := s.GetErrors(ErrorFilter{})
+	if len(errors) != 0 {
+		t.Fatalf("expected 0 errors after delete, got %d", len(errors))
+	}
+	if err := s.deleteError("nonexistent"); err == nil {
+		t.Fatal("expected error for nonexistent delete")
+	}
+}
+
+func TestMount_SelectsSQLiteStorage(t *testing.T) {
+	_, p := setupTestRouter(Config{
+		Storage: StorageConfig{
+			Driver: SQLite,
+			DSN:    filepath.Join(t.TempDir(), "pulse.db"),
+		},
+	})
+	defer p.Shutdown()
+
+	if _, ok := p.GetStorage().(*SQLiteStorage); !ok {
+		t.Fatalf("expected *SQLiteStorage, got %T", p.GetStorage())
+	}
+}
+
+func TestSQLiteStorage_StoreBatch(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	if err := s.StoreBatch(testMetricBatch(250)); err != nil {
+		t.Fatalf("StoreBatch: %v", err)
+	}
+	assertBatchStored(t, s, 250)
+}
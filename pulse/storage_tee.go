@@ -0,0 +1,680 @@
+package pulse
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TeeStorage is a Storage that writes every metric to several backends and
+// routes each read to the backend best placed to answer it:
+//
+//   - Time-series reads (requests, queries, runtime, dependencies, pool stats)
+//     for ranges that start within the hot window go to the hot backend,
+//     older or unbounded ranges to the durable backends.
+//   - Error groups and alerts carry counts and triage state that outlive the
+//     hot window, so they are read from the durable backends.
+//   - Latest-state lookups (slow queries, current pool stats, health) and
+//     traces go to the hot backend.
+//
+// Writes return once the hot backend has them. Each durable backend applies
+// them in order from its own bounded queue, so a slow or failing backend
+// neither stalls the others nor the caller; writes it cannot keep up with
+// are dropped and counted in Stats. Reads served by a durable backend first
+// wait briefly for it to apply the writes queued before them. A read that
+// fails falls back to the next backend.
+type TeeStorage struct {
+	hot       Storage
+	durable   []Storage
+	all       []Storage
+	writers   []*teeWriter // one per durable backend
+	hotWindow time.Duration
+}
+
+// NewTeeStorage creates a TeeStorage that keeps hotWindow of data in hot
+// (typically MemoryStorage) in front of the durable backends. A zero
+// hotWindow serves every time range from hot.
+func NewTeeStorage(hot Storage, hotWindow time.Duration, durable ...Storage) *TeeStorage {
+	t := &TeeStorage{
+		hot:       hot,
+		durable:   durable,
+		all:       append([]Storage{hot}, durable...),
+		hotWindow: hotWindow,
+	}
+	for i, s := range durable {
+		t.writers = append(t.writers, newTeeWriter(i+1, s, defaultTeeQueueSize))
+	}
+	return t
+}
+
+// --- Routing ---
+
+// hotFirst orders the backends for latest-state reads.
+func (t *TeeStorage) hotFirst() []Storage {
+	return t.all
+}
+
+// durableFirst orders the backends for reads of long-lived records. Durable
+// backends that have not applied their queued writes within teeReadWait are
+// tried after the hot backend.
+func (t *TeeStorage) durableFirst() []Storage {
+	if len(t.durable) == 0 {
+		return t.all
+	}
+	var current, behind []Storage
+	deadline := time.Now().Add(teeReadWait)
+	for _, w := range t.writers {
+		if w.sync(time.Until(deadline)) == nil {
+			current = append(current, w.backend)
+		} else {
+			behind = append(behind, w.backend)
+		}
+	}
+	return append(append(current, t.hot), behind...)
+}
+
+// forRange orders the backends for a time-series read: the hot backend when
+// the range starts inside the hot window, the durable backends otherwise.
+func (t *TeeStorage) forRange(tr TimeRange) []Storage {
+	if t.hotWindow <= 0 {
+		return t.hotFirst()
+	}
+	if !tr.Start.IsZero() && !tr.Start.Before(time.Now().Add(-t.hotWindow)) {
+		return t.hotFirst()
+	}
+	return t.durableFirst()
+}
+
+// teeRead returns the first successful result, trying backends in order.
+func teeRead[T any](backends []Storage, read func(Storage) (T, error)) (T, error) {
+	var errs []error
+	for _, s := range backends {
+		v, err := read(s)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err)
+	}
+	var zero T
+	return zero, errors.Join(errs...)
+}
+
+// fanOut writes to the hot backend and queues the write for every durable
+// backend. Only the hot backend's failure is returned; durable failures are
+// counted in Stats.
+func (t *TeeStorage) fanOut(write func(Storage) error) error {
+	for _, w := range t.writers {
+		w.enqueue(write)
+	}
+	if err := write(t.hot); err != nil {
+		return fmt.Errorf("tee backend 0: %w", err)
+	}
+	return nil
+}
+
+// fanOutAny is for updates of records that may be missing from some
+// backends (e.g. pruned from the hot window). It waits for every backend and
+// succeeds if any did.
+func (t *TeeStorage) fanOutAny(write func(Storage) error) error {
+	errs := t.writeAll(write)
+	if len(errs) < len(t.all) {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// writeAll runs write against the hot backend and, after the writes queued
+// before it, against every durable backend, waiting up to teeSyncWait for
+// each. It returns the failures.
+func (t *TeeStorage) writeAll(write func(Storage) error) []error {
+	var errs []error
+	if err := write(t.hot); err != nil {
+		errs = append(errs, fmt.Errorf("tee backend 0: %w", err))
+	}
+	for _, w := range t.writers {
+		if err := w.do(write, teeSyncWait); err != nil {
+			errs = append(errs, fmt.Errorf("tee backend %d: %w", w.index, err))
+		}
+	}
+	return errs
+}
+
+// --- Durable Queues ---
+
+const (
+	// defaultTeeQueueSize bounds the writes waiting for a durable backend.
+	defaultTeeQueueSize = 10000
+	// teeReadWait bounds how long a read waits for a durable backend to
+	// apply the writes queued before it.
+	teeReadWait = time.Second
+	// teeSyncWait bounds how long updates and maintenance wait for a
+	// durable backend.
+	teeSyncWait = 10 * time.Second
+)
+
+// errTeeBackendBehind is returned when a durable backend did not get to an
+// operation in time. The operation stays queued.
+var errTeeBackendBehind = errors.New("backend did not keep up")
+
+// TeeBackendStats reports the write queue of one durable backend.
+type TeeBackendStats struct {
+	Backend   int    `json:"backend"` // position in the tee; the hot backend is 0
+	Capacity  int    `json:"capacity"`
+	Queued    int    `json:"queued"`  // writes waiting to be applied
+	Dropped   uint64 `json:"dropped"` // writes discarded because the queue was full
+	Failed    uint64 `json:"failed"`  // writes the backend rejected
+	LastError string `json:"last_error,omitempty"`
+}
+
+// teeOp is an operation waiting in a durable backend's queue. done, if set,
+// receives its result.
+type teeOp struct {
+	run  func(Storage) error
+	done chan error
+}
+
+// teeWriter applies operations to one durable backend in queue order.
+type teeWriter struct {
+	index   int
+	backend Storage
+	queue   chan teeOp
+	stop    chan struct{}
+	stopped chan struct{}
+
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+	mu      sync.Mutex
+	lastErr string
+}
+
+func newTeeWriter(index int, backend Storage, size int) *teeWriter {
+	w := &teeWriter{
+		index:   index,
+		backend: backend,
+		queue:   make(chan teeOp, size),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *teeWriter) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case op := <-w.queue:
+			w.apply(op)
+		case <-w.stop:
+			// Apply what was queued before Close
+			for {
+				select {
+				case op := <-w.queue:
+					w.apply(op)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *teeWriter) apply(op teeOp) {
+	err := op.run(w.backend)
+	if op.done != nil {
+		op.done <- err
+		return
+	}
+	if err != nil {
+		w.failed.Add(1)
+		w.mu.Lock()
+		w.lastErr = err.Error()
+		w.mu.Unlock()
+	}
+}
+
+// enqueue queues a write without waiting, dropping it when the queue is full.
+func (w *teeWriter) enqueue(write func(Storage) error) {
+	select {
+	case w.queue <- teeOp{run: write}:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// do queues op and waits up to wait for its result.
+func (w *teeWriter) do(op func(Storage) error, wait time.Duration) error {
+	timer := time.NewTimer(max(wait, 0))
+	defer timer.Stop()
+
+	done := make(chan error, 1)
+	select {
+	case w.queue <- teeOp{run: op, done: done}:
+	case <-timer.C:
+		return errTeeBackendBehind
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return errTeeBackendBehind
+	}
+}
+
+// sync waits up to wait for the writes queued so far to be applied.
+func (w *teeWriter) sync(wait time.Duration) error {
+	return w.do(func(Storage) error { return nil }, wait)
+}
+
+// close applies the queued writes, waiting up to teeSyncWait.
+func (w *teeWriter) close() {
+	close(w.stop)
+	select {
+	case <-w.stopped:
+	case <-time.After(teeSyncWait):
+	}
+}
+
+func (w *teeWriter) stats() TeeBackendStats {
+	w.mu.Lock()
+	lastErr := w.lastErr
+	w.mu.Unlock()
+	return TeeBackendStats{
+		Backend:   w.index,
+		Capacity:  cap(w.queue),
+		Queued:    len(w.queue),
+		Dropped:   w.dropped.Load(),
+		Failed:    w.failed.Load(),
+		LastError: lastErr,
+	}
+}
+
+// Stats returns the write queue of each durable backend.
+func (t *TeeStorage) Stats() []TeeBackendStats {
+	stats := make([]TeeBackendStats, len(t.writers))
+	for i, w := range t.writers {
+		stats[i] = w.stats()
+	}
+	return stats
+}
+
+// --- Request Metrics ---
+
+// StoreRequest writes a request metric to every backend.
+func (t *TeeStorage) StoreRequest(m RequestMetric) error {
+	return t.fanOut(func(s Storage) error { return s.StoreRequest(m) })
+}
+
+// GetRequests returns requests matching the filter.
+func (t *TeeStorage) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
+	return teeRead(t.forRange(filter.TimeRange), func(s Storage) ([]RequestMetric, error) { return s.GetRequests(filter) })
+}
+
+// GetRouteStats returns aggregated stats per route within the time range.
+func (t *TeeStorage) GetRouteStats(timeRange TimeRange) ([]RouteStats, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]RouteStats, error) { return s.GetRouteStats(timeRange) })
+}
+
+// GetRouteDetail returns detailed stats for a specific route.
+func (t *TeeStorage) GetRouteDetail(method, path string, timeRange TimeRange) (*RouteDetail, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) (*RouteDetail, error) { return s.GetRouteDetail(method, path, timeRange) })
+}
+
+// --- Query Metrics ---
+
+// StoreQuery writes a query metric to every backend.
+func (t *TeeStorage) StoreQuery(m QueryMetric) error {
+	return t.fanOut(func(s Storage) error { return s.StoreQuery(m) })
+}
+
+// GetSlowQueries returns the most recent slow queries from the hot backend.
+func (t *TeeStorage) GetSlowQueries(threshold time.Duration, limit int) ([]QueryMetric, error) {
+	return teeRead(t.hotFirst(), func(s Storage) ([]QueryMetric, error) { return s.GetSlowQueries(threshold, limit) })
+}
+
+// GetQueryPatterns returns aggregated query patterns within the time range.
+func (t *TeeStorage) GetQueryPatterns(timeRange TimeRange) ([]QueryPattern, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]QueryPattern, error) { return s.GetQueryPatterns(timeRange) })
+}
+
+// GetN1Detections returns N+1 detections within the time range.
+func (t *TeeStorage) GetN1Detections(timeRange TimeRange) ([]N1Detection, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]N1Detection, error) { return s.GetN1Detections(timeRange) })
+}
+
+// StoreN1Detection writes an N+1 detection to every backend.
+func (t *TeeStorage) StoreN1Detection(d N1Detection) error {
+	return t.fanOut(func(s Storage) error { return s.StoreN1Detection(d) })
+}
+
+// --- Connection Pool ---
+
+// StorePoolStats writes a connection pool sample to every backend.
+func (t *TeeStorage) StorePoolStats(stats PoolStats) error {
+	if stats.Timestamp.IsZero() {
+		// Keep the sample identical across backends
+		stats.Timestamp = time.Now()
+	}
+	return t.fanOut(func(s Storage) error { return s.StorePoolStats(stats) })
+}
+
+// GetConnectionPoolStats returns the latest pool stats from the hot backend.
+func (t *TeeStorage) GetConnectionPoolStats() (*PoolStats, error) {
+	return teeRead(t.hotFirst(), func(s Storage) (*PoolStats, error) { return s.GetConnectionPoolStats() })
+}
+
+// GetPoolStatsHistory returns pool stats samples within the time range.
+func (t *TeeStorage) GetPoolStatsHistory(timeRange TimeRange) ([]PoolStats, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]PoolStats, error) { return s.GetPoolStatsHistory(timeRange) })
+}
+
+// --- Runtime Metrics ---
+
+// StoreRuntime writes a runtime sample to every backend.
+func (t *TeeStorage) StoreRuntime(m RuntimeMetric) error {
+	return t.fanOut(func(s Storage) error { return s.StoreRuntime(m) })
+}
+
+// GetRuntimeHistory returns runtime samples within the time range.
+func (t *TeeStorage) GetRuntimeHistory(timeRange TimeRange) ([]RuntimeMetric, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]RuntimeMetric, error) { return s.GetRuntimeHistory(timeRange) })
+}
+
+// --- Error Records ---
+
+// StoreError writes an error record to every backend.
+func (t *TeeStorage) StoreError(e ErrorRecord) error {
+	return t.fanOut(func(s Storage) error { return s.StoreError(e) })
+}
+
+// GetErrors returns errors matching the filter from the durable backends.
+func (t *TeeStorage) GetErrors(filter ErrorFilter) ([]ErrorRecord, error) {
+	return teeRead(t.durableFirst(), func(s Storage) ([]ErrorRecord, error) { return s.GetErrors(filter) })
+}
+
+// GetErrorGroups returns error groups from the durable backends.
+func (t *TeeStorage) GetErrorGroups(timeRange TimeRange) ([]ErrorGroup, error) {
+	return teeRead(t.durableFirst(), func(s Storage) ([]ErrorGroup, error) { return s.GetErrorGroups(timeRange) })
+}
+
+// UpdateError updates an error record in every backend that holds it.
+func (t *TeeStorage) UpdateError(id string, updates map[string]interface{}) error {
+	return t.fanOutAny(func(s Storage) error { return s.UpdateError(id, updates) })
+}
+
+// getErrorByID looks up an error record, durable backends first.
+func (t *TeeStorage) getErrorByID(id string) (*ErrorRecord, error) {
+	return teeRead(t.durableFirst(), func(s Storage) (*ErrorRecord, error) {
+		store, ok := s.(errorRecordStore)
+		if !ok {
+			return nil, fmt.Errorf("error lookup not supported by %T", s)
+		}
+		return store.getErrorByID(id)
+	})
+}
+
+// deleteError deletes an error record from every backend that holds it.
+func (t *TeeStorage) deleteError(id string) error {
+	return t.fanOutAny(func(s Storage) error {
+		store, ok := s.(errorRecordStore)
+		if !ok {
+			return fmt.Errorf("error deletion not supported by %T", s)
+		}
+		return store.deleteError(id)
+	})
+}
+
+// --- Health Results ---
+
+// StoreHealthResult writes a health check result to every backend.
+func (t *TeeStorage) StoreHealthResult(r HealthCheckResult) error {
+	return t.fanOut(func(s Storage) error { return s.StoreHealthResult(r) })
+}
+
+// GetHealthHistory returns recent results for a health check from the hot backend.
+func (t *TeeStorage) GetHealthHistory(name string, limit int) ([]HealthCheckResult, error) {
+	return teeRead(t.hotFirst(), func(s Storage) ([]HealthCheckResult, error) { return s.GetHealthHistory(name, limit) })
+}
+
+// GetLatestHealthResults returns the latest result per check from the hot backend.
+func (t *TeeStorage) GetLatestHealthResults() (map[string]HealthCheckResult, error) {
+	return teeRead(t.hotFirst(), func(s Storage) (map[string]HealthCheckResult, error) { return s.GetLatestHealthResults() })
+}
+
+// --- Alerts ---
+
+// StoreAlert writes an alert record to every backend.
+func (t *TeeStorage) StoreAlert(a AlertRecord) error {
+	return t.fanOut(func(s Storage) error { return s.StoreAlert(a) })
+}
+
+// GetAlerts returns alerts matching the filter from the durable backends.
+func (t *TeeStorage) GetAlerts(filter AlertFilter) ([]AlertRecord, error) {
+	return teeRead(t.durableFirst(), func(s Storage) ([]AlertRecord, error) { return s.GetAlerts(filter) })
+}
+
+// --- Dependencies ---
+
+// StoreDependencyMetric writes a dependency call to every backend.
+func (t *TeeStorage) StoreDependencyMetric(m DependencyMetric) error {
+	return t.fanOut(func(s Storage) error { return s.StoreDependencyMetric(m) })
+}
+
+// GetDependencyStats returns per-dependency stats within the time range.
+func (t *TeeStorage) GetDependencyStats(timeRange TimeRange) ([]DependencyStats, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]DependencyStats, error) { return s.GetDependencyStats(timeRange) })
+}
+
+// --- Overview ---
+
+// GetOverview computes the dashboard overview for the time range.
+func (t *TeeStorage) GetOverview(timeRange TimeRange) (*Overview, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) (*Overview, error) { return s.GetOverview(timeRange) })
+}
+
+// --- Traces ---
+
+// StoreSpans writes spans to every backend that keeps traces.
+func (t *TeeStorage) StoreSpans(spans []Span) error {
+	return t.fanOut(func(s Storage) error {
+		if ts, ok := s.(traceStore); ok {
+			return ts.StoreSpans(spans)
+		}
+		return nil
+	})
+}
+
+// GetTrace returns a trace's spans from the first backend that has them,
+// hot backend first.
+func (t *TeeStorage) GetTrace(traceID string) ([]Span, error) {
+	var errs []error
+	for _, s := range t.hotFirst() {
+		ts, ok := s.(traceStore)
+		if !ok {
+			continue
+		}
+		spans, err := ts.GetTrace(traceID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(spans) > 0 {
+			return spans, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// GetSpanStats returns per-name span stats within the time range.
+func (t *TeeStorage) GetSpanStats(timeRange TimeRange) ([]SpanStats, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]SpanStats, error) {
+		sr, ok := s.(spanStatsReader)
+		if !ok {
+			return nil, errors.New("storage does not keep span stats")
+		}
+		return sr.GetSpanStats(timeRange)
+	})
+}
+
+// --- Connections ---
+
+// StoreConnections writes connections to every backend that keeps them.
+func (t *TeeStorage) StoreConnections(conns []ConnectionMetric) error {
+	return t.fanOut(func(s Storage) error {
+		if cs, ok := s.(connectionStore); ok {
+			return cs.StoreConnections(conns)
+		}
+		return nil
+	})
+}
+
+// GetConnections returns the connections opened within the time range.
+func (t *TeeStorage) GetConnections(timeRange TimeRange) ([]ConnectionMetric, error) {
+	return teeRead(t.forRange(timeRange), func(s Storage) ([]ConnectionMetric, error) {
+		cs, ok := s.(connectionStore)
+		if !ok {
+			return nil, errors.New("storage does not keep connections")
+		}
+		return cs.GetConnections(timeRange)
+	})
+}
+
+// --- Batching ---
+
+// StoreBatch writes a batch to every backend.
+func (t *TeeStorage) StoreBatch(b MetricBatch) error {
+	// The ingester reuses the batch's slices once this returns
+	if len(t.writers) > 0 {
+		b = b.clone()
+	}
+	return t.fanOut(func(s Storage) error { return storeBatch(s, b) })
+}
+
+// --- Maintenance ---
+
+// Cleanup removes data older than the retention period from the durable
+// backends and data older than the hot window from the hot backend.
+func (t *TeeStorage) Cleanup(retention time.Duration) error {
+	_, err := t.Prune(uniformRetention(retention))
+	return err
+}
+
+// Prune applies retention to the durable backends and caps the hot backend
+// at the hot window. Counts are summed across backends.
+func (t *TeeStorage) Prune(retention RetentionConfig) (PruneResult, error) {
+	return t.sumAll(func(i int, s Storage) (PruneResult, error) {
+		r := retention
+		if i == 0 && t.hotWindow > 0 {
+			r = capRetention(retention, t.hotWindow)
+		}
+		return pruneStorage(s, r)
+	})
+}
+
+// DeleteSubject deletes a data subject's records from every backend. Counts
+// are summed across backends; a backend that cannot delete fails the call.
+func (t *TeeStorage) DeleteSubject(filter SubjectFilter) (PruneResult, error) {
+	return t.sumAll(func(i int, s Storage) (PruneResult, error) {
+		deleter, ok := s.(subjectDeleter)
+		if !ok {
+			return PruneResult{}, fmt.Errorf("subject deletion not supported by %T", s)
+		}
+		return deleter.DeleteSubject(filter)
+	})
+}
+
+// sumAll runs fn against every backend, durable ones after the writes
+// queued for them, and sums the counts.
+func (t *TeeStorage) sumAll(fn func(i int, s Storage) (PruneResult, error)) (PruneResult, error) {
+	var errs []error
+	result, err := fn(0, t.hot)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("tee backend 0: %w", err))
+	}
+	for _, w := range t.writers {
+		counted := make(chan PruneResult, 1)
+		err := w.do(func(s Storage) error {
+			r, err := fn(w.index, s)
+			counted <- r
+			return err
+		}, teeSyncWait)
+		if !errors.Is(err, errTeeBackendBehind) {
+			result = addPruneResults(result, <-counted)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tee backend %d: %w", w.index, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// Reset clears every backend, including the writes queued for it.
+func (t *TeeStorage) Reset() error {
+	return errors.Join(t.writeAll(func(s Storage) error { return s.Reset() })...)
+}
+
+// Close applies the queued writes and closes every backend.
+func (t *TeeStorage) Close() error {
+	for _, w := range t.writers {
+		w.close()
+	}
+	var errs []error
+	for i, s := range t.all {
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("tee backend %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneStorage applies retention to a backend, falling back to Cleanup with
+// the longest retention for backends without per-type pruning.
+func pruneStorage(s Storage, retention RetentionConfig) (PruneResult, error) {
+	if pruner, ok := s.(retentionPruner); ok {
+		return pruner.Prune(retention)
+	}
+	longest := retention.Requests
+	for _, d := range []time.Duration{retention.Queries, retention.Runtime, retention.Errors, retention.Alerts, retention.Health, retention.Dependencies} {
+		if d > longest {
+			longest = d
+		}
+	}
+	return PruneResult{}, s.Cleanup(longest)
+}
+
+// capRetention limits every retention period to max.
+func capRetention(r RetentionConfig, max time.Duration) RetentionConfig {
+	capped := func(d time.Duration) time.Duration {
+		if d <= 0 || d > max {
+			return max
+		}
+		return d
+	}
+	return RetentionConfig{
+		Requests:     capped(r.Requests),
+		Queries:      capped(r.Queries),
+		Runtime:      capped(r.Runtime),
+		Errors:       capped(r.Errors),
+		Alerts:       capped(r.Alerts),
+		Health:       capped(r.Health),
+		Dependencies: capped(r.Dependencies),
+	}
+}
+
+func addPruneResults(a, b PruneResult) PruneResult {
+	return PruneResult{
+		Requests:     a.Requests + b.Requests,
+		Queries:      a.Queries + b.Queries,
+		N1Detections: a.N1Detections + b.N1Detections,
+		Runtime:      a.Runtime + b.Runtime,
+		PoolStats:    a.PoolStats + b.PoolStats,
+		Errors:       a.Errors + b.Errors,
+		Alerts:       a.Alerts + b.Alerts,
+		Health:       a.Health + b.Health,
+		Dependencies: a.Dependencies + b.Dependencies,
+		Spans:        a.Spans + b.Spans,
+		Connections:  a.Connections + b.Connections,
+	}
+}
@@ -0,0 +1,221 @@
+package pulse
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// failingStorage is a MemoryStorage whose writes and reads can be made to fail.
+type failingStorage struct {
+	*MemoryStorage
+	failWrites bool
+	failReads  bool
+}
+
+var errBackendDown = errors.New("backend down")
+
+func (s *failingStorage) StoreRequest(m RequestMetric) error {
+	if s.failWrites {
+		return errBackendDown
+	}
+	return s.MemoryStorage.StoreRequest(m)
+}
+
+func (s *failingStorage) GetRequests(filter RequestFilter) ([]RequestMetric, error) {
+	if s.failReads {
+		return nil, errBackendDown
+	}
+	return s.MemoryStorage.GetRequests(filter)
+}
+
+func TestTeeStorage_RoutesReadsByRange(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := NewMemoryStorage("test")
+	tee := NewTeeStorage(hot, time.Hour, durable)
+	now := time.Now()
+
+	tee.StoreRequest(RequestMetric{Method: "GET", Path: "/both", Timestamp: now})
+	// Only the durable backend still holds older data
+	durable.StoreRequest(RequestMetric{Method: "GET", Path: "/old", Timestamp: now.Add(-3 * time.Hour)})
+
+	recent, _ := tee.GetRequests(RequestFilter{TimeRange: TimeRange{Start: now.Add(-30 * time.Minute), End: now}})
+	if len(recent) != 1 {
+		t.Fatalf("expected 1 recent request from hot, got %d", len(recent))
+	}
+
+	old, _ := tee.GetRequests(RequestFilter{TimeRange: TimeRange{Start: now.Add(-6 * time.Hour), End: now}})
+	if len(old) != 2 {
+		t.Fatalf("expected 2 requests from durable, got %d", len(old))
+	}
+
+	all, _ := tee.GetRequests(RequestFilter{})
+	if len(all) != 2 {
+		t.Fatalf("expected unbounded reads to use durable, got %d requests", len(all))
+	}
+}
+
+func TestTeeStorage_FailingBackendDoesNotBlockOthers(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := &failingStorage{MemoryStorage: NewMemoryStorage("test"), failWrites: true}
+	tee := NewTeeStorage(hot, time.Hour, durable)
+	defer tee.Close()
+
+	if err := tee.StoreRequest(RequestMetric{Method: "GET", Path: "/a", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("expected the hot write to succeed, got %v", err)
+	}
+	if hot.requests.Len() != 1 {
+		t.Fatalf("expected hot backend to be written, got %d requests", hot.requests.Len())
+	}
+
+	// Batches fall back per backend the same way
+	if err := tee.StoreBatch(MetricBatch{Requests: []RequestMetric{{Method: "GET", Path: "/b", Timestamp: time.Now()}}}); err != nil {
+		t.Fatalf("expected the hot batch write to succeed, got %v", err)
+	}
+	if hot.requests.Len() != 2 {
+		t.Fatalf("expected hot backend to receive the batch, got %d requests", hot.requests.Len())
+	}
+
+	// The durable failures are counted once applied
+	tee.writers[0].sync(time.Second)
+	stats := tee.Stats()
+	if len(stats) != 1 || stats[0].Backend != 1 || stats[0].Failed != 2 || stats[0].LastError != errBackendDown.Error() {
+		t.Fatalf("expected the durable failure to be counted, got %+v", stats)
+	}
+}
+
+// blockingStorage is a MemoryStorage whose request writes wait for release.
+type blockingStorage struct {
+	*MemoryStorage
+	release chan struct{}
+}
+
+func (s *blockingStorage) StoreRequest(m RequestMetric) error {
+	<-s.release
+	return s.MemoryStorage.StoreRequest(m)
+}
+
+func TestTeeStorage_HungBackendDropsWrites(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := &blockingStorage{MemoryStorage: NewMemoryStorage("test"), release: make(chan struct{})}
+	tee := NewTeeStorage(hot, time.Hour, durable)
+	tee.writers[0] = newTeeWriter(1, durable, 2)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			tee.StoreRequest(RequestMetric{Method: "GET", Path: "/a", Timestamp: time.Now()})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected writes to return while the durable backend hangs")
+	}
+	if hot.requests.Len() != 10 {
+		t.Fatalf("expected every write in the hot backend, got %d", hot.requests.Len())
+	}
+
+	// At most one write in progress and two queued
+	dropped := tee.Stats()[0].Dropped
+	if dropped < 7 {
+		t.Errorf("expected at least 7 dropped writes, got %d", dropped)
+	}
+
+	close(durable.release)
+	tee.Close()
+	if got := uint64(durable.requests.Len()); got+dropped != 10 {
+		t.Errorf("expected the %d queued writes applied on close, got %d", 10-dropped, got)
+	}
+}
+
+func TestTeeStorage_ReadFallsBack(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := &failingStorage{MemoryStorage: NewMemoryStorage("test"), failReads: true}
+	tee := NewTeeStorage(hot, time.Hour, durable)
+
+	tee.StoreRequest(RequestMetric{Method: "GET", Path: "/a", Timestamp: time.Now()})
+
+	reqs, err := tee.GetRequests(RequestFilter{})
+	if err != nil {
+		t.Fatalf("expected fallback to hot, got %v", err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+}
+
+func TestTeeStorage_ErrorsReadFromDurable(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := NewMemoryStorage("test")
+	tee := NewTeeStorage(hot, time.Hour, durable)
+	old := time.Now().Add(-2 * time.Hour)
+
+	tee.StoreError(ErrorRecord{ID: "e1", Fingerprint: "fp1", Count: 1, FirstSeen: old, LastSeen: old})
+	if _, err := tee.Prune(uniformRetention(24 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(hot.errors) != 0 {
+		t.Fatal("expected hot backend to be capped at the hot window")
+	}
+
+	// The group is gone from hot but still triaged and served from durable
+	if err := tee.UpdateError("e1", map[string]interface{}{"muted": true}); err != nil {
+		t.Fatalf("expected update to succeed on durable, got %v", err)
+	}
+	errs, _ := tee.GetErrors(ErrorFilter{})
+	if len(errs) != 1 || !errs[0].Muted {
+		t.Fatalf("expected muted error from durable, got %+v", errs)
+	}
+	if record, err := tee.getErrorByID("e1"); err != nil || record.ID != "e1" {
+		t.Fatalf("expected lookup from durable, got %v", err)
+	}
+
+	if err := tee.UpdateError("missing", map[string]interface{}{"muted": true}); err == nil {
+		t.Fatal("expected an error when no backend holds the record")
+	}
+}
+
+func TestTeeStorage_PruneCapsHotWindow(t *testing.T) {
+	hot := NewMemoryStorage("test")
+	durable := NewMemoryStorage("test")
+	tee := NewTeeStorage(hot, time.Hour, durable)
+
+	tee.StoreRequest(RequestMetric{Method: "GET", Path: "/a", Timestamp: time.Now().Add(-2 * time.Hour)})
+	tee.StoreRequest(RequestMetric{Method: "GET", Path: "/b", Timestamp: time.Now()})
+
+	result, err := tee.Prune(uniformRetention(24 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Requests != 1 {
+		t.Fatalf("expected 1 request pruned from hot, got %d", result.Requests)
+	}
+	if hot.requests.Len() != 1 || durable.requests.Len() != 2 {
+		t.Fatalf("expected hot=1 durable=2, got hot=%d durable=%d", hot.requests.Len(), durable.requests.Len())
+	}
+}
+
+func TestMount_SelectsTeeStorage(t *testing.T) {
+	_, p := setupTestRouter(Config{
+		Storage: StorageConfig{
+			Driver:    SQLite,
+			DSN:       filepath.Join(t.TempDir(), "pulse.db"),
+			HotWindow: time.Hour,
+		},
+	})
+	defer p.Shutdown()
+
+	tee, ok := p.GetStorage().(*TeeStorage)
+	if !ok {
+		t.Fatalf("expected *TeeStorage, got %T", p.GetStorage())
+	}
+	if _, ok := tee.hot.(*MemoryStorage); !ok {
+		t.Errorf("expected memory hot backend, got %T", tee.hot)
+	}
+	if _, ok := tee.durable[0].(*SQLiteStorage); !ok {
+		t.Errorf("expected SQLite durable backend, got %T", tee.durable[0])
+	}
+}
@@ -0,0 +1,972 @@
+// Package storagetest checks pulse.Storage implementations against the
+// behavior of the built-in backends.
+//
+// A custom backend runs the suite from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.RunConformance(t, func(t *testing.T) pulse.Storage {
+//			s, err := NewMyStorage(t.TempDir())
+//			if err != nil {
+//				t.Fatal(err)
+//			}
+//			return s
+//		})
+//	}
+package storagetest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MUKE-coder/pulse/pulse"
+)
+
+// Factory returns a new, empty Storage. It is called once per subtest;
+// RunConformance closes the storage when the subtest ends.
+type Factory func(t *testing.T) pulse.Storage
+
+// RunConformance runs the conformance suite against storages created by
+// newStorage. Each check is a subtest, so failures name the behavior that
+// differs.
+func RunConformance(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s pulse.Storage)
+	}{
+		{"GetRequests/Filters", testGetRequestsFilters},
+		{"GetRequests/TimeRangeInclusive", testGetRequestsTimeRange},
+		{"GetRequests/Pagination", testGetRequestsPagination},
+		{"StoreError/Deduplicates", testStoreErrorDeduplicates},
+		{"StoreError/MergesInstances", testStoreErrorMergesInstances},
+		{"StoreError/MergesTags", testStoreErrorMergesTags},
+		{"UpdateError", testUpdateError},
+		{"UpdateError/SurvivesDeduplication", testUpdateErrorSurvivesDedup},
+		{"Cleanup/Boundaries", testCleanupBoundaries},
+		{"GetOverview", testGetOverview},
+		{"GetRouteStats/ExtrapolatesSampled", testRouteStatsExtrapolatesSampled},
+		{"GetRouteDetail/Phases", testRouteDetailPhases},
+		{"GetRouteStats/TimeShares", testRouteStatsTimeShares},
+		{"ConcurrentWriters", testConcurrentWriters},
+		{"Traces", testTraces},
+		{"Connections", testConnections},
+		{"DeleteSubject", testDeleteSubject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStorage(t)
+			t.Cleanup(func() { s.Close() })
+			tt.fn(t, s)
+		})
+	}
+}
+
+// --- Requests ---
+
+func testGetRequestsFilters(t *testing.T, s pulse.Storage) {
+	now := time.Now()
+	reqs := []pulse.RequestMetric{
+		{Method: "GET", Path: "/users", StatusCode: 200, Latency: 10 * time.Millisecond},
+		{Method: "GET", Path: "/users", StatusCode: 500, Latency: 80 * time.Millisecond, Tags: map[string]string{"tenant": "acme", "plan": "pro"}},
+		{Method: "POST", Path: "/users", StatusCode: 201, Latency: 50 * time.Millisecond, Tags: map[string]string{"tenant": "acme_co"}, TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			Capture: &pulse.RequestCapture{RequestBody: `{"name":"ada"}`, ResponseHeaders: map[string]string{"Location": "/users/7"}}},
+		{Method: "GET", Path: "/orders", StatusCode: 200, Latency: 50 * time.Millisecond, Tags: map[string]string{"tenant": "acme", "plan": "free"}},
+		{Method: "DELETE", Path: "/orders", StatusCode: 404, Latency: 5 * time.Millisecond, Instance: "web-2"},
+	}
+	for i, m := range reqs {
+		m.Timestamp = now.Add(time.Duration(i-len(reqs)) * time.Second)
+		mustStore(t, s.StoreRequest(m))
+	}
+
+	tests := []struct {
+		name   string
+		filter pulse.RequestFilter
+		want   []string
+	}{
+		{"none", pulse.RequestFilter{}, []string{"GET /users 200", "GET /users 500", "POST /users 201", "GET /orders 200", "DELETE /orders 404"}},
+		{"method", pulse.RequestFilter{Method: "GET"}, []string{"GET /users 200", "GET /users 500", "GET /orders 200"}},
+		{"path", pulse.RequestFilter{Path: "/orders"}, []string{"GET /orders 200", "DELETE /orders 404"}},
+		{"status", pulse.RequestFilter{StatusCode: 200}, []string{"GET /users 200", "GET /orders 200"}},
+		{"min latency inclusive", pulse.RequestFilter{MinLatency: 50 * time.Millisecond}, []string{"GET /users 500", "POST /users 201", "GET /orders 200"}},
+		{"combined", pulse.RequestFilter{Method: "GET", Path: "/users", MinLatency: time.Millisecond}, []string{"GET /users 200", "GET /users 500"}},
+		{"instance", pulse.RequestFilter{Instance: "web-2"}, []string{"DELETE /orders 404"}},
+		{"tag", pulse.RequestFilter{Tags: map[string]string{"tenant": "acme"}}, []string{"GET /users 500", "GET /orders 200"}},
+		{"tags", pulse.RequestFilter{Tags: map[string]string{"tenant": "acme", "plan": "pro"}}, []string{"GET /users 500"}},
+		{"tag wildcard characters", pulse.RequestFilter{Tags: map[string]string{"tenant": "acme%"}}, nil},
+		{"trace", pulse.RequestFilter{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"}, []string{"POST /users 201"}},
+		{"no match", pulse.RequestFilter{Method: "PATCH"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.GetRequests(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: GetRequests: %v", tt.name, err)
+		}
+		assertRequests(t, tt.name, got, tt.want)
+	}
+
+	got, _ := s.GetRequests(pulse.RequestFilter{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"})
+	if len(got) != 1 || got[0].Capture == nil || got[0].Capture.RequestBody != `{"name":"ada"}` || got[0].Capture.ResponseHeaders["Location"] != "/users/7" {
+		t.Errorf("expected the captured payloads to round-trip, got %+v", got)
+	}
+}
+
+func testGetRequestsTimeRange(t *testing.T, s pulse.Storage) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{
+			Method:     "GET",
+			Path:       fmt.Sprintf("/r%d", i),
+			StatusCode: 200,
+			Timestamp:  base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	got, err := s.GetRequests(pulse.RequestFilter{
+		TimeRange: pulse.TimeRange{Start: base.Add(time.Minute), End: base.Add(3 * time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, "bounded", got, []string{"GET /r1 200", "GET /r2 200", "GET /r3 200"})
+
+	got, err = s.GetRequests(pulse.RequestFilter{
+		TimeRange: pulse.TimeRange{Start: base.Add(3 * time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, "open end", got, []string{"GET /r3 200", "GET /r4 200"})
+}
+
+func testGetRequestsPagination(t *testing.T, s pulse.Storage) {
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{
+			Method:     "GET",
+			Path:       fmt.Sprintf("/r%d", i),
+			StatusCode: 200,
+			Timestamp:  base.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          []string
+	}{
+		{"limit", 3, 0, []string{"GET /r0 200", "GET /r1 200", "GET /r2 200"}},
+		{"limit and offset", 3, 4, []string{"GET /r4 200", "GET /r5 200", "GET /r6 200"}},
+		{"offset only", 0, 8, []string{"GET /r8 200", "GET /r9 200"}},
+		{"limit past end", 5, 8, []string{"GET /r8 200", "GET /r9 200"}},
+		{"offset past end", 5, 10, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.GetRequests(pulse.RequestFilter{Limit: tt.limit, Offset: tt.offset})
+		if err != nil {
+			t.Fatalf("%s: GetRequests: %v", tt.name, err)
+		}
+		assertRequests(t, tt.name, got, tt.want)
+	}
+}
+
+// --- Errors ---
+
+func testStoreErrorDeduplicates(t *testing.T, s pulse.Storage) {
+	first := time.Now().Add(-10 * time.Minute)
+	last := time.Now().Add(-time.Minute)
+
+	mustStore(t, s.StoreError(newError("err-1", "fp-1", first, "stack-1")))
+	mustStore(t, s.StoreError(newError("err-2", "fp-1", first.Add(time.Minute), "")))
+	mustStore(t, s.StoreError(newError("err-3", "fp-1", last, "stack-3")))
+	mustStore(t, s.StoreError(newError("err-4", "fp-2", last, "")))
+
+	errs, err := s.GetErrors(pulse.ErrorFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 error groups, got %d", len(errs))
+	}
+
+	e := findError(t, errs, "fp-1")
+	if e.ID != "err-1" {
+		t.Errorf("expected the first record's ID to be kept, got %q", e.ID)
+	}
+	if e.Count != 3 {
+		t.Errorf("expected count 3, got %d", e.Count)
+	}
+	if !e.FirstSeen.Equal(first) {
+		t.Errorf("expected FirstSeen %v to be kept, got %v", first, e.FirstSeen)
+	}
+	if !e.LastSeen.Equal(last) {
+		t.Errorf("expected LastSeen %v, got %v", last, e.LastSeen)
+	}
+	if e.StackTrace != "stack-3" {
+		t.Errorf("expected the latest non-empty stack trace, got %q", e.StackTrace)
+	}
+
+	if e := findError(t, errs, "fp-2"); e.Count != 1 {
+		t.Errorf("expected fp-2 count 1, got %d", e.Count)
+	}
+}
+
+func testStoreErrorMergesInstances(t *testing.T, s pulse.Storage) {
+	now := time.Now()
+	for i, instance := range []string{"web-1", "web-2", "web-1"} {
+		e := newError(fmt.Sprintf("err-%d", i), "fp-1", now, "")
+		e.Instances = []string{instance}
+		mustStore(t, s.StoreError(e))
+	}
+
+	e := getError(t, s, "fp-1")
+	if len(e.Instances) != 2 || e.Instances[0] != "web-1" || e.Instances[1] != "web-2" {
+		t.Errorf("expected instances [web-1 web-2], got %v", e.Instances)
+	}
+
+	for _, tt := range []struct {
+		instance string
+		want     int
+	}{{"web-2", 1}, {"web-3", 0}} {
+		errs, err := s.GetErrors(pulse.ErrorFilter{Instance: tt.instance})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != tt.want {
+			t.Errorf("instance %s: expected %d errors, got %d", tt.instance, tt.want, len(errs))
+		}
+	}
+}
+
+func testStoreErrorMergesTags(t *testing.T, s pulse.Storage) {
+	now := time.Now()
+	for i, tenant := range []string{"acme", "globex", "acme"} {
+		e := newError(fmt.Sprintf("err-%d", i), "fp-1", now, "")
+		e.Tags = map[string][]string{"tenant": {tenant}}
+		mustStore(t, s.StoreError(e))
+	}
+
+	e := getError(t, s, "fp-1")
+	if got := e.Tags["tenant"]; len(got) != 2 || got[0] != "acme" || got[1] != "globex" {
+		t.Errorf("expected tenant values [acme globex], got %v", got)
+	}
+
+	for _, tt := range []struct {
+		tenant string
+		want   int
+	}{{"globex", 1}, {"initech", 0}} {
+		errs, err := s.GetErrors(pulse.ErrorFilter{Tags: map[string]string{"tenant": tt.tenant}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != tt.want {
+			t.Errorf("tenant %s: expected %d errors, got %d", tt.tenant, tt.want, len(errs))
+		}
+	}
+}
+
+func testUpdateError(t *testing.T, s pulse.Storage) {
+	now := time.Now()
+	mustStore(t, s.StoreError(newError("err-1", "fp-1", now, "")))
+	mustStore(t, s.StoreError(newError("err-2", "fp-2", now, "")))
+
+	if err := s.UpdateError("err-1", map[string]interface{}{"muted": true}); err != nil {
+		t.Fatal(err)
+	}
+	e := getError(t, s, "fp-1")
+	if !e.Muted || e.Resolved {
+		t.Fatalf("expected muted only, got muted=%v resolved=%v", e.Muted, e.Resolved)
+	}
+
+	if err := s.UpdateError("err-1", map[string]interface{}{"resolved": true, "muted": false}); err != nil {
+		t.Fatal(err)
+	}
+	e = getError(t, s, "fp-1")
+	if e.Muted || !e.Resolved {
+		t.Fatalf("expected resolved only, got muted=%v resolved=%v", e.Muted, e.Resolved)
+	}
+
+	// Non-bool values and unknown fields are ignored
+	if err := s.UpdateError("err-1", map[string]interface{}{"resolved": "no", "count": 99}); err != nil {
+		t.Fatal(err)
+	}
+	e = getError(t, s, "fp-1")
+	if !e.Resolved || e.Count != 1 {
+		t.Fatalf("expected record unchanged, got resolved=%v count=%d", e.Resolved, e.Count)
+	}
+
+	if e := getError(t, s, "fp-2"); e.Muted || e.Resolved {
+		t.Error("expected other records to be untouched")
+	}
+
+	if err := s.UpdateError("missing", map[string]interface{}{"muted": true}); err == nil {
+		t.Error("expected an error for an unknown ID")
+	}
+
+	muted := true
+	errs, err := s.GetErrors(pulse.ErrorFilter{Muted: &muted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected no muted errors, got %d", len(errs))
+	}
+	resolved := true
+	errs, err = s.GetErrors(pulse.ErrorFilter{Resolved: &resolved})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Fingerprint != "fp-1" {
+		t.Errorf("expected only fp-1 to be resolved, got %d records", len(errs))
+	}
+}
+
+func testUpdateErrorSurvivesDedup(t *testing.T, s pulse.Storage) {
+	now := time.Now()
+	mustStore(t, s.StoreError(newError("err-1", "fp-1", now.Add(-time.Minute), "")))
+	if err := s.UpdateError("err-1", map[string]interface{}{"muted": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repeat occurrence arrives unmuted; the group keeps its triage state
+	mustStore(t, s.StoreError(newError("err-2", "fp-1", now, "")))
+
+	e := getError(t, s, "fp-1")
+	if !e.Muted {
+		t.Error("expected muted flag to survive deduplication")
+	}
+	if e.Count != 2 {
+		t.Errorf("expected count 2, got %d", e.Count)
+	}
+}
+
+// --- Traces ---
+
+// traceStore is implemented by backends that keep trace spans.
+type traceStore interface {
+	StoreSpans(spans []pulse.Span) error
+	GetTrace(traceID string) ([]pulse.Span, error)
+}
+
+// spanStatsReader is implemented by backends that aggregate span latencies.
+type spanStatsReader interface {
+	GetSpanStats(timeRange pulse.TimeRange) ([]pulse.SpanStats, error)
+}
+
+func testTraces(t *testing.T, s pulse.Storage) {
+	ts, ok := s.(traceStore)
+	if !ok {
+		t.Skip("storage does not keep traces")
+	}
+
+	now := time.Now()
+	mustStore(t, ts.StoreSpans([]pulse.Span{
+		{TraceID: "trace-old", SpanID: "d", Name: "old", StartTime: now.Add(-2 * time.Hour)},
+		{TraceID: "trace-1", SpanID: "b", ParentID: "a", Name: "child", StartTime: now.Add(-time.Second), Duration: time.Millisecond},
+		{TraceID: "trace-1", SpanID: "a", Name: "root", StartTime: now.Add(-2 * time.Second), Duration: 2 * time.Second,
+			Attributes: map[string]string{"http.route": "/users"}},
+		{TraceID: "trace-2", SpanID: "c", Name: "other", StartTime: now},
+	}))
+
+	spans, err := ts.GetTrace("trace-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 2 || spans[0].SpanID != "a" || spans[1].SpanID != "b" {
+		t.Fatalf("expected spans a, b ordered by start time, got %+v", spans)
+	}
+	if spans[0].Attributes["http.route"] != "/users" || spans[1].ParentID != "a" {
+		t.Errorf("expected span fields to round-trip, got %+v", spans)
+	}
+
+	if spans, err := ts.GetTrace("missing"); err != nil || len(spans) != 0 {
+		t.Errorf("expected no spans for an unknown trace, got %d (%v)", len(spans), err)
+	}
+
+	if sr, ok := s.(spanStatsReader); ok {
+		stats, err := sr.GetSpanStats(pulse.TimeRange{Start: now.Add(-time.Minute), End: now.Add(time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats) != 3 || stats[0].Name != "root" || stats[0].Count != 1 || stats[0].MaxLatency != 2*time.Second {
+			t.Errorf("expected stats for root, child and other ordered by total duration, got %+v", stats)
+		}
+	}
+
+	if err := s.Cleanup(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if spans, _ := ts.GetTrace("trace-old"); len(spans) != 0 {
+		t.Errorf("expected expired spans to be cleaned up, got %d", len(spans))
+	}
+	if spans, _ := ts.GetTrace("trace-2"); len(spans) != 1 {
+		t.Errorf("expected recent spans to survive cleanup, got %d", len(spans))
+	}
+}
+
+// --- Connections ---
+
+// connectionStore is implemented by backends that keep long-lived connections.
+type connectionStore interface {
+	StoreConnections(conns []pulse.ConnectionMetric) error
+	GetConnections(timeRange pulse.TimeRange) ([]pulse.ConnectionMetric, error)
+}
+
+func testConnections(t *testing.T, s pulse.Storage) {
+	cs, ok := s.(connectionStore)
+	if !ok {
+		t.Skip("storage does not keep connections")
+	}
+
+	now := time.Now()
+	mustStore(t, cs.StoreConnections([]pulse.ConnectionMetric{
+		{Kind: pulse.ConnectionWebSocket, Method: "GET", Path: "/ws", Duration: time.Hour, Messages: 40, Timestamp: now.Add(-2 * time.Hour)},
+		{Kind: pulse.ConnectionSSE, Method: "GET", Path: "/events", Duration: time.Minute, BytesSent: 512, Timestamp: now.Add(-time.Second)},
+		{Kind: pulse.ConnectionWebSocket, Method: "GET", Path: "/ws", Duration: 5 * time.Minute, Messages: 12, TTFB: time.Millisecond, Timestamp: now},
+	}))
+
+	conns, err := cs.GetConnections(pulse.TimeRange{Start: now.Add(-time.Minute), End: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conns) != 2 || conns[0].Path != "/events" || conns[1].Path != "/ws" {
+		t.Fatalf("expected /events and /ws ordered by open time, got %+v", conns)
+	}
+	if conns[0].Kind != pulse.ConnectionSSE || conns[0].BytesSent != 512 || conns[1].Messages != 12 || conns[1].TTFB != time.Millisecond {
+		t.Errorf("expected connection fields to round-trip, got %+v", conns)
+	}
+
+	if err := s.Cleanup(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	all, _ := cs.GetConnections(pulse.TimeRange{Start: now.Add(-3 * time.Hour), End: now})
+	if len(all) != 2 {
+		t.Errorf("expected the expired connection to be cleaned up, got %d", len(all))
+	}
+}
+
+// --- Data Subjects ---
+
+// subjectDeleter is implemented by backends that can erase one data
+// subject's records.
+type subjectDeleter interface {
+	DeleteSubject(filter pulse.SubjectFilter) (pulse.PruneResult, error)
+}
+
+func testDeleteSubject(t *testing.T, s pulse.Storage) {
+	sd, ok := s.(subjectDeleter)
+	if !ok {
+		t.Skip("storage does not delete data subjects")
+	}
+
+	now := time.Now()
+	for _, m := range []pulse.RequestMetric{
+		{Method: "GET", Path: "/a", StatusCode: 200, ClientIP: "10.0.0.1", TraceID: "t1", Tags: map[string]string{"user": "1"}, Timestamp: now},
+		{Method: "GET", Path: "/b", StatusCode: 200, ClientIP: "10.0.0.2", TraceID: "t2", Tags: map[string]string{"user": "2"}, Timestamp: now},
+		{Method: "GET", Path: "/c", StatusCode: 500, ClientIP: "10.0.0.1", TraceID: "t3", Tags: map[string]string{"user": "2"}, Timestamp: now},
+	} {
+		mustStore(t, s.StoreRequest(m))
+	}
+	mustStore(t, s.StoreQuery(pulse.QueryMetric{SQL: "SELECT 1", RequestTraceID: "t1", Timestamp: now}))
+	mustStore(t, s.StoreQuery(pulse.QueryMetric{SQL: "SELECT 2", RequestTraceID: "t2", Timestamp: now}))
+	mustStore(t, s.StoreDependencyMetric(pulse.DependencyMetric{Name: "billing", TraceID: "t3", Timestamp: now}))
+
+	first := newError("e1", "fp1", now, "")
+	first.RequestContext = &pulse.RequestContext{ClientIP: "10.0.0.1", TraceID: "t1"}
+	first.TraceIDs = []string{"t1"}
+	second := newError("e2", "fp2", now, "")
+	second.RequestContext = &pulse.RequestContext{ClientIP: "10.0.0.2", TraceID: "t2"}
+	second.Tags = map[string][]string{"user": {"1", "2"}}
+	second.TraceIDs = []string{"t2", "t9"}
+	third := newError("e3", "fp3", now, "")
+	fourth := newError("e4", "fp4", now, "")
+	fourth.RequestContext = &pulse.RequestContext{ClientIP: "10.0.0.3", TraceID: "t5"}
+	fourth.TraceIDs = []string{"t4", "t5"}
+	for _, e := range []pulse.ErrorRecord{first, second, third, fourth} {
+		mustStore(t, s.StoreError(e))
+	}
+
+	ts, traces := s.(traceStore)
+	if traces {
+		mustStore(t, ts.StoreSpans([]pulse.Span{
+			{TraceID: "t1", SpanID: "a", Name: "root", StartTime: now},
+			{TraceID: "t2", SpanID: "b", Name: "root", StartTime: now},
+			{TraceID: "ws", SpanID: "c", Name: "root", StartTime: now},
+		}))
+	}
+	cs, conns := s.(connectionStore)
+	if conns {
+		mustStore(t, cs.StoreConnections([]pulse.ConnectionMetric{
+			{Kind: pulse.ConnectionWebSocket, Path: "/ws", ClientIP: "10.0.0.1", TraceID: "ws", Timestamp: now},
+			{Kind: pulse.ConnectionSSE, Path: "/events", ClientIP: "10.0.0.2", Timestamp: now},
+		}))
+	}
+
+	if deleted, err := sd.DeleteSubject(pulse.SubjectFilter{}); err != nil || deleted.Total() != 0 {
+		t.Fatalf("expected an empty filter to delete nothing, got %+v (%v)", deleted, err)
+	}
+
+	deleted, err := sd.DeleteSubject(pulse.SubjectFilter{ClientIP: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Requests == 0 || deleted.Errors == 0 || deleted.Queries == 0 || deleted.Dependencies == 0 {
+		t.Errorf("expected requests, errors, queries and dependencies deleted, got %+v", deleted)
+	}
+	reqs, _ := s.GetRequests(pulse.RequestFilter{})
+	assertRequests(t, "after deleting the client IP", reqs, []string{"GET /b 200"})
+	errs, _ := s.GetErrors(pulse.ErrorFilter{})
+	if len(errs) != 3 {
+		t.Errorf("expected the error seen only from the client IP deleted, got %d errors", len(errs))
+	}
+	queries, _ := s.GetSlowQueries(0, 10)
+	if len(queries) != 1 || queries[0].RequestTraceID != "t2" {
+		t.Errorf("expected the queries of the deleted requests gone, got %+v", queries)
+	}
+	if traces {
+		if spans, _ := ts.GetTrace("t1"); len(spans) != 0 {
+			t.Errorf("expected the spans of a deleted request gone, got %d", len(spans))
+		}
+		if spans, _ := ts.GetTrace("ws"); conns && len(spans) != 0 {
+			t.Errorf("expected the spans of a deleted connection gone, got %d", len(spans))
+		}
+	}
+	if conns {
+		remaining, _ := cs.GetConnections(pulse.TimeRange{Start: now.Add(-time.Minute), End: now})
+		if len(remaining) != 1 || remaining[0].ClientIP != "10.0.0.2" {
+			t.Errorf("expected only the other client's connection left, got %+v", remaining)
+		}
+	}
+
+	// A tag is removed from the error groups shared with other subjects
+	if deleted, err := sd.DeleteSubject(pulse.SubjectFilter{Tags: map[string]string{"user": "2"}}); err != nil || deleted.Errors == 0 {
+		t.Fatalf("expected an error group changed, got %+v (%v)", deleted, err)
+	}
+	reqs, _ = s.GetRequests(pulse.RequestFilter{})
+	assertRequests(t, "after deleting the tag", reqs, nil)
+	shared := getError(t, s, "fp2")
+	if len(shared.Tags["user"]) != 1 || shared.Tags["user"][0] != "1" || shared.RequestContext != nil || shared.Count != 1 {
+		t.Errorf("expected only the tag value and request context removed, got %+v", shared)
+	}
+	if traces {
+		if spans, _ := ts.GetTrace("t2"); len(spans) != 0 {
+			t.Errorf("expected the spans of the tagged request gone, got %d", len(spans))
+		}
+	}
+
+	// A trace ID is removed from its error groups, which are deleted once
+	// nothing else is left
+	if deleted, err := sd.DeleteSubject(pulse.SubjectFilter{TraceID: "t4"}); err != nil || deleted.Errors == 0 {
+		t.Fatalf("expected an error group changed, got %+v (%v)", deleted, err)
+	}
+	if e := getError(t, s, "fp4"); len(e.TraceIDs) != 1 || e.RequestContext == nil {
+		t.Errorf("expected another trace's request context kept, got %+v", e)
+	}
+	if _, err := sd.DeleteSubject(pulse.SubjectFilter{TraceID: "t5"}); err != nil {
+		t.Fatal(err)
+	}
+	errs, _ = s.GetErrors(pulse.ErrorFilter{})
+	if len(errs) != 2 {
+		t.Errorf("expected the error group left empty deleted, got %+v", errs)
+	}
+
+	if deleted, err := sd.DeleteSubject(pulse.SubjectFilter{TraceID: "unknown"}); err != nil || deleted.Total() != 0 {
+		t.Errorf("expected an unknown trace to delete nothing, got %+v (%v)", deleted, err)
+	}
+}
+
+// --- Maintenance ---
+
+func testCleanupBoundaries(t *testing.T, s pulse.Storage) {
+	const retention = time.Hour
+	now := time.Now()
+	expired := now.Add(-retention - time.Minute)
+	kept := now.Add(-retention + time.Minute)
+
+	for _, ts := range []time.Time{expired, kept} {
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/r", StatusCode: 200, Timestamp: ts}))
+		mustStore(t, s.StoreQuery(pulse.QueryMetric{SQL: "SELECT 1", NormalizedSQL: "SELECT ?", Operation: "SELECT", Duration: time.Second, Timestamp: ts}))
+		mustStore(t, s.StoreRuntime(pulse.RuntimeMetric{NumGoroutine: 1, Timestamp: ts}))
+		mustStore(t, s.StoreDependencyMetric(pulse.DependencyMetric{Name: "api", StatusCode: 200, Timestamp: ts}))
+		mustStore(t, s.StoreHealthResult(pulse.HealthCheckResult{Name: "db", Status: "healthy", Timestamp: ts}))
+		mustStore(t, s.StoreAlert(pulse.AlertRecord{ID: "alert-" + ts.String(), State: pulse.AlertStateFiring, FiredAt: ts}))
+	}
+
+	// Errors expire by LastSeen, so an old group that recurred is kept
+	mustStore(t, s.StoreError(newError("err-old", "fp-old", expired, "")))
+	recurring := newError("err-recurring", "fp-recurring", expired, "")
+	recurring.LastSeen = kept
+	mustStore(t, s.StoreError(recurring))
+
+	if err := s.Cleanup(retention); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	all := pulse.TimeRange{Start: now.Add(-24 * time.Hour), End: now.Add(time.Minute)}
+
+	reqs, _ := s.GetRequests(pulse.RequestFilter{})
+	if len(reqs) != 1 || !reqs[0].Timestamp.Equal(kept) {
+		t.Errorf("requests: expected only the sample inside retention, got %d", len(reqs))
+	}
+	queries, _ := s.GetSlowQueries(0, 0)
+	if len(queries) != 1 {
+		t.Errorf("queries: expected 1 after cleanup, got %d", len(queries))
+	}
+	runtime, _ := s.GetRuntimeHistory(all)
+	if len(runtime) != 1 {
+		t.Errorf("runtime: expected 1 after cleanup, got %d", len(runtime))
+	}
+	deps, _ := s.GetDependencyStats(all)
+	if len(deps) != 1 || deps[0].RequestCount != 1 {
+		t.Errorf("dependencies: expected 1 call after cleanup, got %+v", deps)
+	}
+	health, _ := s.GetHealthHistory("db", 10)
+	if len(health) != 1 {
+		t.Errorf("health: expected 1 after cleanup, got %d", len(health))
+	}
+	alerts, _ := s.GetAlerts(pulse.AlertFilter{})
+	if len(alerts) != 1 {
+		t.Errorf("alerts: expected 1 after cleanup, got %d", len(alerts))
+	}
+
+	errs, _ := s.GetErrors(pulse.ErrorFilter{})
+	if len(errs) != 1 || errs[0].Fingerprint != "fp-recurring" {
+		t.Errorf("errors: expected only fp-recurring after cleanup, got %d records", len(errs))
+	}
+}
+
+func testGetOverview(t *testing.T, s pulse.Storage) {
+	end := time.Now()
+	tr := pulse.TimeRange{Start: end.Add(-10 * time.Minute), End: end}
+
+	var latencies []time.Duration
+	for i := 1; i <= 10; i++ {
+		status := 200
+		switch i {
+		case 4:
+			status = 399
+		case 7:
+			status = 400
+		case 9, 10:
+			status = 503
+		}
+		latency := time.Duration(i) * 10 * time.Millisecond
+		latencies = append(latencies, latency)
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{
+			Method:     "GET",
+			Path:       fmt.Sprintf("/route-%d", i%3),
+			StatusCode: status,
+			Latency:    latency,
+			Timestamp:  end.Add(-time.Duration(i) * 30 * time.Second),
+		}))
+	}
+	// Outside the range
+	mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/old", StatusCode: 500, Latency: time.Second, Timestamp: end.Add(-time.Hour)}))
+
+	mustStore(t, s.StoreRuntime(pulse.RuntimeMetric{NumGoroutine: 5, HeapAlloc: 1 << 20, Timestamp: end.Add(-2 * time.Minute)}))
+	mustStore(t, s.StoreRuntime(pulse.RuntimeMetric{NumGoroutine: 42, HeapAlloc: 8 << 20, Timestamp: end.Add(-time.Minute)}))
+
+	mustStore(t, s.StoreAlert(pulse.AlertRecord{ID: "a1", State: pulse.AlertStateFiring, FiredAt: end}))
+	mustStore(t, s.StoreAlert(pulse.AlertRecord{ID: "a2", State: pulse.AlertStateFiring, FiredAt: end}))
+	mustStore(t, s.StoreAlert(pulse.AlertRecord{ID: "a3", State: pulse.AlertStateResolved, FiredAt: end}))
+
+	ov, err := s.GetOverview(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ov == nil {
+		t.Fatal("expected an overview")
+	}
+
+	if ov.TotalRequests != 10 {
+		t.Errorf("TotalRequests: expected 10, got %d", ov.TotalRequests)
+	}
+	if ov.TotalErrors != 3 {
+		t.Errorf("TotalErrors: expected 3 (status >= 400), got %d", ov.TotalErrors)
+	}
+	if !approx(ov.ErrorRate, 30) {
+		t.Errorf("ErrorRate: expected 30, got %f", ov.ErrorRate)
+	}
+	if !approx(ov.RPM, 1) {
+		t.Errorf("RPM: expected 1 (10 requests in 10 minutes), got %f", ov.RPM)
+	}
+	if ov.AvgLatency != 55*time.Millisecond {
+		t.Errorf("AvgLatency: expected 55ms, got %v", ov.AvgLatency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if want := pulse.Percentile(latencies, 95); ov.P95Latency != want {
+		t.Errorf("P95Latency: expected %v, got %v", want, ov.P95Latency)
+	}
+	if ov.ActiveGoroutines != 42 || !approx(ov.HeapAllocMB, 8) {
+		t.Errorf("expected the latest runtime sample, got goroutines=%d heap=%.2fMB", ov.ActiveGoroutines, ov.HeapAllocMB)
+	}
+	if ov.ActiveAlerts != 2 {
+		t.Errorf("ActiveAlerts: expected 2, got %d", ov.ActiveAlerts)
+	}
+
+	var routeTotal int64
+	for _, r := range ov.TopRoutes {
+		routeTotal += r.RequestCount
+	}
+	if len(ov.TopRoutes) != 3 || routeTotal != 10 {
+		t.Errorf("TopRoutes: expected 3 routes covering 10 requests, got %d routes covering %d", len(ov.TopRoutes), routeTotal)
+	}
+
+	empty, err := s.GetOverview(pulse.TimeRange{Start: end.Add(time.Hour), End: end.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.TotalRequests != 0 || empty.ErrorRate != 0 || empty.AvgLatency != 0 {
+		t.Errorf("expected zero values for an empty range, got %+v", empty)
+	}
+}
+
+func testRouteStatsExtrapolatesSampled(t *testing.T, s pulse.Storage) {
+	end := time.Now()
+	tr := pulse.TimeRange{Start: end.Add(-10 * time.Minute), End: end}
+
+	// Five requests recorded at 10% stand for fifty; two were always kept
+	for i := 0; i < 5; i++ {
+		status := 200
+		if i == 0 {
+			status = 500
+		}
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/feed", StatusCode: status,
+			Latency: 10 * time.Millisecond, SampleRate: 0.1, Timestamp: end.Add(-time.Duration(i+1) * time.Minute)}))
+	}
+	for i := 0; i < 2; i++ {
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/feed", StatusCode: 200,
+			Latency: 40 * time.Millisecond, Timestamp: end.Add(-time.Duration(i+6) * time.Minute)}))
+	}
+
+	stats, err := s.GetRouteStats(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(stats))
+	}
+	rs := stats[0]
+	if rs.RequestCount != 52 || rs.ErrorCount != 10 || rs.StatusCodes[500] != 10 || rs.StatusCodes[200] != 42 {
+		t.Errorf("expected 52 requests / 10 errors, got %d / %d (codes %v)", rs.RequestCount, rs.ErrorCount, rs.StatusCodes)
+	}
+	if !approx(rs.RPM, 5.2) {
+		t.Errorf("RPM: expected 5.2, got %f", rs.RPM)
+	}
+	// Latencies describe the recorded requests only
+	if rs.AvgLatency != 130*time.Millisecond/7 {
+		t.Errorf("AvgLatency: expected %v, got %v", 130*time.Millisecond/7, rs.AvgLatency)
+	}
+
+	ov, err := s.GetOverview(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ov.TotalRequests != 52 || ov.TotalErrors != 10 {
+		t.Errorf("expected overview of 52 requests / 10 errors, got %d / %d", ov.TotalRequests, ov.TotalErrors)
+	}
+}
+
+func testRouteDetailPhases(t *testing.T, s pulse.Storage) {
+	end := time.Now()
+	tr := pulse.TimeRange{Start: end.Add(-10 * time.Minute), End: end}
+
+	for i := 1; i <= 4; i++ {
+		mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/report", StatusCode: 200,
+			Latency: time.Duration(i) * 10 * time.Millisecond, TTFB: time.Duration(i) * time.Millisecond,
+			Middleware: []pulse.MiddlewareTiming{{Name: "auth", Duration: time.Millisecond}, {Name: "render", Duration: time.Duration(i) * 5 * time.Millisecond}},
+			Timestamp:  end.Add(-time.Duration(i) * time.Minute)}))
+	}
+	// Recorded before TTFB was tracked
+	mustStore(t, s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: "/report", StatusCode: 200,
+		Latency: 50 * time.Millisecond, Timestamp: end.Add(-5 * time.Minute)}))
+
+	detail, err := s.GetRouteDetail("GET", "/report", tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail == nil {
+		t.Fatal("expected route detail")
+	}
+	if detail.TTFB == nil || detail.TTFB.Count != 4 || detail.TTFB.Max != 4*time.Millisecond {
+		t.Errorf("expected TTFB over 4 requests up to 4ms, got %+v", detail.TTFB)
+	}
+	if detail.Transfer == nil || detail.Transfer.Max != 36*time.Millisecond {
+		t.Errorf("expected transfer up to 36ms, got %+v", detail.Transfer)
+	}
+	if len(detail.Middleware) != 2 || detail.Middleware[0].Name != "render" || detail.Middleware[1].Name != "auth" {
+		t.Fatalf("expected render then auth, got %+v", detail.Middleware)
+	}
+	if m := detail.Middleware[0]; m.Count != 4 || m.Avg != 12500*time.Microsecond {
+		t.Errorf("expected render over 4 requests averaging 12.5ms, got %+v", m)
+	}
+}
+
+func testRouteStatsTimeShares(t *testing.T, s pulse.Storage) {
+	end := time.Now()
+	tr := pulse.TimeRange{Start: end.Add(-10 * time.Minute), End: end}
+
+	for i, m := range []pulse.RequestMetric{
+		{Latency: 100 * time.Millisecond, QueryCount: 3, DBTime: 30 * time.Millisecond, DependencyCount: 1, DependencyTime: 50 * time.Millisecond},
+		{Latency: 100 * time.Millisecond},
+		// Parallel calls outlasting the request leave no app time
+		{Latency: 200 * time.Millisecond, QueryCount: 2, DBTime: 150 * time.Millisecond, DependencyCount: 2, DependencyTime: 150 * time.Millisecond},
+	} {
+		m.Method, m.Path, m.StatusCode = "GET", "/orders", 200
+		m.Timestamp = end.Add(-time.Duration(i+1) * time.Minute)
+		mustStore(t, s.StoreRequest(m))
+	}
+
+	stats, err := s.GetRouteStats(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(stats))
+	}
+	if rs := stats[0]; !approx(rs.DBShare, 45) || !approx(rs.DependencyShare, 50) || !approx(rs.AppShare, 30) {
+		t.Errorf("expected 45%% / 50%% / 30%% of latency, got %f / %f / %f", rs.DBShare, rs.DependencyShare, rs.AppShare)
+	}
+
+	detail, err := s.GetRouteDetail("GET", "/orders", tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail == nil || detail.DBTime == nil || detail.DependencyTime == nil || detail.AppTime == nil {
+		t.Fatalf("expected time attribution on route detail, got %+v", detail)
+	}
+	if !approx(detail.DBShare, 45) {
+		t.Errorf("expected route detail to share the route's DB share, got %f", detail.DBShare)
+	}
+	if detail.DBTime.Max != 150*time.Millisecond || detail.DependencyTime.P50 != 50*time.Millisecond || detail.AppTime.P50 != 20*time.Millisecond {
+		t.Errorf("unexpected percentiles: db %+v, dependencies %+v, app %+v", detail.DBTime, detail.DependencyTime, detail.AppTime)
+	}
+
+	reqs, err := s.GetRequests(pulse.RequestFilter{Path: "/orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queries, calls int
+	for _, r := range reqs {
+		queries += r.QueryCount
+		calls += r.DependencyCount
+	}
+	if queries != 5 || calls != 3 {
+		t.Errorf("expected query and call counts to round-trip, got %d and %d", queries, calls)
+	}
+}
+
+// --- Concurrency ---
+
+func testConcurrentWriters(t *testing.T, s pulse.Storage) {
+	const writers, perWriter = 8, 50
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	errc := make(chan error, writers*perWriter*3)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				ts := now.Add(-time.Duration(w*perWriter+i) * time.Millisecond)
+				errc <- s.StoreRequest(pulse.RequestMetric{Method: "GET", Path: fmt.Sprintf("/w%d", w), StatusCode: 200, Timestamp: ts})
+				errc <- s.StoreError(newError(fmt.Sprintf("err-%d-%d", w, i), "fp-shared", ts, ""))
+				if _, err := s.GetRequests(pulse.RequestFilter{Limit: 10}); err != nil {
+					errc <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errc)
+
+	for err := range errc {
+		if err != nil {
+			t.Fatalf("concurrent write failed: %v", err)
+		}
+	}
+
+	reqs, err := s.GetRequests(pulse.RequestFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != writers*perWriter {
+		t.Errorf("expected %d requests, got %d", writers*perWriter, len(reqs))
+	}
+
+	e := getError(t, s, "fp-shared")
+	if e.Count != writers*perWriter {
+		t.Errorf("expected error count %d, got %d", writers*perWriter, e.Count)
+	}
+}
+
+// --- Helpers ---
+
+func mustStore(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+}
+
+func newError(id, fingerprint string, seen time.Time, stack string) pulse.ErrorRecord {
+	return pulse.ErrorRecord{
+		ID:           id,
+		Fingerprint:  fingerprint,
+		Method:       "GET",
+		Route:        "/fail",
+		ErrorMessage: "boom",
+		ErrorType:    pulse.ErrorTypeInternal,
+		StackTrace:   stack,
+		Count:        1,
+		FirstSeen:    seen,
+		LastSeen:     seen,
+	}
+}
+
+func findError(t *testing.T, errs []pulse.ErrorRecord, fingerprint string) pulse.ErrorRecord {
+	t.Helper()
+	for _, e := range errs {
+		if e.Fingerprint == fingerprint {
+			return e
+		}
+	}
+	t.Fatalf("no error record with fingerprint %q", fingerprint)
+	return pulse.ErrorRecord{}
+}
+
+func getError(t *testing.T, s pulse.Storage, fingerprint string) pulse.ErrorRecord {
+	t.Helper()
+	errs, err := s.GetErrors(pulse.ErrorFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findError(t, errs, fingerprint)
+}
+
+// assertRequests compares requests, in order, by "METHOD path status".
+func assertRequests(t *testing.T, name string, got []pulse.RequestMetric, want []string) {
+	t.Helper()
+	keys := make([]string, len(got))
+	for i, m := range got {
+		keys[i] = fmt.Sprintf("%s %s %d", m.Method, m.Path, m.StatusCode)
+	}
+	if len(keys) != len(want) {
+		t.Errorf("%s: expected %v, got %v", name, want, keys)
+		return
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("%s: expected %v, got %v", name, want, keys)
+			return
+		}
+	}
+}
+
+func approx(got, want float64) bool {
+	d := got - want
+	return d > -0.01 && d < 0.01
+}
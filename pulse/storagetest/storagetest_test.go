@@ -0,0 +1,66 @@
+package storagetest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/MUKE-coder/pulse/pulse"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	RunConformance(t, func(t *testing.T) pulse.Storage {
+		return pulse.NewMemoryStorage("conformance")
+	})
+}
+
+func TestSQLiteStorage(t *testing.T) {
+	RunConformance(t, func(t *testing.T) pulse.Storage {
+		s, err := pulse.NewSQLiteStorage(filepath.Join(t.TempDir(), "pulse.db"), "conformance")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+func TestTeeStorage(t *testing.T) {
+	RunConformance(t, func(t *testing.T) pulse.Storage {
+		durable, err := pulse.NewSQLiteStorage(filepath.Join(t.TempDir(), "pulse.db"), "conformance")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Latest-state reads are served from the hot backend only, so its
+		// window must cover the suite's data
+		return pulse.NewTeeStorage(pulse.NewMemoryStorage("conformance"), 24*time.Hour, durable)
+	})
+}
+
+func TestGormStorage(t *testing.T) {
+	RunConformance(t, func(t *testing.T) pulse.Storage {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "app.db")), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// SQLite allows one writer; a single connection keeps concurrent
+		// writers from failing with SQLITE_BUSY, as an app would configure it
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		s, err := pulse.NewGormStorage(db, "conformance")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,146 @@
+package pulse
+
+// SubjectFilter selects the stored records of one data subject, e.g. for a
+// GDPR erasure request. Every set field must match; at least one must be set.
+//
+// Each kind of record is matched on the fields it keeps, and kinds keeping
+// none of the set fields are left alone: requests on all three, connections
+// on client IP and trace ID. Spans, queries and dependency calls go with the
+// traces of the deleted requests and connections, and with TraceID.
+//
+// Error groups are shared by every request failing the same way, so only
+// the subject's part is removed: their tag values and trace ID, and the
+// captured request context when it is theirs. A group is deleted once
+// nothing tied to anyone else is left.
+type SubjectFilter struct {
+	ClientIP string            `json:"client_ip,omitempty"` // as stored, after AnonymizeIP
+	Tags     map[string]string `json:"tags,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+}
+
+// IsZero reports whether no field is set. A zero filter matches nothing.
+func (f SubjectFilter) IsZero() bool {
+	return f.ClientIP == "" && len(f.Tags) == 0 && f.TraceID == ""
+}
+
+func (f SubjectFilter) matchRequest(m RequestMetric) bool {
+	if f.IsZero() {
+		return false
+	}
+	return (f.ClientIP == "" || m.ClientIP == f.ClientIP) &&
+		(f.TraceID == "" || m.TraceID == f.TraceID) &&
+		matchTags(m.Tags, f.Tags)
+}
+
+// eraseError removes the subject's data from an error group: the tag
+// values, the traces of the subject's deleted requests and connections, and
+// the captured request context if it may be theirs. It reports whether the
+// group held any, and whether the group is left empty and should be deleted
+// rather than updated.
+func (f SubjectFilter) eraseError(e ErrorRecord, traces subjectTraces) (erased ErrorRecord, matched, empty bool) {
+	if f.IsZero() {
+		return e, false, false
+	}
+	matched = f.matchError(e)
+	for _, id := range e.TraceIDs {
+		matched = matched || traces[id]
+	}
+	if !matched {
+		return e, false, false
+	}
+
+	if len(f.Tags) > 0 {
+		tags := make(map[string][]string, len(e.Tags))
+		for key, values := range e.Tags {
+			if value, ok := f.Tags[key]; ok {
+				values = removeString(values, value)
+			}
+			if len(values) > 0 {
+				tags[key] = values
+			}
+		}
+		e.Tags = tags
+	}
+	var kept []string
+	for _, id := range e.TraceIDs {
+		if !traces[id] {
+			kept = append(kept, id)
+		}
+	}
+	e.TraceIDs = kept
+	if rc := e.RequestContext; rc != nil && f.ownsContext(*rc, traces) {
+		e.TraceIDs = removeString(e.TraceIDs, rc.TraceID)
+		e.RequestContext = nil
+	}
+	return e, true, len(e.Tags) == 0 && len(e.TraceIDs) == 0 && e.RequestContext == nil
+}
+
+// matchError reports whether an error group holds every set field: the
+// client IP of its captured request context, the tag values seen across
+// occurrences and the trace ID among its traces.
+func (f SubjectFilter) matchError(e ErrorRecord) bool {
+	if f.ClientIP != "" && (e.RequestContext == nil || e.RequestContext.ClientIP != f.ClientIP) {
+		return false
+	}
+	if f.TraceID != "" && !containsString(e.TraceIDs, f.TraceID) {
+		return false
+	}
+	for key, value := range f.Tags {
+		if !containsString(e.Tags[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ownsContext reports whether an error group's captured request context may
+// be the subject's. The context keeps no tags, so with tags alone it is only
+// kept when its trace proves it is another request's.
+func (f SubjectFilter) ownsContext(rc RequestContext, traces subjectTraces) bool {
+	switch {
+	case traces[rc.TraceID]:
+		return true
+	case f.ClientIP != "":
+		return rc.ClientIP == f.ClientIP
+	default:
+		return f.TraceID == ""
+	}
+}
+
+// removeString returns list without s. list is not modified.
+func removeString(list []string, s string) []string {
+	if !containsString(list, s) {
+		return list
+	}
+	kept := make([]string, 0, len(list)-1)
+	for _, v := range list {
+		if v != s {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func (f SubjectFilter) matchConnection(c ConnectionMetric) bool {
+	if f.ClientIP == "" && f.TraceID == "" {
+		return false
+	}
+	return (f.ClientIP == "" || c.ClientIP == f.ClientIP) &&
+		(f.TraceID == "" || c.TraceID == f.TraceID)
+}
+
+// subjectTraces collects the trace IDs whose spans, queries and dependency
+// calls are deleted along with a subject's requests and connections.
+type subjectTraces map[string]bool
+
+func newSubjectTraces(f SubjectFilter) subjectTraces {
+	traces := make(subjectTraces)
+	traces.add(f.TraceID)
+	return traces
+}
+
+func (t subjectTraces) add(traceID string) {
+	if traceID != "" {
+		t[traceID] = true
+	}
+}
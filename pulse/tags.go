@@ -0,0 +1,202 @@
+package pulse
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRequestTags caps the tags one request can carry.
+	maxRequestTags = 32
+	// maxTagValueLen truncates longer tag values.
+	maxTagValueLen = 256
+	// maxErrorTagValues caps the values an error group keeps per tag key.
+	maxErrorTagValues = 20
+)
+
+type requestTagsKey struct{}
+
+// requestTags holds the tags set on a request while its handler runs.
+type requestTags struct {
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+// contextWithRequestTags attaches an empty tag set to the context unless it
+// already carries one, so the error and tracing middleware share a set.
+func contextWithRequestTags(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(requestTagsKey{}).(*requestTags); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, requestTagsKey{}, &requestTags{})
+}
+
+// SetTag labels the current request with a tag, such as the tenant, user
+// plan or API version, so its metrics can be filtered and grouped by it:
+//
+//	pulse.SetTag(c.Request.Context(), "tenant", tenantID)
+//
+// The request, its errors and the queries it runs afterwards carry the tag.
+// Setting a key again replaces its value. Outside a request handled by the
+// Pulse middleware it does nothing.
+func SetTag(ctx context.Context, key, value string) {
+	rt, ok := ctx.Value(requestTagsKey{}).(*requestTags)
+	if !ok || key == "" {
+		return
+	}
+	if len(value) > maxTagValueLen {
+		value = value[:maxTagValueLen]
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.tags == nil {
+		rt.tags = make(map[string]string)
+	}
+	if _, exists := rt.tags[key]; !exists && len(rt.tags) >= maxRequestTags {
+		return
+	}
+	rt.tags[key] = value
+}
+
+// TagsFromContext returns a copy of the tags set on the current request, or
+// nil if there are none.
+func TagsFromContext(ctx context.Context) map[string]string {
+	rt, ok := ctx.Value(requestTagsKey{}).(*requestTags)
+	if !ok {
+		return nil
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(rt.tags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(rt.tags))
+	for k, v := range rt.tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// mergeTags returns the union of two tag sets; b wins on conflicting keys.
+func mergeTags(a, b map[string]string) map[string]string {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	merged := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		merged[k] = v
+	}
+	return merged
+}
+
+// matchTags reports whether tags carries every tag in want.
+func matchTags(tags, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := tags[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// matchTagValues reports whether an error group has seen every tag in want.
+func matchTagValues(values map[string][]string, want map[string]string) bool {
+	for k, v := range want {
+		if !containsString(values[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagValues converts a request's tags to the per-key value lists error
+// groups keep.
+func tagValues(tags map[string]string) map[string][]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	values := make(map[string][]string, len(tags))
+	for k, v := range tags {
+		values[k] = []string{v}
+	}
+	return values
+}
+
+// mergeTagValues adds the tag values of src to dst, keeping at most
+// maxErrorTagValues values per key. dst is not modified.
+func mergeTagValues(dst, src map[string][]string) map[string][]string {
+	if len(src) == 0 {
+		return dst
+	}
+	merged := make(map[string][]string, len(dst)+len(src))
+	for k, vs := range dst {
+		merged[k] = vs
+	}
+	for k, vs := range src {
+		for _, v := range vs {
+			if len(merged[k]) < maxErrorTagValues && !containsString(merged[k], v) {
+				merged[k] = append(merged[k][:len(merged[k]):len(merged[k])], v)
+			}
+		}
+	}
+	return merged
+}
+
+// TagStats aggregates the requests sharing one value of a tag.
+type TagStats struct {
+	Tag          string        `json:"tag"`
+	Value        string        `json:"value"` // "" for requests without the tag
+	RequestCount int64         `json:"request_count"`
+	ErrorCount   int64         `json:"error_count"`
+	ErrorRate    float64       `json:"error_rate"`
+	AvgLatency   time.Duration `json:"avg_latency"`
+	P95Latency   time.Duration `json:"p95_latency"`
+	RPM          float64       `json:"rpm"`
+	Routes       []RouteStats  `json:"routes,omitempty"` // per-route breakdown, routes API only
+}
+
+// buildTagStats groups requests by their value of tag, sorted by request
+// count descending. withRoutes adds each group's route stats.
+func buildTagStats(reqs []RequestMetric, tag string, duration time.Duration, withRoutes bool) []TagStats {
+	groups := make(map[string][]RequestMetric)
+	for _, r := range reqs {
+		value := r.Tags[tag]
+		groups[value] = append(groups[value], r)
+	}
+
+	stats := make([]TagStats, 0, len(groups))
+	for value, group := range groups {
+		rs := computeRouteStats("", "", group, duration)
+		ts := TagStats{
+			Tag:          tag,
+			Value:        value,
+			RequestCount: rs.RequestCount,
+			ErrorCount:   rs.ErrorCount,
+			ErrorRate:    rs.ErrorRate,
+			AvgLatency:   rs.AvgLatency,
+			P95Latency:   rs.P95Latency,
+			RPM:          rs.RPM,
+		}
+		if withRoutes {
+			ts.Routes = buildRouteStats(group, duration)
+		}
+		stats = append(stats, ts)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].RequestCount != stats[j].RequestCount {
+			return stats[i].RequestCount > stats[j].RequestCount
+		}
+		return stats[i].Value < stats[j].Value
+	})
+	return stats
+}
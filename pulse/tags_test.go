@@ -0,0 +1,165 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetTag(t *testing.T) {
+	// Outside a traced request tags are dropped
+	SetTag(context.Background(), "tenant", "acme")
+	if tags := TagsFromContext(context.Background()); tags != nil {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+
+	ctx := contextWithRequestTags(context.Background())
+	if contextWithRequestTags(ctx) != ctx {
+		t.Error("expected the existing tag set to be reused")
+	}
+	SetTag(ctx, "tenant", "acme")
+	SetTag(ctx, "tenant", "globex")
+	SetTag(ctx, "", "ignored")
+	SetTag(ctx, "note", strings.Repeat("x", maxTagValueLen+10))
+	for i := 0; i < maxRequestTags; i++ {
+		SetTag(ctx, fmt.Sprintf("k%d", i), "v")
+	}
+
+	tags := TagsFromContext(ctx)
+	if len(tags) != maxRequestTags {
+		t.Errorf("expected %d tags, got %d", maxRequestTags, len(tags))
+	}
+	if tags["tenant"] != "globex" {
+		t.Errorf("expected the last value to win, got %q", tags["tenant"])
+	}
+	if len(tags["note"]) != maxTagValueLen {
+		t.Errorf("expected the value truncated to %d bytes, got %d", maxTagValueLen, len(tags["note"]))
+	}
+
+	// The returned map is a copy
+	tags["tenant"] = "changed"
+	if TagsFromContext(ctx)["tenant"] != "globex" {
+		t.Error("expected TagsFromContext to return a copy")
+	}
+}
+
+func TestMergeTagValues(t *testing.T) {
+	dst := map[string][]string{"tenant": {"acme"}}
+	merged := mergeTagValues(dst, map[string][]string{"tenant": {"acme", "globex"}, "plan": {"pro"}})
+	if got := merged["tenant"]; len(got) != 2 || got[1] != "globex" || merged["plan"][0] != "pro" {
+		t.Errorf("unexpected merged values: %v", merged)
+	}
+	if len(dst["tenant"]) != 1 {
+		t.Errorf("expected dst to be left unmodified, got %v", dst)
+	}
+
+	for i := 0; i < 2*maxErrorTagValues; i++ {
+		merged = mergeTagValues(merged, map[string][]string{"user": {fmt.Sprint(i)}})
+	}
+	if len(merged["user"]) != maxErrorTagValues {
+		t.Errorf("expected at most %d values, got %d", maxErrorTagValues, len(merged["user"]))
+	}
+}
+
+func TestMiddleware_RecordsTags(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+	router.GET("/tenants/:tenant/fail", func(c *gin.Context) {
+		SetTag(c.Request.Context(), "tenant", c.Param("tenant"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/tenants/acme/fail", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/tenants/globex/fail", nil))
+
+	reqs := waitForRequests(t, p, 2)
+	for _, r := range reqs {
+		if tenant := r.Tags["tenant"]; tenant != "acme" && tenant != "globex" {
+			t.Errorf("expected the request to carry its tenant tag, got %v", r.Tags)
+		}
+	}
+
+	errs := waitForErrors(t, p, 1)
+	if got := errs[0].Tags["tenant"]; len(got) != 2 {
+		t.Errorf("expected both tenants on the error group, got %v", errs[0].Tags)
+	}
+}
+
+func TestAPI_TagFiltersAndGroupBy(t *testing.T) {
+	p, router := setupAPIPulse(t)
+	token := loginAndGetToken(t, router)
+	now := time.Now()
+
+	acme := map[string]string{"tenant": "acme", "plan": "pro"}
+	globex := map[string]string{"tenant": "globex", "plan": "free"}
+	for i := 0; i < 3; i++ {
+		p.storage.StoreRequest(RequestMetric{Method: "GET", Path: "/a", StatusCode: 200, Latency: 10 * time.Millisecond, Tags: acme, Timestamp: now})
+	}
+	p.storage.StoreRequest(RequestMetric{Method: "GET", Path: "/b", StatusCode: 500, Latency: 10 * time.Millisecond, Tags: globex, Timestamp: now})
+	p.storage.StoreRequest(RequestMetric{Method: "GET", Path: "/b", StatusCode: 200, Latency: 10 * time.Millisecond, Timestamp: now})
+	p.storage.StoreError(ErrorRecord{ID: "e1", Fingerprint: "fp", Count: 1, FirstSeen: now, LastSeen: now, Tags: tagValues(globex)})
+
+	get := func(path string, v interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", path, token, ""))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, w.Code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	var routes []RouteStats
+	get("/pulse/api/routes?range=1h&tag=tenant:globex", &routes)
+	if len(routes) != 1 || routes[0].Path != "/b" || routes[0].RequestCount != 1 {
+		t.Errorf("expected only globex's /b request, got %+v", routes)
+	}
+
+	var groups []TagStats
+	get("/pulse/api/routes?range=1h&group_by=tenant", &groups)
+	if len(groups) != 3 {
+		t.Fatalf("expected groups for acme, globex and untagged, got %+v", groups)
+	}
+	if groups[0].Value != "acme" || groups[0].RequestCount != 3 || len(groups[0].Routes) != 1 {
+		t.Errorf("unexpected acme group: %+v", groups[0])
+	}
+	for _, g := range groups[1:] {
+		if g.Value == "globex" && (g.ErrorCount != 1 || g.ErrorRate != 100) {
+			t.Errorf("expected globex to own the error, got %+v", g)
+		}
+	}
+
+	var overview Overview
+	get("/pulse/api/overview?range=1h&tag=plan:pro&group_by=tenant", &overview)
+	if overview.TotalRequests != 3 || len(overview.TagGroups) != 1 || overview.TagGroups[0].Value != "acme" {
+		t.Errorf("expected the pro plan overview grouped by tenant, got %d requests / %+v", overview.TotalRequests, overview.TagGroups)
+	}
+	if overview.TagGroups[0].Routes != nil {
+		t.Error("expected no per-route breakdown in the overview")
+	}
+
+	var errs []ErrorRecord
+	get("/pulse/api/errors?range=1h&tag=tenant:globex", &errs)
+	if len(errs) != 1 {
+		t.Errorf("expected globex's error, got %d", len(errs))
+	}
+	get("/pulse/api/errors?range=1h&tag=tenant:acme", &errs)
+	if len(errs) != 0 {
+		t.Errorf("expected no errors for acme, got %d", len(errs))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("GET", "/pulse/api/routes?tag=tenant", token, ""))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a malformed tag filter, got %d", w.Code)
+	}
+}
@@ -15,6 +15,8 @@ const (
 
 	traceIDKey contextKey = "pulse_trace_id"
 	pulseKey   contextKey = "pulse_instance"
+
+	internalQueryKey contextKey = "pulse_internal_query"
 )
 
 // traceID pool to reduce allocations
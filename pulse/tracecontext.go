@@ -0,0 +1,160 @@
+package pulse
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header carrying the trace
+	// ID, parent span ID and trace flags.
+	TraceParentHeader = "traceparent"
+
+	// TraceStateHeader is the W3C Trace Context header carrying
+	// vendor-specific trace state.
+	TraceStateHeader = "tracestate"
+
+	traceContextKey contextKey = "pulse_trace_context"
+
+	// traceFlagSampled is the W3C "sampled" trace flag.
+	traceFlagSampled byte = 0x01
+
+	// maxTraceStateLen bounds the tracestate value we propagate. The spec
+	// allows at most 32 list members; longer values are dropped.
+	maxTraceStateLen = 512
+)
+
+// TraceContext is the W3C Trace Context of the span handling a request.
+type TraceContext struct {
+	TraceID      string // 32 lowercase hex characters
+	SpanID       string // 16 lowercase hex characters, this service's span
+	ParentSpanID string // span ID received from the caller, if any
+	Flags        byte
+	TraceState   string
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&traceFlagSampled != 0
+}
+
+// TraceParent formats the traceparent header value with this context's span
+// as the parent.
+func (tc TraceContext) TraceParent() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + hex.EncodeToString([]byte{tc.Flags})
+}
+
+// GenerateSpanID creates a new random 16-character hex span ID.
+func GenerateSpanID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// Fallback — should never happen
+		return "0000000000000001"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ParseTraceParent parses a traceparent header value. The returned context
+// carries the caller's span ID in ParentSpanID; SpanID is left empty.
+func ParseTraceParent(value string) (TraceContext, bool) {
+	value = strings.TrimSpace(value)
+	// version(2) - trace-id(32) - parent-id(16) - flags(2)
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return TraceContext{}, false
+	}
+
+	version := value[0:2]
+	if !isLowerHex(version) || version == "ff" {
+		return TraceContext{}, false
+	}
+	// Version 00 has exactly four fields; later versions may append more
+	if len(value) > 55 && (version == "00" || value[55] != '-') {
+		return TraceContext{}, false
+	}
+
+	traceID, parentID, flags := value[3:35], value[36:52], value[53:55]
+	if !isValidTraceID(traceID) || !isLowerHex(parentID) || parentID == "0000000000000000" || !isLowerHex(flags) {
+		return TraceContext{}, false
+	}
+
+	f, _ := hex.DecodeString(flags)
+	return TraceContext{TraceID: traceID, ParentSpanID: parentID, Flags: f[0]}, true
+}
+
+// extractTraceContext reads the caller's trace context from request headers.
+// A valid traceparent wins; otherwise a well-formed X-Pulse-Trace-ID is used
+// so older Pulse callers still share their trace ID. That header carries no
+// sampling decision, so the returned context has no flags set.
+func extractTraceContext(h http.Header) (TraceContext, bool) {
+	if tc, ok := ParseTraceParent(h.Get(TraceParentHeader)); ok {
+		// tracestate may be split across several header lines
+		state := strings.Join(h.Values(TraceStateHeader), ",")
+		if len(state) <= maxTraceStateLen {
+			tc.TraceState = strings.TrimSpace(state)
+		}
+		return tc, true
+	}
+	if id := h.Get(TraceIDHeader); isValidTraceID(id) {
+		return TraceContext{TraceID: id}, true
+	}
+	return TraceContext{}, false
+}
+
+// InjectTraceContext sets the traceparent and tracestate headers for an
+// outgoing request made within ctx. It does nothing when ctx carries no
+// valid trace.
+func InjectTraceContext(ctx context.Context, h http.Header) {
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		// Contexts built with ContextWithTraceID only carry the trace ID
+		traceID := TraceIDFromContext(ctx)
+		if !isValidTraceID(traceID) {
+			return
+		}
+		tc = TraceContext{TraceID: traceID, SpanID: GenerateSpanID(), Flags: traceFlagSampled}
+	}
+
+	setTraceHeaders(h, tc)
+}
+
+// setTraceHeaders writes tc as the traceparent and tracestate headers.
+func setTraceHeaders(h http.Header, tc TraceContext) {
+	h.Set(TraceParentHeader, tc.TraceParent())
+	if tc.TraceState != "" {
+		h.Set(TraceStateHeader, tc.TraceState)
+	} else {
+		h.Del(TraceStateHeader)
+	}
+}
+
+// ContextWithTraceContext returns a new context carrying the trace context.
+// The trace ID is also available through TraceIDFromContext.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	ctx = ContextWithTraceID(ctx, tc.TraceID)
+	return context.WithValue(ctx, traceContextKey, tc)
+}
+
+// TraceContextFromContext extracts the trace context from a context.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+// isValidTraceID reports whether id is a W3C trace ID: 32 lowercase hex
+// characters, not all zero.
+func isValidTraceID(id string) bool {
+	return len(id) == 32 && isLowerHex(id) && id != "00000000000000000000000000000000"
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return s != ""
+}
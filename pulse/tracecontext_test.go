@@ -0,0 +1,250 @@
+package pulse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID = "00f067aa0ba902b7"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"valid sampled", "00-" + testTraceID + "-" + testParentID + "-01", true},
+		{"valid unsampled", "00-" + testTraceID + "-" + testParentID + "-00", true},
+		{"surrounding spaces", "  00-" + testTraceID + "-" + testParentID + "-01 ", true},
+		{"future version with extra fields", "cc-" + testTraceID + "-" + testParentID + "-01-what-the-future", true},
+		{"version 00 with extra fields", "00-" + testTraceID + "-" + testParentID + "-01-extra", false},
+		{"forbidden version", "ff-" + testTraceID + "-" + testParentID + "-01", false},
+		{"zero trace ID", "00-00000000000000000000000000000000-" + testParentID + "-01", false},
+		{"zero parent ID", "00-" + testTraceID + "-0000000000000000-01", false},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testParentID + "-01", false},
+		{"short trace ID", "00-4bf92f3577b34da6a3ce929d0e0e47-" + testParentID + "-01", false},
+		{"bad separators", "00_" + testTraceID + "_" + testParentID + "_01", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		tc, ok := ParseTraceParent(tt.value)
+		if ok != tt.ok {
+			t.Errorf("%s: expected ok=%v, got %v", tt.name, tt.ok, ok)
+			continue
+		}
+		if ok && (tc.TraceID != testTraceID || tc.ParentSpanID != testParentID) {
+			t.Errorf("%s: unexpected context %+v", tt.name, tc)
+		}
+	}
+
+	tc, _ := ParseTraceParent("00-" + testTraceID + "-" + testParentID + "-01")
+	if !tc.Sampled() {
+		t.Error("expected sampled flag")
+	}
+}
+
+func TestTraceContext_RoundTrip(t *testing.T) {
+	tc := TraceContext{TraceID: testTraceID, SpanID: GenerateSpanID(), Flags: traceFlagSampled}
+	if len(tc.SpanID) != 16 || !isLowerHex(tc.SpanID) {
+		t.Fatalf("expected 16 hex char span ID, got %q", tc.SpanID)
+	}
+
+	parsed, ok := ParseTraceParent(tc.TraceParent())
+	if !ok {
+		t.Fatalf("expected %q to parse", tc.TraceParent())
+	}
+	if parsed.TraceID != tc.TraceID || parsed.ParentSpanID != tc.SpanID || !parsed.Sampled() {
+		t.Errorf("round trip mismatch: %+v vs %+v", parsed, tc)
+	}
+}
+
+func TestExtractTraceContext(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+	h.Add(TraceStateHeader, "congo=t61rcWkgMzE")
+	h.Add(TraceStateHeader, "rojo=00f067aa0ba902b7")
+
+	tc, ok := extractTraceContext(h)
+	if !ok || tc.TraceState != "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7" {
+		t.Errorf("expected combined tracestate, got %+v", tc)
+	}
+
+	// tracestate is ignored without a valid traceparent
+	h.Set(TraceParentHeader, "garbage")
+	if _, ok := extractTraceContext(h); ok {
+		t.Error("expected invalid traceparent to be ignored")
+	}
+
+	// Older Pulse callers only send X-Pulse-Trace-ID
+	h = http.Header{}
+	h.Set(TraceIDHeader, testTraceID)
+	if tc, ok := extractTraceContext(h); !ok || tc.TraceID != testTraceID || tc.ParentSpanID != "" {
+		t.Errorf("expected trace ID from X-Pulse-Trace-ID, got %+v", tc)
+	}
+	h.Set(TraceIDHeader, "not-a-trace-id")
+	if _, ok := extractTraceContext(h); ok {
+		t.Error("expected malformed X-Pulse-Trace-ID to be ignored")
+	}
+}
+
+func TestInjectTraceContext(t *testing.T) {
+	h := http.Header{}
+	InjectTraceContext(context.Background(), h)
+	if h.Get(TraceParentHeader) != "" {
+		t.Error("expected no traceparent without a trace")
+	}
+
+	// Non-W3C trace IDs are not propagated
+	InjectTraceContext(ContextWithTraceID(context.Background(), "test-trace-123"), h)
+	if h.Get(TraceParentHeader) != "" {
+		t.Error("expected no traceparent for a non-W3C trace ID")
+	}
+
+	InjectTraceContext(ContextWithTraceID(context.Background(), testTraceID), h)
+	if tc, ok := ParseTraceParent(h.Get(TraceParentHeader)); !ok || tc.TraceID != testTraceID {
+		t.Errorf("expected traceparent from bare trace ID, got %q", h.Get(TraceParentHeader))
+	}
+
+	span := TraceContext{TraceID: testTraceID, SpanID: "b7ad6b7169203331", TraceState: "rojo=1"}
+	InjectTraceContext(ContextWithTraceContext(context.Background(), span), h)
+	if got := h.Get(TraceParentHeader); got != "00-"+testTraceID+"-b7ad6b7169203331-00" {
+		t.Errorf("unexpected traceparent %q", got)
+	}
+	if h.Get(TraceStateHeader) != "rojo=1" {
+		t.Errorf("expected tracestate to be propagated, got %q", h.Get(TraceStateHeader))
+	}
+}
+
+func TestMiddleware_HonorsIncomingTraceParent(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	var seen TraceContext
+	router.GET("/ctx", func(c *gin.Context) {
+		seen, _ = TraceContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/ctx", nil)
+	req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+	req.Header.Set(TraceStateHeader, "rojo=00f067aa0ba902b7")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(TraceIDHeader); got != testTraceID {
+		t.Fatalf("expected incoming trace ID to be kept, got %s", got)
+	}
+	if seen.ParentSpanID != testParentID || seen.SpanID == "" || seen.SpanID == testParentID {
+		t.Errorf("expected a new span under the caller's span, got %+v", seen)
+	}
+	if seen.TraceState != "rojo=00f067aa0ba902b7" {
+		t.Errorf("expected tracestate in context, got %q", seen.TraceState)
+	}
+
+	reqs := waitForRequests(t, p, 1)
+	if reqs[0].TraceID != testTraceID || reqs[0].ParentSpanID != testParentID || reqs[0].SpanID != seen.SpanID {
+		t.Errorf("unexpected stored trace fields: %+v", reqs[0])
+	}
+}
+
+func TestMiddleware_HonorsUnsampledParent(t *testing.T) {
+	router, p := setupTestRouter()
+	defer p.Shutdown()
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-00")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Errors are still recorded regardless of the caller's decision
+	req = httptest.NewRequest("GET", "/error", nil)
+	req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-00")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	reqs := waitForRequests(t, p, 1)
+	if len(reqs) != 1 || reqs[0].Path != "/error" {
+		t.Errorf("expected only the error to be recorded, got %+v", reqs)
+	}
+}
+
+func TestWrapHTTPClient_PropagatesTrace(t *testing.T) {
+	// Downstream service instrumented with its own Pulse
+	downstream, pd := setupTestRouter()
+	defer pd.Shutdown()
+	srv := httptest.NewServer(downstream)
+	defer srv.Close()
+
+	// Upstream service calls downstream while handling a request
+	upstream, pu := setupTestRouter()
+	defer pu.Shutdown()
+	client := WrapHTTPClient(pu, nil, "users-service")
+	var upstreamSpan TraceContext
+	upstream.GET("/proxy", func(c *gin.Context) {
+		upstreamSpan, _ = TraceContextFromContext(c.Request.Context())
+		req, _ := http.NewRequestWithContext(c.Request.Context(), "GET", srv.URL+"/users", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		resp.Body.Close()
+		if req.Header.Get(TraceParentHeader) != "" {
+			t.Error("expected the caller's request to be left unmodified")
+		}
+		c.Status(resp.StatusCode)
+	})
+
+	w := httptest.NewRecorder()
+	upstream.ServeHTTP(w, httptest.NewRequest("GET", "/proxy", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	up := waitForRequests(t, pu, 1)
+	down := waitForRequests(t, pd, 1)
+	if up[0].TraceID != down[0].TraceID {
+		t.Fatalf("expected one trace across services, got %s and %s", up[0].TraceID, down[0].TraceID)
+	}
+
+	// The downstream server span hangs off the upstream client span, which
+	// hangs off the upstream server span
+	spans := waitForSpans(t, pu, up[0].TraceID, 2)
+	var clientSpan Span
+	for _, span := range spans {
+		if span.Kind == SpanKindClient {
+			clientSpan = span
+		}
+	}
+	if clientSpan.ParentID != upstreamSpan.SpanID {
+		t.Errorf("expected client span under %s, got parent %q", upstreamSpan.SpanID, clientSpan.ParentID)
+	}
+	if down[0].ParentSpanID != clientSpan.SpanID {
+		t.Errorf("expected downstream parent %s, got %s", clientSpan.SpanID, down[0].ParentSpanID)
+	}
+}
+
+// waitForRequests polls until at least n requests are stored.
+func waitForRequests(t *testing.T, p *Pulse, n int) []RequestMetric {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		reqs, _ := p.storage.GetRequests(RequestFilter{})
+		if len(reqs) >= n {
+			// Give stragglers a moment so callers can assert exact counts
+			time.Sleep(20 * time.Millisecond)
+			reqs, _ = p.storage.GetRequests(RequestFilter{})
+			return reqs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d stored requests, got %d", n, len(reqs))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -49,7 +49,7 @@ func main() {
 		{"/pulse/ui/errors", 200, "", "<div id=\"root\">"},
 
 		// Static assets
-		{"/pulse/ui/assets/index-CKc0di6z.js", 200, "", ""},
+		{"/pulse/ui/assets/index-18faCbIW.js", 200, "", ""},
 		{"/pulse/ui/assets/index-DmEaoMqh.css", 200, "", ""},
 
 		// API (auth required)
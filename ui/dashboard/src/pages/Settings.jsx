@@ -4,6 +4,7 @@ import { useAPI } from '../hooks/useAPI'
 export default function SettingsPage() {
   const { get, post } = useAPI()
   const [settings, setSettings] = useState(null)
+  const [retention, setRetention] = useState(null)
   const [exportType, setExportType] = useState('requests')
   const [exportFormat, setExportFormat] = useState('json')
   const [exportRange, setExportRange] = useState('1h')
@@ -16,6 +17,8 @@ export default function SettingsPage() {
       try {
         const res = await get('/settings')
         if (res.ok) setSettings(await res.json())
+        const retRes = await get('/settings/retention')
+        if (retRes.ok) setRetention(await retRes.json())
       } catch {}
     }
     load()
@@ -145,7 +148,7 @@ export default function SettingsPage() {
           <ConfigRow label="Prefix" value={settings.Prefix} />
           <ConfigRow label="Dev Mode" value={settings.DevMode} />
           <ConfigRow label="Dashboard Username" value={settings.Dashboard?.Username} />
-          <ConfigRow label="Storage Driver" value={settings.Storage?.Driver === 0 ? 'Memory' : 'SQLite'} />
+          <ConfigRow label="Storage Driver" value={['Memory', 'SQLite', 'GORM'][settings.Storage?.Driver] ?? settings.Storage?.Driver} />
           <ConfigRow label="Retention Hours" value={settings.Storage?.RetentionHours} />
           <ConfigRow label="Tracing Enabled" value={settings.Tracing?.Enabled} />
           <ConfigRow label="Slow Request Threshold" value={`${(settings.Tracing?.SlowRequestThreshold / 1e6)?.toFixed(0)}ms`} />
@@ -160,6 +163,54 @@ export default function SettingsPage() {
         </Section>
       )}
 
+      {/* Ingestion Queue */}
+      {settings?.ingestion_stats && (
+        <Section title="Ingestion Queue">
+          <ConfigRow label="Overflow Policy" value={settings.ingestion_stats.policy} />
+          <ConfigRow label="Queued" value={`${settings.ingestion_stats.queued} / ${settings.ingestion_stats.capacity}`} />
+          <ConfigRow label="Enqueued" value={settings.ingestion_stats.enqueued} />
+          <ConfigRow label="Written" value={settings.ingestion_stats.written} />
+          <ConfigRow label="Dropped" value={settings.ingestion_stats.dropped} />
+          <ConfigRow label="Failed Batches" value={settings.ingestion_stats.failed} />
+          <ConfigRow label="Failed Items" value={settings.ingestion_stats.failed_items} />
+        </Section>
+      )}
+
+      {/* Retention */}
+      {retention && (
+        <Section title="Data Retention">
+          <ConfigRow label="Requests" value={formatRetention(retention.policy?.Requests)} />
+          <ConfigRow label="Queries" value={formatRetention(retention.policy?.Queries)} />
+          <ConfigRow label="Runtime" value={formatRetention(retention.policy?.Runtime)} />
+          <ConfigRow label="Errors" value={formatRetention(retention.policy?.Errors)} />
+          <ConfigRow label="Alerts" value={formatRetention(retention.policy?.Alerts)} />
+          <ConfigRow label="Health" value={formatRetention(retention.policy?.Health)} />
+          <ConfigRow label="Dependencies" value={formatRetention(retention.policy?.Dependencies)} />
+          <ConfigRow
+            label="Last Cleanup"
+            value={retention.last_cleanup?.runs ? new Date(retention.last_cleanup.last_run).toLocaleString() : 'Not run yet'}
+          />
+          <ConfigRow label="Records Pruned" value={retention.last_cleanup?.runs ? totalPruned(retention.last_cleanup.pruned) : '-'} />
+          {retention.last_cleanup?.error && (
+            <ConfigRow label="Last Error" value={retention.last_cleanup.error} />
+          )}
+          {retention.snapshot && (
+            <>
+              <ConfigRow label="Snapshot File" value={retention.snapshot.path} />
+              <ConfigRow
+                label="Last Snapshot"
+                value={retention.snapshot.last_saved && !retention.snapshot.last_saved.startsWith('0001')
+                  ? new Date(retention.snapshot.last_saved).toLocaleString()
+                  : (retention.snapshot.restored ? 'Restored on startup' : 'Not written yet')}
+              />
+              {retention.snapshot.error && (
+                <ConfigRow label="Snapshot Error" value={retention.snapshot.error} />
+              )}
+            </>
+          )}
+        </Section>
+      )}
+
       {/* Danger Zone */}
       <Section title="Danger Zone">
         <p style={{ color: '#94a3b8', fontSize: 13, marginBottom: 12 }}>
@@ -193,3 +244,13 @@ export default function SettingsPage() {
     </div>
   )
 }
+
+function formatRetention(ns) {
+  if (!ns) return '-'
+  const hours = ns / 3.6e12
+  return hours >= 24 && hours % 24 === 0 ? `${hours / 24}d` : `${+hours.toFixed(2)}h`
+}
+
+function totalPruned(pruned) {
+  return Object.values(pruned || {}).reduce((sum, n) => sum + n, 0)
+}
@@ -125,4 +125,4 @@ In order to be iterable, non-array objects must have a [Symbol.iterator]() metho
 In order to be iterable, non-array objects must have a [Symbol.iterator]() method.`)}function gY(e,t){if(e){if(typeof e=="string")return Kb(e,t);var n=Object.prototype.toString.call(e).slice(8,-1);if(n==="Object"&&e.constructor&&(n=e.constructor.name),n==="Map"||n==="Set")return Array.from(e);if(n==="Arguments"||/^(?:Ui|I)nt(?:8|16|32)(?:Clamped)?Array$/.test(n))return Kb(e,t)}}function bY(e){if(typeof Symbol<"u"&&e[Symbol.iterator]!=null||e["@@iterator"]!=null)return Array.from(e)}function xY(e){if(Array.isArray(e))return Kb(e)}function Kb(e,t){(t==null||t>e.length)&&(t=e.length);for(var n=0,r=new Array(t);n<t;n++)r[n]=e[n];return r}function SY(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function eM(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,CD(r.key),r)}}function OY(e,t,n){return t&&eM(e.prototype,t),n&&eM(e,n),Object.defineProperty(e,"prototype",{writable:!1}),e}function _Y(e,t,n){return t=Cf(t),wY(e,MD()?Reflect.construct(t,n||[],Cf(e).constructor):t.apply(e,n))}function wY(e,t){if(t&&(Co(t)==="object"||typeof t=="function"))return t;if(t!==void 0)throw new TypeError("Derived constructors may only return object or undefined");return AY(e)}function AY(e){if(e===void 0)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}function MD(){try{var e=!Boolean.prototype.valueOf.call(Reflect.construct(Boolean,[],function(){}))}catch{}return(MD=function(){return!!e})()}function Cf(e){return Cf=Object.setPrototypeOf?Object.getPrototypeOf.bind():function(n){return n.__proto__||Object.getPrototypeOf(n)},Cf(e)}function EY(e,t){if(typeof t!="function"&&t!==null)throw new TypeError("Super expression must either be null or a function");e.prototype=Object.create(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),Object.defineProperty(e,"prototype",{writable:!1}),t&&Vb(e,t)}function Vb(e,t){return Vb=Object.setPrototypeOf?Object.setPrototypeOf.bind():function(r,o){return r.__proto__=o,r},Vb(e,t)}function Hn(e,t,n){return t=CD(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function CD(e){var t=TY(e,"string");return Co(t)=="symbol"?t:t+""}function TY(e,t){if(Co(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Co(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return String(e)}var ii=(function(e){function t(){var n;SY(this,t);for(var r=arguments.length,o=new Array(r),u=0;u<r;u++)o[u]=arguments[u];return n=_Y(this,t,[].concat(o)),Hn(n,"state",{isAnimationFinished:!0,totalLength:0}),Hn(n,"generateSimpleStrokeDasharray",function(c,f){return"".concat(f,"px ").concat(c-f,"px")}),Hn(n,"getStrokeDasharray",function(c,f,d){var h=d.reduce(function(A,O){return A+O});if(!h)return n.generateSimpleStrokeDasharray(f,c);for(var v=Math.floor(c/h),y=c%h,g=f-c,S=[],w=0,b=0;w<d.length;b+=d[w],++w)if(b+d[w]>y){S=[].concat(no(d.slice(0,w)),[y-b]);break}var x=S.length%2===0?[0,g]:[g];return[].concat(no(t.repeat(d,v)),no(S),x).map(function(A){return"".concat(A,"px")}).join(", ")}),Hn(n,"id",Io("recharts-line-")),Hn(n,"pathRef",function(c){n.mainCurve=c}),Hn(n,"handleAnimationEnd",function(){n.setState({isAnimationFinished:!0}),n.props.onAnimationEnd&&n.props.onAnimationEnd()}),Hn(n,"handleAnimationStart",function(){n.setState({isAnimationFinished:!1}),n.props.onAnimationStart&&n.props.onAnimationStart()}),n}return EY(t,e),OY(t,[{key:"componentDidMount",value:function(){if(this.props.isAnimationActive){var r=this.getTotalLength();this.setState({totalLength:r})}}},{key:"componentDidUpdate",value:function(){if(this.props.isAnimationActive){var r=this.getTotalLength();r!==this.state.totalLength&&this.setState({totalLength:r})}}},{key:"getTotalLength",value:function(){var r=this.mainCurve;try{return r&&r.getTotalLength&&r.getTotalLength()||0}catch{return 0}}},{key:"renderErrorBar",value:function(r,o){if(this.props.isAnimationActive&&!this.state.isAnimationFinished)return null;var u=this.props,c=u.points,f=u.xAxis,d=u.yAxis,h=u.layout,v=u.children,y=Pn(v,Qu);if(!y)return null;var g=function(b,x){return{x:b.x,y:b.y,value:b.value,errorVal:Gt(b.payload,x)}},S={clipPath:r?"url(#clipPath-".concat(o,")"):null};return I.createElement(Ze,S,y.map(function(w){return I.cloneElement(w,{key:"bar-".concat(w.props.dataKey),data:c,xAxis:f,yAxis:d,layout:h,dataPointFormatter:g})}))}},{key:"renderDots",value:function(r,o,u){var c=this.props.isAnimationActive;if(c&&!this.state.isAnimationFinished)return null;var f=this.props,d=f.dot,h=f.points,v=f.dataKey,y=Ee(this.props,!1),g=Ee(d,!0),S=h.map(function(b,x){var A=fn(fn(fn({key:"dot-".concat(x),r:3},y),g),{},{index:x,cx:b.x,cy:b.y,value:b.value,dataKey:v,payload:b.payload,points:h});return t.renderDotItem(d,A)}),w={clipPath:r?"url(#clipPath-".concat(o?"":"dots-").concat(u,")"):null};return I.createElement(Ze,iu({className:"recharts-line-dots",key:"dots"},w),S)}},{key:"renderCurveStatically",value:function(r,o,u,c){var f=this.props,d=f.type,h=f.layout,v=f.connectNulls;f.ref;var y=Z2(f,pY),g=fn(fn(fn({},Ee(y,!0)),{},{fill:"none",className:"recharts-line-curve",clipPath:o?"url(#clipPath-".concat(u,")"):null,points:r},c),{},{type:d,layout:h,connectNulls:v});return I.createElement(so,iu({},g,{pathRef:this.pathRef}))}},{key:"renderCurveWithAnimation",value:function(r,o){var u=this,c=this.props,f=c.points,d=c.strokeDasharray,h=c.isAnimationActive,v=c.animationBegin,y=c.animationDuration,g=c.animationEasing,S=c.animationId,w=c.animateNewValues,b=c.width,x=c.height,A=this.state,O=A.prevPoints,T=A.totalLength;return I.createElement(ir,{begin:v,duration:y,isActive:h,easing:g,from:{t:0},to:{t:1},key:"line-".concat(S),onAnimationEnd:this.handleAnimationEnd,onAnimationStart:this.handleAnimationStart},function(C){var E=C.t;if(O){var j=O.length/f.length,D=f.map(function(L,V){var Z=Math.floor(V*j);if(O[Z]){var Q=O[Z],q=zt(Q.x,L.x),U=zt(Q.y,L.y);return fn(fn({},L),{},{x:q(E),y:U(E)})}if(w){var J=zt(b*2,L.x),oe=zt(x/2,L.y);return fn(fn({},L),{},{x:J(E),y:oe(E)})}return fn(fn({},L),{},{x:L.x,y:L.y})});return u.renderCurveStatically(D,r,o)}var z=zt(0,T),B=z(E),F;if(d){var H="".concat(d).split(/[,\s]+/gim).map(function(L){return parseFloat(L)});F=u.getStrokeDasharray(B,T,H)}else F=u.generateSimpleStrokeDasharray(T,B);return u.renderCurveStatically(f,r,o,{strokeDasharray:F})})}},{key:"renderCurve",value:function(r,o){var u=this.props,c=u.points,f=u.isAnimationActive,d=this.state,h=d.prevPoints,v=d.totalLength;return f&&c&&c.length&&(!h&&v>0||!xo(h,c))?this.renderCurveWithAnimation(r,o):this.renderCurveStatically(c,r,o)}},{key:"render",value:function(){var r,o=this.props,u=o.hide,c=o.dot,f=o.points,d=o.className,h=o.xAxis,v=o.yAxis,y=o.top,g=o.left,S=o.width,w=o.height,b=o.isAnimationActive,x=o.id;if(u||!f||!f.length)return null;var A=this.state.isAnimationFinished,O=f.length===1,T=ze("recharts-line",d),C=h&&h.allowDataOverflow,E=v&&v.allowDataOverflow,j=C||E,D=je(x)?this.id:x,z=(r=Ee(c,!1))!==null&&r!==void 0?r:{r:3,strokeWidth:2},B=z.r,F=B===void 0?3:B,H=z.strokeWidth,L=H===void 0?2:H,V=YM(c)?c:{},Z=V.clipDot,Q=Z===void 0?!0:Z,q=F*2+L;return I.createElement(Ze,{className:T},C||E?I.createElement("defs",null,I.createElement("clipPath",{id:"clipPath-".concat(D)},I.createElement("rect",{x:C?g:g-S/2,y:E?y:y-w/2,width:C?S:S*2,height:E?w:w*2})),!Q&&I.createElement("clipPath",{id:"clipPath-dots-".concat(D)},I.createElement("rect",{x:g-q/2,y:y-q/2,width:S+q,height:w+q}))):null,!O&&this.renderCurve(j,D),this.renderErrorBar(j,D),(O||c)&&this.renderDots(j,Q,D),(!b||A)&&qr.renderCallByParent(this.props,f))}}],[{key:"getDerivedStateFromProps",value:function(r,o){return r.animationId!==o.prevAnimationId?{prevAnimationId:r.animationId,curPoints:r.points,prevPoints:o.curPoints}:r.points!==o.curPoints?{curPoints:r.points}:null}},{key:"repeat",value:function(r,o){for(var u=r.length%2!==0?[].concat(no(r),[0]):r,c=[],f=0;f<o;++f)c=[].concat(no(c),no(u));return c}},{key:"renderDotItem",value:function(r,o){var u;if(I.isValidElement(r))u=I.cloneElement(r,o);else if(Te(r))u=r(o);else{var c=o.key,f=Z2(o,yY),d=ze("recharts-line-dot",typeof r!="boolean"?r.className:"");u=I.createElement(od,iu({key:c},f,{className:d}))}return u}}])})(P.PureComponent);Hn(ii,"displayName","Line");Hn(ii,"defaultProps",{xAxisId:0,yAxisId:0,connectNulls:!1,activeDot:!0,dot:!0,legendType:"line",stroke:"#3182bd",strokeWidth:1,fill:"#fff",points:[],isAnimationActive:!fi.isSsr,animateNewValues:!0,animationBegin:0,animationDuration:1500,animationEasing:"ease",hide:!1,label:!1});Hn(ii,"getComposedData",function(e){var t=e.props,n=e.xAxis,r=e.yAxis,o=e.xAxisTicks,u=e.yAxisTicks,c=e.dataKey,f=e.bandSize,d=e.displayedData,h=e.offset,v=t.layout,y=d.map(function(g,S){var w=Gt(g,c);return v==="horizontal"?{x:cf({axis:n,ticks:o,bandSize:f,entry:g,index:S}),y:je(w)?null:r.scale(w),value:w,payload:g}:{x:je(w)?null:n.scale(w),y:cf({axis:r,ticks:u,bandSize:f,entry:g,index:S}),value:w,payload:g}});return fn({points:y,layout:v},h)});var jY=["layout","type","stroke","connectNulls","isRange","ref"],MY=["key"],RD;function Ro(e){"@babel/helpers - typeof";return Ro=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},Ro(e)}function DD(e,t){if(e==null)return{};var n=CY(e,t),r,o;if(Object.getOwnPropertySymbols){var u=Object.getOwnPropertySymbols(e);for(o=0;o<u.length;o++)r=u[o],!(t.indexOf(r)>=0)&&Object.prototype.propertyIsEnumerable.call(e,r)&&(n[r]=e[r])}return n}function CY(e,t){if(e==null)return{};var n={};for(var r in e)if(Object.prototype.hasOwnProperty.call(e,r)){if(t.indexOf(r)>=0)continue;n[r]=e[r]}return n}function ei(){return ei=Object.assign?Object.assign.bind():function(e){for(var t=1;t<arguments.length;t++){var n=arguments[t];for(var r in n)Object.prototype.hasOwnProperty.call(n,r)&&(e[r]=n[r])}return e},ei.apply(this,arguments)}function tM(e,t){var n=Object.keys(e);if(Object.getOwnPropertySymbols){var r=Object.getOwnPropertySymbols(e);t&&(r=r.filter(function(o){return Object.getOwnPropertyDescriptor(e,o).enumerable})),n.push.apply(n,r)}return n}function ma(e){for(var t=1;t<arguments.length;t++){var n=arguments[t]!=null?arguments[t]:{};t%2?tM(Object(n),!0).forEach(function(r){tr(e,r,n[r])}):Object.getOwnPropertyDescriptors?Object.defineProperties(e,Object.getOwnPropertyDescriptors(n)):tM(Object(n)).forEach(function(r){Object.defineProperty(e,r,Object.getOwnPropertyDescriptor(n,r))})}return e}function RY(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function nM(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,ND(r.key),r)}}function DY(e,t,n){return t&&nM(e.prototype,t),n&&nM(e,n),Object.defineProperty(e,"prototype",{writable:!1}),e}function PY(e,t,n){return t=Rf(t),NY(e,PD()?Reflect.construct(t,n||[],Rf(e).constructor):t.apply(e,n))}function NY(e,t){if(t&&(Ro(t)==="object"||typeof t=="function"))return t;if(t!==void 0)throw new TypeError("Derived constructors may only return object or undefined");return zY(e)}function zY(e){if(e===void 0)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}function PD(){try{var e=!Boolean.prototype.valueOf.call(Reflect.construct(Boolean,[],function(){}))}catch{}return(PD=function(){return!!e})()}function Rf(e){return Rf=Object.setPrototypeOf?Object.getPrototypeOf.bind():function(n){return n.__proto__||Object.getPrototypeOf(n)},Rf(e)}function qY(e,t){if(typeof t!="function"&&t!==null)throw new TypeError("Super expression must either be null or a function");e.prototype=Object.create(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),Object.defineProperty(e,"prototype",{writable:!1}),t&&Wb(e,t)}function Wb(e,t){return Wb=Object.setPrototypeOf?Object.setPrototypeOf.bind():function(r,o){return r.__proto__=o,r},Wb(e,t)}function tr(e,t,n){return t=ND(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function ND(e){var t=$Y(e,"string");return Ro(t)=="symbol"?t:t+""}function $Y(e,t){if(Ro(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Ro(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return String(e)}var Ur=(function(e){function t(){var n;RY(this,t);for(var r=arguments.length,o=new Array(r),u=0;u<r;u++)o[u]=arguments[u];return n=PY(this,t,[].concat(o)),tr(n,"state",{isAnimationFinished:!0}),tr(n,"id",Io("recharts-area-")),tr(n,"handleAnimationEnd",function(){var c=n.props.onAnimationEnd;n.setState({isAnimationFinished:!0}),Te(c)&&c()}),tr(n,"handleAnimationStart",function(){var c=n.props.onAnimationStart;n.setState({isAnimationFinished:!1}),Te(c)&&c()}),n}return qY(t,e),DY(t,[{key:"renderDots",value:function(r,o,u){var c=this.props.isAnimationActive,f=this.state.isAnimationFinished;if(c&&!f)return null;var d=this.props,h=d.dot,v=d.points,y=d.dataKey,g=Ee(this.props,!1),S=Ee(h,!0),w=v.map(function(x,A){var O=ma(ma(ma({key:"dot-".concat(A),r:3},g),S),{},{index:A,cx:x.x,cy:x.y,dataKey:y,value:x.value,payload:x.payload,points:v});return t.renderDotItem(h,O)}),b={clipPath:r?"url(#clipPath-".concat(o?"":"dots-").concat(u,")"):null};return I.createElement(Ze,ei({className:"recharts-area-dots"},b),w)}},{key:"renderHorizontalRect",value:function(r){var o=this.props,u=o.baseLine,c=o.points,f=o.strokeWidth,d=c[0].x,h=c[c.length-1].x,v=r*Math.abs(d-h),y=ba(c.map(function(g){return g.y||0}));return fe(u)&&typeof u=="number"?y=Math.max(u,y):u&&Array.isArray(u)&&u.length&&(y=Math.max(ba(u.map(function(g){return g.y||0})),y)),fe(y)?I.createElement("rect",{x:d<h?d:d-v,y:0,width:v,height:Math.floor(y+(f?parseInt("".concat(f),10):1))}):null}},{key:"renderVerticalRect",value:function(r){var o=this.props,u=o.baseLine,c=o.points,f=o.strokeWidth,d=c[0].y,h=c[c.length-1].y,v=r*Math.abs(d-h),y=ba(c.map(function(g){return g.x||0}));return fe(u)&&typeof u=="number"?y=Math.max(u,y):u&&Array.isArray(u)&&u.length&&(y=Math.max(ba(u.map(function(g){return g.x||0})),y)),fe(y)?I.createElement("rect",{x:0,y:d<h?d:d-v,width:y+(f?parseInt("".concat(f),10):1),height:Math.floor(v)}):null}},{key:"renderClipRect",value:function(r){var o=this.props.layout;return o==="vertical"?this.renderVerticalRect(r):this.renderHorizontalRect(r)}},{key:"renderAreaStatically",value:function(r,o,u,c){var f=this.props,d=f.layout,h=f.type,v=f.stroke,y=f.connectNulls,g=f.isRange;f.ref;var S=DD(f,jY);return I.createElement(Ze,{clipPath:u?"url(#clipPath-".concat(c,")"):null},I.createElement(so,ei({},Ee(S,!0),{points:r,connectNulls:y,type:h,baseLine:o,layout:d,stroke:"none",className:"recharts-area-area"})),v!=="none"&&I.createElement(so,ei({},Ee(this.props,!1),{className:"recharts-area-curve",layout:d,type:h,connectNulls:y,fill:"none",points:r})),v!=="none"&&g&&I.createElement(so,ei({},Ee(this.props,!1),{className:"recharts-area-curve",layout:d,type:h,connectNulls:y,fill:"none",points:o})))}},{key:"renderAreaWithAnimation",value:function(r,o){var u=this,c=this.props,f=c.points,d=c.baseLine,h=c.isAnimationActive,v=c.animationBegin,y=c.animationDuration,g=c.animationEasing,S=c.animationId,w=this.state,b=w.prevPoints,x=w.prevBaseLine;return I.createElement(ir,{begin:v,duration:y,isActive:h,easing:g,from:{t:0},to:{t:1},key:"area-".concat(S),onAnimationEnd:this.handleAnimationEnd,onAnimationStart:this.handleAnimationStart},function(A){var O=A.t;if(b){var T=b.length/f.length,C=f.map(function(z,B){var F=Math.floor(B*T);if(b[F]){var H=b[F],L=zt(H.x,z.x),V=zt(H.y,z.y);return ma(ma({},z),{},{x:L(O),y:V(O)})}return z}),E;if(fe(d)&&typeof d=="number"){var j=zt(x,d);E=j(O)}else if(je(d)||Uo(d)){var D=zt(x,0);E=D(O)}else E=d.map(function(z,B){var F=Math.floor(B*T);if(x[F]){var H=x[F],L=zt(H.x,z.x),V=zt(H.y,z.y);return ma(ma({},z),{},{x:L(O),y:V(O)})}return z});return u.renderAreaStatically(C,E,r,o)}return I.createElement(Ze,null,I.createElement("defs",null,I.createElement("clipPath",{id:"animationClipPath-".concat(o)},u.renderClipRect(O))),I.createElement(Ze,{clipPath:"url(#animationClipPath-".concat(o,")")},u.renderAreaStatically(f,d,r,o)))})}},{key:"renderArea",value:function(r,o){var u=this.props,c=u.points,f=u.baseLine,d=u.isAnimationActive,h=this.state,v=h.prevPoints,y=h.prevBaseLine,g=h.totalLength;return d&&c&&c.length&&(!v&&g>0||!xo(v,c)||!xo(y,f))?this.renderAreaWithAnimation(r,o):this.renderAreaStatically(c,f,r,o)}},{key:"render",value:function(){var r,o=this.props,u=o.hide,c=o.dot,f=o.points,d=o.className,h=o.top,v=o.left,y=o.xAxis,g=o.yAxis,S=o.width,w=o.height,b=o.isAnimationActive,x=o.id;if(u||!f||!f.length)return null;var A=this.state.isAnimationFinished,O=f.length===1,T=ze("recharts-area",d),C=y&&y.allowDataOverflow,E=g&&g.allowDataOverflow,j=C||E,D=je(x)?this.id:x,z=(r=Ee(c,!1))!==null&&r!==void 0?r:{r:3,strokeWidth:2},B=z.r,F=B===void 0?3:B,H=z.strokeWidth,L=H===void 0?2:H,V=YM(c)?c:{},Z=V.clipDot,Q=Z===void 0?!0:Z,q=F*2+L;return I.createElement(Ze,{className:T},C||E?I.createElement("defs",null,I.createElement("clipPath",{id:"clipPath-".concat(D)},I.createElement("rect",{x:C?v:v-S/2,y:E?h:h-w/2,width:C?S:S*2,height:E?w:w*2})),!Q&&I.createElement("clipPath",{id:"clipPath-dots-".concat(D)},I.createElement("rect",{x:v-q/2,y:h-q/2,width:S+q,height:w+q}))):null,O?null:this.renderArea(j,D),(c||O)&&this.renderDots(j,Q,D),(!b||A)&&qr.renderCallByParent(this.props,f))}}],[{key:"getDerivedStateFromProps",value:function(r,o){return r.animationId!==o.prevAnimationId?{prevAnimationId:r.animationId,curPoints:r.points,curBaseLine:r.baseLine,prevPoints:o.curPoints,prevBaseLine:o.curBaseLine}:r.points!==o.curPoints||r.baseLine!==o.curBaseLine?{curPoints:r.points,curBaseLine:r.baseLine}:null}}])})(P.PureComponent);RD=Ur;tr(Ur,"displayName","Area");tr(Ur,"defaultProps",{stroke:"#3182bd",fill:"#3182bd",fillOpacity:.6,xAxisId:0,yAxisId:0,legendType:"line",connectNulls:!1,points:[],dot:!1,activeDot:!0,hide:!1,isAnimationActive:!fi.isSsr,animationBegin:0,animationDuration:1500,animationEasing:"ease"});tr(Ur,"getBaseValue",function(e,t,n,r){var o=e.layout,u=e.baseValue,c=t.props.baseValue,f=c??u;if(fe(f)&&typeof f=="number")return f;var d=o==="horizontal"?r:n,h=d.scale.domain();if(d.type==="number"){var v=Math.max(h[0],h[1]),y=Math.min(h[0],h[1]);return f==="dataMin"?y:f==="dataMax"||v<0?v:Math.max(Math.min(h[0],h[1]),0)}return f==="dataMin"?h[0]:f==="dataMax"?h[1]:h[0]});tr(Ur,"getComposedData",function(e){var t=e.props,n=e.item,r=e.xAxis,o=e.yAxis,u=e.xAxisTicks,c=e.yAxisTicks,f=e.bandSize,d=e.dataKey,h=e.stackedData,v=e.dataStartIndex,y=e.displayedData,g=e.offset,S=t.layout,w=h&&h.length,b=RD.getBaseValue(t,n,r,o),x=S==="horizontal",A=!1,O=y.map(function(C,E){var j;w?j=h[v+E]:(j=Gt(C,d),Array.isArray(j)?A=!0:j=[b,j]);var D=j[1]==null||w&&Gt(C,d)==null;return x?{x:cf({axis:r,ticks:u,bandSize:f,entry:C,index:E}),y:D?null:o.scale(j[1]),value:j,payload:C}:{x:D?null:r.scale(j[1]),y:cf({axis:o,ticks:c,bandSize:f,entry:C,index:E}),value:j,payload:C}}),T;return w||A?T=O.map(function(C){var E=Array.isArray(C.value)?C.value[0]:null;return x?{x:C.x,y:E!=null&&C.y!=null?o.scale(E):null}:{x:E!=null?r.scale(E):null,y:C.y}}):T=x?o.scale(b):r.scale(b),ma({points:O,baseLine:T,layout:S,isRange:A},g)});tr(Ur,"renderDotItem",function(e,t){var n;if(I.isValidElement(e))n=I.cloneElement(e,t);else if(Te(e))n=e(t);else{var r=ze("recharts-area-dot",typeof e!="boolean"?e.className:""),o=t.key,u=DD(t,MY);n=I.createElement(od,ei({},u,{key:o,className:r}))}return n});function Do(e){"@babel/helpers - typeof";return Do=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},Do(e)}function BY(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function LY(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,$D(r.key),r)}}function kY(e,t,n){return t&&LY(e.prototype,t),Object.defineProperty(e,"prototype",{writable:!1}),e}function UY(e,t,n){return t=Df(t),IY(e,zD()?Reflect.construct(t,n||[],Df(e).constructor):t.apply(e,n))}function IY(e,t){if(t&&(Do(t)==="object"||typeof t=="function"))return t;if(t!==void 0)throw new TypeError("Derived constructors may only return object or undefined");return HY(e)}function HY(e){if(e===void 0)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}function zD(){try{var e=!Boolean.prototype.valueOf.call(Reflect.construct(Boolean,[],function(){}))}catch{}return(zD=function(){return!!e})()}function Df(e){return Df=Object.setPrototypeOf?Object.getPrototypeOf.bind():function(n){return n.__proto__||Object.getPrototypeOf(n)},Df(e)}function GY(e,t){if(typeof t!="function"&&t!==null)throw new TypeError("Super expression must either be null or a function");e.prototype=Object.create(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),Object.defineProperty(e,"prototype",{writable:!1}),t&&Fb(e,t)}function Fb(e,t){return Fb=Object.setPrototypeOf?Object.setPrototypeOf.bind():function(r,o){return r.__proto__=o,r},Fb(e,t)}function qD(e,t,n){return t=$D(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function $D(e){var t=YY(e,"string");return Do(t)=="symbol"?t:t+""}function YY(e,t){if(Do(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Do(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return String(e)}function Qb(){return Qb=Object.assign?Object.assign.bind():function(e){for(var t=1;t<arguments.length;t++){var n=arguments[t];for(var r in n)Object.prototype.hasOwnProperty.call(n,r)&&(e[r]=n[r])}return e},Qb.apply(this,arguments)}function XY(e){var t=e.xAxisId,n=gD(),r=bD(),o=vD(t);return o==null?null:P.createElement(pd,Qb({},o,{className:ze("recharts-".concat(o.axisType," ").concat(o.axisType),o.className),viewBox:{x:0,y:0,width:n,height:r},ticksGenerator:function(c){return Ja(c,!0)}}))}var or=(function(e){function t(){return BY(this,t),UY(this,t,arguments)}return GY(t,e),kY(t,[{key:"render",value:function(){return P.createElement(XY,this.props)}}])})(P.Component);qD(or,"displayName","XAxis");qD(or,"defaultProps",{allowDecimals:!0,hide:!1,orientation:"bottom",width:0,height:30,mirror:!1,xAxisId:0,tickCount:5,type:"category",padding:{left:0,right:0},allowDataOverflow:!1,scale:"auto",reversed:!1,allowDuplicatedCategory:!0});function Po(e){"@babel/helpers - typeof";return Po=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},Po(e)}function KY(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function VY(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,kD(r.key),r)}}function WY(e,t,n){return t&&VY(e.prototype,t),Object.defineProperty(e,"prototype",{writable:!1}),e}function FY(e,t,n){return t=Pf(t),QY(e,BD()?Reflect.construct(t,n||[],Pf(e).constructor):t.apply(e,n))}function QY(e,t){if(t&&(Po(t)==="object"||typeof t=="function"))return t;if(t!==void 0)throw new TypeError("Derived constructors may only return object or undefined");return ZY(e)}function ZY(e){if(e===void 0)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}function BD(){try{var e=!Boolean.prototype.valueOf.call(Reflect.construct(Boolean,[],function(){}))}catch{}return(BD=function(){return!!e})()}function Pf(e){return Pf=Object.setPrototypeOf?Object.getPrototypeOf.bind():function(n){return n.__proto__||Object.getPrototypeOf(n)},Pf(e)}function JY(e,t){if(typeof t!="function"&&t!==null)throw new TypeError("Super expression must either be null or a function");e.prototype=Object.create(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),Object.defineProperty(e,"prototype",{writable:!1}),t&&Zb(e,t)}function Zb(e,t){return Zb=Object.setPrototypeOf?Object.setPrototypeOf.bind():function(r,o){return r.__proto__=o,r},Zb(e,t)}function LD(e,t,n){return t=kD(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function kD(e){var t=eX(e,"string");return Po(t)=="symbol"?t:t+""}function eX(e,t){if(Po(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Po(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return String(e)}function Jb(){return Jb=Object.assign?Object.assign.bind():function(e){for(var t=1;t<arguments.length;t++){var n=arguments[t];for(var r in n)Object.prototype.hasOwnProperty.call(n,r)&&(e[r]=n[r])}return e},Jb.apply(this,arguments)}var tX=function(t){var n=t.yAxisId,r=gD(),o=bD(),u=mD(n);return u==null?null:P.createElement(pd,Jb({},u,{className:ze("recharts-".concat(u.axisType," ").concat(u.axisType),u.className),viewBox:{x:0,y:0,width:r,height:o},ticksGenerator:function(f){return Ja(f,!0)}}))},lr=(function(e){function t(){return KY(this,t),FY(this,t,arguments)}return JY(t,e),WY(t,[{key:"render",value:function(){return P.createElement(tX,this.props)}}])})(P.Component);LD(lr,"displayName","YAxis");LD(lr,"defaultProps",{allowDuplicatedCategory:!0,allowDecimals:!0,hide:!1,orientation:"left",width:60,height:0,mirror:!1,yAxisId:0,tickCount:5,type:"number",padding:{top:0,bottom:0},allowDataOverflow:!1,scale:"auto",reversed:!1});function rM(e){return iX(e)||aX(e)||rX(e)||nX()}function nX(){throw new TypeError(`Invalid attempt to spread non-iterable instance.
 In order to be iterable, non-array objects must have a [Symbol.iterator]() method.`)}function rX(e,t){if(e){if(typeof e=="string")return e0(e,t);var n=Object.prototype.toString.call(e).slice(8,-1);if(n==="Object"&&e.constructor&&(n=e.constructor.name),n==="Map"||n==="Set")return Array.from(e);if(n==="Arguments"||/^(?:Ui|I)nt(?:8|16|32)(?:Clamped)?Array$/.test(n))return e0(e,t)}}function aX(e){if(typeof Symbol<"u"&&e[Symbol.iterator]!=null||e["@@iterator"]!=null)return Array.from(e)}function iX(e){if(Array.isArray(e))return e0(e)}function e0(e,t){(t==null||t>e.length)&&(t=e.length);for(var n=0,r=new Array(t);n<t;n++)r[n]=e[n];return r}var t0=function(t,n,r,o,u){var c=Pn(t,f1),f=Pn(t,fd),d=[].concat(rM(c),rM(f)),h=Pn(t,hd),v="".concat(o,"Id"),y=o[0],g=n;if(d.length&&(g=d.reduce(function(b,x){if(x.props[v]===r&&rr(x.props,"extendDomain")&&fe(x.props[y])){var A=x.props[y];return[Math.min(b[0],A),Math.max(b[1],A)]}return b},g)),h.length){var S="".concat(y,"1"),w="".concat(y,"2");g=h.reduce(function(b,x){if(x.props[v]===r&&rr(x.props,"extendDomain")&&fe(x.props[S])&&fe(x.props[w])){var A=x.props[S],O=x.props[w];return[Math.min(b[0],A,O),Math.max(b[1],A,O)]}return b},g)}return u&&u.length&&(g=u.reduce(function(b,x){return fe(x)?[Math.min(b[0],x),Math.max(b[1],x)]:b},g)),g},Pg={exports:{}},aM;function oX(){return aM||(aM=1,(function(e){var t=Object.prototype.hasOwnProperty,n="~";function r(){}Object.create&&(r.prototype=Object.create(null),new r().__proto__||(n=!1));function o(d,h,v){this.fn=d,this.context=h,this.once=v||!1}function u(d,h,v,y,g){if(typeof v!="function")throw new TypeError("The listener must be a function");var S=new o(v,y||d,g),w=n?n+h:h;return d._events[w]?d._events[w].fn?d._events[w]=[d._events[w],S]:d._events[w].push(S):(d._events[w]=S,d._eventsCount++),d}function c(d,h){--d._eventsCount===0?d._events=new r:delete d._events[h]}function f(){this._events=new r,this._eventsCount=0}f.prototype.eventNames=function(){var h=[],v,y;if(this._eventsCount===0)return h;for(y in v=this._events)t.call(v,y)&&h.push(n?y.slice(1):y);return Object.getOwnPropertySymbols?h.concat(Object.getOwnPropertySymbols(v)):h},f.prototype.listeners=function(h){var v=n?n+h:h,y=this._events[v];if(!y)return[];if(y.fn)return[y.fn];for(var g=0,S=y.length,w=new Array(S);g<S;g++)w[g]=y[g].fn;return w},f.prototype.listenerCount=function(h){var v=n?n+h:h,y=this._events[v];return y?y.fn?1:y.length:0},f.prototype.emit=function(h,v,y,g,S,w){var b=n?n+h:h;if(!this._events[b])return!1;var x=this._events[b],A=arguments.length,O,T;if(x.fn){switch(x.once&&this.removeListener(h,x.fn,void 0,!0),A){case 1:return x.fn.call(x.context),!0;case 2:return x.fn.call(x.context,v),!0;case 3:return x.fn.call(x.context,v,y),!0;case 4:return x.fn.call(x.context,v,y,g),!0;case 5:return x.fn.call(x.context,v,y,g,S),!0;case 6:return x.fn.call(x.context,v,y,g,S,w),!0}for(T=1,O=new Array(A-1);T<A;T++)O[T-1]=arguments[T];x.fn.apply(x.context,O)}else{var C=x.length,E;for(T=0;T<C;T++)switch(x[T].once&&this.removeListener(h,x[T].fn,void 0,!0),A){case 1:x[T].fn.call(x[T].context);break;case 2:x[T].fn.call(x[T].context,v);break;case 3:x[T].fn.call(x[T].context,v,y);break;case 4:x[T].fn.call(x[T].context,v,y,g);break;default:if(!O)for(E=1,O=new Array(A-1);E<A;E++)O[E-1]=arguments[E];x[T].fn.apply(x[T].context,O)}}return!0},f.prototype.on=function(h,v,y){return u(this,h,v,y,!1)},f.prototype.once=function(h,v,y){return u(this,h,v,y,!0)},f.prototype.removeListener=function(h,v,y,g){var S=n?n+h:h;if(!this._events[S])return this;if(!v)return c(this,S),this;var w=this._events[S];if(w.fn)w.fn===v&&(!g||w.once)&&(!y||w.context===y)&&c(this,S);else{for(var b=0,x=[],A=w.length;b<A;b++)(w[b].fn!==v||g&&!w[b].once||y&&w[b].context!==y)&&x.push(w[b]);x.length?this._events[S]=x.length===1?x[0]:x:c(this,S)}return this},f.prototype.removeAllListeners=function(h){var v;return h?(v=n?n+h:h,this._events[v]&&c(this,v)):(this._events=new r,this._eventsCount=0),this},f.prototype.off=f.prototype.removeListener,f.prototype.addListener=f.prototype.on,f.prefixed=n,f.EventEmitter=f,e.exports=f})(Pg)),Pg.exports}var lX=oX();const uX=Je(lX);var Ng=new uX,zg="recharts.syncMouseEvents";function Uu(e){"@babel/helpers - typeof";return Uu=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},Uu(e)}function cX(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function sX(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,UD(r.key),r)}}function fX(e,t,n){return t&&sX(e.prototype,t),Object.defineProperty(e,"prototype",{writable:!1}),e}function qg(e,t,n){return t=UD(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function UD(e){var t=dX(e,"string");return Uu(t)=="symbol"?t:t+""}function dX(e,t){if(Uu(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Uu(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return String(e)}var hX=(function(){function e(){cX(this,e),qg(this,"activeIndex",0),qg(this,"coordinateList",[]),qg(this,"layout","horizontal")}return fX(e,[{key:"setDetails",value:function(n){var r,o=n.coordinateList,u=o===void 0?null:o,c=n.container,f=c===void 0?null:c,d=n.layout,h=d===void 0?null:d,v=n.offset,y=v===void 0?null:v,g=n.mouseHandlerCallback,S=g===void 0?null:g;this.coordinateList=(r=u??this.coordinateList)!==null&&r!==void 0?r:[],this.container=f??this.container,this.layout=h??this.layout,this.offset=y??this.offset,this.mouseHandlerCallback=S??this.mouseHandlerCallback,this.activeIndex=Math.min(Math.max(this.activeIndex,0),this.coordinateList.length-1)}},{key:"focus",value:function(){this.spoofMouse()}},{key:"keyboardEvent",value:function(n){if(this.coordinateList.length!==0)switch(n.key){case"ArrowRight":{if(this.layout!=="horizontal")return;this.activeIndex=Math.min(this.activeIndex+1,this.coordinateList.length-1),this.spoofMouse();break}case"ArrowLeft":{if(this.layout!=="horizontal")return;this.activeIndex=Math.max(this.activeIndex-1,0),this.spoofMouse();break}}}},{key:"setIndex",value:function(n){this.activeIndex=n}},{key:"spoofMouse",value:function(){var n,r;if(this.layout==="horizontal"&&this.coordinateList.length!==0){var o=this.container.getBoundingClientRect(),u=o.x,c=o.y,f=o.height,d=this.coordinateList[this.activeIndex].coordinate,h=((n=window)===null||n===void 0?void 0:n.scrollX)||0,v=((r=window)===null||r===void 0?void 0:r.scrollY)||0,y=u+d+h,g=c+this.offset.top+f/2+v;this.mouseHandlerCallback({pageX:y,pageY:g})}}}])})();function pX(e,t,n){if(n==="number"&&t===!0&&Array.isArray(e)){var r=e==null?void 0:e[0],o=e==null?void 0:e[1];if(r&&o&&fe(r)&&fe(o))return!0}return!1}function yX(e,t,n,r){var o=r/2;return{stroke:"none",fill:"#ccc",x:e==="horizontal"?t.x-o:n.left+.5,y:e==="horizontal"?n.top+.5:t.y-o,width:e==="horizontal"?r:n.width-1,height:e==="horizontal"?n.height-1:r}}function ID(e){var t=e.cx,n=e.cy,r=e.radius,o=e.startAngle,u=e.endAngle,c=qt(t,n,r,o),f=qt(t,n,r,u);return{points:[c,f],cx:t,cy:n,radius:r,startAngle:o,endAngle:u}}function vX(e,t,n){var r,o,u,c;if(e==="horizontal")r=t.x,u=r,o=n.top,c=n.top+n.height;else if(e==="vertical")o=t.y,c=o,r=n.left,u=n.left+n.width;else if(t.cx!=null&&t.cy!=null)if(e==="centric"){var f=t.cx,d=t.cy,h=t.innerRadius,v=t.outerRadius,y=t.angle,g=qt(f,d,h,y),S=qt(f,d,v,y);r=g.x,o=g.y,u=S.x,c=S.y}else return ID(t);return[{x:r,y:o},{x:u,y:c}]}function Iu(e){"@babel/helpers - typeof";return Iu=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},Iu(e)}function iM(e,t){var n=Object.keys(e);if(Object.getOwnPropertySymbols){var r=Object.getOwnPropertySymbols(e);t&&(r=r.filter(function(o){return Object.getOwnPropertyDescriptor(e,o).enumerable})),n.push.apply(n,r)}return n}function Ms(e){for(var t=1;t<arguments.length;t++){var n=arguments[t]!=null?arguments[t]:{};t%2?iM(Object(n),!0).forEach(function(r){mX(e,r,n[r])}):Object.getOwnPropertyDescriptors?Object.defineProperties(e,Object.getOwnPropertyDescriptors(n)):iM(Object(n)).forEach(function(r){Object.defineProperty(e,r,Object.getOwnPropertyDescriptor(n,r))})}return e}function mX(e,t,n){return t=gX(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function gX(e){var t=bX(e,"string");return Iu(t)=="symbol"?t:t+""}function bX(e,t){if(Iu(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(Iu(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return(t==="string"?String:Number)(e)}function xX(e){var t,n,r=e.element,o=e.tooltipEventType,u=e.isActive,c=e.activeCoordinate,f=e.activePayload,d=e.offset,h=e.activeTooltipIndex,v=e.tooltipAxisBandSize,y=e.layout,g=e.chartName,S=(t=r.props.cursor)!==null&&t!==void 0?t:(n=r.type.defaultProps)===null||n===void 0?void 0:n.cursor;if(!r||!S||!u||!c||g!=="ScatterChart"&&o!=="axis")return null;var w,b=so;if(g==="ScatterChart")w=c,b=_H;else if(g==="BarChart")w=yX(y,c,d,v),b=l1;else if(y==="radial"){var x=ID(c),A=x.cx,O=x.cy,T=x.radius,C=x.startAngle,E=x.endAngle;w={cx:A,cy:O,startAngle:C,endAngle:E,innerRadius:T,outerRadius:T},b=XR}else w={points:vX(y,c,d)},b=so;var j=Ms(Ms(Ms(Ms({stroke:"#ccc",pointerEvents:"none"},d),w),Ee(S,!1)),{},{payload:f,payloadIndex:h,className:ze("recharts-tooltip-cursor",S.className)});return P.isValidElement(S)?P.cloneElement(S,j):P.createElement(b,j)}var SX=["item"],OX=["children","className","width","height","style","compact","title","desc"];function No(e){"@babel/helpers - typeof";return No=typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?function(t){return typeof t}:function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},No(e)}function oo(){return oo=Object.assign?Object.assign.bind():function(e){for(var t=1;t<arguments.length;t++){var n=arguments[t];for(var r in n)Object.prototype.hasOwnProperty.call(n,r)&&(e[r]=n[r])}return e},oo.apply(this,arguments)}function oM(e,t){return AX(e)||wX(e,t)||GD(e,t)||_X()}function _X(){throw new TypeError(`Invalid attempt to destructure non-iterable instance.
 In order to be iterable, non-array objects must have a [Symbol.iterator]() method.`)}function wX(e,t){var n=e==null?null:typeof Symbol<"u"&&e[Symbol.iterator]||e["@@iterator"];if(n!=null){var r,o,u,c,f=[],d=!0,h=!1;try{if(u=(n=n.call(e)).next,t!==0)for(;!(d=(r=u.call(n)).done)&&(f.push(r.value),f.length!==t);d=!0);}catch(v){h=!0,o=v}finally{try{if(!d&&n.return!=null&&(c=n.return(),Object(c)!==c))return}finally{if(h)throw o}}return f}}function AX(e){if(Array.isArray(e))return e}function lM(e,t){if(e==null)return{};var n=EX(e,t),r,o;if(Object.getOwnPropertySymbols){var u=Object.getOwnPropertySymbols(e);for(o=0;o<u.length;o++)r=u[o],!(t.indexOf(r)>=0)&&Object.prototype.propertyIsEnumerable.call(e,r)&&(n[r]=e[r])}return n}function EX(e,t){if(e==null)return{};var n={};for(var r in e)if(Object.prototype.hasOwnProperty.call(e,r)){if(t.indexOf(r)>=0)continue;n[r]=e[r]}return n}function TX(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}function jX(e,t){for(var n=0;n<t.length;n++){var r=t[n];r.enumerable=r.enumerable||!1,r.configurable=!0,"value"in r&&(r.writable=!0),Object.defineProperty(e,YD(r.key),r)}}function MX(e,t,n){return t&&jX(e.prototype,t),Object.defineProperty(e,"prototype",{writable:!1}),e}function CX(e,t,n){return t=Nf(t),RX(e,HD()?Reflect.construct(t,n||[],Nf(e).constructor):t.apply(e,n))}function RX(e,t){if(t&&(No(t)==="object"||typeof t=="function"))return t;if(t!==void 0)throw new TypeError("Derived constructors may only return object or undefined");return DX(e)}function DX(e){if(e===void 0)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}function HD(){try{var e=!Boolean.prototype.valueOf.call(Reflect.construct(Boolean,[],function(){}))}catch{}return(HD=function(){return!!e})()}function Nf(e){return Nf=Object.setPrototypeOf?Object.getPrototypeOf.bind():function(n){return n.__proto__||Object.getPrototypeOf(n)},Nf(e)}function PX(e,t){if(typeof t!="function"&&t!==null)throw new TypeError("Super expression must either be null or a function");e.prototype=Object.create(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),Object.defineProperty(e,"prototype",{writable:!1}),t&&n0(e,t)}function n0(e,t){return n0=Object.setPrototypeOf?Object.setPrototypeOf.bind():function(r,o){return r.__proto__=o,r},n0(e,t)}function zo(e){return qX(e)||zX(e)||GD(e)||NX()}function NX(){throw new TypeError(`Invalid attempt to spread non-iterable instance.
-In order to be iterable, non-array objects must have a [Symbol.iterator]() method.`)}function GD(e,t){if(e){if(typeof e=="string")return r0(e,t);var n=Object.prototype.toString.call(e).slice(8,-1);if(n==="Object"&&e.constructor&&(n=e.constructor.name),n==="Map"||n==="Set")return Array.from(e);if(n==="Arguments"||/^(?:Ui|I)nt(?:8|16|32)(?:Clamped)?Array$/.test(n))return r0(e,t)}}function zX(e){if(typeof Symbol<"u"&&e[Symbol.iterator]!=null||e["@@iterator"]!=null)return Array.from(e)}function qX(e){if(Array.isArray(e))return r0(e)}function r0(e,t){(t==null||t>e.length)&&(t=e.length);for(var n=0,r=new Array(t);n<t;n++)r[n]=e[n];return r}function uM(e,t){var n=Object.keys(e);if(Object.getOwnPropertySymbols){var r=Object.getOwnPropertySymbols(e);t&&(r=r.filter(function(o){return Object.getOwnPropertyDescriptor(e,o).enumerable})),n.push.apply(n,r)}return n}function re(e){for(var t=1;t<arguments.length;t++){var n=arguments[t]!=null?arguments[t]:{};t%2?uM(Object(n),!0).forEach(function(r){be(e,r,n[r])}):Object.getOwnPropertyDescriptors?Object.defineProperties(e,Object.getOwnPropertyDescriptors(n)):uM(Object(n)).forEach(function(r){Object.defineProperty(e,r,Object.getOwnPropertyDescriptor(n,r))})}return e}function be(e,t,n){return t=YD(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function YD(e){var t=$X(e,"string");return No(t)=="symbol"?t:t+""}function $X(e,t){if(No(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(No(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return(t==="string"?String:Number)(e)}var BX={xAxis:["bottom","top"],yAxis:["left","right"]},LX={width:"100%",height:"100%"},XD={x:0,y:0};function Cs(e){return e}var kX=function(t,n){return n==="horizontal"?t.x:n==="vertical"?t.y:n==="centric"?t.angle:t.radius},UX=function(t,n,r,o){var u=n.find(function(v){return v&&v.index===r});if(u){if(t==="horizontal")return{x:u.coordinate,y:o.y};if(t==="vertical")return{x:o.x,y:u.coordinate};if(t==="centric"){var c=u.coordinate,f=o.radius;return re(re(re({},o),qt(o.cx,o.cy,f,c)),{},{angle:c,radius:f})}var d=u.coordinate,h=o.angle;return re(re(re({},o),qt(o.cx,o.cy,d,h)),{},{angle:h,radius:d})}return XD},yd=function(t,n){var r=n.graphicalItems,o=n.dataStartIndex,u=n.dataEndIndex,c=(r??[]).reduce(function(f,d){var h=d.props.data;return h&&h.length?[].concat(zo(f),zo(h)):f},[]);return c.length>0?c:t&&t.length&&fe(o)&&fe(u)?t.slice(o,u+1):[]};function KD(e){return e==="number"?[0,"auto"]:void 0}var a0=function(t,n,r,o){var u=t.graphicalItems,c=t.tooltipAxis,f=yd(n,t);return r<0||!u||!u.length||r>=f.length?null:u.reduce(function(d,h){var v,y=(v=h.props.data)!==null&&v!==void 0?v:n;y&&t.dataStartIndex+t.dataEndIndex!==0&&t.dataEndIndex-t.dataStartIndex>=r&&(y=y.slice(t.dataStartIndex,t.dataEndIndex+1));var g;if(c.dataKey&&!c.allowDuplicatedCategory){var S=y===void 0?f:y;g=Ns(S,c.dataKey,o)}else g=y&&y[r]||f[r];return g?[].concat(zo(d),[HR(h,g)]):d},[])},cM=function(t,n,r,o){var u=o||{x:t.chartX,y:t.chartY},c=kX(u,r),f=t.orderedTooltipTicks,d=t.tooltipAxis,h=t.tooltipTicks,v=nU(c,f,h,d);if(v>=0&&h){var y=h[v]&&h[v].value,g=a0(t,n,v,y),S=UX(r,f,v,u);return{activeTooltipIndex:v,activeLabel:y,activePayload:g,activeCoordinate:S}}return null},IX=function(t,n){var r=n.axes,o=n.graphicalItems,u=n.axisType,c=n.axisIdKey,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.layout,y=t.children,g=t.stackOffset,S=UR(v,u);return r.reduce(function(w,b){var x,A=b.type.defaultProps!==void 0?re(re({},b.type.defaultProps),b.props):b.props,O=A.type,T=A.dataKey,C=A.allowDataOverflow,E=A.allowDuplicatedCategory,j=A.scale,D=A.ticks,z=A.includeHidden,B=A[c];if(w[B])return w;var F=yd(t.data,{graphicalItems:o.filter(function(G){var te,se=c in G.props?G.props[c]:(te=G.type.defaultProps)===null||te===void 0?void 0:te[c];return se===B}),dataStartIndex:d,dataEndIndex:h}),H=F.length,L,V,Z;pX(A.domain,C,O)&&(L=Sb(A.domain,null,C),S&&(O==="number"||j!=="auto")&&(Z=ru(F,T,"category")));var Q=KD(O);if(!L||L.length===0){var q,U=(q=A.domain)!==null&&q!==void 0?q:Q;if(T){if(L=ru(F,T,O),O==="category"&&S){var J=V6(L);E&&J?(V=L,L=xf(0,H)):E||(L=_j(U,L,b).reduce(function(G,te){return G.indexOf(te)>=0?G:[].concat(zo(G),[te])},[]))}else if(O==="category")E?L=L.filter(function(G){return G!==""&&!je(G)}):L=_j(U,L,b).reduce(function(G,te){return G.indexOf(te)>=0||te===""||je(te)?G:[].concat(zo(G),[te])},[]);else if(O==="number"){var oe=lU(F,o.filter(function(G){var te,se,pe=c in G.props?G.props[c]:(te=G.type.defaultProps)===null||te===void 0?void 0:te[c],me="hide"in G.props?G.props.hide:(se=G.type.defaultProps)===null||se===void 0?void 0:se.hide;return pe===B&&(z||!me)}),T,u,v);oe&&(L=oe)}S&&(O==="number"||j!=="auto")&&(Z=ru(F,T,"category"))}else S?L=xf(0,H):f&&f[B]&&f[B].hasStack&&O==="number"?L=g==="expand"?[0,1]:IR(f[B].stackGroups,d,h):L=kR(F,o.filter(function(G){var te=c in G.props?G.props[c]:G.type.defaultProps[c],se="hide"in G.props?G.props.hide:G.type.defaultProps.hide;return te===B&&(z||!se)}),O,v,!0);if(O==="number")L=t0(y,L,B,u,D),U&&(L=Sb(U,L,C));else if(O==="category"&&U){var ue=U,N=L.every(function(G){return ue.indexOf(G)>=0});N&&(L=ue)}}return re(re({},w),{},be({},B,re(re({},A),{},{axisType:u,domain:L,categoricalDomain:Z,duplicateDomain:V,originalDomain:(x=A.domain)!==null&&x!==void 0?x:Q,isCategorical:S,layout:v})))},{})},HX=function(t,n){var r=n.graphicalItems,o=n.Axis,u=n.axisType,c=n.axisIdKey,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.layout,y=t.children,g=yd(t.data,{graphicalItems:r,dataStartIndex:d,dataEndIndex:h}),S=g.length,w=UR(v,u),b=-1;return r.reduce(function(x,A){var O=A.type.defaultProps!==void 0?re(re({},A.type.defaultProps),A.props):A.props,T=O[c],C=KD("number");if(!x[T]){b++;var E;return w?E=xf(0,S):f&&f[T]&&f[T].hasStack?(E=IR(f[T].stackGroups,d,h),E=t0(y,E,T,u)):(E=Sb(C,kR(g,r.filter(function(j){var D,z,B=c in j.props?j.props[c]:(D=j.type.defaultProps)===null||D===void 0?void 0:D[c],F="hide"in j.props?j.props.hide:(z=j.type.defaultProps)===null||z===void 0?void 0:z.hide;return B===T&&!F}),"number",v),o.defaultProps.allowDataOverflow),E=t0(y,E,T,u)),re(re({},x),{},be({},T,re(re({axisType:u},o.defaultProps),{},{hide:!0,orientation:Dn(BX,"".concat(u,".").concat(b%2),null),domain:E,originalDomain:C,isCategorical:w,layout:v})))}return x},{})},GX=function(t,n){var r=n.axisType,o=r===void 0?"xAxis":r,u=n.AxisComp,c=n.graphicalItems,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.children,y="".concat(o,"Id"),g=Pn(v,u),S={};return g&&g.length?S=IX(t,{axes:g,graphicalItems:c,axisType:o,axisIdKey:y,stackGroups:f,dataStartIndex:d,dataEndIndex:h}):c&&c.length&&(S=HX(t,{Axis:u,graphicalItems:c,axisType:o,axisIdKey:y,stackGroups:f,dataStartIndex:d,dataEndIndex:h})),S},YX=function(t){var n=ro(t),r=Ja(n,!1,!0);return{tooltipTicks:r,orderedTooltipTicks:N0(r,function(o){return o.coordinate}),tooltipAxis:n,tooltipAxisBandSize:sf(n,r)}},sM=function(t){var n=t.children,r=t.defaultShowTooltip,o=hn(n,wo),u=0,c=0;return t.data&&t.data.length!==0&&(c=t.data.length-1),o&&o.props&&(o.props.startIndex>=0&&(u=o.props.startIndex),o.props.endIndex>=0&&(c=o.props.endIndex)),{chartX:0,chartY:0,dataStartIndex:u,dataEndIndex:c,activeTooltipIndex:-1,isTooltipActive:!!r}},XX=function(t){return!t||!t.length?!1:t.some(function(n){var r=Nr(n&&n.type);return r&&r.indexOf("Bar")>=0})},fM=function(t){return t==="horizontal"?{numericAxisName:"yAxis",cateAxisName:"xAxis"}:t==="vertical"?{numericAxisName:"xAxis",cateAxisName:"yAxis"}:t==="centric"?{numericAxisName:"radiusAxis",cateAxisName:"angleAxis"}:{numericAxisName:"angleAxis",cateAxisName:"radiusAxis"}},KX=function(t,n){var r=t.props,o=t.graphicalItems,u=t.xAxisMap,c=u===void 0?{}:u,f=t.yAxisMap,d=f===void 0?{}:f,h=r.width,v=r.height,y=r.children,g=r.margin||{},S=hn(y,wo),w=hn(y,ni),b=Object.keys(d).reduce(function(E,j){var D=d[j],z=D.orientation;return!D.mirror&&!D.hide?re(re({},E),{},be({},z,E[z]+D.width)):E},{left:g.left||0,right:g.right||0}),x=Object.keys(c).reduce(function(E,j){var D=c[j],z=D.orientation;return!D.mirror&&!D.hide?re(re({},E),{},be({},z,Dn(E,"".concat(z))+D.height)):E},{top:g.top||0,bottom:g.bottom||0}),A=re(re({},x),b),O=A.bottom;S&&(A.bottom+=S.props.height||wo.defaultProps.height),w&&n&&(A=iU(A,o,r,n));var T=h-A.left-A.right,C=v-A.top-A.bottom;return re(re({brushBottom:O},A),{},{width:Math.max(T,0),height:Math.max(C,0)})},VX=function(t,n){if(n==="xAxis")return t[n].width;if(n==="yAxis")return t[n].height},h1=function(t){var n=t.chartName,r=t.GraphicalChild,o=t.defaultTooltipEventType,u=o===void 0?"axis":o,c=t.validateTooltipEventTypes,f=c===void 0?["axis"]:c,d=t.axisComponents,h=t.legendContent,v=t.formatAxisMap,y=t.defaultProps,g=function(A,O){var T=O.graphicalItems,C=O.stackGroups,E=O.offset,j=O.updateId,D=O.dataStartIndex,z=O.dataEndIndex,B=A.barSize,F=A.layout,H=A.barGap,L=A.barCategoryGap,V=A.maxBarSize,Z=fM(F),Q=Z.numericAxisName,q=Z.cateAxisName,U=XX(T),J=[];return T.forEach(function(oe,ue){var N=yd(A.data,{graphicalItems:[oe],dataStartIndex:D,dataEndIndex:z}),G=oe.type.defaultProps!==void 0?re(re({},oe.type.defaultProps),oe.props):oe.props,te=G.dataKey,se=G.maxBarSize,pe=G["".concat(Q,"Id")],me=G["".concat(q,"Id")],Me={},ke=d.reduce(function($n,cr){var Ko=O["".concat(cr.axisType,"Map")],Bt=G["".concat(cr.axisType,"Id")];Ko&&Ko[Bt]||cr.axisType==="zAxis"||ci();var Zu=Ko[Bt];return re(re({},$n),{},be(be({},cr.axisType,Zu),"".concat(cr.axisType,"Ticks"),Ja(Zu)))},Me),ce=ke[q],xe=ke["".concat(q,"Ticks")],Se=C&&C[pe]&&C[pe].hasStack&&bU(oe,C[pe].stackGroups),le=Nr(oe.type).indexOf("Bar")>=0,et=sf(ce,xe),we=[],lt=U&&rU({barSize:B,stackGroups:C,totalSize:VX(ke,q)});if(le){var ut,$t,qn=je(se)?V:se,Wn=(ut=($t=sf(ce,xe,!0))!==null&&$t!==void 0?$t:qn)!==null&&ut!==void 0?ut:0;we=aU({barGap:H,barCategoryGap:L,bandSize:Wn!==et?Wn:et,sizeList:lt[me],maxBarSize:qn}),Wn!==et&&(we=we.map(function($n){return re(re({},$n),{},{position:re(re({},$n.position),{},{offset:$n.position.offset-Wn/2})})}))}var Yr=oe&&oe.type&&oe.type.getComposedData;Yr&&J.push({props:re(re({},Yr(re(re({},ke),{},{displayedData:N,props:A,dataKey:te,item:oe,bandSize:et,barPosition:we,offset:E,stackedData:Se,layout:F,dataStartIndex:D,dataEndIndex:z}))),{},be(be(be({key:oe.key||"item-".concat(ue)},Q,ke[Q]),q,ke[q]),"animationId",j)),childIndex:o8(oe,A.children),item:oe})}),J},S=function(A,O){var T=A.props,C=A.dataStartIndex,E=A.dataEndIndex,j=A.updateId;if(!Bw({props:T}))return null;var D=T.children,z=T.layout,B=T.stackOffset,F=T.data,H=T.reverseStackOrder,L=fM(z),V=L.numericAxisName,Z=L.cateAxisName,Q=Pn(D,r),q=vU(F,Q,"".concat(V,"Id"),"".concat(Z,"Id"),B,H),U=d.reduce(function(G,te){var se="".concat(te.axisType,"Map");return re(re({},G),{},be({},se,GX(T,re(re({},te),{},{graphicalItems:Q,stackGroups:te.axisType===V&&q,dataStartIndex:C,dataEndIndex:E}))))},{}),J=KX(re(re({},U),{},{props:T,graphicalItems:Q}),O==null?void 0:O.legendBBox);Object.keys(U).forEach(function(G){U[G]=v(T,U[G],J,G.replace("Map",""),n)});var oe=U["".concat(Z,"Map")],ue=YX(oe),N=g(T,re(re({},U),{},{dataStartIndex:C,dataEndIndex:E,updateId:j,graphicalItems:Q,stackGroups:q,offset:J}));return re(re({formattedGraphicalItems:N,graphicalItems:Q,offset:J,stackGroups:q},ue),U)},w=(function(x){function A(O){var T,C,E;return TX(this,A),E=CX(this,A,[O]),be(E,"eventEmitterSymbol",Symbol("rechartsEventEmitter")),be(E,"accessibilityManager",new hX),be(E,"handleLegendBBoxUpdate",function(j){if(j){var D=E.state,z=D.dataStartIndex,B=D.dataEndIndex,F=D.updateId;E.setState(re({legendBBox:j},S({props:E.props,dataStartIndex:z,dataEndIndex:B,updateId:F},re(re({},E.state),{},{legendBBox:j}))))}}),be(E,"handleReceiveSyncEvent",function(j,D,z){if(E.props.syncId===j){if(z===E.eventEmitterSymbol&&typeof E.props.syncMethod!="function")return;E.applySyncEvent(D)}}),be(E,"handleBrushChange",function(j){var D=j.startIndex,z=j.endIndex;if(D!==E.state.dataStartIndex||z!==E.state.dataEndIndex){var B=E.state.updateId;E.setState(function(){return re({dataStartIndex:D,dataEndIndex:z},S({props:E.props,dataStartIndex:D,dataEndIndex:z,updateId:B},E.state))}),E.triggerSyncEvent({dataStartIndex:D,dataEndIndex:z})}}),be(E,"handleMouseEnter",function(j){var D=E.getMouseInfo(j);if(D){var z=re(re({},D),{},{isTooltipActive:!0});E.setState(z),E.triggerSyncEvent(z);var B=E.props.onMouseEnter;Te(B)&&B(z,j)}}),be(E,"triggeredAfterMouseMove",function(j){var D=E.getMouseInfo(j),z=D?re(re({},D),{},{isTooltipActive:!0}):{isTooltipActive:!1};E.setState(z),E.triggerSyncEvent(z);var B=E.props.onMouseMove;Te(B)&&B(z,j)}),be(E,"handleItemMouseEnter",function(j){E.setState(function(){return{isTooltipActive:!0,activeItem:j,activePayload:j.tooltipPayload,activeCoordinate:j.tooltipPosition||{x:j.cx,y:j.cy}}})}),be(E,"handleItemMouseLeave",function(){E.setState(function(){return{isTooltipActive:!1}})}),be(E,"handleMouseMove",function(j){j.persist(),E.throttleTriggeredAfterMouseMove(j)}),be(E,"handleMouseLeave",function(j){E.throttleTriggeredAfterMouseMove.cancel();var D={isTooltipActive:!1};E.setState(D),E.triggerSyncEvent(D);var z=E.props.onMouseLeave;Te(z)&&z(D,j)}),be(E,"handleOuterEvent",function(j){var D=i8(j),z=Dn(E.props,"".concat(D));if(D&&Te(z)){var B,F;/.*touch.*/i.test(D)?F=E.getMouseInfo(j.changedTouches[0]):F=E.getMouseInfo(j),z((B=F)!==null&&B!==void 0?B:{},j)}}),be(E,"handleClick",function(j){var D=E.getMouseInfo(j);if(D){var z=re(re({},D),{},{isTooltipActive:!0});E.setState(z),E.triggerSyncEvent(z);var B=E.props.onClick;Te(B)&&B(z,j)}}),be(E,"handleMouseDown",function(j){var D=E.props.onMouseDown;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleMouseUp",function(j){var D=E.props.onMouseUp;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleTouchMove",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.throttleTriggeredAfterMouseMove(j.changedTouches[0])}),be(E,"handleTouchStart",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.handleMouseDown(j.changedTouches[0])}),be(E,"handleTouchEnd",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.handleMouseUp(j.changedTouches[0])}),be(E,"handleDoubleClick",function(j){var D=E.props.onDoubleClick;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleContextMenu",function(j){var D=E.props.onContextMenu;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"triggerSyncEvent",function(j){E.props.syncId!==void 0&&Ng.emit(zg,E.props.syncId,j,E.eventEmitterSymbol)}),be(E,"applySyncEvent",function(j){var D=E.props,z=D.layout,B=D.syncMethod,F=E.state.updateId,H=j.dataStartIndex,L=j.dataEndIndex;if(j.dataStartIndex!==void 0||j.dataEndIndex!==void 0)E.setState(re({dataStartIndex:H,dataEndIndex:L},S({props:E.props,dataStartIndex:H,dataEndIndex:L,updateId:F},E.state)));else if(j.activeTooltipIndex!==void 0){var V=j.chartX,Z=j.chartY,Q=j.activeTooltipIndex,q=E.state,U=q.offset,J=q.tooltipTicks;if(!U)return;if(typeof B=="function")Q=B(J,j);else if(B==="value"){Q=-1;for(var oe=0;oe<J.length;oe++)if(J[oe].value===j.activeLabel){Q=oe;break}}var ue=re(re({},U),{},{x:U.left,y:U.top}),N=Math.min(V,ue.x+ue.width),G=Math.min(Z,ue.y+ue.height),te=J[Q]&&J[Q].value,se=a0(E.state,E.props.data,Q),pe=J[Q]?{x:z==="horizontal"?J[Q].coordinate:N,y:z==="horizontal"?G:J[Q].coordinate}:XD;E.setState(re(re({},j),{},{activeLabel:te,activeCoordinate:pe,activePayload:se,activeTooltipIndex:Q}))}else E.setState(j)}),be(E,"renderCursor",function(j){var D,z=E.state,B=z.isTooltipActive,F=z.activeCoordinate,H=z.activePayload,L=z.offset,V=z.activeTooltipIndex,Z=z.tooltipAxisBandSize,Q=E.getTooltipEventType(),q=(D=j.props.active)!==null&&D!==void 0?D:B,U=E.props.layout,J=j.key||"_recharts-cursor";return I.createElement(xX,{key:J,activeCoordinate:F,activePayload:H,activeTooltipIndex:V,chartName:n,element:j,isActive:q,layout:U,offset:L,tooltipAxisBandSize:Z,tooltipEventType:Q})}),be(E,"renderPolarAxis",function(j,D,z){var B=Dn(j,"type.axisType"),F=Dn(E.state,"".concat(B,"Map")),H=j.type.defaultProps,L=H!==void 0?re(re({},H),j.props):j.props,V=F&&F[L["".concat(B,"Id")]];return P.cloneElement(j,re(re({},V),{},{className:ze(B,V.className),key:j.key||"".concat(D,"-").concat(z),ticks:Ja(V,!0)}))}),be(E,"renderPolarGrid",function(j){var D=j.props,z=D.radialLines,B=D.polarAngles,F=D.polarRadius,H=E.state,L=H.radiusAxisMap,V=H.angleAxisMap,Z=ro(L),Q=ro(V),q=Q.cx,U=Q.cy,J=Q.innerRadius,oe=Q.outerRadius;return P.cloneElement(j,{polarAngles:Array.isArray(B)?B:Ja(Q,!0).map(function(ue){return ue.coordinate}),polarRadius:Array.isArray(F)?F:Ja(Z,!0).map(function(ue){return ue.coordinate}),cx:q,cy:U,innerRadius:J,outerRadius:oe,key:j.key||"polar-grid",radialLines:z})}),be(E,"renderLegend",function(){var j=E.state.formattedGraphicalItems,D=E.props,z=D.children,B=D.width,F=D.height,H=E.props.margin||{},L=B-(H.left||0)-(H.right||0),V=BR({children:z,formattedGraphicalItems:j,legendWidth:L,legendContent:h});if(!V)return null;var Z=V.item,Q=lM(V,SX);return P.cloneElement(Z,re(re({},Q),{},{chartWidth:B,chartHeight:F,margin:H,onBBoxUpdate:E.handleLegendBBoxUpdate}))}),be(E,"renderTooltip",function(){var j,D=E.props,z=D.children,B=D.accessibilityLayer,F=hn(z,Zt);if(!F)return null;var H=E.state,L=H.isTooltipActive,V=H.activeCoordinate,Z=H.activePayload,Q=H.activeLabel,q=H.offset,U=(j=F.props.active)!==null&&j!==void 0?j:L;return P.cloneElement(F,{viewBox:re(re({},q),{},{x:q.left,y:q.top}),active:U,label:Q,payload:U?Z:[],coordinate:V,accessibilityLayer:B})}),be(E,"renderBrush",function(j){var D=E.props,z=D.margin,B=D.data,F=E.state,H=F.offset,L=F.dataStartIndex,V=F.dataEndIndex,Z=F.updateId;return P.cloneElement(j,{key:j.key||"_recharts-brush",onChange:As(E.handleBrushChange,j.props.onChange),data:B,x:fe(j.props.x)?j.props.x:H.left,y:fe(j.props.y)?j.props.y:H.top+H.height+H.brushBottom-(z.bottom||0),width:fe(j.props.width)?j.props.width:H.width,startIndex:L,endIndex:V,updateId:"brush-".concat(Z)})}),be(E,"renderReferenceElement",function(j,D,z){if(!j)return null;var B=E,F=B.clipPathId,H=E.state,L=H.xAxisMap,V=H.yAxisMap,Z=H.offset,Q=j.type.defaultProps||{},q=j.props,U=q.xAxisId,J=U===void 0?Q.xAxisId:U,oe=q.yAxisId,ue=oe===void 0?Q.yAxisId:oe;return P.cloneElement(j,{key:j.key||"".concat(D,"-").concat(z),xAxis:L[J],yAxis:V[ue],viewBox:{x:Z.left,y:Z.top,width:Z.width,height:Z.height},clipPathId:F})}),be(E,"renderActivePoints",function(j){var D=j.item,z=j.activePoint,B=j.basePoint,F=j.childIndex,H=j.isRange,L=[],V=D.props.key,Z=D.item.type.defaultProps!==void 0?re(re({},D.item.type.defaultProps),D.item.props):D.item.props,Q=Z.activeDot,q=Z.dataKey,U=re(re({index:F,dataKey:q,cx:z.x,cy:z.y,r:4,fill:o1(D.item),strokeWidth:2,stroke:"#fff",payload:z.payload,value:z.value},Ee(Q,!1)),zs(Q));return L.push(A.renderActiveDot(Q,U,"".concat(V,"-activePoint-").concat(F))),B?L.push(A.renderActiveDot(Q,re(re({},U),{},{cx:B.x,cy:B.y}),"".concat(V,"-basePoint-").concat(F))):H&&L.push(null),L}),be(E,"renderGraphicChild",function(j,D,z){var B=E.filterFormatItem(j,D,z);if(!B)return null;var F=E.getTooltipEventType(),H=E.state,L=H.isTooltipActive,V=H.tooltipAxis,Z=H.activeTooltipIndex,Q=H.activeLabel,q=E.props.children,U=hn(q,Zt),J=B.props,oe=J.points,ue=J.isRange,N=J.baseLine,G=B.item.type.defaultProps!==void 0?re(re({},B.item.type.defaultProps),B.item.props):B.item.props,te=G.activeDot,se=G.hide,pe=G.activeBar,me=G.activeShape,Me=!!(!se&&L&&U&&(te||pe||me)),ke={};F!=="axis"&&U&&U.props.trigger==="click"?ke={onClick:As(E.handleItemMouseEnter,j.props.onClick)}:F!=="axis"&&(ke={onMouseLeave:As(E.handleItemMouseLeave,j.props.onMouseLeave),onMouseEnter:As(E.handleItemMouseEnter,j.props.onMouseEnter)});var ce=P.cloneElement(j,re(re({},B.props),ke));function xe(cr){return typeof V.dataKey=="function"?V.dataKey(cr.payload):null}if(Me)if(Z>=0){var Se,le;if(V.dataKey&&!V.allowDuplicatedCategory){var et=typeof V.dataKey=="function"?xe:"payload.".concat(V.dataKey.toString());Se=Ns(oe,et,Q),le=ue&&N&&Ns(N,et,Q)}else Se=oe==null?void 0:oe[Z],le=ue&&N&&N[Z];if(me||pe){var we=j.props.activeIndex!==void 0?j.props.activeIndex:Z;return[P.cloneElement(j,re(re(re({},B.props),ke),{},{activeIndex:we})),null,null]}if(!je(Se))return[ce].concat(zo(E.renderActivePoints({item:B,activePoint:Se,basePoint:le,childIndex:Z,isRange:ue})))}else{var lt,ut=(lt=E.getItemByXY(E.state.activeCoordinate))!==null&&lt!==void 0?lt:{graphicalItem:ce},$t=ut.graphicalItem,qn=$t.item,Wn=qn===void 0?j:qn,Yr=$t.childIndex,$n=re(re(re({},B.props),ke),{},{activeIndex:Yr});return[P.cloneElement(Wn,$n),null,null]}return ue?[ce,null,null]:[ce,null]}),be(E,"renderCustomized",function(j,D,z){return P.cloneElement(j,re(re({key:"recharts-customized-".concat(z)},E.props),E.state))}),be(E,"renderMap",{CartesianGrid:{handler:Cs,once:!0},ReferenceArea:{handler:E.renderReferenceElement},ReferenceLine:{handler:Cs},ReferenceDot:{handler:E.renderReferenceElement},XAxis:{handler:Cs},YAxis:{handler:Cs},Brush:{handler:E.renderBrush,once:!0},Bar:{handler:E.renderGraphicChild},Line:{handler:E.renderGraphicChild},Area:{handler:E.renderGraphicChild},Radar:{handler:E.renderGraphicChild},RadialBar:{handler:E.renderGraphicChild},Scatter:{handler:E.renderGraphicChild},Pie:{handler:E.renderGraphicChild},Funnel:{handler:E.renderGraphicChild},Tooltip:{handler:E.renderCursor,once:!0},PolarGrid:{handler:E.renderPolarGrid,once:!0},PolarAngleAxis:{handler:E.renderPolarAxis},PolarRadiusAxis:{handler:E.renderPolarAxis},Customized:{handler:E.renderCustomized}}),E.clipPathId="".concat((T=O.id)!==null&&T!==void 0?T:Io("recharts"),"-clip"),E.throttleTriggeredAfterMouseMove=BC(E.triggeredAfterMouseMove,(C=O.throttleDelay)!==null&&C!==void 0?C:1e3/60),E.state={},E}return PX(A,x),MX(A,[{key:"componentDidMount",value:function(){var T,C;this.addListener(),this.accessibilityManager.setDetails({container:this.container,offset:{left:(T=this.props.margin.left)!==null&&T!==void 0?T:0,top:(C=this.props.margin.top)!==null&&C!==void 0?C:0},coordinateList:this.state.tooltipTicks,mouseHandlerCallback:this.triggeredAfterMouseMove,layout:this.props.layout}),this.displayDefaultTooltip()}},{key:"displayDefaultTooltip",value:function(){var T=this.props,C=T.children,E=T.data,j=T.height,D=T.layout,z=hn(C,Zt);if(z){var B=z.props.defaultIndex;if(!(typeof B!="number"||B<0||B>this.state.tooltipTicks.length-1)){var F=this.state.tooltipTicks[B]&&this.state.tooltipTicks[B].value,H=a0(this.state,E,B,F),L=this.state.tooltipTicks[B].coordinate,V=(this.state.offset.top+j)/2,Z=D==="horizontal",Q=Z?{x:L,y:V}:{y:L,x:V},q=this.state.formattedGraphicalItems.find(function(J){var oe=J.item;return oe.type.name==="Scatter"});q&&(Q=re(re({},Q),q.props.points[B].tooltipPosition),H=q.props.points[B].tooltipPayload);var U={activeTooltipIndex:B,isTooltipActive:!0,activeLabel:F,activePayload:H,activeCoordinate:Q};this.setState(U),this.renderCursor(z),this.accessibilityManager.setIndex(B)}}}},{key:"getSnapshotBeforeUpdate",value:function(T,C){if(!this.props.accessibilityLayer)return null;if(this.state.tooltipTicks!==C.tooltipTicks&&this.accessibilityManager.setDetails({coordinateList:this.state.tooltipTicks}),this.props.layout!==T.layout&&this.accessibilityManager.setDetails({layout:this.props.layout}),this.props.margin!==T.margin){var E,j;this.accessibilityManager.setDetails({offset:{left:(E=this.props.margin.left)!==null&&E!==void 0?E:0,top:(j=this.props.margin.top)!==null&&j!==void 0?j:0}})}return null}},{key:"componentDidUpdate",value:function(T){Hg([hn(T.children,Zt)],[hn(this.props.children,Zt)])||this.displayDefaultTooltip()}},{key:"componentWillUnmount",value:function(){this.removeListener(),this.throttleTriggeredAfterMouseMove.cancel()}},{key:"getTooltipEventType",value:function(){var T=hn(this.props.children,Zt);if(T&&typeof T.props.shared=="boolean"){var C=T.props.shared?"axis":"item";return f.indexOf(C)>=0?C:u}return u}},{key:"getMouseInfo",value:function(T){if(!this.container)return null;var C=this.container,E=C.getBoundingClientRect(),j=X$(E),D={chartX:Math.round(T.pageX-j.left),chartY:Math.round(T.pageY-j.top)},z=E.width/C.offsetWidth||1,B=this.inRange(D.chartX,D.chartY,z);if(!B)return null;var F=this.state,H=F.xAxisMap,L=F.yAxisMap,V=this.getTooltipEventType(),Z=cM(this.state,this.props.data,this.props.layout,B);if(V!=="axis"&&H&&L){var Q=ro(H).scale,q=ro(L).scale,U=Q&&Q.invert?Q.invert(D.chartX):null,J=q&&q.invert?q.invert(D.chartY):null;return re(re({},D),{},{xValue:U,yValue:J},Z)}return Z?re(re({},D),Z):null}},{key:"inRange",value:function(T,C){var E=arguments.length>2&&arguments[2]!==void 0?arguments[2]:1,j=this.props.layout,D=T/E,z=C/E;if(j==="horizontal"||j==="vertical"){var B=this.state.offset,F=D>=B.left&&D<=B.left+B.width&&z>=B.top&&z<=B.top+B.height;return F?{x:D,y:z}:null}var H=this.state,L=H.angleAxisMap,V=H.radiusAxisMap;if(L&&V){var Z=ro(L);return Ej({x:D,y:z},Z)}return null}},{key:"parseEventsOfWrapper",value:function(){var T=this.props.children,C=this.getTooltipEventType(),E=hn(T,Zt),j={};E&&C==="axis"&&(E.props.trigger==="click"?j={onClick:this.handleClick}:j={onMouseEnter:this.handleMouseEnter,onDoubleClick:this.handleDoubleClick,onMouseMove:this.handleMouseMove,onMouseLeave:this.handleMouseLeave,onTouchMove:this.handleTouchMove,onTouchStart:this.handleTouchStart,onTouchEnd:this.handleTouchEnd,onContextMenu:this.handleContextMenu});var D=zs(this.props,this.handleOuterEvent);return re(re({},D),j)}},{key:"addListener",value:function(){Ng.on(zg,this.handleReceiveSyncEvent)}},{key:"removeListener",value:function(){Ng.removeListener(zg,this.handleReceiveSyncEvent)}},{key:"filterFormatItem",value:function(T,C,E){for(var j=this.state.formattedGraphicalItems,D=0,z=j.length;D<z;D++){var B=j[D];if(B.item===T||B.props.key===T.key||C===Nr(B.item.type)&&E===B.childIndex)return B}return null}},{key:"renderClipPath",value:function(){var T=this.clipPathId,C=this.state.offset,E=C.left,j=C.top,D=C.height,z=C.width;return I.createElement("defs",null,I.createElement("clipPath",{id:T},I.createElement("rect",{x:E,y:j,height:D,width:z})))}},{key:"getXScales",value:function(){var T=this.state.xAxisMap;return T?Object.entries(T).reduce(function(C,E){var j=oM(E,2),D=j[0],z=j[1];return re(re({},C),{},be({},D,z.scale))},{}):null}},{key:"getYScales",value:function(){var T=this.state.yAxisMap;return T?Object.entries(T).reduce(function(C,E){var j=oM(E,2),D=j[0],z=j[1];return re(re({},C),{},be({},D,z.scale))},{}):null}},{key:"getXScaleByAxisId",value:function(T){var C;return(C=this.state.xAxisMap)===null||C===void 0||(C=C[T])===null||C===void 0?void 0:C.scale}},{key:"getYScaleByAxisId",value:function(T){var C;return(C=this.state.yAxisMap)===null||C===void 0||(C=C[T])===null||C===void 0?void 0:C.scale}},{key:"getItemByXY",value:function(T){var C=this.state,E=C.formattedGraphicalItems,j=C.activeItem;if(E&&E.length)for(var D=0,z=E.length;D<z;D++){var B=E[D],F=B.props,H=B.item,L=H.type.defaultProps!==void 0?re(re({},H.type.defaultProps),H.props):H.props,V=Nr(H.type);if(V==="Bar"){var Z=(F.data||[]).find(function(J){return hH(T,J)});if(Z)return{graphicalItem:B,payload:Z}}else if(V==="RadialBar"){var Q=(F.data||[]).find(function(J){return Ej(T,J)});if(Q)return{graphicalItem:B,payload:Q}}else if(ld(B,j)||ud(B,j)||qu(B,j)){var q=r9({graphicalItem:B,activeTooltipItem:j,itemData:L.data}),U=L.activeIndex===void 0?q:L.activeIndex;return{graphicalItem:re(re({},B),{},{childIndex:U}),payload:qu(B,j)?L.data[q]:B.props.data[q]}}}return null}},{key:"render",value:function(){var T=this;if(!Bw(this))return null;var C=this.props,E=C.children,j=C.className,D=C.width,z=C.height,B=C.style,F=C.compact,H=C.title,L=C.desc,V=lM(C,OX),Z=Ee(V,!1);if(F)return I.createElement(U2,{state:this.state,width:this.props.width,height:this.props.height,clipPathId:this.clipPathId},I.createElement(Yg,oo({},Z,{width:D,height:z,title:H,desc:L}),this.renderClipPath(),kw(E,this.renderMap)));if(this.props.accessibilityLayer){var Q,q;Z.tabIndex=(Q=this.props.tabIndex)!==null&&Q!==void 0?Q:0,Z.role=(q=this.props.role)!==null&&q!==void 0?q:"application",Z.onKeyDown=function(J){T.accessibilityManager.keyboardEvent(J)},Z.onFocus=function(){T.accessibilityManager.focus()}}var U=this.parseEventsOfWrapper();return I.createElement(U2,{state:this.state,width:this.props.width,height:this.props.height,clipPathId:this.clipPathId},I.createElement("div",oo({className:ze("recharts-wrapper",j),style:re({position:"relative",cursor:"default",width:D,height:z},B)},U,{ref:function(oe){T.container=oe}}),I.createElement(Yg,oo({},Z,{width:D,height:z,title:H,desc:L,style:LX}),this.renderClipPath(),kw(E,this.renderMap)),this.renderLegend(),this.renderTooltip()))}}])})(P.Component);be(w,"displayName",n),be(w,"defaultProps",re({layout:"horizontal",stackOffset:"none",barCategoryGap:"10%",barGap:4,margin:{top:5,right:5,bottom:5,left:5},reverseStackOrder:!1,syncMethod:"index"},y)),be(w,"getDerivedStateFromProps",function(x,A){var O=x.dataKey,T=x.data,C=x.children,E=x.width,j=x.height,D=x.layout,z=x.stackOffset,B=x.margin,F=A.dataStartIndex,H=A.dataEndIndex;if(A.updateId===void 0){var L=sM(x);return re(re(re({},L),{},{updateId:0},S(re(re({props:x},L),{},{updateId:0}),A)),{},{prevDataKey:O,prevData:T,prevWidth:E,prevHeight:j,prevLayout:D,prevStackOffset:z,prevMargin:B,prevChildren:C})}if(O!==A.prevDataKey||T!==A.prevData||E!==A.prevWidth||j!==A.prevHeight||D!==A.prevLayout||z!==A.prevStackOffset||!lo(B,A.prevMargin)){var V=sM(x),Z={chartX:A.chartX,chartY:A.chartY,isTooltipActive:A.isTooltipActive},Q=re(re({},cM(A,T,D)),{},{updateId:A.updateId+1}),q=re(re(re({},V),Z),Q);return re(re(re({},q),S(re({props:x},q),A)),{},{prevDataKey:O,prevData:T,prevWidth:E,prevHeight:j,prevLayout:D,prevStackOffset:z,prevMargin:B,prevChildren:C})}if(!Hg(C,A.prevChildren)){var U,J,oe,ue,N=hn(C,wo),G=N&&(U=(J=N.props)===null||J===void 0?void 0:J.startIndex)!==null&&U!==void 0?U:F,te=N&&(oe=(ue=N.props)===null||ue===void 0?void 0:ue.endIndex)!==null&&oe!==void 0?oe:H,se=G!==F||te!==H,pe=!je(T),me=pe&&!se?A.updateId:A.updateId+1;return re(re({updateId:me},S(re(re({props:x},A),{},{updateId:me,dataStartIndex:G,dataEndIndex:te}),A)),{},{prevChildren:C,dataStartIndex:G,dataEndIndex:te})}return null}),be(w,"renderActiveDot",function(x,A,O){var T;return P.isValidElement(x)?T=P.cloneElement(x,A):Te(x)?T=x(A):T=I.createElement(od,A),I.createElement(Ze,{className:"recharts-active-dot",key:O},T)});var b=P.forwardRef(function(A,O){return I.createElement(w,oo({},A,{ref:O}))});return b.displayName=w.displayName,b},dM=h1({chartName:"LineChart",GraphicalChild:ii,axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1}),WX=h1({chartName:"BarChart",GraphicalChild:yi,defaultTooltipEventType:"axis",validateTooltipEventTypes:["axis","item"],axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1}),hM=h1({chartName:"AreaChart",GraphicalChild:Ur,axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1});function FX(e){return e<1?`${(e*1e3).toFixed(0)}us`:e<1e3?`${e.toFixed(0)}ms`:`${(e/1e3).toFixed(2)}s`}function pM(e){const t=e/1e6;return FX(t)}function QX(){var g,S,w;const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState(!0),{lastMessage:u}=Bf(["overview"]),c=async()=>{try{const b=await e("/overview?range=1h");b.ok&&n(await b.json())}catch{}o(!1)};if(P.useEffect(()=>{c()},[]),P.useEffect(()=>{(u==null?void 0:u.type)==="overview"&&(u!=null&&u.data)&&n(u.data)},[u]),r)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const f=t||{},d=(f.throughput_series||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),value:b.value})),h=(f.error_series||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),value:b.value})),v=[{key:"method",label:"Method",render:b=>M.jsx("span",{style:{padding:"2px 8px",borderRadius:4,fontSize:11,fontWeight:700,background:b==="GET"?"#22c55e18":b==="POST"?"#6366f118":b==="PUT"?"#f59e0b18":"#ef444418",color:b==="GET"?"#22c55e":b==="POST"?"#818cf8":b==="PUT"?"#f59e0b":"#ef4444"},children:b})},{key:"path",label:"Path"},{key:"request_count",label:"Requests"},{key:"error_rate",label:"Error Rate",render:b=>M.jsxs("span",{style:{color:b>5?"#ef4444":b>1?"#f59e0b":"#22c55e"},children:[b==null?void 0:b.toFixed(1),"%"]})},{key:"avg_latency",label:"Avg Latency",render:b=>pM(b)},{key:"rpm",label:"RPM",render:b=>b==null?void 0:b.toFixed(1)}],y=[{key:"error_type",label:"Type",render:b=>M.jsx(Pr,{status:b})},{key:"method",label:"Method"},{key:"route",label:"Route"},{key:"error_message",label:"Message",render:b=>M.jsx("span",{style:{maxWidth:300,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:b})},{key:"count",label:"Count"}];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:20},children:[M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,color:"#e2e8f0"},children:f.app_name||"Pulse"}),M.jsxs("p",{style:{color:"#64748b",fontSize:13,marginTop:2},children:["Uptime: ",f.uptime||"-"]})]}),M.jsx(Pr,{status:f.health_status||"healthy"})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Total Requests",value:((g=f.total_requests)==null?void 0:g.toLocaleString())||"0",color:"#818cf8"}),M.jsx(Cn,{label:"Error Rate",value:`${((S=f.error_rate)==null?void 0:S.toFixed(2))||"0.00"}%`,color:f.error_rate>5?"#ef4444":"#22c55e"}),M.jsx(Cn,{label:"Avg Latency",value:f.avg_latency?pM(f.avg_latency):"-",color:"#f59e0b"}),M.jsx(Cn,{label:"Goroutines",value:f.active_goroutines||"0",sub:`Heap: ${((w=f.heap_alloc_mb)==null?void 0:w.toFixed(1))||"0"} MB`,color:"#22c55e"})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"1fr 1fr",gap:14,marginBottom:20},children:[M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"THROUGHPUT (RPM)"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(hM,{data:d,children:[M.jsx("defs",{children:M.jsxs("linearGradient",{id:"tg",x1:"0",y1:"0",x2:"0",y2:"1",children:[M.jsx("stop",{offset:"5%",stopColor:"#6366f1",stopOpacity:.3}),M.jsx("stop",{offset:"95%",stopColor:"#6366f1",stopOpacity:0})]})}),M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:40}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(Ur,{type:"monotone",dataKey:"value",stroke:"#6366f1",fill:"url(#tg)",strokeWidth:2})]})})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"ERRORS"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(hM,{data:h,children:[M.jsx("defs",{children:M.jsxs("linearGradient",{id:"eg",x1:"0",y1:"0",x2:"0",y2:"1",children:[M.jsx("stop",{offset:"5%",stopColor:"#ef4444",stopOpacity:.3}),M.jsx("stop",{offset:"95%",stopColor:"#ef4444",stopOpacity:0})]})}),M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:40}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(Ur,{type:"monotone",dataKey:"value",stroke:"#ef4444",fill:"url(#eg)",strokeWidth:2})]})})]})]}),M.jsxs("div",{style:{marginBottom:20},children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:10},children:"Top Routes"}),M.jsx(Dr,{columns:v,data:(f.top_routes||[]).slice(0,8),emptyText:"No request data yet"})]}),M.jsxs("div",{children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:10},children:"Recent Errors"}),M.jsx(Dr,{columns:y,data:(f.recent_errors||[]).slice(0,5),emptyText:"No errors recorded"})]})]})}function VD({open:e,onClose:t,title:n,children:r,width:o=640}){return e?M.jsx("div",{onClick:t,style:{position:"fixed",inset:0,background:"rgba(0,0,0,0.6)",display:"flex",alignItems:"center",justifyContent:"center",zIndex:1e3,padding:20},children:M.jsxs("div",{onClick:u=>u.stopPropagation(),style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:10,width:"100%",maxWidth:o,maxHeight:"80vh",overflow:"auto"},children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",padding:"16px 20px",borderBottom:"1px solid #1e1e2e"},children:[M.jsx("h3",{style:{fontSize:16,fontWeight:600,color:"#e2e8f0"},children:n}),M.jsx("button",{onClick:t,style:{background:"none",border:"none",color:"#64748b",fontSize:20,cursor:"pointer",padding:"0 4px",lineHeight:1},children:"×"})]}),M.jsx("div",{style:{padding:20},children:r})]})}):null}function $g(e){const t=e/1e6;return t<1?`${(t*1e3).toFixed(0)}us`:t<1e3?`${t.toFixed(0)}ms`:`${(t/1e3).toFixed(2)}s`}function ZX(){var b,x,A;const{get:e}=_a(),[t,n]=P.useState([]),[r,o]=P.useState(""),[u,c]=P.useState("1h"),[f,d]=P.useState(null),[h,v]=P.useState(!0),y=async()=>{try{const O=new URLSearchParams({range:u});r&&O.set("search",r);const T=await e(`/routes?${O}`);T.ok&&n(await T.json())}catch{}v(!1)};P.useEffect(()=>{y()},[u,r]);const g=async O=>{try{const T=await e(`/routes/${O.method}${O.path}?range=${u}`);T.ok&&d(await T.json())}catch{}},S=[{key:"method",label:"Method",render:O=>M.jsx("span",{style:{padding:"2px 8px",borderRadius:4,fontSize:11,fontWeight:700,background:O==="GET"?"#22c55e18":O==="POST"?"#6366f118":O==="PUT"?"#f59e0b18":O==="DELETE"?"#ef444418":"#64748b18",color:O==="GET"?"#22c55e":O==="POST"?"#818cf8":O==="PUT"?"#f59e0b":O==="DELETE"?"#ef4444":"#94a3b8"},children:O})},{key:"path",label:"Path"},{key:"request_count",label:"Requests",render:O=>O==null?void 0:O.toLocaleString()},{key:"error_rate",label:"Error Rate",render:O=>M.jsxs("span",{style:{color:O>5?"#ef4444":O>1?"#f59e0b":"#22c55e"},children:[O==null?void 0:O.toFixed(1),"%"]})},{key:"avg_latency",label:"Avg",render:O=>$g(O)},{key:"p95_latency",label:"P95",render:O=>$g(O)},{key:"p99_latency",label:"P99",render:O=>$g(O)},{key:"rpm",label:"RPM",render:O=>O==null?void 0:O.toFixed(1)},{key:"trend",label:"Trend",render:O=>M.jsx("span",{style:{color:O==="up"?"#22c55e":O==="down"?"#ef4444":"#64748b"},children:O==="up"?"↑":O==="down"?"↓":"—"})}],w=f?[{name:"Avg",value:f.avg_latency/1e6},{name:"P50",value:f.p50_latency/1e6},{name:"P75",value:f.p75_latency/1e6},{name:"P90",value:f.p90_latency/1e6},{name:"P95",value:f.p95_latency/1e6},{name:"P99",value:f.p99_latency/1e6}]:[];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Routes"}),M.jsxs("div",{style:{display:"flex",gap:8},children:[M.jsx("input",{placeholder:"Search routes...",value:r,onChange:O=>o(O.target.value),style:{width:200}}),M.jsxs("select",{value:u,onChange:O=>c(O.target.value),children:[M.jsx("option",{value:"5m",children:"5m"}),M.jsx("option",{value:"15m",children:"15m"}),M.jsx("option",{value:"1h",children:"1h"}),M.jsx("option",{value:"6h",children:"6h"}),M.jsx("option",{value:"24h",children:"24h"}),M.jsx("option",{value:"7d",children:"7d"})]})]})]}),h?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:S,data:t||[],onRowClick:g,emptyText:"No routes tracked yet"}),M.jsx(VD,{open:!!f,onClose:()=>d(null),title:`${f==null?void 0:f.method} ${f==null?void 0:f.path}`,width:700,children:f&&M.jsxs("div",{children:[M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:12,marginBottom:20},children:[M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"REQUESTS"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#818cf8"},children:(b=f.request_count)==null?void 0:b.toLocaleString()})]}),M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"ERROR RATE"}),M.jsxs("p",{style:{fontSize:20,fontWeight:700,color:f.error_rate>5?"#ef4444":"#22c55e"},children:[(x=f.error_rate)==null?void 0:x.toFixed(1),"%"]})]}),M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"RPM"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#f59e0b"},children:(A=f.rpm)==null?void 0:A.toFixed(1)})]})]}),M.jsx("h4",{style:{fontSize:13,color:"#64748b",marginBottom:8,fontWeight:600},children:"LATENCY DISTRIBUTION (ms)"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(WX,{data:w,children:[M.jsx(or,{dataKey:"name",tick:{fill:"#64748b",fontSize:11},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:11},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(yi,{dataKey:"value",fill:"#6366f1",radius:[4,4,0,0]})]})}),f.status_codes&&Object.keys(f.status_codes).length>0&&M.jsxs("div",{style:{marginTop:16},children:[M.jsx("h4",{style:{fontSize:13,color:"#64748b",marginBottom:8,fontWeight:600},children:"STATUS CODES"}),M.jsx("div",{style:{display:"flex",gap:8,flexWrap:"wrap"},children:Object.entries(f.status_codes).map(([O,T])=>M.jsxs("span",{style:{padding:"4px 10px",borderRadius:6,fontSize:12,fontWeight:600,background:O.startsWith("2")?"#22c55e18":O.startsWith("4")?"#f59e0b18":"#ef444418",color:O.startsWith("2")?"#22c55e":O.startsWith("4")?"#f59e0b":"#ef4444"},children:[O,": ",T]},O))})]})]})})]})}function JX(){var T;const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState([]),[u,c]=P.useState([]),[f,d]=P.useState([]),[h,v]=P.useState(null),[y,g]=P.useState("slow"),[S,w]=P.useState(!0);P.useEffect(()=>{(async()=>{try{const[E,j,D,z,B]=await Promise.all([e("/database/overview?range=1h"),e("/database/slow-queries?limit=50"),e("/database/patterns?range=1h"),e("/database/n1?range=1h"),e("/database/pool")]);E.ok&&n(await E.json()),j.ok&&o(await j.json()),D.ok&&c(await D.json()),z.ok&&d(await z.json()),B.ok&&v(await B.json())}catch{}w(!1)})()},[]);const b=[{key:"operation",label:"Op",render:C=>M.jsx("span",{style:{color:"#818cf8",fontWeight:600,textTransform:"uppercase",fontSize:11},children:C})},{key:"table",label:"Table"},{key:"duration",label:"Duration",render:C=>M.jsxs("span",{style:{color:C>5e8?"#ef4444":C>2e8?"#f59e0b":"#e2e8f0"},children:[(C/1e6).toFixed(1),"ms"]})},{key:"rows_affected",label:"Rows"},{key:"caller_file",label:"Caller",render:(C,E)=>M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:[C,E.caller_line>0?`:${E.caller_line}`:""]})},{key:"sql",label:"SQL",render:C=>M.jsx("span",{style:{maxWidth:300,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap",color:"#94a3b8",fontSize:12,fontFamily:"'SF Mono', 'Fira Code', monospace"},children:C})}],x=[{key:"operation",label:"Operation",render:C=>M.jsx("span",{style:{color:"#818cf8",fontWeight:600,textTransform:"uppercase",fontSize:11},children:C})},{key:"table",label:"Table"},{key:"count",label:"Count",render:C=>C==null?void 0:C.toLocaleString()},{key:"avg_duration",label:"Avg Duration",render:C=>`${(C/1e6).toFixed(1)}ms`},{key:"max_duration",label:"Max",render:C=>`${(C/1e6).toFixed(1)}ms`},{key:"total_duration",label:"Total Time",render:C=>`${(C/1e6).toFixed(0)}ms`}],A=[{key:"pattern",label:"SQL Pattern",render:C=>M.jsx("span",{style:{fontFamily:"'SF Mono', 'Fira Code', monospace",fontSize:12,color:"#f59e0b"},children:C})},{key:"count",label:"Repetitions",render:C=>M.jsxs("span",{style:{color:"#ef4444",fontWeight:700},children:[C,"x"]})},{key:"request_trace_id",label:"Trace ID",render:C=>M.jsxs("span",{style:{color:"#64748b",fontSize:11},children:[C==null?void 0:C.substring(0,12),"..."]})}],O=[{id:"slow",label:"Slow Queries",count:r==null?void 0:r.length},{id:"patterns",label:"Patterns",count:u==null?void 0:u.length},{id:"n1",label:"N+1 Detections",count:f==null?void 0:f.length}];return S?M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."}):M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Database"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Total Queries",value:((T=t==null?void 0:t.total_queries)==null?void 0:T.toLocaleString())||"0",color:"#818cf8"}),M.jsx(Cn,{label:"Query Patterns",value:(t==null?void 0:t.pattern_count)||"0",color:"#22c55e"}),M.jsx(Cn,{label:"Slow Queries",value:(t==null?void 0:t.slow_query_count)||"0",color:"#f59e0b"}),M.jsx(Cn,{label:"N+1 Detected",value:(t==null?void 0:t.n1_count)||"0",color:(t==null?void 0:t.n1_count)>0?"#ef4444":"#22c55e"})]}),h&&h.open_connections!==void 0&&M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:20},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",fontWeight:600,marginBottom:10},children:"CONNECTION POOL"}),M.jsxs("div",{style:{display:"flex",gap:24},children:[M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Open:"})," ",M.jsx("span",{style:{color:"#818cf8",fontWeight:600},children:h.open_connections})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"In Use:"})," ",M.jsx("span",{style:{color:"#f59e0b",fontWeight:600},children:h.in_use})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Idle:"})," ",M.jsx("span",{style:{color:"#22c55e",fontWeight:600},children:h.idle})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Wait Count:"})," ",M.jsx("span",{style:{color:"#94a3b8"},children:h.wait_count})]})]})]}),M.jsx("div",{style:{display:"flex",gap:4,marginBottom:14},children:O.map(({id:C,label:E,count:j})=>M.jsxs("button",{onClick:()=>g(C),style:{padding:"8px 16px",borderRadius:6,border:"none",fontSize:13,fontWeight:600,cursor:"pointer",background:y===C?"#6366f120":"transparent",color:y===C?"#818cf8":"#64748b"},children:[E," ",j>0&&M.jsxs("span",{style:{fontSize:11,opacity:.7},children:["(",j,")"]})]},C))}),y==="slow"&&M.jsx(Dr,{columns:b,data:r||[],emptyText:"No slow queries detected"}),y==="patterns"&&M.jsx(Dr,{columns:x,data:u||[],emptyText:"No query patterns yet"}),y==="n1"&&M.jsx(Dr,{columns:A,data:f||[],emptyText:"No N+1 queries detected"})]})}function eK(){const{get:e,post:t,del:n}=_a(),[r,o]=P.useState([]),[u,c]=P.useState({type:"",resolved:"",muted:""}),[f,d]=P.useState(null),[h,v]=P.useState(!0),y=async()=>{const O=new URLSearchParams({range:"24h",limit:"100"});u.type&&O.set("type",u.type),u.resolved&&O.set("resolved",u.resolved),u.muted&&O.set("muted",u.muted);try{const T=await e(`/errors?${O}`);T.ok&&o(await T.json())}catch{}v(!1)};P.useEffect(()=>{y()},[u]);const g=async O=>{try{const T=await e(`/errors/${O.id}`);T.ok&&d(await T.json())}catch{}},S=async O=>{await t(`/errors/${O}/mute`),d(null),y()},w=async O=>{await t(`/errors/${O}/resolve`),d(null),y()},b=async O=>{await n(`/errors/${O}`),d(null),y()},x=[{key:"error_type",label:"Type",render:O=>M.jsx(Pr,{status:O})},{key:"method",label:"Method",render:O=>M.jsx("span",{style:{fontWeight:600,fontSize:12,color:"#818cf8"},children:O})},{key:"route",label:"Route"},{key:"error_message",label:"Message",render:O=>M.jsx("span",{style:{maxWidth:280,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:O})},{key:"count",label:"Count",render:O=>M.jsx("span",{style:{fontWeight:700,color:O>10?"#ef4444":"#e2e8f0"},children:O})},{key:"muted",label:"Muted",render:O=>O?M.jsx("span",{style:{color:"#64748b"},children:"Yes"}):null},{key:"resolved",label:"Resolved",render:O=>O?M.jsx("span",{style:{color:"#22c55e"},children:"Yes"}):null},{key:"last_seen",label:"Last Seen",render:O=>O?new Date(O).toLocaleString():"-"}],A=["","panic","internal","database","validation","timeout","auth","not_found"];return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Errors"}),M.jsxs("div",{style:{display:"flex",gap:8,marginBottom:14},children:[M.jsxs("select",{value:u.type,onChange:O=>c({...u,type:O.target.value}),children:[M.jsx("option",{value:"",children:"All Types"}),A.filter(Boolean).map(O=>M.jsx("option",{value:O,children:O},O))]}),M.jsxs("select",{value:u.resolved,onChange:O=>c({...u,resolved:O.target.value}),children:[M.jsx("option",{value:"",children:"All Status"}),M.jsx("option",{value:"false",children:"Unresolved"}),M.jsx("option",{value:"true",children:"Resolved"})]}),M.jsxs("select",{value:u.muted,onChange:O=>c({...u,muted:O.target.value}),children:[M.jsx("option",{value:"",children:"Muted/Unmuted"}),M.jsx("option",{value:"false",children:"Not Muted"}),M.jsx("option",{value:"true",children:"Muted"})]})]}),h?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:x,data:r||[],onRowClick:g,emptyText:"No errors recorded"}),M.jsx(VD,{open:!!f,onClose:()=>d(null),title:"Error Detail",width:720,children:f&&M.jsxs("div",{children:[M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"1fr 1fr",gap:12,marginBottom:16},children:[M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Type"}),M.jsx("div",{style:{marginTop:4},children:M.jsx(Pr,{status:f.error_type})})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Route"}),M.jsxs("p",{style:{fontSize:14,color:"#e2e8f0",marginTop:4},children:[f.method," ",f.route]})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Count"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#ef4444",marginTop:4},children:f.count})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"First Seen"}),M.jsx("p",{style:{fontSize:13,color:"#94a3b8",marginTop:4},children:new Date(f.first_seen).toLocaleString()})]})]}),M.jsxs("div",{style:{marginBottom:16},children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Message"}),M.jsx("p",{style:{marginTop:4,padding:"10px 14px",background:"#0a0a12",borderRadius:6,fontSize:13,color:"#ef4444",fontFamily:"'SF Mono', monospace"},children:f.error_message})]}),f.stack_trace&&M.jsxs("div",{style:{marginBottom:16},children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Stack Trace"}),M.jsx("pre",{style:{marginTop:4,padding:14,background:"#0a0a12",borderRadius:6,fontSize:11,color:"#94a3b8",overflow:"auto",maxHeight:250,fontFamily:"'SF Mono', 'Fira Code', monospace",lineHeight:1.5,whiteSpace:"pre-wrap",wordBreak:"break-word"},children:f.stack_trace})]}),M.jsxs("div",{style:{display:"flex",gap:8,marginTop:16},children:[!f.muted&&M.jsx("button",{onClick:()=>S(f.id),style:Bg("#f59e0b"),children:"Mute"}),!f.resolved&&M.jsx("button",{onClick:()=>w(f.id),style:Bg("#22c55e"),children:"Resolve"}),M.jsx("button",{onClick:()=>b(f.id),style:Bg("#ef4444"),children:"Delete"})]})]})})]})}const Bg=e=>({padding:"6px 14px",borderRadius:6,border:`1px solid ${e}40`,background:`${e}18`,color:e,fontSize:13,fontWeight:600,cursor:"pointer"});function yM(e){return e?e<1024?`${e} B`:e<1048576?`${(e/1024).toFixed(1)} KB`:e<1073741824?`${(e/1048576).toFixed(1)} MB`:`${(e/1073741824).toFixed(2)} GB`:"0 B"}function tK(){const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState([]),[u,c]=P.useState(null),[f,d]=P.useState("1h"),[h,v]=P.useState(!0),{lastMessage:y}=Bf(["runtime"]),g=async()=>{try{const[b,x,A]=await Promise.all([e("/runtime/current"),e(`/runtime/history?range=${f}`),e("/runtime/info")]);b.ok&&n(await b.json()),x.ok&&o(await x.json()),A.ok&&c(await A.json())}catch{}v(!1)};if(P.useEffect(()=>{g()},[f]),P.useEffect(()=>{(y==null?void 0:y.type)==="runtime"&&(y!=null&&y.data)&&n(y.data)},[y]),h)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const S=t||{},w=(r||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),heap:(b.heap_alloc||0)/1048576,heapInUse:(b.heap_in_use||0)/1048576,goroutines:b.num_goroutine||0,gcPause:(b.gc_pause_ns||0)/1e6}));return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Runtime"}),M.jsxs("select",{value:f,onChange:b=>d(b.target.value),children:[M.jsx("option",{value:"5m",children:"5m"}),M.jsx("option",{value:"15m",children:"15m"}),M.jsx("option",{value:"1h",children:"1h"}),M.jsx("option",{value:"6h",children:"6h"}),M.jsx("option",{value:"24h",children:"24h"})]})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Heap Alloc",value:yM(S.heap_alloc),color:"#818cf8"}),M.jsx(Cn,{label:"Goroutines",value:S.num_goroutine||"0",color:"#22c55e"}),M.jsx(Cn,{label:"GC Cycles",value:S.num_gc||"0",color:"#f59e0b"}),M.jsx(Cn,{label:"Sys Memory",value:yM(S.sys),color:"#94a3b8"})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:14},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"MEMORY (MB)"}),M.jsx(du,{width:"100%",height:220,children:M.jsxs(dM,{data:w,children:[M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(ni,{wrapperStyle:{fontSize:12}}),M.jsx(ii,{type:"monotone",dataKey:"heap",name:"Heap Alloc",stroke:"#6366f1",strokeWidth:2,dot:!1}),M.jsx(ii,{type:"monotone",dataKey:"heapInUse",name:"Heap In Use",stroke:"#8b5cf6",strokeWidth:2,dot:!1})]})})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:14},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"GOROUTINES"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(dM,{data:w,children:[M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(ii,{type:"monotone",dataKey:"goroutines",stroke:"#22c55e",strokeWidth:2,dot:!1})]})})]}),u&&M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"SYSTEM INFO"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:12,fontSize:13},children:[u.system&&Object.entries(u.system).map(([b,x])=>M.jsxs("div",{children:[M.jsxs("span",{style:{color:"#64748b"},children:[b.replace(/_/g," "),": "]}),M.jsx("span",{style:{color:"#e2e8f0"},children:String(x)})]},b)),u.uptime&&M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b"},children:"uptime: "}),M.jsx("span",{style:{color:"#22c55e"},children:u.uptime})]})]})]})]})}function nK(){const{get:e,post:t}=_a(),[n,r]=P.useState(null),[o,u]=P.useState(null),[c,f]=P.useState([]),[d,h]=P.useState(!0),{lastMessage:v}=Bf(["health"]),y=async()=>{try{const A=await e("/health/checks");A.ok&&r(await A.json())}catch{}h(!1)};P.useEffect(()=>{y()},[]),P.useEffect(()=>{(v==null?void 0:v.type)==="health"&&y()},[v]);const g=async A=>{u(A);try{const O=await e(`/health/checks/${A}/history?limit=20`);O.ok&&f(await O.json())}catch{}},S=async A=>{try{await t(`/health/checks/${A}/run`),y(),o===A&&g(A)}catch{}};if(d)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const w=(n==null?void 0:n.checks)||{},b=(n==null?void 0:n.status)||"unknown",x=[{key:"status",label:"Status",render:A=>M.jsx(Pr,{status:A})},{key:"latency_ms",label:"Latency",render:A=>`${A==null?void 0:A.toFixed(1)}ms`},{key:"error",label:"Error",render:A=>A?M.jsx("span",{style:{color:"#ef4444",fontSize:12},children:A}):M.jsx("span",{style:{color:"#64748b"},children:"-"})},{key:"timestamp",label:"Time",render:A=>A?new Date(A).toLocaleString():"-"}];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Health"}),M.jsx(Pr,{status:b})]}),(n==null?void 0:n.uptime)&&M.jsxs("p",{style:{color:"#64748b",fontSize:13,marginBottom:16},children:["Uptime: ",n.uptime]}),M.jsx("div",{style:{display:"grid",gridTemplateColumns:"repeat(auto-fill, minmax(280, 1fr))",gap:14,marginBottom:20},children:Object.entries(w).map(([A,O])=>{var T;return M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,cursor:"pointer",borderLeft:`3px solid ${O.status==="healthy"?"#22c55e":O.status==="degraded"?"#f59e0b":"#ef4444"}`},onClick:()=>g(A),children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:8},children:[M.jsx("h3",{style:{fontSize:15,fontWeight:600,color:"#e2e8f0"},children:A}),M.jsx(Pr,{status:O.status})]}),M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center"},children:[M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:["Latency: ",M.jsxs("span",{style:{color:"#e2e8f0"},children:[(T=O.latency_ms)==null?void 0:T.toFixed(1),"ms"]})]}),M.jsx("button",{onClick:C=>{C.stopPropagation(),S(A)},style:{padding:"3px 10px",borderRadius:4,fontSize:11,fontWeight:600,background:"#6366f118",color:"#818cf8",border:"1px solid #6366f130",cursor:"pointer"},children:"Run"})]}),O.error&&M.jsx("p",{style:{color:"#ef4444",fontSize:12,marginTop:8},children:O.error})]},A)})}),Object.keys(w).length===0&&M.jsx("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:40,textAlign:"center",color:"#64748b",fontSize:14},children:"No health checks registered"}),o&&M.jsxs("div",{children:[M.jsxs("h2",{style:{fontSize:16,fontWeight:600,marginBottom:10},children:["History: ",M.jsx("span",{style:{color:"#818cf8"},children:o})]}),M.jsx(Dr,{columns:x,data:c||[],emptyText:"No history available"})]})]})}function rK(){const{get:e}=_a(),[t,n]=P.useState([]),[r,o]=P.useState(""),[u,c]=P.useState(""),[f,d]=P.useState(!0),{lastMessage:h}=Bf(["alert"]),v=async()=>{const b=new URLSearchParams({range:"24h",limit:"100"});r&&b.set("state",r),u&&b.set("severity",u);try{const x=await e(`/alerts?${b}`);x.ok&&n(await x.json())}catch{}d(!1)};P.useEffect(()=>{v()},[r,u]),P.useEffect(()=>{(h==null?void 0:h.type)==="alert"&&v()},[h]);const y=[{key:"state",label:"State",render:b=>M.jsx(Pr,{status:b})},{key:"severity",label:"Severity",render:b=>M.jsx(Pr,{status:b})},{key:"rule_name",label:"Rule"},{key:"metric",label:"Metric",render:b=>M.jsx("span",{style:{color:"#818cf8",fontSize:12,fontFamily:"'SF Mono', monospace"},children:b})},{key:"value",label:"Value",render:(b,x)=>{var A;return M.jsxs("span",{children:[M.jsx("span",{style:{fontWeight:600,color:"#e2e8f0"},children:b==null?void 0:b.toFixed(1)}),M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:[" ",x.operator," ",(A=x.threshold)==null?void 0:A.toFixed(1)]})]})}},{key:"message",label:"Message",render:b=>M.jsx("span",{style:{maxWidth:250,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:b})},{key:"fired_at",label:"Fired At",render:b=>b?new Date(b).toLocaleString():"-"},{key:"resolved_at",label:"Resolved",render:b=>b?new Date(b).toLocaleString():M.jsx("span",{style:{color:"#64748b"},children:"-"})}],g=(t==null?void 0:t.filter(b=>b.state==="firing").length)||0,S=(t==null?void 0:t.filter(b=>b.state==="resolved").length)||0,w=(t==null?void 0:t.filter(b=>b.severity==="critical").length)||0;return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Alerts"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:14,marginBottom:20},children:[M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #ef4444"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"FIRING"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:g>0?"#ef4444":"#22c55e"},children:g})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #f59e0b"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"CRITICAL"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:w>0?"#f59e0b":"#94a3b8"},children:w})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #22c55e"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"RESOLVED"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:"#22c55e"},children:S})]})]}),M.jsxs("div",{style:{display:"flex",gap:8,marginBottom:14},children:[M.jsxs("select",{value:r,onChange:b=>o(b.target.value),children:[M.jsx("option",{value:"",children:"All States"}),M.jsx("option",{value:"firing",children:"Firing"}),M.jsx("option",{value:"resolved",children:"Resolved"})]}),M.jsxs("select",{value:u,onChange:b=>c(b.target.value),children:[M.jsx("option",{value:"",children:"All Severities"}),M.jsx("option",{value:"critical",children:"Critical"}),M.jsx("option",{value:"warning",children:"Warning"}),M.jsx("option",{value:"info",children:"Info"})]})]}),f?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:y,data:t||[],emptyText:"No alerts in the last 24 hours"})]})}function aK(){var C,E,j,D,z,B,F,H,L,V,Z,Q,q;const{get:e,post:t}=_a(),[n,r]=P.useState(null),[o,u]=P.useState("requests"),[c,f]=P.useState("json"),[d,h]=P.useState("1h"),[v,y]=P.useState(!1),[g,S]=P.useState(!1),[w,b]=P.useState("");P.useEffect(()=>{(async()=>{try{const J=await e("/settings");J.ok&&r(await J.json())}catch{}})()},[]);const x=async()=>{y(!0),b("");try{const U=await t("/data/export",{format:c,type:o,range:d});if(!U.ok){const se=await U.json();b(`Export failed: ${se.error}`);return}const J=await U.blob(),ue=(U.headers.get("Content-Disposition")||"").match(/filename=(.+)/),N=ue?ue[1]:`pulse_${o}.${c}`,G=URL.createObjectURL(J),te=document.createElement("a");te.href=G,te.download=N,te.click(),URL.revokeObjectURL(G),b("Export downloaded successfully")}catch(U){b(`Export error: ${U.message}`)}finally{y(!1)}},A=async()=>{try{const U=await t("/data/reset",{confirm:!0});if(U.ok)b("All data has been reset"),S(!1);else{const J=await U.json();b(`Reset failed: ${J.error}`)}}catch(U){b(`Reset error: ${U.message}`)}},O=({title:U,children:J})=>M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:20,marginBottom:16},children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:14},children:U}),J]}),T=({label:U,value:J})=>M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",padding:"6px 0",borderBottom:"1px solid #16161e"},children:[M.jsx("span",{style:{color:"#64748b",fontSize:13},children:U}),M.jsx("span",{style:{color:"#e2e8f0",fontSize:13,fontFamily:"'SF Mono', monospace"},children:typeof J=="boolean"?J?"true":"false":String(J??"-")})]});return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Settings"}),w&&M.jsx("div",{style:{padding:"10px 14px",borderRadius:6,marginBottom:14,fontSize:13,background:w.includes("fail")||w.includes("error")?"#ef444418":"#22c55e18",color:w.includes("fail")||w.includes("error")?"#ef4444":"#22c55e",border:`1px solid ${w.includes("fail")||w.includes("error")?"#ef444430":"#22c55e30"}`},children:w}),M.jsx(O,{title:"Data Export",children:M.jsxs("div",{style:{display:"flex",gap:8,alignItems:"center",flexWrap:"wrap"},children:[M.jsxs("select",{value:o,onChange:U=>u(U.target.value),children:[M.jsx("option",{value:"requests",children:"Requests"}),M.jsx("option",{value:"queries",children:"Queries"}),M.jsx("option",{value:"errors",children:"Errors"}),M.jsx("option",{value:"runtime",children:"Runtime"}),M.jsx("option",{value:"alerts",children:"Alerts"})]}),M.jsxs("select",{value:c,onChange:U=>f(U.target.value),children:[M.jsx("option",{value:"json",children:"JSON"}),M.jsx("option",{value:"csv",children:"CSV"})]}),M.jsxs("select",{value:d,onChange:U=>h(U.target.value),children:[M.jsx("option",{value:"5m",children:"Last 5m"}),M.jsx("option",{value:"15m",children:"Last 15m"}),M.jsx("option",{value:"1h",children:"Last 1h"}),M.jsx("option",{value:"6h",children:"Last 6h"}),M.jsx("option",{value:"24h",children:"Last 24h"}),M.jsx("option",{value:"7d",children:"Last 7d"})]}),M.jsx("button",{onClick:x,disabled:v,style:{padding:"8px 16px",borderRadius:6,border:"none",fontSize:13,fontWeight:600,cursor:"pointer",background:"linear-gradient(135deg, #6366f1, #7c3aed)",color:"#fff",opacity:v?.6:1},children:v?"Exporting...":"Export"})]})}),n&&M.jsxs(O,{title:"Current Configuration",children:[M.jsx(T,{label:"App Name",value:n.AppName}),M.jsx(T,{label:"Prefix",value:n.Prefix}),M.jsx(T,{label:"Dev Mode",value:n.DevMode}),M.jsx(T,{label:"Dashboard Username",value:(C=n.Dashboard)==null?void 0:C.Username}),M.jsx(T,{label:"Storage Driver",value:((E=n.Storage)==null?void 0:E.Driver)===0?"Memory":"SQLite"}),M.jsx(T,{label:"Retention Hours",value:(j=n.Storage)==null?void 0:j.RetentionHours}),M.jsx(T,{label:"Tracing Enabled",value:(D=n.Tracing)==null?void 0:D.Enabled}),M.jsx(T,{label:"Slow Request Threshold",value:`${(((z=n.Tracing)==null?void 0:z.SlowRequestThreshold)/1e6).toFixed(0)}ms`}),M.jsx(T,{label:"Database Enabled",value:(B=n.Database)==null?void 0:B.Enabled}),M.jsx(T,{label:"Slow Query Threshold",value:`${(((F=n.Database)==null?void 0:F.SlowQueryThreshold)/1e6).toFixed(0)}ms`}),M.jsx(T,{label:"N+1 Detection",value:(H=n.Database)==null?void 0:H.DetectN1}),M.jsx(T,{label:"Runtime Enabled",value:(L=n.Runtime)==null?void 0:L.Enabled}),M.jsx(T,{label:"Error Tracking",value:(V=n.Errors)==null?void 0:V.Enabled}),M.jsx(T,{label:"Health Checks",value:(Z=n.Health)==null?void 0:Z.Enabled}),M.jsx(T,{label:"Alerts Enabled",value:(Q=n.Alerts)==null?void 0:Q.Enabled}),M.jsx(T,{label:"Prometheus",value:(q=n.Prometheus)==null?void 0:q.Enabled})]}),M.jsxs(O,{title:"Danger Zone",children:[M.jsx("p",{style:{color:"#94a3b8",fontSize:13,marginBottom:12},children:"Reset all stored metrics, errors, health history, and alerts. This action cannot be undone."}),g?M.jsxs("div",{style:{display:"flex",gap:8,alignItems:"center"},children:[M.jsx("span",{style:{color:"#ef4444",fontSize:13,fontWeight:600},children:"Are you sure?"}),M.jsx("button",{onClick:A,style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#ef4444",color:"#fff",border:"none"},children:"Yes, Reset Everything"}),M.jsx("button",{onClick:()=>S(!1),style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#2a2a3e",color:"#94a3b8",border:"none"},children:"Cancel"})]}):M.jsx("button",{onClick:()=>S(!0),style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#ef444418",color:"#ef4444",border:"1px solid #ef444430"},children:"Reset All Data"})]})]})}function iK({children:e}){const{isAuthenticated:t}=$f();return t?e:M.jsx(MM,{to:"/pulse/ui/login",replace:!0})}function oK(){return M.jsxs(_3,{children:[M.jsx(Mn,{path:"/pulse/ui/login",element:M.jsx(i6,{})}),M.jsxs(Mn,{path:"/pulse/ui",element:M.jsx(iK,{children:M.jsx(a6,{})}),children:[M.jsx(Mn,{index:!0,element:M.jsx(QX,{})}),M.jsx(Mn,{path:"routes",element:M.jsx(ZX,{})}),M.jsx(Mn,{path:"database",element:M.jsx(JX,{})}),M.jsx(Mn,{path:"errors",element:M.jsx(eK,{})}),M.jsx(Mn,{path:"runtime",element:M.jsx(tK,{})}),M.jsx(Mn,{path:"health",element:M.jsx(nK,{})}),M.jsx(Mn,{path:"alerts",element:M.jsx(rK,{})}),M.jsx(Mn,{path:"settings",element:M.jsx(aK,{})})]}),M.jsx(Mn,{path:"*",element:M.jsx(MM,{to:"/pulse/ui",replace:!0})})]})}_N.createRoot(document.getElementById("root")).render(M.jsx(P.StrictMode,{children:M.jsx(K3,{children:M.jsx(n6,{children:M.jsx(oK,{})})})}));
+In order to be iterable, non-array objects must have a [Symbol.iterator]() method.`)}function GD(e,t){if(e){if(typeof e=="string")return r0(e,t);var n=Object.prototype.toString.call(e).slice(8,-1);if(n==="Object"&&e.constructor&&(n=e.constructor.name),n==="Map"||n==="Set")return Array.from(e);if(n==="Arguments"||/^(?:Ui|I)nt(?:8|16|32)(?:Clamped)?Array$/.test(n))return r0(e,t)}}function zX(e){if(typeof Symbol<"u"&&e[Symbol.iterator]!=null||e["@@iterator"]!=null)return Array.from(e)}function qX(e){if(Array.isArray(e))return r0(e)}function r0(e,t){(t==null||t>e.length)&&(t=e.length);for(var n=0,r=new Array(t);n<t;n++)r[n]=e[n];return r}function uM(e,t){var n=Object.keys(e);if(Object.getOwnPropertySymbols){var r=Object.getOwnPropertySymbols(e);t&&(r=r.filter(function(o){return Object.getOwnPropertyDescriptor(e,o).enumerable})),n.push.apply(n,r)}return n}function re(e){for(var t=1;t<arguments.length;t++){var n=arguments[t]!=null?arguments[t]:{};t%2?uM(Object(n),!0).forEach(function(r){be(e,r,n[r])}):Object.getOwnPropertyDescriptors?Object.defineProperties(e,Object.getOwnPropertyDescriptors(n)):uM(Object(n)).forEach(function(r){Object.defineProperty(e,r,Object.getOwnPropertyDescriptor(n,r))})}return e}function be(e,t,n){return t=YD(t),t in e?Object.defineProperty(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}function YD(e){var t=$X(e,"string");return No(t)=="symbol"?t:t+""}function $X(e,t){if(No(e)!="object"||!e)return e;var n=e[Symbol.toPrimitive];if(n!==void 0){var r=n.call(e,t);if(No(r)!="object")return r;throw new TypeError("@@toPrimitive must return a primitive value.")}return(t==="string"?String:Number)(e)}var BX={xAxis:["bottom","top"],yAxis:["left","right"]},LX={width:"100%",height:"100%"},XD={x:0,y:0};function Cs(e){return e}var kX=function(t,n){return n==="horizontal"?t.x:n==="vertical"?t.y:n==="centric"?t.angle:t.radius},UX=function(t,n,r,o){var u=n.find(function(v){return v&&v.index===r});if(u){if(t==="horizontal")return{x:u.coordinate,y:o.y};if(t==="vertical")return{x:o.x,y:u.coordinate};if(t==="centric"){var c=u.coordinate,f=o.radius;return re(re(re({},o),qt(o.cx,o.cy,f,c)),{},{angle:c,radius:f})}var d=u.coordinate,h=o.angle;return re(re(re({},o),qt(o.cx,o.cy,d,h)),{},{angle:h,radius:d})}return XD},yd=function(t,n){var r=n.graphicalItems,o=n.dataStartIndex,u=n.dataEndIndex,c=(r??[]).reduce(function(f,d){var h=d.props.data;return h&&h.length?[].concat(zo(f),zo(h)):f},[]);return c.length>0?c:t&&t.length&&fe(o)&&fe(u)?t.slice(o,u+1):[]};function KD(e){return e==="number"?[0,"auto"]:void 0}var a0=function(t,n,r,o){var u=t.graphicalItems,c=t.tooltipAxis,f=yd(n,t);return r<0||!u||!u.length||r>=f.length?null:u.reduce(function(d,h){var v,y=(v=h.props.data)!==null&&v!==void 0?v:n;y&&t.dataStartIndex+t.dataEndIndex!==0&&t.dataEndIndex-t.dataStartIndex>=r&&(y=y.slice(t.dataStartIndex,t.dataEndIndex+1));var g;if(c.dataKey&&!c.allowDuplicatedCategory){var S=y===void 0?f:y;g=Ns(S,c.dataKey,o)}else g=y&&y[r]||f[r];return g?[].concat(zo(d),[HR(h,g)]):d},[])},cM=function(t,n,r,o){var u=o||{x:t.chartX,y:t.chartY},c=kX(u,r),f=t.orderedTooltipTicks,d=t.tooltipAxis,h=t.tooltipTicks,v=nU(c,f,h,d);if(v>=0&&h){var y=h[v]&&h[v].value,g=a0(t,n,v,y),S=UX(r,f,v,u);return{activeTooltipIndex:v,activeLabel:y,activePayload:g,activeCoordinate:S}}return null},IX=function(t,n){var r=n.axes,o=n.graphicalItems,u=n.axisType,c=n.axisIdKey,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.layout,y=t.children,g=t.stackOffset,S=UR(v,u);return r.reduce(function(w,b){var x,A=b.type.defaultProps!==void 0?re(re({},b.type.defaultProps),b.props):b.props,O=A.type,T=A.dataKey,C=A.allowDataOverflow,E=A.allowDuplicatedCategory,j=A.scale,D=A.ticks,z=A.includeHidden,B=A[c];if(w[B])return w;var F=yd(t.data,{graphicalItems:o.filter(function(G){var te,se=c in G.props?G.props[c]:(te=G.type.defaultProps)===null||te===void 0?void 0:te[c];return se===B}),dataStartIndex:d,dataEndIndex:h}),H=F.length,L,V,Z;pX(A.domain,C,O)&&(L=Sb(A.domain,null,C),S&&(O==="number"||j!=="auto")&&(Z=ru(F,T,"category")));var Q=KD(O);if(!L||L.length===0){var q,U=(q=A.domain)!==null&&q!==void 0?q:Q;if(T){if(L=ru(F,T,O),O==="category"&&S){var J=V6(L);E&&J?(V=L,L=xf(0,H)):E||(L=_j(U,L,b).reduce(function(G,te){return G.indexOf(te)>=0?G:[].concat(zo(G),[te])},[]))}else if(O==="category")E?L=L.filter(function(G){return G!==""&&!je(G)}):L=_j(U,L,b).reduce(function(G,te){return G.indexOf(te)>=0||te===""||je(te)?G:[].concat(zo(G),[te])},[]);else if(O==="number"){var oe=lU(F,o.filter(function(G){var te,se,pe=c in G.props?G.props[c]:(te=G.type.defaultProps)===null||te===void 0?void 0:te[c],me="hide"in G.props?G.props.hide:(se=G.type.defaultProps)===null||se===void 0?void 0:se.hide;return pe===B&&(z||!me)}),T,u,v);oe&&(L=oe)}S&&(O==="number"||j!=="auto")&&(Z=ru(F,T,"category"))}else S?L=xf(0,H):f&&f[B]&&f[B].hasStack&&O==="number"?L=g==="expand"?[0,1]:IR(f[B].stackGroups,d,h):L=kR(F,o.filter(function(G){var te=c in G.props?G.props[c]:G.type.defaultProps[c],se="hide"in G.props?G.props.hide:G.type.defaultProps.hide;return te===B&&(z||!se)}),O,v,!0);if(O==="number")L=t0(y,L,B,u,D),U&&(L=Sb(U,L,C));else if(O==="category"&&U){var ue=U,N=L.every(function(G){return ue.indexOf(G)>=0});N&&(L=ue)}}return re(re({},w),{},be({},B,re(re({},A),{},{axisType:u,domain:L,categoricalDomain:Z,duplicateDomain:V,originalDomain:(x=A.domain)!==null&&x!==void 0?x:Q,isCategorical:S,layout:v})))},{})},HX=function(t,n){var r=n.graphicalItems,o=n.Axis,u=n.axisType,c=n.axisIdKey,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.layout,y=t.children,g=yd(t.data,{graphicalItems:r,dataStartIndex:d,dataEndIndex:h}),S=g.length,w=UR(v,u),b=-1;return r.reduce(function(x,A){var O=A.type.defaultProps!==void 0?re(re({},A.type.defaultProps),A.props):A.props,T=O[c],C=KD("number");if(!x[T]){b++;var E;return w?E=xf(0,S):f&&f[T]&&f[T].hasStack?(E=IR(f[T].stackGroups,d,h),E=t0(y,E,T,u)):(E=Sb(C,kR(g,r.filter(function(j){var D,z,B=c in j.props?j.props[c]:(D=j.type.defaultProps)===null||D===void 0?void 0:D[c],F="hide"in j.props?j.props.hide:(z=j.type.defaultProps)===null||z===void 0?void 0:z.hide;return B===T&&!F}),"number",v),o.defaultProps.allowDataOverflow),E=t0(y,E,T,u)),re(re({},x),{},be({},T,re(re({axisType:u},o.defaultProps),{},{hide:!0,orientation:Dn(BX,"".concat(u,".").concat(b%2),null),domain:E,originalDomain:C,isCategorical:w,layout:v})))}return x},{})},GX=function(t,n){var r=n.axisType,o=r===void 0?"xAxis":r,u=n.AxisComp,c=n.graphicalItems,f=n.stackGroups,d=n.dataStartIndex,h=n.dataEndIndex,v=t.children,y="".concat(o,"Id"),g=Pn(v,u),S={};return g&&g.length?S=IX(t,{axes:g,graphicalItems:c,axisType:o,axisIdKey:y,stackGroups:f,dataStartIndex:d,dataEndIndex:h}):c&&c.length&&(S=HX(t,{Axis:u,graphicalItems:c,axisType:o,axisIdKey:y,stackGroups:f,dataStartIndex:d,dataEndIndex:h})),S},YX=function(t){var n=ro(t),r=Ja(n,!1,!0);return{tooltipTicks:r,orderedTooltipTicks:N0(r,function(o){return o.coordinate}),tooltipAxis:n,tooltipAxisBandSize:sf(n,r)}},sM=function(t){var n=t.children,r=t.defaultShowTooltip,o=hn(n,wo),u=0,c=0;return t.data&&t.data.length!==0&&(c=t.data.length-1),o&&o.props&&(o.props.startIndex>=0&&(u=o.props.startIndex),o.props.endIndex>=0&&(c=o.props.endIndex)),{chartX:0,chartY:0,dataStartIndex:u,dataEndIndex:c,activeTooltipIndex:-1,isTooltipActive:!!r}},XX=function(t){return!t||!t.length?!1:t.some(function(n){var r=Nr(n&&n.type);return r&&r.indexOf("Bar")>=0})},fM=function(t){return t==="horizontal"?{numericAxisName:"yAxis",cateAxisName:"xAxis"}:t==="vertical"?{numericAxisName:"xAxis",cateAxisName:"yAxis"}:t==="centric"?{numericAxisName:"radiusAxis",cateAxisName:"angleAxis"}:{numericAxisName:"angleAxis",cateAxisName:"radiusAxis"}},KX=function(t,n){var r=t.props,o=t.graphicalItems,u=t.xAxisMap,c=u===void 0?{}:u,f=t.yAxisMap,d=f===void 0?{}:f,h=r.width,v=r.height,y=r.children,g=r.margin||{},S=hn(y,wo),w=hn(y,ni),b=Object.keys(d).reduce(function(E,j){var D=d[j],z=D.orientation;return!D.mirror&&!D.hide?re(re({},E),{},be({},z,E[z]+D.width)):E},{left:g.left||0,right:g.right||0}),x=Object.keys(c).reduce(function(E,j){var D=c[j],z=D.orientation;return!D.mirror&&!D.hide?re(re({},E),{},be({},z,Dn(E,"".concat(z))+D.height)):E},{top:g.top||0,bottom:g.bottom||0}),A=re(re({},x),b),O=A.bottom;S&&(A.bottom+=S.props.height||wo.defaultProps.height),w&&n&&(A=iU(A,o,r,n));var T=h-A.left-A.right,C=v-A.top-A.bottom;return re(re({brushBottom:O},A),{},{width:Math.max(T,0),height:Math.max(C,0)})},VX=function(t,n){if(n==="xAxis")return t[n].width;if(n==="yAxis")return t[n].height},h1=function(t){var n=t.chartName,r=t.GraphicalChild,o=t.defaultTooltipEventType,u=o===void 0?"axis":o,c=t.validateTooltipEventTypes,f=c===void 0?["axis"]:c,d=t.axisComponents,h=t.legendContent,v=t.formatAxisMap,y=t.defaultProps,g=function(A,O){var T=O.graphicalItems,C=O.stackGroups,E=O.offset,j=O.updateId,D=O.dataStartIndex,z=O.dataEndIndex,B=A.barSize,F=A.layout,H=A.barGap,L=A.barCategoryGap,V=A.maxBarSize,Z=fM(F),Q=Z.numericAxisName,q=Z.cateAxisName,U=XX(T),J=[];return T.forEach(function(oe,ue){var N=yd(A.data,{graphicalItems:[oe],dataStartIndex:D,dataEndIndex:z}),G=oe.type.defaultProps!==void 0?re(re({},oe.type.defaultProps),oe.props):oe.props,te=G.dataKey,se=G.maxBarSize,pe=G["".concat(Q,"Id")],me=G["".concat(q,"Id")],Me={},ke=d.reduce(function($n,cr){var Ko=O["".concat(cr.axisType,"Map")],Bt=G["".concat(cr.axisType,"Id")];Ko&&Ko[Bt]||cr.axisType==="zAxis"||ci();var Zu=Ko[Bt];return re(re({},$n),{},be(be({},cr.axisType,Zu),"".concat(cr.axisType,"Ticks"),Ja(Zu)))},Me),ce=ke[q],xe=ke["".concat(q,"Ticks")],Se=C&&C[pe]&&C[pe].hasStack&&bU(oe,C[pe].stackGroups),le=Nr(oe.type).indexOf("Bar")>=0,et=sf(ce,xe),we=[],lt=U&&rU({barSize:B,stackGroups:C,totalSize:VX(ke,q)});if(le){var ut,$t,qn=je(se)?V:se,Wn=(ut=($t=sf(ce,xe,!0))!==null&&$t!==void 0?$t:qn)!==null&&ut!==void 0?ut:0;we=aU({barGap:H,barCategoryGap:L,bandSize:Wn!==et?Wn:et,sizeList:lt[me],maxBarSize:qn}),Wn!==et&&(we=we.map(function($n){return re(re({},$n),{},{position:re(re({},$n.position),{},{offset:$n.position.offset-Wn/2})})}))}var Yr=oe&&oe.type&&oe.type.getComposedData;Yr&&J.push({props:re(re({},Yr(re(re({},ke),{},{displayedData:N,props:A,dataKey:te,item:oe,bandSize:et,barPosition:we,offset:E,stackedData:Se,layout:F,dataStartIndex:D,dataEndIndex:z}))),{},be(be(be({key:oe.key||"item-".concat(ue)},Q,ke[Q]),q,ke[q]),"animationId",j)),childIndex:o8(oe,A.children),item:oe})}),J},S=function(A,O){var T=A.props,C=A.dataStartIndex,E=A.dataEndIndex,j=A.updateId;if(!Bw({props:T}))return null;var D=T.children,z=T.layout,B=T.stackOffset,F=T.data,H=T.reverseStackOrder,L=fM(z),V=L.numericAxisName,Z=L.cateAxisName,Q=Pn(D,r),q=vU(F,Q,"".concat(V,"Id"),"".concat(Z,"Id"),B,H),U=d.reduce(function(G,te){var se="".concat(te.axisType,"Map");return re(re({},G),{},be({},se,GX(T,re(re({},te),{},{graphicalItems:Q,stackGroups:te.axisType===V&&q,dataStartIndex:C,dataEndIndex:E}))))},{}),J=KX(re(re({},U),{},{props:T,graphicalItems:Q}),O==null?void 0:O.legendBBox);Object.keys(U).forEach(function(G){U[G]=v(T,U[G],J,G.replace("Map",""),n)});var oe=U["".concat(Z,"Map")],ue=YX(oe),N=g(T,re(re({},U),{},{dataStartIndex:C,dataEndIndex:E,updateId:j,graphicalItems:Q,stackGroups:q,offset:J}));return re(re({formattedGraphicalItems:N,graphicalItems:Q,offset:J,stackGroups:q},ue),U)},w=(function(x){function A(O){var T,C,E;return TX(this,A),E=CX(this,A,[O]),be(E,"eventEmitterSymbol",Symbol("rechartsEventEmitter")),be(E,"accessibilityManager",new hX),be(E,"handleLegendBBoxUpdate",function(j){if(j){var D=E.state,z=D.dataStartIndex,B=D.dataEndIndex,F=D.updateId;E.setState(re({legendBBox:j},S({props:E.props,dataStartIndex:z,dataEndIndex:B,updateId:F},re(re({},E.state),{},{legendBBox:j}))))}}),be(E,"handleReceiveSyncEvent",function(j,D,z){if(E.props.syncId===j){if(z===E.eventEmitterSymbol&&typeof E.props.syncMethod!="function")return;E.applySyncEvent(D)}}),be(E,"handleBrushChange",function(j){var D=j.startIndex,z=j.endIndex;if(D!==E.state.dataStartIndex||z!==E.state.dataEndIndex){var B=E.state.updateId;E.setState(function(){return re({dataStartIndex:D,dataEndIndex:z},S({props:E.props,dataStartIndex:D,dataEndIndex:z,updateId:B},E.state))}),E.triggerSyncEvent({dataStartIndex:D,dataEndIndex:z})}}),be(E,"handleMouseEnter",function(j){var D=E.getMouseInfo(j);if(D){var z=re(re({},D),{},{isTooltipActive:!0});E.setState(z),E.triggerSyncEvent(z);var B=E.props.onMouseEnter;Te(B)&&B(z,j)}}),be(E,"triggeredAfterMouseMove",function(j){var D=E.getMouseInfo(j),z=D?re(re({},D),{},{isTooltipActive:!0}):{isTooltipActive:!1};E.setState(z),E.triggerSyncEvent(z);var B=E.props.onMouseMove;Te(B)&&B(z,j)}),be(E,"handleItemMouseEnter",function(j){E.setState(function(){return{isTooltipActive:!0,activeItem:j,activePayload:j.tooltipPayload,activeCoordinate:j.tooltipPosition||{x:j.cx,y:j.cy}}})}),be(E,"handleItemMouseLeave",function(){E.setState(function(){return{isTooltipActive:!1}})}),be(E,"handleMouseMove",function(j){j.persist(),E.throttleTriggeredAfterMouseMove(j)}),be(E,"handleMouseLeave",function(j){E.throttleTriggeredAfterMouseMove.cancel();var D={isTooltipActive:!1};E.setState(D),E.triggerSyncEvent(D);var z=E.props.onMouseLeave;Te(z)&&z(D,j)}),be(E,"handleOuterEvent",function(j){var D=i8(j),z=Dn(E.props,"".concat(D));if(D&&Te(z)){var B,F;/.*touch.*/i.test(D)?F=E.getMouseInfo(j.changedTouches[0]):F=E.getMouseInfo(j),z((B=F)!==null&&B!==void 0?B:{},j)}}),be(E,"handleClick",function(j){var D=E.getMouseInfo(j);if(D){var z=re(re({},D),{},{isTooltipActive:!0});E.setState(z),E.triggerSyncEvent(z);var B=E.props.onClick;Te(B)&&B(z,j)}}),be(E,"handleMouseDown",function(j){var D=E.props.onMouseDown;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleMouseUp",function(j){var D=E.props.onMouseUp;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleTouchMove",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.throttleTriggeredAfterMouseMove(j.changedTouches[0])}),be(E,"handleTouchStart",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.handleMouseDown(j.changedTouches[0])}),be(E,"handleTouchEnd",function(j){j.changedTouches!=null&&j.changedTouches.length>0&&E.handleMouseUp(j.changedTouches[0])}),be(E,"handleDoubleClick",function(j){var D=E.props.onDoubleClick;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"handleContextMenu",function(j){var D=E.props.onContextMenu;if(Te(D)){var z=E.getMouseInfo(j);D(z,j)}}),be(E,"triggerSyncEvent",function(j){E.props.syncId!==void 0&&Ng.emit(zg,E.props.syncId,j,E.eventEmitterSymbol)}),be(E,"applySyncEvent",function(j){var D=E.props,z=D.layout,B=D.syncMethod,F=E.state.updateId,H=j.dataStartIndex,L=j.dataEndIndex;if(j.dataStartIndex!==void 0||j.dataEndIndex!==void 0)E.setState(re({dataStartIndex:H,dataEndIndex:L},S({props:E.props,dataStartIndex:H,dataEndIndex:L,updateId:F},E.state)));else if(j.activeTooltipIndex!==void 0){var V=j.chartX,Z=j.chartY,Q=j.activeTooltipIndex,q=E.state,U=q.offset,J=q.tooltipTicks;if(!U)return;if(typeof B=="function")Q=B(J,j);else if(B==="value"){Q=-1;for(var oe=0;oe<J.length;oe++)if(J[oe].value===j.activeLabel){Q=oe;break}}var ue=re(re({},U),{},{x:U.left,y:U.top}),N=Math.min(V,ue.x+ue.width),G=Math.min(Z,ue.y+ue.height),te=J[Q]&&J[Q].value,se=a0(E.state,E.props.data,Q),pe=J[Q]?{x:z==="horizontal"?J[Q].coordinate:N,y:z==="horizontal"?G:J[Q].coordinate}:XD;E.setState(re(re({},j),{},{activeLabel:te,activeCoordinate:pe,activePayload:se,activeTooltipIndex:Q}))}else E.setState(j)}),be(E,"renderCursor",function(j){var D,z=E.state,B=z.isTooltipActive,F=z.activeCoordinate,H=z.activePayload,L=z.offset,V=z.activeTooltipIndex,Z=z.tooltipAxisBandSize,Q=E.getTooltipEventType(),q=(D=j.props.active)!==null&&D!==void 0?D:B,U=E.props.layout,J=j.key||"_recharts-cursor";return I.createElement(xX,{key:J,activeCoordinate:F,activePayload:H,activeTooltipIndex:V,chartName:n,element:j,isActive:q,layout:U,offset:L,tooltipAxisBandSize:Z,tooltipEventType:Q})}),be(E,"renderPolarAxis",function(j,D,z){var B=Dn(j,"type.axisType"),F=Dn(E.state,"".concat(B,"Map")),H=j.type.defaultProps,L=H!==void 0?re(re({},H),j.props):j.props,V=F&&F[L["".concat(B,"Id")]];return P.cloneElement(j,re(re({},V),{},{className:ze(B,V.className),key:j.key||"".concat(D,"-").concat(z),ticks:Ja(V,!0)}))}),be(E,"renderPolarGrid",function(j){var D=j.props,z=D.radialLines,B=D.polarAngles,F=D.polarRadius,H=E.state,L=H.radiusAxisMap,V=H.angleAxisMap,Z=ro(L),Q=ro(V),q=Q.cx,U=Q.cy,J=Q.innerRadius,oe=Q.outerRadius;return P.cloneElement(j,{polarAngles:Array.isArray(B)?B:Ja(Q,!0).map(function(ue){return ue.coordinate}),polarRadius:Array.isArray(F)?F:Ja(Z,!0).map(function(ue){return ue.coordinate}),cx:q,cy:U,innerRadius:J,outerRadius:oe,key:j.key||"polar-grid",radialLines:z})}),be(E,"renderLegend",function(){var j=E.state.formattedGraphicalItems,D=E.props,z=D.children,B=D.width,F=D.height,H=E.props.margin||{},L=B-(H.left||0)-(H.right||0),V=BR({children:z,formattedGraphicalItems:j,legendWidth:L,legendContent:h});if(!V)return null;var Z=V.item,Q=lM(V,SX);return P.cloneElement(Z,re(re({},Q),{},{chartWidth:B,chartHeight:F,margin:H,onBBoxUpdate:E.handleLegendBBoxUpdate}))}),be(E,"renderTooltip",function(){var j,D=E.props,z=D.children,B=D.accessibilityLayer,F=hn(z,Zt);if(!F)return null;var H=E.state,L=H.isTooltipActive,V=H.activeCoordinate,Z=H.activePayload,Q=H.activeLabel,q=H.offset,U=(j=F.props.active)!==null&&j!==void 0?j:L;return P.cloneElement(F,{viewBox:re(re({},q),{},{x:q.left,y:q.top}),active:U,label:Q,payload:U?Z:[],coordinate:V,accessibilityLayer:B})}),be(E,"renderBrush",function(j){var D=E.props,z=D.margin,B=D.data,F=E.state,H=F.offset,L=F.dataStartIndex,V=F.dataEndIndex,Z=F.updateId;return P.cloneElement(j,{key:j.key||"_recharts-brush",onChange:As(E.handleBrushChange,j.props.onChange),data:B,x:fe(j.props.x)?j.props.x:H.left,y:fe(j.props.y)?j.props.y:H.top+H.height+H.brushBottom-(z.bottom||0),width:fe(j.props.width)?j.props.width:H.width,startIndex:L,endIndex:V,updateId:"brush-".concat(Z)})}),be(E,"renderReferenceElement",function(j,D,z){if(!j)return null;var B=E,F=B.clipPathId,H=E.state,L=H.xAxisMap,V=H.yAxisMap,Z=H.offset,Q=j.type.defaultProps||{},q=j.props,U=q.xAxisId,J=U===void 0?Q.xAxisId:U,oe=q.yAxisId,ue=oe===void 0?Q.yAxisId:oe;return P.cloneElement(j,{key:j.key||"".concat(D,"-").concat(z),xAxis:L[J],yAxis:V[ue],viewBox:{x:Z.left,y:Z.top,width:Z.width,height:Z.height},clipPathId:F})}),be(E,"renderActivePoints",function(j){var D=j.item,z=j.activePoint,B=j.basePoint,F=j.childIndex,H=j.isRange,L=[],V=D.props.key,Z=D.item.type.defaultProps!==void 0?re(re({},D.item.type.defaultProps),D.item.props):D.item.props,Q=Z.activeDot,q=Z.dataKey,U=re(re({index:F,dataKey:q,cx:z.x,cy:z.y,r:4,fill:o1(D.item),strokeWidth:2,stroke:"#fff",payload:z.payload,value:z.value},Ee(Q,!1)),zs(Q));return L.push(A.renderActiveDot(Q,U,"".concat(V,"-activePoint-").concat(F))),B?L.push(A.renderActiveDot(Q,re(re({},U),{},{cx:B.x,cy:B.y}),"".concat(V,"-basePoint-").concat(F))):H&&L.push(null),L}),be(E,"renderGraphicChild",function(j,D,z){var B=E.filterFormatItem(j,D,z);if(!B)return null;var F=E.getTooltipEventType(),H=E.state,L=H.isTooltipActive,V=H.tooltipAxis,Z=H.activeTooltipIndex,Q=H.activeLabel,q=E.props.children,U=hn(q,Zt),J=B.props,oe=J.points,ue=J.isRange,N=J.baseLine,G=B.item.type.defaultProps!==void 0?re(re({},B.item.type.defaultProps),B.item.props):B.item.props,te=G.activeDot,se=G.hide,pe=G.activeBar,me=G.activeShape,Me=!!(!se&&L&&U&&(te||pe||me)),ke={};F!=="axis"&&U&&U.props.trigger==="click"?ke={onClick:As(E.handleItemMouseEnter,j.props.onClick)}:F!=="axis"&&(ke={onMouseLeave:As(E.handleItemMouseLeave,j.props.onMouseLeave),onMouseEnter:As(E.handleItemMouseEnter,j.props.onMouseEnter)});var ce=P.cloneElement(j,re(re({},B.props),ke));function xe(cr){return typeof V.dataKey=="function"?V.dataKey(cr.payload):null}if(Me)if(Z>=0){var Se,le;if(V.dataKey&&!V.allowDuplicatedCategory){var et=typeof V.dataKey=="function"?xe:"payload.".concat(V.dataKey.toString());Se=Ns(oe,et,Q),le=ue&&N&&Ns(N,et,Q)}else Se=oe==null?void 0:oe[Z],le=ue&&N&&N[Z];if(me||pe){var we=j.props.activeIndex!==void 0?j.props.activeIndex:Z;return[P.cloneElement(j,re(re(re({},B.props),ke),{},{activeIndex:we})),null,null]}if(!je(Se))return[ce].concat(zo(E.renderActivePoints({item:B,activePoint:Se,basePoint:le,childIndex:Z,isRange:ue})))}else{var lt,ut=(lt=E.getItemByXY(E.state.activeCoordinate))!==null&&lt!==void 0?lt:{graphicalItem:ce},$t=ut.graphicalItem,qn=$t.item,Wn=qn===void 0?j:qn,Yr=$t.childIndex,$n=re(re(re({},B.props),ke),{},{activeIndex:Yr});return[P.cloneElement(Wn,$n),null,null]}return ue?[ce,null,null]:[ce,null]}),be(E,"renderCustomized",function(j,D,z){return P.cloneElement(j,re(re({key:"recharts-customized-".concat(z)},E.props),E.state))}),be(E,"renderMap",{CartesianGrid:{handler:Cs,once:!0},ReferenceArea:{handler:E.renderReferenceElement},ReferenceLine:{handler:Cs},ReferenceDot:{handler:E.renderReferenceElement},XAxis:{handler:Cs},YAxis:{handler:Cs},Brush:{handler:E.renderBrush,once:!0},Bar:{handler:E.renderGraphicChild},Line:{handler:E.renderGraphicChild},Area:{handler:E.renderGraphicChild},Radar:{handler:E.renderGraphicChild},RadialBar:{handler:E.renderGraphicChild},Scatter:{handler:E.renderGraphicChild},Pie:{handler:E.renderGraphicChild},Funnel:{handler:E.renderGraphicChild},Tooltip:{handler:E.renderCursor,once:!0},PolarGrid:{handler:E.renderPolarGrid,once:!0},PolarAngleAxis:{handler:E.renderPolarAxis},PolarRadiusAxis:{handler:E.renderPolarAxis},Customized:{handler:E.renderCustomized}}),E.clipPathId="".concat((T=O.id)!==null&&T!==void 0?T:Io("recharts"),"-clip"),E.throttleTriggeredAfterMouseMove=BC(E.triggeredAfterMouseMove,(C=O.throttleDelay)!==null&&C!==void 0?C:1e3/60),E.state={},E}return PX(A,x),MX(A,[{key:"componentDidMount",value:function(){var T,C;this.addListener(),this.accessibilityManager.setDetails({container:this.container,offset:{left:(T=this.props.margin.left)!==null&&T!==void 0?T:0,top:(C=this.props.margin.top)!==null&&C!==void 0?C:0},coordinateList:this.state.tooltipTicks,mouseHandlerCallback:this.triggeredAfterMouseMove,layout:this.props.layout}),this.displayDefaultTooltip()}},{key:"displayDefaultTooltip",value:function(){var T=this.props,C=T.children,E=T.data,j=T.height,D=T.layout,z=hn(C,Zt);if(z){var B=z.props.defaultIndex;if(!(typeof B!="number"||B<0||B>this.state.tooltipTicks.length-1)){var F=this.state.tooltipTicks[B]&&this.state.tooltipTicks[B].value,H=a0(this.state,E,B,F),L=this.state.tooltipTicks[B].coordinate,V=(this.state.offset.top+j)/2,Z=D==="horizontal",Q=Z?{x:L,y:V}:{y:L,x:V},q=this.state.formattedGraphicalItems.find(function(J){var oe=J.item;return oe.type.name==="Scatter"});q&&(Q=re(re({},Q),q.props.points[B].tooltipPosition),H=q.props.points[B].tooltipPayload);var U={activeTooltipIndex:B,isTooltipActive:!0,activeLabel:F,activePayload:H,activeCoordinate:Q};this.setState(U),this.renderCursor(z),this.accessibilityManager.setIndex(B)}}}},{key:"getSnapshotBeforeUpdate",value:function(T,C){if(!this.props.accessibilityLayer)return null;if(this.state.tooltipTicks!==C.tooltipTicks&&this.accessibilityManager.setDetails({coordinateList:this.state.tooltipTicks}),this.props.layout!==T.layout&&this.accessibilityManager.setDetails({layout:this.props.layout}),this.props.margin!==T.margin){var E,j;this.accessibilityManager.setDetails({offset:{left:(E=this.props.margin.left)!==null&&E!==void 0?E:0,top:(j=this.props.margin.top)!==null&&j!==void 0?j:0}})}return null}},{key:"componentDidUpdate",value:function(T){Hg([hn(T.children,Zt)],[hn(this.props.children,Zt)])||this.displayDefaultTooltip()}},{key:"componentWillUnmount",value:function(){this.removeListener(),this.throttleTriggeredAfterMouseMove.cancel()}},{key:"getTooltipEventType",value:function(){var T=hn(this.props.children,Zt);if(T&&typeof T.props.shared=="boolean"){var C=T.props.shared?"axis":"item";return f.indexOf(C)>=0?C:u}return u}},{key:"getMouseInfo",value:function(T){if(!this.container)return null;var C=this.container,E=C.getBoundingClientRect(),j=X$(E),D={chartX:Math.round(T.pageX-j.left),chartY:Math.round(T.pageY-j.top)},z=E.width/C.offsetWidth||1,B=this.inRange(D.chartX,D.chartY,z);if(!B)return null;var F=this.state,H=F.xAxisMap,L=F.yAxisMap,V=this.getTooltipEventType(),Z=cM(this.state,this.props.data,this.props.layout,B);if(V!=="axis"&&H&&L){var Q=ro(H).scale,q=ro(L).scale,U=Q&&Q.invert?Q.invert(D.chartX):null,J=q&&q.invert?q.invert(D.chartY):null;return re(re({},D),{},{xValue:U,yValue:J},Z)}return Z?re(re({},D),Z):null}},{key:"inRange",value:function(T,C){var E=arguments.length>2&&arguments[2]!==void 0?arguments[2]:1,j=this.props.layout,D=T/E,z=C/E;if(j==="horizontal"||j==="vertical"){var B=this.state.offset,F=D>=B.left&&D<=B.left+B.width&&z>=B.top&&z<=B.top+B.height;return F?{x:D,y:z}:null}var H=this.state,L=H.angleAxisMap,V=H.radiusAxisMap;if(L&&V){var Z=ro(L);return Ej({x:D,y:z},Z)}return null}},{key:"parseEventsOfWrapper",value:function(){var T=this.props.children,C=this.getTooltipEventType(),E=hn(T,Zt),j={};E&&C==="axis"&&(E.props.trigger==="click"?j={onClick:this.handleClick}:j={onMouseEnter:this.handleMouseEnter,onDoubleClick:this.handleDoubleClick,onMouseMove:this.handleMouseMove,onMouseLeave:this.handleMouseLeave,onTouchMove:this.handleTouchMove,onTouchStart:this.handleTouchStart,onTouchEnd:this.handleTouchEnd,onContextMenu:this.handleContextMenu});var D=zs(this.props,this.handleOuterEvent);return re(re({},D),j)}},{key:"addListener",value:function(){Ng.on(zg,this.handleReceiveSyncEvent)}},{key:"removeListener",value:function(){Ng.removeListener(zg,this.handleReceiveSyncEvent)}},{key:"filterFormatItem",value:function(T,C,E){for(var j=this.state.formattedGraphicalItems,D=0,z=j.length;D<z;D++){var B=j[D];if(B.item===T||B.props.key===T.key||C===Nr(B.item.type)&&E===B.childIndex)return B}return null}},{key:"renderClipPath",value:function(){var T=this.clipPathId,C=this.state.offset,E=C.left,j=C.top,D=C.height,z=C.width;return I.createElement("defs",null,I.createElement("clipPath",{id:T},I.createElement("rect",{x:E,y:j,height:D,width:z})))}},{key:"getXScales",value:function(){var T=this.state.xAxisMap;return T?Object.entries(T).reduce(function(C,E){var j=oM(E,2),D=j[0],z=j[1];return re(re({},C),{},be({},D,z.scale))},{}):null}},{key:"getYScales",value:function(){var T=this.state.yAxisMap;return T?Object.entries(T).reduce(function(C,E){var j=oM(E,2),D=j[0],z=j[1];return re(re({},C),{},be({},D,z.scale))},{}):null}},{key:"getXScaleByAxisId",value:function(T){var C;return(C=this.state.xAxisMap)===null||C===void 0||(C=C[T])===null||C===void 0?void 0:C.scale}},{key:"getYScaleByAxisId",value:function(T){var C;return(C=this.state.yAxisMap)===null||C===void 0||(C=C[T])===null||C===void 0?void 0:C.scale}},{key:"getItemByXY",value:function(T){var C=this.state,E=C.formattedGraphicalItems,j=C.activeItem;if(E&&E.length)for(var D=0,z=E.length;D<z;D++){var B=E[D],F=B.props,H=B.item,L=H.type.defaultProps!==void 0?re(re({},H.type.defaultProps),H.props):H.props,V=Nr(H.type);if(V==="Bar"){var Z=(F.data||[]).find(function(J){return hH(T,J)});if(Z)return{graphicalItem:B,payload:Z}}else if(V==="RadialBar"){var Q=(F.data||[]).find(function(J){return Ej(T,J)});if(Q)return{graphicalItem:B,payload:Q}}else if(ld(B,j)||ud(B,j)||qu(B,j)){var q=r9({graphicalItem:B,activeTooltipItem:j,itemData:L.data}),U=L.activeIndex===void 0?q:L.activeIndex;return{graphicalItem:re(re({},B),{},{childIndex:U}),payload:qu(B,j)?L.data[q]:B.props.data[q]}}}return null}},{key:"render",value:function(){var T=this;if(!Bw(this))return null;var C=this.props,E=C.children,j=C.className,D=C.width,z=C.height,B=C.style,F=C.compact,H=C.title,L=C.desc,V=lM(C,OX),Z=Ee(V,!1);if(F)return I.createElement(U2,{state:this.state,width:this.props.width,height:this.props.height,clipPathId:this.clipPathId},I.createElement(Yg,oo({},Z,{width:D,height:z,title:H,desc:L}),this.renderClipPath(),kw(E,this.renderMap)));if(this.props.accessibilityLayer){var Q,q;Z.tabIndex=(Q=this.props.tabIndex)!==null&&Q!==void 0?Q:0,Z.role=(q=this.props.role)!==null&&q!==void 0?q:"application",Z.onKeyDown=function(J){T.accessibilityManager.keyboardEvent(J)},Z.onFocus=function(){T.accessibilityManager.focus()}}var U=this.parseEventsOfWrapper();return I.createElement(U2,{state:this.state,width:this.props.width,height:this.props.height,clipPathId:this.clipPathId},I.createElement("div",oo({className:ze("recharts-wrapper",j),style:re({position:"relative",cursor:"default",width:D,height:z},B)},U,{ref:function(oe){T.container=oe}}),I.createElement(Yg,oo({},Z,{width:D,height:z,title:H,desc:L,style:LX}),this.renderClipPath(),kw(E,this.renderMap)),this.renderLegend(),this.renderTooltip()))}}])})(P.Component);be(w,"displayName",n),be(w,"defaultProps",re({layout:"horizontal",stackOffset:"none",barCategoryGap:"10%",barGap:4,margin:{top:5,right:5,bottom:5,left:5},reverseStackOrder:!1,syncMethod:"index"},y)),be(w,"getDerivedStateFromProps",function(x,A){var O=x.dataKey,T=x.data,C=x.children,E=x.width,j=x.height,D=x.layout,z=x.stackOffset,B=x.margin,F=A.dataStartIndex,H=A.dataEndIndex;if(A.updateId===void 0){var L=sM(x);return re(re(re({},L),{},{updateId:0},S(re(re({props:x},L),{},{updateId:0}),A)),{},{prevDataKey:O,prevData:T,prevWidth:E,prevHeight:j,prevLayout:D,prevStackOffset:z,prevMargin:B,prevChildren:C})}if(O!==A.prevDataKey||T!==A.prevData||E!==A.prevWidth||j!==A.prevHeight||D!==A.prevLayout||z!==A.prevStackOffset||!lo(B,A.prevMargin)){var V=sM(x),Z={chartX:A.chartX,chartY:A.chartY,isTooltipActive:A.isTooltipActive},Q=re(re({},cM(A,T,D)),{},{updateId:A.updateId+1}),q=re(re(re({},V),Z),Q);return re(re(re({},q),S(re({props:x},q),A)),{},{prevDataKey:O,prevData:T,prevWidth:E,prevHeight:j,prevLayout:D,prevStackOffset:z,prevMargin:B,prevChildren:C})}if(!Hg(C,A.prevChildren)){var U,J,oe,ue,N=hn(C,wo),G=N&&(U=(J=N.props)===null||J===void 0?void 0:J.startIndex)!==null&&U!==void 0?U:F,te=N&&(oe=(ue=N.props)===null||ue===void 0?void 0:ue.endIndex)!==null&&oe!==void 0?oe:H,se=G!==F||te!==H,pe=!je(T),me=pe&&!se?A.updateId:A.updateId+1;return re(re({updateId:me},S(re(re({props:x},A),{},{updateId:me,dataStartIndex:G,dataEndIndex:te}),A)),{},{prevChildren:C,dataStartIndex:G,dataEndIndex:te})}return null}),be(w,"renderActiveDot",function(x,A,O){var T;return P.isValidElement(x)?T=P.cloneElement(x,A):Te(x)?T=x(A):T=I.createElement(od,A),I.createElement(Ze,{className:"recharts-active-dot",key:O},T)});var b=P.forwardRef(function(A,O){return I.createElement(w,oo({},A,{ref:O}))});return b.displayName=w.displayName,b},dM=h1({chartName:"LineChart",GraphicalChild:ii,axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1}),WX=h1({chartName:"BarChart",GraphicalChild:yi,defaultTooltipEventType:"axis",validateTooltipEventTypes:["axis","item"],axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1}),hM=h1({chartName:"AreaChart",GraphicalChild:Ur,axisComponents:[{axisType:"xAxis",AxisComp:or},{axisType:"yAxis",AxisComp:lr}],formatAxisMap:u1});function FX(e){return e<1?`${(e*1e3).toFixed(0)}us`:e<1e3?`${e.toFixed(0)}ms`:`${(e/1e3).toFixed(2)}s`}function pM(e){const t=e/1e6;return FX(t)}function QX(){var g,S,w;const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState(!0),{lastMessage:u}=Bf(["overview"]),c=async()=>{try{const b=await e("/overview?range=1h");b.ok&&n(await b.json())}catch{}o(!1)};if(P.useEffect(()=>{c()},[]),P.useEffect(()=>{(u==null?void 0:u.type)==="overview"&&(u!=null&&u.data)&&n(u.data)},[u]),r)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const f=t||{},d=(f.throughput_series||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),value:b.value})),h=(f.error_series||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),value:b.value})),v=[{key:"method",label:"Method",render:b=>M.jsx("span",{style:{padding:"2px 8px",borderRadius:4,fontSize:11,fontWeight:700,background:b==="GET"?"#22c55e18":b==="POST"?"#6366f118":b==="PUT"?"#f59e0b18":"#ef444418",color:b==="GET"?"#22c55e":b==="POST"?"#818cf8":b==="PUT"?"#f59e0b":"#ef4444"},children:b})},{key:"path",label:"Path"},{key:"request_count",label:"Requests"},{key:"error_rate",label:"Error Rate",render:b=>M.jsxs("span",{style:{color:b>5?"#ef4444":b>1?"#f59e0b":"#22c55e"},children:[b==null?void 0:b.toFixed(1),"%"]})},{key:"avg_latency",label:"Avg Latency",render:b=>pM(b)},{key:"rpm",label:"RPM",render:b=>b==null?void 0:b.toFixed(1)}],y=[{key:"error_type",label:"Type",render:b=>M.jsx(Pr,{status:b})},{key:"method",label:"Method"},{key:"route",label:"Route"},{key:"error_message",label:"Message",render:b=>M.jsx("span",{style:{maxWidth:300,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:b})},{key:"count",label:"Count"}];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:20},children:[M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,color:"#e2e8f0"},children:f.app_name||"Pulse"}),M.jsxs("p",{style:{color:"#64748b",fontSize:13,marginTop:2},children:["Uptime: ",f.uptime||"-"]})]}),M.jsx(Pr,{status:f.health_status||"healthy"})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Total Requests",value:((g=f.total_requests)==null?void 0:g.toLocaleString())||"0",color:"#818cf8"}),M.jsx(Cn,{label:"Error Rate",value:`${((S=f.error_rate)==null?void 0:S.toFixed(2))||"0.00"}%`,color:f.error_rate>5?"#ef4444":"#22c55e"}),M.jsx(Cn,{label:"Avg Latency",value:f.avg_latency?pM(f.avg_latency):"-",color:"#f59e0b"}),M.jsx(Cn,{label:"Goroutines",value:f.active_goroutines||"0",sub:`Heap: ${((w=f.heap_alloc_mb)==null?void 0:w.toFixed(1))||"0"} MB`,color:"#22c55e"})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"1fr 1fr",gap:14,marginBottom:20},children:[M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"THROUGHPUT (RPM)"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(hM,{data:d,children:[M.jsx("defs",{children:M.jsxs("linearGradient",{id:"tg",x1:"0",y1:"0",x2:"0",y2:"1",children:[M.jsx("stop",{offset:"5%",stopColor:"#6366f1",stopOpacity:.3}),M.jsx("stop",{offset:"95%",stopColor:"#6366f1",stopOpacity:0})]})}),M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:40}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(Ur,{type:"monotone",dataKey:"value",stroke:"#6366f1",fill:"url(#tg)",strokeWidth:2})]})})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"ERRORS"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(hM,{data:h,children:[M.jsx("defs",{children:M.jsxs("linearGradient",{id:"eg",x1:"0",y1:"0",x2:"0",y2:"1",children:[M.jsx("stop",{offset:"5%",stopColor:"#ef4444",stopOpacity:.3}),M.jsx("stop",{offset:"95%",stopColor:"#ef4444",stopOpacity:0})]})}),M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:40}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(Ur,{type:"monotone",dataKey:"value",stroke:"#ef4444",fill:"url(#eg)",strokeWidth:2})]})})]})]}),M.jsxs("div",{style:{marginBottom:20},children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:10},children:"Top Routes"}),M.jsx(Dr,{columns:v,data:(f.top_routes||[]).slice(0,8),emptyText:"No request data yet"})]}),M.jsxs("div",{children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:10},children:"Recent Errors"}),M.jsx(Dr,{columns:y,data:(f.recent_errors||[]).slice(0,5),emptyText:"No errors recorded"})]})]})}function VD({open:e,onClose:t,title:n,children:r,width:o=640}){return e?M.jsx("div",{onClick:t,style:{position:"fixed",inset:0,background:"rgba(0,0,0,0.6)",display:"flex",alignItems:"center",justifyContent:"center",zIndex:1e3,padding:20},children:M.jsxs("div",{onClick:u=>u.stopPropagation(),style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:10,width:"100%",maxWidth:o,maxHeight:"80vh",overflow:"auto"},children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",padding:"16px 20px",borderBottom:"1px solid #1e1e2e"},children:[M.jsx("h3",{style:{fontSize:16,fontWeight:600,color:"#e2e8f0"},children:n}),M.jsx("button",{onClick:t,style:{background:"none",border:"none",color:"#64748b",fontSize:20,cursor:"pointer",padding:"0 4px",lineHeight:1},children:"×"})]}),M.jsx("div",{style:{padding:20},children:r})]})}):null}function $g(e){const t=e/1e6;return t<1?`${(t*1e3).toFixed(0)}us`:t<1e3?`${t.toFixed(0)}ms`:`${(t/1e3).toFixed(2)}s`}function ZX(){var b,x,A;const{get:e}=_a(),[t,n]=P.useState([]),[r,o]=P.useState(""),[u,c]=P.useState("1h"),[f,d]=P.useState(null),[h,v]=P.useState(!0),y=async()=>{try{const O=new URLSearchParams({range:u});r&&O.set("search",r);const T=await e(`/routes?${O}`);T.ok&&n(await T.json())}catch{}v(!1)};P.useEffect(()=>{y()},[u,r]);const g=async O=>{try{const T=await e(`/routes/${O.method}${O.path}?range=${u}`);T.ok&&d(await T.json())}catch{}},S=[{key:"method",label:"Method",render:O=>M.jsx("span",{style:{padding:"2px 8px",borderRadius:4,fontSize:11,fontWeight:700,background:O==="GET"?"#22c55e18":O==="POST"?"#6366f118":O==="PUT"?"#f59e0b18":O==="DELETE"?"#ef444418":"#64748b18",color:O==="GET"?"#22c55e":O==="POST"?"#818cf8":O==="PUT"?"#f59e0b":O==="DELETE"?"#ef4444":"#94a3b8"},children:O})},{key:"path",label:"Path"},{key:"request_count",label:"Requests",render:O=>O==null?void 0:O.toLocaleString()},{key:"error_rate",label:"Error Rate",render:O=>M.jsxs("span",{style:{color:O>5?"#ef4444":O>1?"#f59e0b":"#22c55e"},children:[O==null?void 0:O.toFixed(1),"%"]})},{key:"avg_latency",label:"Avg",render:O=>$g(O)},{key:"p95_latency",label:"P95",render:O=>$g(O)},{key:"p99_latency",label:"P99",render:O=>$g(O)},{key:"rpm",label:"RPM",render:O=>O==null?void 0:O.toFixed(1)},{key:"trend",label:"Trend",render:O=>M.jsx("span",{style:{color:O==="up"?"#22c55e":O==="down"?"#ef4444":"#64748b"},children:O==="up"?"↑":O==="down"?"↓":"—"})}],w=f?[{name:"Avg",value:f.avg_latency/1e6},{name:"P50",value:f.p50_latency/1e6},{name:"P75",value:f.p75_latency/1e6},{name:"P90",value:f.p90_latency/1e6},{name:"P95",value:f.p95_latency/1e6},{name:"P99",value:f.p99_latency/1e6}]:[];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Routes"}),M.jsxs("div",{style:{display:"flex",gap:8},children:[M.jsx("input",{placeholder:"Search routes...",value:r,onChange:O=>o(O.target.value),style:{width:200}}),M.jsxs("select",{value:u,onChange:O=>c(O.target.value),children:[M.jsx("option",{value:"5m",children:"5m"}),M.jsx("option",{value:"15m",children:"15m"}),M.jsx("option",{value:"1h",children:"1h"}),M.jsx("option",{value:"6h",children:"6h"}),M.jsx("option",{value:"24h",children:"24h"}),M.jsx("option",{value:"7d",children:"7d"})]})]})]}),h?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:S,data:t||[],onRowClick:g,emptyText:"No routes tracked yet"}),M.jsx(VD,{open:!!f,onClose:()=>d(null),title:`${f==null?void 0:f.method} ${f==null?void 0:f.path}`,width:700,children:f&&M.jsxs("div",{children:[M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:12,marginBottom:20},children:[M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"REQUESTS"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#818cf8"},children:(b=f.request_count)==null?void 0:b.toLocaleString()})]}),M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"ERROR RATE"}),M.jsxs("p",{style:{fontSize:20,fontWeight:700,color:f.error_rate>5?"#ef4444":"#22c55e"},children:[(x=f.error_rate)==null?void 0:x.toFixed(1),"%"]})]}),M.jsxs("div",{style:{background:"#0a0a12",borderRadius:6,padding:12},children:[M.jsx("p",{style:{color:"#64748b",fontSize:11},children:"RPM"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#f59e0b"},children:(A=f.rpm)==null?void 0:A.toFixed(1)})]})]}),M.jsx("h4",{style:{fontSize:13,color:"#64748b",marginBottom:8,fontWeight:600},children:"LATENCY DISTRIBUTION (ms)"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(WX,{data:w,children:[M.jsx(or,{dataKey:"name",tick:{fill:"#64748b",fontSize:11},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:11},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(yi,{dataKey:"value",fill:"#6366f1",radius:[4,4,0,0]})]})}),f.status_codes&&Object.keys(f.status_codes).length>0&&M.jsxs("div",{style:{marginTop:16},children:[M.jsx("h4",{style:{fontSize:13,color:"#64748b",marginBottom:8,fontWeight:600},children:"STATUS CODES"}),M.jsx("div",{style:{display:"flex",gap:8,flexWrap:"wrap"},children:Object.entries(f.status_codes).map(([O,T])=>M.jsxs("span",{style:{padding:"4px 10px",borderRadius:6,fontSize:12,fontWeight:600,background:O.startsWith("2")?"#22c55e18":O.startsWith("4")?"#f59e0b18":"#ef444418",color:O.startsWith("2")?"#22c55e":O.startsWith("4")?"#f59e0b":"#ef4444"},children:[O,": ",T]},O))})]})]})})]})}function JX(){var T;const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState([]),[u,c]=P.useState([]),[f,d]=P.useState([]),[h,v]=P.useState(null),[y,g]=P.useState("slow"),[S,w]=P.useState(!0);P.useEffect(()=>{(async()=>{try{const[E,j,D,z,B]=await Promise.all([e("/database/overview?range=1h"),e("/database/slow-queries?limit=50"),e("/database/patterns?range=1h"),e("/database/n1?range=1h"),e("/database/pool")]);E.ok&&n(await E.json()),j.ok&&o(await j.json()),D.ok&&c(await D.json()),z.ok&&d(await z.json()),B.ok&&v(await B.json())}catch{}w(!1)})()},[]);const b=[{key:"operation",label:"Op",render:C=>M.jsx("span",{style:{color:"#818cf8",fontWeight:600,textTransform:"uppercase",fontSize:11},children:C})},{key:"table",label:"Table"},{key:"duration",label:"Duration",render:C=>M.jsxs("span",{style:{color:C>5e8?"#ef4444":C>2e8?"#f59e0b":"#e2e8f0"},children:[(C/1e6).toFixed(1),"ms"]})},{key:"rows_affected",label:"Rows"},{key:"caller_file",label:"Caller",render:(C,E)=>M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:[C,E.caller_line>0?`:${E.caller_line}`:""]})},{key:"sql",label:"SQL",render:C=>M.jsx("span",{style:{maxWidth:300,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap",color:"#94a3b8",fontSize:12,fontFamily:"'SF Mono', 'Fira Code', monospace"},children:C})}],x=[{key:"operation",label:"Operation",render:C=>M.jsx("span",{style:{color:"#818cf8",fontWeight:600,textTransform:"uppercase",fontSize:11},children:C})},{key:"table",label:"Table"},{key:"count",label:"Count",render:C=>C==null?void 0:C.toLocaleString()},{key:"avg_duration",label:"Avg Duration",render:C=>`${(C/1e6).toFixed(1)}ms`},{key:"max_duration",label:"Max",render:C=>`${(C/1e6).toFixed(1)}ms`},{key:"total_duration",label:"Total Time",render:C=>`${(C/1e6).toFixed(0)}ms`}],A=[{key:"pattern",label:"SQL Pattern",render:C=>M.jsx("span",{style:{fontFamily:"'SF Mono', 'Fira Code', monospace",fontSize:12,color:"#f59e0b"},children:C})},{key:"count",label:"Repetitions",render:C=>M.jsxs("span",{style:{color:"#ef4444",fontWeight:700},children:[C,"x"]})},{key:"request_trace_id",label:"Trace ID",render:C=>M.jsxs("span",{style:{color:"#64748b",fontSize:11},children:[C==null?void 0:C.substring(0,12),"..."]})}],O=[{id:"slow",label:"Slow Queries",count:r==null?void 0:r.length},{id:"patterns",label:"Patterns",count:u==null?void 0:u.length},{id:"n1",label:"N+1 Detections",count:f==null?void 0:f.length}];return S?M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."}):M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Database"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Total Queries",value:((T=t==null?void 0:t.total_queries)==null?void 0:T.toLocaleString())||"0",color:"#818cf8"}),M.jsx(Cn,{label:"Query Patterns",value:(t==null?void 0:t.pattern_count)||"0",color:"#22c55e"}),M.jsx(Cn,{label:"Slow Queries",value:(t==null?void 0:t.slow_query_count)||"0",color:"#f59e0b"}),M.jsx(Cn,{label:"N+1 Detected",value:(t==null?void 0:t.n1_count)||"0",color:(t==null?void 0:t.n1_count)>0?"#ef4444":"#22c55e"})]}),h&&h.open_connections!==void 0&&M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:20},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",fontWeight:600,marginBottom:10},children:"CONNECTION POOL"}),M.jsxs("div",{style:{display:"flex",gap:24},children:[M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Open:"})," ",M.jsx("span",{style:{color:"#818cf8",fontWeight:600},children:h.open_connections})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"In Use:"})," ",M.jsx("span",{style:{color:"#f59e0b",fontWeight:600},children:h.in_use})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Idle:"})," ",M.jsx("span",{style:{color:"#22c55e",fontWeight:600},children:h.idle})]}),M.jsxs("span",{style:{fontSize:13},children:[M.jsx("span",{style:{color:"#64748b"},children:"Wait Count:"})," ",M.jsx("span",{style:{color:"#94a3b8"},children:h.wait_count})]})]})]}),M.jsx("div",{style:{display:"flex",gap:4,marginBottom:14},children:O.map(({id:C,label:E,count:j})=>M.jsxs("button",{onClick:()=>g(C),style:{padding:"8px 16px",borderRadius:6,border:"none",fontSize:13,fontWeight:600,cursor:"pointer",background:y===C?"#6366f120":"transparent",color:y===C?"#818cf8":"#64748b"},children:[E," ",j>0&&M.jsxs("span",{style:{fontSize:11,opacity:.7},children:["(",j,")"]})]},C))}),y==="slow"&&M.jsx(Dr,{columns:b,data:r||[],emptyText:"No slow queries detected"}),y==="patterns"&&M.jsx(Dr,{columns:x,data:u||[],emptyText:"No query patterns yet"}),y==="n1"&&M.jsx(Dr,{columns:A,data:f||[],emptyText:"No N+1 queries detected"})]})}function eK(){const{get:e,post:t,del:n}=_a(),[r,o]=P.useState([]),[u,c]=P.useState({type:"",resolved:"",muted:""}),[f,d]=P.useState(null),[h,v]=P.useState(!0),y=async()=>{const O=new URLSearchParams({range:"24h",limit:"100"});u.type&&O.set("type",u.type),u.resolved&&O.set("resolved",u.resolved),u.muted&&O.set("muted",u.muted);try{const T=await e(`/errors?${O}`);T.ok&&o(await T.json())}catch{}v(!1)};P.useEffect(()=>{y()},[u]);const g=async O=>{try{const T=await e(`/errors/${O.id}`);T.ok&&d(await T.json())}catch{}},S=async O=>{await t(`/errors/${O}/mute`),d(null),y()},w=async O=>{await t(`/errors/${O}/resolve`),d(null),y()},b=async O=>{await n(`/errors/${O}`),d(null),y()},x=[{key:"error_type",label:"Type",render:O=>M.jsx(Pr,{status:O})},{key:"method",label:"Method",render:O=>M.jsx("span",{style:{fontWeight:600,fontSize:12,color:"#818cf8"},children:O})},{key:"route",label:"Route"},{key:"error_message",label:"Message",render:O=>M.jsx("span",{style:{maxWidth:280,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:O})},{key:"count",label:"Count",render:O=>M.jsx("span",{style:{fontWeight:700,color:O>10?"#ef4444":"#e2e8f0"},children:O})},{key:"muted",label:"Muted",render:O=>O?M.jsx("span",{style:{color:"#64748b"},children:"Yes"}):null},{key:"resolved",label:"Resolved",render:O=>O?M.jsx("span",{style:{color:"#22c55e"},children:"Yes"}):null},{key:"last_seen",label:"Last Seen",render:O=>O?new Date(O).toLocaleString():"-"}],A=["","panic","internal","database","validation","timeout","auth","not_found"];return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Errors"}),M.jsxs("div",{style:{display:"flex",gap:8,marginBottom:14},children:[M.jsxs("select",{value:u.type,onChange:O=>c({...u,type:O.target.value}),children:[M.jsx("option",{value:"",children:"All Types"}),A.filter(Boolean).map(O=>M.jsx("option",{value:O,children:O},O))]}),M.jsxs("select",{value:u.resolved,onChange:O=>c({...u,resolved:O.target.value}),children:[M.jsx("option",{value:"",children:"All Status"}),M.jsx("option",{value:"false",children:"Unresolved"}),M.jsx("option",{value:"true",children:"Resolved"})]}),M.jsxs("select",{value:u.muted,onChange:O=>c({...u,muted:O.target.value}),children:[M.jsx("option",{value:"",children:"Muted/Unmuted"}),M.jsx("option",{value:"false",children:"Not Muted"}),M.jsx("option",{value:"true",children:"Muted"})]})]}),h?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:x,data:r||[],onRowClick:g,emptyText:"No errors recorded"}),M.jsx(VD,{open:!!f,onClose:()=>d(null),title:"Error Detail",width:720,children:f&&M.jsxs("div",{children:[M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"1fr 1fr",gap:12,marginBottom:16},children:[M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Type"}),M.jsx("div",{style:{marginTop:4},children:M.jsx(Pr,{status:f.error_type})})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Route"}),M.jsxs("p",{style:{fontSize:14,color:"#e2e8f0",marginTop:4},children:[f.method," ",f.route]})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Count"}),M.jsx("p",{style:{fontSize:20,fontWeight:700,color:"#ef4444",marginTop:4},children:f.count})]}),M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"First Seen"}),M.jsx("p",{style:{fontSize:13,color:"#94a3b8",marginTop:4},children:new Date(f.first_seen).toLocaleString()})]})]}),M.jsxs("div",{style:{marginBottom:16},children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Message"}),M.jsx("p",{style:{marginTop:4,padding:"10px 14px",background:"#0a0a12",borderRadius:6,fontSize:13,color:"#ef4444",fontFamily:"'SF Mono', monospace"},children:f.error_message})]}),f.stack_trace&&M.jsxs("div",{style:{marginBottom:16},children:[M.jsx("span",{style:{color:"#64748b",fontSize:12},children:"Stack Trace"}),M.jsx("pre",{style:{marginTop:4,padding:14,background:"#0a0a12",borderRadius:6,fontSize:11,color:"#94a3b8",overflow:"auto",maxHeight:250,fontFamily:"'SF Mono', 'Fira Code', monospace",lineHeight:1.5,whiteSpace:"pre-wrap",wordBreak:"break-word"},children:f.stack_trace})]}),M.jsxs("div",{style:{display:"flex",gap:8,marginTop:16},children:[!f.muted&&M.jsx("button",{onClick:()=>S(f.id),style:Bg("#f59e0b"),children:"Mute"}),!f.resolved&&M.jsx("button",{onClick:()=>w(f.id),style:Bg("#22c55e"),children:"Resolve"}),M.jsx("button",{onClick:()=>b(f.id),style:Bg("#ef4444"),children:"Delete"})]})]})})]})}const Bg=e=>({padding:"6px 14px",borderRadius:6,border:`1px solid ${e}40`,background:`${e}18`,color:e,fontSize:13,fontWeight:600,cursor:"pointer"});function yM(e){return e?e<1024?`${e} B`:e<1048576?`${(e/1024).toFixed(1)} KB`:e<1073741824?`${(e/1048576).toFixed(1)} MB`:`${(e/1073741824).toFixed(2)} GB`:"0 B"}function tK(){const{get:e}=_a(),[t,n]=P.useState(null),[r,o]=P.useState([]),[u,c]=P.useState(null),[f,d]=P.useState("1h"),[h,v]=P.useState(!0),{lastMessage:y}=Bf(["runtime"]),g=async()=>{try{const[b,x,A]=await Promise.all([e("/runtime/current"),e(`/runtime/history?range=${f}`),e("/runtime/info")]);b.ok&&n(await b.json()),x.ok&&o(await x.json()),A.ok&&c(await A.json())}catch{}v(!1)};if(P.useEffect(()=>{g()},[f]),P.useEffect(()=>{(y==null?void 0:y.type)==="runtime"&&(y!=null&&y.data)&&n(y.data)},[y]),h)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const S=t||{},w=(r||[]).map(b=>({time:new Date(b.timestamp).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"}),heap:(b.heap_alloc||0)/1048576,heapInUse:(b.heap_in_use||0)/1048576,goroutines:b.num_goroutine||0,gcPause:(b.gc_pause_ns||0)/1e6}));return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Runtime"}),M.jsxs("select",{value:f,onChange:b=>d(b.target.value),children:[M.jsx("option",{value:"5m",children:"5m"}),M.jsx("option",{value:"15m",children:"15m"}),M.jsx("option",{value:"1h",children:"1h"}),M.jsx("option",{value:"6h",children:"6h"}),M.jsx("option",{value:"24h",children:"24h"})]})]}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(4, 1fr)",gap:14,marginBottom:20},children:[M.jsx(Cn,{label:"Heap Alloc",value:yM(S.heap_alloc),color:"#818cf8"}),M.jsx(Cn,{label:"Goroutines",value:S.num_goroutine||"0",color:"#22c55e"}),M.jsx(Cn,{label:"GC Cycles",value:S.num_gc||"0",color:"#f59e0b"}),M.jsx(Cn,{label:"Sys Memory",value:yM(S.sys),color:"#94a3b8"})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:14},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"MEMORY (MB)"}),M.jsx(du,{width:"100%",height:220,children:M.jsxs(dM,{data:w,children:[M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(ni,{wrapperStyle:{fontSize:12}}),M.jsx(ii,{type:"monotone",dataKey:"heap",name:"Heap Alloc",stroke:"#6366f1",strokeWidth:2,dot:!1}),M.jsx(ii,{type:"monotone",dataKey:"heapInUse",name:"Heap In Use",stroke:"#8b5cf6",strokeWidth:2,dot:!1})]})})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,marginBottom:14},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"GOROUTINES"}),M.jsx(du,{width:"100%",height:180,children:M.jsxs(dM,{data:w,children:[M.jsx(or,{dataKey:"time",tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1}),M.jsx(lr,{tick:{fill:"#64748b",fontSize:10},axisLine:!1,tickLine:!1,width:50}),M.jsx(Zt,{contentStyle:{background:"#16161e",border:"1px solid #2a2a3e",borderRadius:6,fontSize:12}}),M.jsx(ii,{type:"monotone",dataKey:"goroutines",stroke:"#22c55e",strokeWidth:2,dot:!1})]})})]}),u&&M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16},children:[M.jsx("h3",{style:{fontSize:13,color:"#64748b",marginBottom:12,fontWeight:600},children:"SYSTEM INFO"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:12,fontSize:13},children:[u.system&&Object.entries(u.system).map(([b,x])=>M.jsxs("div",{children:[M.jsxs("span",{style:{color:"#64748b"},children:[b.replace(/_/g," "),": "]}),M.jsx("span",{style:{color:"#e2e8f0"},children:String(x)})]},b)),u.uptime&&M.jsxs("div",{children:[M.jsx("span",{style:{color:"#64748b"},children:"uptime: "}),M.jsx("span",{style:{color:"#22c55e"},children:u.uptime})]})]})]})]})}function nK(){const{get:e,post:t}=_a(),[n,r]=P.useState(null),[o,u]=P.useState(null),[c,f]=P.useState([]),[d,h]=P.useState(!0),{lastMessage:v}=Bf(["health"]),y=async()=>{try{const A=await e("/health/checks");A.ok&&r(await A.json())}catch{}h(!1)};P.useEffect(()=>{y()},[]),P.useEffect(()=>{(v==null?void 0:v.type)==="health"&&y()},[v]);const g=async A=>{u(A);try{const O=await e(`/health/checks/${A}/history?limit=20`);O.ok&&f(await O.json())}catch{}},S=async A=>{try{await t(`/health/checks/${A}/run`),y(),o===A&&g(A)}catch{}};if(d)return M.jsx("div",{style:{color:"#64748b",padding:40},children:"Loading..."});const w=(n==null?void 0:n.checks)||{},b=(n==null?void 0:n.status)||"unknown",x=[{key:"status",label:"Status",render:A=>M.jsx(Pr,{status:A})},{key:"latency_ms",label:"Latency",render:A=>`${A==null?void 0:A.toFixed(1)}ms`},{key:"error",label:"Error",render:A=>A?M.jsx("span",{style:{color:"#ef4444",fontSize:12},children:A}):M.jsx("span",{style:{color:"#64748b"},children:"-"})},{key:"timestamp",label:"Time",render:A=>A?new Date(A).toLocaleString():"-"}];return M.jsxs("div",{children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:16},children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700},children:"Health"}),M.jsx(Pr,{status:b})]}),(n==null?void 0:n.uptime)&&M.jsxs("p",{style:{color:"#64748b",fontSize:13,marginBottom:16},children:["Uptime: ",n.uptime]}),M.jsx("div",{style:{display:"grid",gridTemplateColumns:"repeat(auto-fill, minmax(280, 1fr))",gap:14,marginBottom:20},children:Object.entries(w).map(([A,O])=>{var T;return M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:16,cursor:"pointer",borderLeft:`3px solid ${O.status==="healthy"?"#22c55e":O.status==="degraded"?"#f59e0b":"#ef4444"}`},onClick:()=>g(A),children:[M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center",marginBottom:8},children:[M.jsx("h3",{style:{fontSize:15,fontWeight:600,color:"#e2e8f0"},children:A}),M.jsx(Pr,{status:O.status})]}),M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",alignItems:"center"},children:[M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:["Latency: ",M.jsxs("span",{style:{color:"#e2e8f0"},children:[(T=O.latency_ms)==null?void 0:T.toFixed(1),"ms"]})]}),M.jsx("button",{onClick:C=>{C.stopPropagation(),S(A)},style:{padding:"3px 10px",borderRadius:4,fontSize:11,fontWeight:600,background:"#6366f118",color:"#818cf8",border:"1px solid #6366f130",cursor:"pointer"},children:"Run"})]}),O.error&&M.jsx("p",{style:{color:"#ef4444",fontSize:12,marginTop:8},children:O.error})]},A)})}),Object.keys(w).length===0&&M.jsx("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:40,textAlign:"center",color:"#64748b",fontSize:14},children:"No health checks registered"}),o&&M.jsxs("div",{children:[M.jsxs("h2",{style:{fontSize:16,fontWeight:600,marginBottom:10},children:["History: ",M.jsx("span",{style:{color:"#818cf8"},children:o})]}),M.jsx(Dr,{columns:x,data:c||[],emptyText:"No history available"})]})]})}function rK(){const{get:e}=_a(),[t,n]=P.useState([]),[r,o]=P.useState(""),[u,c]=P.useState(""),[f,d]=P.useState(!0),{lastMessage:h}=Bf(["alert"]),v=async()=>{const b=new URLSearchParams({range:"24h",limit:"100"});r&&b.set("state",r),u&&b.set("severity",u);try{const x=await e(`/alerts?${b}`);x.ok&&n(await x.json())}catch{}d(!1)};P.useEffect(()=>{v()},[r,u]),P.useEffect(()=>{(h==null?void 0:h.type)==="alert"&&v()},[h]);const y=[{key:"state",label:"State",render:b=>M.jsx(Pr,{status:b})},{key:"severity",label:"Severity",render:b=>M.jsx(Pr,{status:b})},{key:"rule_name",label:"Rule"},{key:"metric",label:"Metric",render:b=>M.jsx("span",{style:{color:"#818cf8",fontSize:12,fontFamily:"'SF Mono', monospace"},children:b})},{key:"value",label:"Value",render:(b,x)=>{var A;return M.jsxs("span",{children:[M.jsx("span",{style:{fontWeight:600,color:"#e2e8f0"},children:b==null?void 0:b.toFixed(1)}),M.jsxs("span",{style:{color:"#64748b",fontSize:12},children:[" ",x.operator," ",(A=x.threshold)==null?void 0:A.toFixed(1)]})]})}},{key:"message",label:"Message",render:b=>M.jsx("span",{style:{maxWidth:250,display:"inline-block",overflow:"hidden",textOverflow:"ellipsis",whiteSpace:"nowrap"},children:b})},{key:"fired_at",label:"Fired At",render:b=>b?new Date(b).toLocaleString():"-"},{key:"resolved_at",label:"Resolved",render:b=>b?new Date(b).toLocaleString():M.jsx("span",{style:{color:"#64748b"},children:"-"})}],g=(t==null?void 0:t.filter(b=>b.state==="firing").length)||0,S=(t==null?void 0:t.filter(b=>b.state==="resolved").length)||0,w=(t==null?void 0:t.filter(b=>b.severity==="critical").length)||0;return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Alerts"}),M.jsxs("div",{style:{display:"grid",gridTemplateColumns:"repeat(3, 1fr)",gap:14,marginBottom:20},children:[M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #ef4444"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"FIRING"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:g>0?"#ef4444":"#22c55e"},children:g})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #f59e0b"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"CRITICAL"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:w>0?"#f59e0b":"#94a3b8"},children:w})]}),M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:"14px 18px",borderLeft:"3px solid #22c55e"},children:[M.jsx("p",{style:{color:"#64748b",fontSize:12},children:"RESOLVED"}),M.jsx("p",{style:{fontSize:28,fontWeight:700,color:"#22c55e"},children:S})]})]}),M.jsxs("div",{style:{display:"flex",gap:8,marginBottom:14},children:[M.jsxs("select",{value:r,onChange:b=>o(b.target.value),children:[M.jsx("option",{value:"",children:"All States"}),M.jsx("option",{value:"firing",children:"Firing"}),M.jsx("option",{value:"resolved",children:"Resolved"})]}),M.jsxs("select",{value:u,onChange:b=>c(b.target.value),children:[M.jsx("option",{value:"",children:"All Severities"}),M.jsx("option",{value:"critical",children:"Critical"}),M.jsx("option",{value:"warning",children:"Warning"}),M.jsx("option",{value:"info",children:"Info"})]})]}),f?M.jsx("p",{style:{color:"#64748b"},children:"Loading..."}):M.jsx(Dr,{columns:y,data:t||[],emptyText:"No alerts in the last 24 hours"})]})}function aK(){var C,E,j,D,z,B,F,H,L,V,Z,Q,q;const{get:e,post:t}=_a(),[n,r]=P.useState(null),[o,u]=P.useState("requests"),[c,f]=P.useState("json"),[d,h]=P.useState("1h"),[v,y]=P.useState(!1),[g,S]=P.useState(!1),[w,b]=P.useState(""),[dK,fK]=P.useState(null);P.useEffect(()=>{(async()=>{try{const J=await e("/settings");J.ok&&r(await J.json());const U=await e("/settings/retention");U.ok&&fK(await U.json())}catch{}})()},[]);const x=async()=>{y(!0),b("");try{const U=await t("/data/export",{format:c,type:o,range:d});if(!U.ok){const se=await U.json();b(`Export failed: ${se.error}`);return}const J=await U.blob(),ue=(U.headers.get("Content-Disposition")||"").match(/filename=(.+)/),N=ue?ue[1]:`pulse_${o}.${c}`,G=URL.createObjectURL(J),te=document.createElement("a");te.href=G,te.download=N,te.click(),URL.revokeObjectURL(G),b("Export downloaded successfully")}catch(U){b(`Export error: ${U.message}`)}finally{y(!1)}},A=async()=>{try{const U=await t("/data/reset",{confirm:!0});if(U.ok)b("All data has been reset"),S(!1);else{const J=await U.json();b(`Reset failed: ${J.error}`)}}catch(U){b(`Reset error: ${U.message}`)}},O=({title:U,children:J})=>M.jsxs("div",{style:{background:"#111118",border:"1px solid #1e1e2e",borderRadius:8,padding:20,marginBottom:16},children:[M.jsx("h3",{style:{fontSize:14,fontWeight:600,color:"#e2e8f0",marginBottom:14},children:U}),J]}),T=({label:U,value:J})=>M.jsxs("div",{style:{display:"flex",justifyContent:"space-between",padding:"6px 0",borderBottom:"1px solid #16161e"},children:[M.jsx("span",{style:{color:"#64748b",fontSize:13},children:U}),M.jsx("span",{style:{color:"#e2e8f0",fontSize:13,fontFamily:"'SF Mono', monospace"},children:typeof J=="boolean"?J?"true":"false":String(J??"-")})]});const hK=n==null?void 0:n.ingestion_stats,mK=dK==null?void 0:dK.policy,gK=dK==null?void 0:dK.last_cleanup,yK=dK==null?void 0:dK.snapshot;return M.jsxs("div",{children:[M.jsx("h1",{style:{fontSize:22,fontWeight:700,marginBottom:16},children:"Settings"}),w&&M.jsx("div",{style:{padding:"10px 14px",borderRadius:6,marginBottom:14,fontSize:13,background:w.includes("fail")||w.includes("error")?"#ef444418":"#22c55e18",color:w.includes("fail")||w.includes("error")?"#ef4444":"#22c55e",border:`1px solid ${w.includes("fail")||w.includes("error")?"#ef444430":"#22c55e30"}`},children:w}),M.jsx(O,{title:"Data Export",children:M.jsxs("div",{style:{display:"flex",gap:8,alignItems:"center",flexWrap:"wrap"},children:[M.jsxs("select",{value:o,onChange:U=>u(U.target.value),children:[M.jsx("option",{value:"requests",children:"Requests"}),M.jsx("option",{value:"queries",children:"Queries"}),M.jsx("option",{value:"errors",children:"Errors"}),M.jsx("option",{value:"runtime",children:"Runtime"}),M.jsx("option",{value:"alerts",children:"Alerts"})]}),M.jsxs("select",{value:c,onChange:U=>f(U.target.value),children:[M.jsx("option",{value:"json",children:"JSON"}),M.jsx("option",{value:"csv",children:"CSV"})]}),M.jsxs("select",{value:d,onChange:U=>h(U.target.value),children:[M.jsx("option",{value:"5m",children:"Last 5m"}),M.jsx("option",{value:"15m",children:"Last 15m"}),M.jsx("option",{value:"1h",children:"Last 1h"}),M.jsx("option",{value:"6h",children:"Last 6h"}),M.jsx("option",{value:"24h",children:"Last 24h"}),M.jsx("option",{value:"7d",children:"Last 7d"})]}),M.jsx("button",{onClick:x,disabled:v,style:{padding:"8px 16px",borderRadius:6,border:"none",fontSize:13,fontWeight:600,cursor:"pointer",background:"linear-gradient(135deg, #6366f1, #7c3aed)",color:"#fff",opacity:v?.6:1},children:v?"Exporting...":"Export"})]})}),n&&M.jsxs(O,{title:"Current Configuration",children:[M.jsx(T,{label:"App Name",value:n.AppName}),M.jsx(T,{label:"Prefix",value:n.Prefix}),M.jsx(T,{label:"Dev Mode",value:n.DevMode}),M.jsx(T,{label:"Dashboard Username",value:(C=n.Dashboard)==null?void 0:C.Username}),M.jsx(T,{label:"Storage Driver",value:["Memory","SQLite","GORM"][(E=n.Storage)==null?void 0:E.Driver]??((E=n.Storage)==null?void 0:E.Driver)}),M.jsx(T,{label:"Retention Hours",value:(j=n.Storage)==null?void 0:j.RetentionHours}),M.jsx(T,{label:"Tracing Enabled",value:(D=n.Tracing)==null?void 0:D.Enabled}),M.jsx(T,{label:"Slow Request Threshold",value:`${(((z=n.Tracing)==null?void 0:z.SlowRequestThreshold)/1e6).toFixed(0)}ms`}),M.jsx(T,{label:"Database Enabled",value:(B=n.Database)==null?void 0:B.Enabled}),M.jsx(T,{label:"Slow Query Threshold",value:`${(((F=n.Database)==null?void 0:F.SlowQueryThreshold)/1e6).toFixed(0)}ms`}),M.jsx(T,{label:"N+1 Detection",value:(H=n.Database)==null?void 0:H.DetectN1}),M.jsx(T,{label:"Runtime Enabled",value:(L=n.Runtime)==null?void 0:L.Enabled}),M.jsx(T,{label:"Error Tracking",value:(V=n.Errors)==null?void 0:V.Enabled}),M.jsx(T,{label:"Health Checks",value:(Z=n.Health)==null?void 0:Z.Enabled}),M.jsx(T,{label:"Alerts Enabled",value:(Q=n.Alerts)==null?void 0:Q.Enabled}),M.jsx(T,{label:"Prometheus",value:(q=n.Prometheus)==null?void 0:q.Enabled})]}),hK&&M.jsxs(O,{title:"Ingestion Queue",children:[M.jsx(T,{label:"Overflow Policy",value:hK.policy}),M.jsx(T,{label:"Queued",value:`${hK.queued} / ${hK.capacity}`}),M.jsx(T,{label:"Enqueued",value:hK.enqueued}),M.jsx(T,{label:"Written",value:hK.written}),M.jsx(T,{label:"Dropped",value:hK.dropped}),M.jsx(T,{label:"Failed Batches",value:hK.failed}),M.jsx(T,{label:"Failed Items",value:hK.failed_items})]}),dK&&M.jsxs(O,{title:"Data Retention",children:[M.jsx(T,{label:"Requests",value:uK(mK==null?void 0:mK.Requests)}),M.jsx(T,{label:"Queries",value:uK(mK==null?void 0:mK.Queries)}),M.jsx(T,{label:"Runtime",value:uK(mK==null?void 0:mK.Runtime)}),M.jsx(T,{label:"Errors",value:uK(mK==null?void 0:mK.Errors)}),M.jsx(T,{label:"Alerts",value:uK(mK==null?void 0:mK.Alerts)}),M.jsx(T,{label:"Health",value:uK(mK==null?void 0:mK.Health)}),M.jsx(T,{label:"Dependencies",value:uK(mK==null?void 0:mK.Dependencies)}),M.jsx(T,{label:"Last Cleanup",value:gK!=null&&gK.runs?new Date(gK.last_run).toLocaleString():"Not run yet"}),M.jsx(T,{label:"Records Pruned",value:gK!=null&&gK.runs?cK(gK.pruned):"-"}),(gK==null?void 0:gK.error)&&M.jsx(T,{label:"Last Error",value:gK.error}),yK&&M.jsxs(M.Fragment,{children:[M.jsx(T,{label:"Snapshot File",value:yK.path}),M.jsx(T,{label:"Last Snapshot",value:yK.last_saved&&!yK.last_saved.startsWith("0001")?new Date(yK.last_saved).toLocaleString():yK.restored?"Restored on startup":"Not written yet"}),yK.error&&M.jsx(T,{label:"Snapshot Error",value:yK.error})]})]}),M.jsxs(O,{title:"Danger Zone",children:[M.jsx("p",{style:{color:"#94a3b8",fontSize:13,marginBottom:12},children:"Reset all stored metrics, errors, health history, and alerts. This action cannot be undone."}),g?M.jsxs("div",{style:{display:"flex",gap:8,alignItems:"center"},children:[M.jsx("span",{style:{color:"#ef4444",fontSize:13,fontWeight:600},children:"Are you sure?"}),M.jsx("button",{onClick:A,style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#ef4444",color:"#fff",border:"none"},children:"Yes, Reset Everything"}),M.jsx("button",{onClick:()=>S(!1),style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#2a2a3e",color:"#94a3b8",border:"none"},children:"Cancel"})]}):M.jsx("button",{onClick:()=>S(!0),style:{padding:"8px 16px",borderRadius:6,fontSize:13,fontWeight:600,cursor:"pointer",background:"#ef444418",color:"#ef4444",border:"1px solid #ef444430"},children:"Reset All Data"})]})]})}function uK(e){if(!e)return"-";const t=e/36e11;return t>=24&&t%24===0?`${t/24}d`:`${+t.toFixed(2)}h`}function cK(e){return Object.values(e||{}).reduce((t,n)=>t+n,0)}function iK({children:e}){const{isAuthenticated:t}=$f();return t?e:M.jsx(MM,{to:"/pulse/ui/login",replace:!0})}function oK(){return M.jsxs(_3,{children:[M.jsx(Mn,{path:"/pulse/ui/login",element:M.jsx(i6,{})}),M.jsxs(Mn,{path:"/pulse/ui",element:M.jsx(iK,{children:M.jsx(a6,{})}),children:[M.jsx(Mn,{index:!0,element:M.jsx(QX,{})}),M.jsx(Mn,{path:"routes",element:M.jsx(ZX,{})}),M.jsx(Mn,{path:"database",element:M.jsx(JX,{})}),M.jsx(Mn,{path:"errors",element:M.jsx(eK,{})}),M.jsx(Mn,{path:"runtime",element:M.jsx(tK,{})}),M.jsx(Mn,{path:"health",element:M.jsx(nK,{})}),M.jsx(Mn,{path:"alerts",element:M.jsx(rK,{})}),M.jsx(Mn,{path:"settings",element:M.jsx(aK,{})})]}),M.jsx(Mn,{path:"*",element:M.jsx(MM,{to:"/pulse/ui",replace:!0})})]})}_N.createRoot(document.getElementById("root")).render(M.jsx(P.StrictMode,{children:M.jsx(K3,{children:M.jsx(n6,{children:M.jsx(oK,{})})})}));